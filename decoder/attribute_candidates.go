@@ -15,12 +15,17 @@ import (
 	"github.com/zclconf/go-cty/cty"
 )
 
-func attributeSchemaToCandidate(ctx context.Context, name string, attr *schema.AttributeSchema, rng hcl.Range) lang.Candidate {
+func attributeSchemaToCandidate(ctx context.Context, name string, attr *schema.AttributeSchema, defaultCtx schema.DefaultContext, rng hcl.Range) lang.Candidate {
 	var snippet string
 	var triggerSuggest bool
-	cData := attr.Constraint.EmptyCompletionData(ctx, 1, 0)
-	snippet = fmt.Sprintf("%s = %s", name, cData.Snippet)
-	triggerSuggest = cData.TriggerSuggest
+	if defaultSnippet, ok := snippetForDefault(attr, defaultCtx, 1); ok && prefillDefaultValues(ctx) {
+		// prefill the default which applies when the attribute is omitted
+		snippet = fmt.Sprintf("%s = %s", name, defaultSnippet)
+	} else {
+		cData := attr.Constraint.EmptyCompletionData(ctx, 1, 0)
+		snippet = fmt.Sprintf("%s = %s", name, cData.Snippet)
+		triggerSuggest = cData.TriggerSuggest
+	}
 
 	return lang.Candidate{
 		Label:        name,
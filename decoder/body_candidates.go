@@ -21,6 +21,7 @@ func (d *PathDecoder) bodySchemaCandidates(ctx context.Context, body *hclsyntax.
 
 	candidates := lang.NewCandidates()
 	count := 0
+	defaultCtx := d.defaultContextForBody(body)
 
 	if schema.Extensions != nil {
 		// check if count attribute "extension" is enabled here
@@ -28,7 +29,7 @@ func (d *PathDecoder) bodySchemaCandidates(ctx context.Context, body *hclsyntax.
 			// check if count attribute is already declared, so we don't
 			// suggest a duplicate
 			if _, ok := body.Attributes["count"]; !ok {
-				candidates.List = append(candidates.List, attributeSchemaToCandidate(ctx, "count", schemahelper.CountAttributeSchema(), editRng))
+				candidates.List = append(candidates.List, attributeSchemaToCandidate(ctx, "count", schemahelper.CountAttributeSchema(), defaultCtx, editRng))
 			}
 		}
 
@@ -36,7 +37,7 @@ func (d *PathDecoder) bodySchemaCandidates(ctx context.Context, body *hclsyntax.
 			// check if for_each attribute is already declared, so we don't
 			// suggest a duplicate
 			if _, present := body.Attributes["for_each"]; !present {
-				candidates.List = append(candidates.List, attributeSchemaToCandidate(ctx, "for_each", schemahelper.ForEachAttributeSchema(), editRng))
+				candidates.List = append(candidates.List, attributeSchemaToCandidate(ctx, "for_each", schemahelper.ForEachAttributeSchema(), defaultCtx, editRng))
 			}
 		}
 	}
@@ -56,7 +57,7 @@ func (d *PathDecoder) bodySchemaCandidates(ctx context.Context, body *hclsyntax.
 				return candidates
 			}
 
-			candidates.List = append(candidates.List, attributeSchemaToCandidate(ctx, name, attr, editRng))
+			candidates.List = append(candidates.List, attributeSchemaToCandidate(ctx, name, attr, defaultCtx, editRng))
 			count++
 		}
 	} else if attr := schema.AnyAttribute; attr != nil && len(prefix) == 0 {
@@ -64,7 +65,7 @@ func (d *PathDecoder) bodySchemaCandidates(ctx context.Context, body *hclsyntax.
 			return candidates
 		}
 
-		candidates.List = append(candidates.List, attributeSchemaToCandidate(ctx, "name", attr, editRng))
+		candidates.List = append(candidates.List, attributeSchemaToCandidate(ctx, "name", attr, defaultCtx, editRng))
 		count++
 	}
 
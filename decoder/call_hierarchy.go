@@ -0,0 +1,355 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package decoder
+
+import (
+	"context"
+	"sort"
+
+	"github.com/hashicorp/hcl-lang/decoder/internal/ast"
+	"github.com/hashicorp/hcl-lang/lang"
+	"github.com/hashicorp/hcl-lang/reference"
+	"github.com/hashicorp/hcl-lang/schema"
+	"github.com/hashicorp/hcl/v2"
+)
+
+// CallHierarchyItem represents an addressable block
+// (i.e. block with BlockAddrSchema) in the call hierarchy
+type CallHierarchyItem struct {
+	Path    lang.Path
+	Address lang.Address
+
+	// BlockType represents the type of the block, e.g. resource
+	BlockType string
+
+	// Range represents range of the whole block
+	Range hcl.Range
+
+	// DefRange represents range of the block header
+	DefRange hcl.Range
+}
+
+// CallHierarchyIncomingCall represents a block which references
+// the item the call hierarchy was requested for
+type CallHierarchyIncomingCall struct {
+	From CallHierarchyItem
+
+	// FromRanges represents ranges of origins within From
+	// which refer to the requested item
+	FromRanges []hcl.Range
+}
+
+// CallHierarchyOutgoingCall represents a block which is referenced
+// from the item the call hierarchy was requested for
+type CallHierarchyOutgoingCall struct {
+	To CallHierarchyItem
+
+	// FromRanges represents ranges of origins within the requested
+	// item which refer to To
+	FromRanges []hcl.Range
+}
+
+// CallHierarchyItemsAtPos returns the innermost addressable block
+// at the given position, if there is one.
+func (d *Decoder) CallHierarchyItemsAtPos(path lang.Path, file string, pos hcl.Pos) ([]CallHierarchyItem, error) {
+	pathDecoder, err := d.Path(path)
+	if err != nil {
+		return nil, err
+	}
+	if pathDecoder.pathCtx.Schema == nil {
+		return nil, &NoSchemaError{}
+	}
+	if _, err := pathDecoder.fileByName(file); err != nil {
+		return nil, err
+	}
+
+	items := make([]CallHierarchyItem, 0)
+	item, ok := innermostCallHierarchyItem(pathDecoder.callHierarchyItems(), hcl.Range{
+		Filename: file,
+		Start:    pos,
+		End:      pos,
+	})
+	if ok {
+		items = append(items, item)
+	}
+
+	return items, nil
+}
+
+// CallHierarchyIncomingCalls returns blocks across all known paths
+// which contain origins targeting the given item.
+func (d *Decoder) CallHierarchyIncomingCalls(ctx context.Context, item CallHierarchyItem) ([]CallHierarchyIncomingCall, error) {
+	itemCtx, err := d.pathReader.PathContext(item.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	targets := make(reference.Targets, 0)
+	for _, target := range itemCtx.ReferenceTargets.OutermostInFile(item.Range.Filename) {
+		if rangeContainsRange(item.Range, *target.RangePtr) {
+			targets = append(targets, target)
+		}
+	}
+
+	calls := make([]CallHierarchyIncomingCall, 0)
+	callIdx := make(map[string]int, 0)
+
+	for _, p := range d.pathReader.Paths(ctx) {
+		if ctx.Err() != nil {
+			return calls, ctx.Err()
+		}
+
+		pathDecoder, err := d.Path(p)
+		if err != nil || pathDecoder.pathCtx.Schema == nil {
+			continue
+		}
+
+		var pathItems []CallHierarchyItem
+		for _, target := range targets {
+			for _, origin := range pathDecoder.pathCtx.ReferenceOrigins.Match(p, target, item.Path) {
+				if pathItems == nil {
+					pathItems = pathDecoder.callHierarchyItems()
+				}
+
+				fromItem, ok := innermostCallHierarchyItem(pathItems, origin.OriginRange())
+				if !ok || fromItem.equals(item) {
+					continue
+				}
+
+				key := fromItem.key()
+				idx, ok := callIdx[key]
+				if !ok {
+					idx = len(calls)
+					callIdx[key] = idx
+					calls = append(calls, CallHierarchyIncomingCall{
+						From:       fromItem,
+						FromRanges: make([]hcl.Range, 0),
+					})
+				}
+				calls[idx].FromRanges = appendUniqueRange(calls[idx].FromRanges, origin.OriginRange())
+			}
+		}
+	}
+
+	sort.SliceStable(calls, func(i, j int) bool {
+		return calls[i].From.less(calls[j].From)
+	})
+	for _, call := range calls {
+		sortRanges(call.FromRanges)
+	}
+
+	return calls, nil
+}
+
+// CallHierarchyOutgoingCalls returns blocks which are targeted by any origins
+// within the given item, including those in other paths via PathOrigin.
+func (d *Decoder) CallHierarchyOutgoingCalls(ctx context.Context, item CallHierarchyItem) ([]CallHierarchyOutgoingCall, error) {
+	itemCtx, err := d.pathReader.PathContext(item.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	calls := make([]CallHierarchyOutgoingCall, 0)
+	callIdx := make(map[string]int, 0)
+	pathItems := make(map[string][]CallHierarchyItem, 0)
+
+	for _, origin := range itemCtx.ReferenceOrigins {
+		if ctx.Err() != nil {
+			return calls, ctx.Err()
+		}
+		if !rangeContainsRange(item.Range, origin.OriginRange()) {
+			continue
+		}
+
+		matchableOrigin, ok := origin.(reference.MatchableOrigin)
+		if !ok {
+			continue
+		}
+
+		targetPath := item.Path
+		targetCtx := itemCtx
+		if pathOrigin, ok := origin.(reference.PathOrigin); ok {
+			targetPath = pathOrigin.TargetPath
+			targetCtx, err = d.pathReader.PathContext(targetPath)
+			if err != nil {
+				continue
+			}
+		}
+
+		targets, ok := targetCtx.ReferenceTargets.Match(matchableOrigin)
+		if !ok {
+			continue
+		}
+
+		items, ok := pathItems[targetPath.Path]
+		if !ok {
+			pathDecoder, err := d.Path(targetPath)
+			if err == nil && pathDecoder.pathCtx.Schema != nil {
+				items = pathDecoder.callHierarchyItems()
+			}
+			pathItems[targetPath.Path] = items
+		}
+
+		for _, target := range targets {
+			if target.RangePtr == nil {
+				// target is not addressable
+				continue
+			}
+
+			toItem, ok := innermostCallHierarchyItem(items, *target.RangePtr)
+			if !ok {
+				toItem = callHierarchyItemForTarget(targetPath, target)
+			}
+			if toItem.equals(item) {
+				// ignore self-references
+				continue
+			}
+
+			key := toItem.key()
+			idx, ok := callIdx[key]
+			if !ok {
+				idx = len(calls)
+				callIdx[key] = idx
+				calls = append(calls, CallHierarchyOutgoingCall{
+					To:         toItem,
+					FromRanges: make([]hcl.Range, 0),
+				})
+			}
+			calls[idx].FromRanges = appendUniqueRange(calls[idx].FromRanges, origin.OriginRange())
+		}
+	}
+
+	sort.SliceStable(calls, func(i, j int) bool {
+		return calls[i].To.less(calls[j].To)
+	})
+	for _, call := range calls {
+		sortRanges(call.FromRanges)
+	}
+
+	return calls, nil
+}
+
+// callHierarchyItems returns all addressable blocks in all files of the path
+func (d *PathDecoder) callHierarchyItems() []CallHierarchyItem {
+	items := make([]CallHierarchyItem, 0)
+
+	for _, filename := range d.filenames() {
+		f, err := d.fileByName(filename)
+		if err != nil {
+			// skip unparseable file
+			continue
+		}
+		items = append(items, d.callHierarchyItemsInBody(f.Body, d.pathCtx.Schema)...)
+	}
+
+	return items
+}
+
+func (d *PathDecoder) callHierarchyItemsInBody(body hcl.Body, bodySchema *schema.BodySchema) []CallHierarchyItem {
+	items := make([]CallHierarchyItem, 0)
+
+	if bodySchema == nil {
+		return items
+	}
+
+	content := ast.DecodeBody(body, bodySchema)
+
+	for _, blk := range content.Blocks {
+		bSchema, ok := bodySchema.Blocks[blk.Type]
+		if !ok {
+			// unknown block (no schema)
+			continue
+		}
+
+		addr, ok := resolveBlockAddress(blk.Block, bSchema)
+		if ok {
+			items = append(items, CallHierarchyItem{
+				Path:      d.path,
+				Address:   addr,
+				BlockType: blk.Type,
+				Range:     blk.Range,
+				DefRange:  blk.DefRange,
+			})
+		}
+
+		mergedSchema, _ := d.mergeBlockBodySchemas(blk.Block, bSchema)
+		items = append(items, d.callHierarchyItemsInBody(blk.Body, mergedSchema)...)
+	}
+
+	return items
+}
+
+func callHierarchyItemForTarget(path lang.Path, target reference.Target) CallHierarchyItem {
+	item := CallHierarchyItem{
+		Path:     path,
+		Address:  target.Addr,
+		Range:    *target.RangePtr,
+		DefRange: *target.RangePtr,
+	}
+	if target.DefRangePtr != nil {
+		item.DefRange = *target.DefRangePtr
+	}
+	return item
+}
+
+// innermostCallHierarchyItem returns the innermost item which contains
+// the whole given range
+func innermostCallHierarchyItem(items []CallHierarchyItem, rng hcl.Range) (CallHierarchyItem, bool) {
+	var innermost CallHierarchyItem
+	found := false
+
+	for _, item := range items {
+		if !rangeContainsRange(item.Range, rng) {
+			continue
+		}
+		if !found || rangeContainsRange(innermost.Range, item.Range) {
+			innermost = item
+			found = true
+		}
+	}
+
+	return innermost, found
+}
+
+func rangeContainsRange(outer, inner hcl.Range) bool {
+	return outer.Filename == inner.Filename &&
+		outer.Start.Byte <= inner.Start.Byte &&
+		outer.End.Byte >= inner.End.Byte
+}
+
+func appendUniqueRange(ranges []hcl.Range, rng hcl.Range) []hcl.Range {
+	for _, r := range ranges {
+		if r == rng {
+			return ranges
+		}
+	}
+	return append(ranges, rng)
+}
+
+func sortRanges(ranges []hcl.Range) {
+	sort.SliceStable(ranges, func(i, j int) bool {
+		if ranges[i].Filename != ranges[j].Filename {
+			return ranges[i].Filename < ranges[j].Filename
+		}
+		return ranges[i].Start.Byte < ranges[j].Start.Byte
+	})
+}
+
+func (item CallHierarchyItem) key() string {
+	return item.Path.Path + ":" + item.Range.String()
+}
+
+func (item CallHierarchyItem) equals(other CallHierarchyItem) bool {
+	return item.Path.Equals(other.Path) && item.Range == other.Range
+}
+
+func (item CallHierarchyItem) less(other CallHierarchyItem) bool {
+	if item.Path.Path != other.Path.Path {
+		return item.Path.Path < other.Path.Path
+	}
+	if item.Range.Filename != other.Range.Filename {
+		return item.Range.Filename < other.Range.Filename
+	}
+	return item.Range.Start.Byte < other.Range.Start.Byte
+}
@@ -0,0 +1,409 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package decoder
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/hcl-lang/lang"
+	"github.com/hashicorp/hcl-lang/reference"
+	"github.com/hashicorp/hcl-lang/schema"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/zclconf/go-cty/cty"
+)
+
+func TestCallHierarchy(t *testing.T) {
+	dirPath := t.TempDir()
+
+	bodySchema := &schema.BodySchema{
+		Blocks: map[string]*schema.BlockSchema{
+			"variable": {
+				Labels: []*schema.LabelSchema{
+					{Name: "name"},
+				},
+				Address: &schema.BlockAddrSchema{
+					Steps: []schema.AddrStep{
+						schema.StaticStep{Name: "var"},
+						schema.LabelStep{Index: 0},
+					},
+					AsTypeOf: &schema.BlockAsTypeOf{},
+				},
+				Body: &schema.BodySchema{},
+			},
+			"output": {
+				Labels: []*schema.LabelSchema{
+					{Name: "name"},
+				},
+				Address: &schema.BlockAddrSchema{
+					Steps: []schema.AddrStep{
+						schema.StaticStep{Name: "output"},
+						schema.LabelStep{Index: 0},
+					},
+				},
+				Body: &schema.BodySchema{
+					Attributes: map[string]*schema.AttributeSchema{
+						"value": {
+							IsOptional: true,
+							Constraint: schema.AnyExpression{OfType: cty.DynamicPseudoType},
+						},
+					},
+				},
+			},
+		},
+	}
+	cfg := `variable "foo" {
+}
+
+output "bar" {
+  value = var.foo
+}
+
+output "baz" {
+  value = [var.foo, var.foo]
+}
+`
+	f, _ := hclsyntax.ParseConfig([]byte(cfg), "test.tf", hcl.InitialPos)
+	pathCtx := &PathContext{
+		Schema: bodySchema,
+		Files: map[string]*hcl.File{
+			"test.tf": f,
+		},
+	}
+	path := lang.Path{Path: dirPath}
+	d := NewDecoder(&testPathReader{
+		paths: map[string]*PathContext{
+			dirPath: pathCtx,
+		},
+	})
+
+	pd, err := d.Path(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pathCtx.ReferenceTargets, err = pd.CollectReferenceTargets()
+	if err != nil {
+		t.Fatal(err)
+	}
+	pathCtx.ReferenceOrigins, err = pd.CollectReferenceOrigins()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	varItem := CallHierarchyItem{
+		Path: path,
+		Address: lang.Address{
+			lang.RootStep{Name: "var"},
+			lang.AttrStep{Name: "foo"},
+		},
+		BlockType: "variable",
+		Range: hcl.Range{
+			Filename: "test.tf",
+			Start:    hcl.Pos{Line: 1, Column: 1, Byte: 0},
+			End:      hcl.Pos{Line: 2, Column: 2, Byte: 18},
+		},
+		DefRange: hcl.Range{
+			Filename: "test.tf",
+			Start:    hcl.Pos{Line: 1, Column: 1, Byte: 0},
+			End:      hcl.Pos{Line: 1, Column: 15, Byte: 14},
+		},
+	}
+	barItem := CallHierarchyItem{
+		Path: path,
+		Address: lang.Address{
+			lang.RootStep{Name: "output"},
+			lang.AttrStep{Name: "bar"},
+		},
+		BlockType: "output",
+		Range: hcl.Range{
+			Filename: "test.tf",
+			Start:    hcl.Pos{Line: 4, Column: 1, Byte: 20},
+			End:      hcl.Pos{Line: 6, Column: 2, Byte: 54},
+		},
+		DefRange: hcl.Range{
+			Filename: "test.tf",
+			Start:    hcl.Pos{Line: 4, Column: 1, Byte: 20},
+			End:      hcl.Pos{Line: 4, Column: 13, Byte: 32},
+		},
+	}
+	bazItem := CallHierarchyItem{
+		Path: path,
+		Address: lang.Address{
+			lang.RootStep{Name: "output"},
+			lang.AttrStep{Name: "baz"},
+		},
+		BlockType: "output",
+		Range: hcl.Range{
+			Filename: "test.tf",
+			Start:    hcl.Pos{Line: 8, Column: 1, Byte: 56},
+			End:      hcl.Pos{Line: 10, Column: 2, Byte: 101},
+		},
+		DefRange: hcl.Range{
+			Filename: "test.tf",
+			Start:    hcl.Pos{Line: 8, Column: 1, Byte: 56},
+			End:      hcl.Pos{Line: 8, Column: 13, Byte: 68},
+		},
+	}
+
+	items, err := d.CallHierarchyItemsAtPos(path, "test.tf", hcl.Pos{Line: 5, Column: 5, Byte: 39})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff([]CallHierarchyItem{barItem}, items); diff != "" {
+		t.Fatalf("mismatch of items: %s", diff)
+	}
+
+	ctx := context.Background()
+
+	incomingCalls, err := d.CallHierarchyIncomingCalls(ctx, varItem)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectedIncomingCalls := []CallHierarchyIncomingCall{
+		{
+			From: barItem,
+			FromRanges: []hcl.Range{
+				{
+					Filename: "test.tf",
+					Start:    hcl.Pos{Line: 5, Column: 11, Byte: 45},
+					End:      hcl.Pos{Line: 5, Column: 18, Byte: 52},
+				},
+			},
+		},
+		{
+			From: bazItem,
+			FromRanges: []hcl.Range{
+				{
+					Filename: "test.tf",
+					Start:    hcl.Pos{Line: 9, Column: 12, Byte: 82},
+					End:      hcl.Pos{Line: 9, Column: 19, Byte: 89},
+				},
+				{
+					Filename: "test.tf",
+					Start:    hcl.Pos{Line: 9, Column: 21, Byte: 91},
+					End:      hcl.Pos{Line: 9, Column: 28, Byte: 98},
+				},
+			},
+		},
+	}
+	if diff := cmp.Diff(expectedIncomingCalls, incomingCalls); diff != "" {
+		t.Fatalf("mismatch of incoming calls: %s", diff)
+	}
+
+	outgoingCalls, err := d.CallHierarchyOutgoingCalls(ctx, barItem)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectedOutgoingCalls := []CallHierarchyOutgoingCall{
+		{
+			To: varItem,
+			FromRanges: []hcl.Range{
+				{
+					Filename: "test.tf",
+					Start:    hcl.Pos{Line: 5, Column: 11, Byte: 45},
+					End:      hcl.Pos{Line: 5, Column: 18, Byte: 52},
+				},
+			},
+		},
+	}
+	if diff := cmp.Diff(expectedOutgoingCalls, outgoingCalls); diff != "" {
+		t.Fatalf("mismatch of outgoing calls: %s", diff)
+	}
+}
+
+func TestCallHierarchy_pathOrigin(t *testing.T) {
+	rootPath := lang.Path{Path: t.TempDir()}
+	childPath := lang.Path{Path: t.TempDir()}
+
+	rootSchema := &schema.BodySchema{
+		Blocks: map[string]*schema.BlockSchema{
+			"module": {
+				Labels: []*schema.LabelSchema{
+					{Name: "name"},
+				},
+				Address: &schema.BlockAddrSchema{
+					Steps: []schema.AddrStep{
+						schema.StaticStep{Name: "module"},
+						schema.LabelStep{Index: 0},
+					},
+				},
+				Body: &schema.BodySchema{
+					AnyAttribute: &schema.AttributeSchema{
+						Constraint: schema.AnyExpression{OfType: cty.DynamicPseudoType},
+					},
+				},
+			},
+		},
+	}
+	childSchema := &schema.BodySchema{
+		Blocks: map[string]*schema.BlockSchema{
+			"variable": {
+				Labels: []*schema.LabelSchema{
+					{Name: "name"},
+				},
+				Address: &schema.BlockAddrSchema{
+					Steps: []schema.AddrStep{
+						schema.StaticStep{Name: "var"},
+						schema.LabelStep{Index: 0},
+					},
+					AsTypeOf: &schema.BlockAsTypeOf{},
+				},
+				Body: &schema.BodySchema{},
+			},
+		},
+	}
+
+	rootCfg := `module "child" {
+  size = 5
+}
+`
+	childCfg := `variable "size" {
+}
+`
+	rootFile, _ := hclsyntax.ParseConfig([]byte(rootCfg), "main.tf", hcl.InitialPos)
+	childFile, _ := hclsyntax.ParseConfig([]byte(childCfg), "variables.tf", hcl.InitialPos)
+
+	rootCtx := &PathContext{
+		Schema: rootSchema,
+		Files: map[string]*hcl.File{
+			"main.tf": rootFile,
+		},
+	}
+	childCtx := &PathContext{
+		Schema: childSchema,
+		Files: map[string]*hcl.File{
+			"variables.tf": childFile,
+		},
+	}
+	d := NewDecoder(&testPathReader{
+		paths: map[string]*PathContext{
+			rootPath.Path:  rootCtx,
+			childPath.Path: childCtx,
+		},
+	})
+
+	for path, pathCtx := range map[lang.Path]*PathContext{rootPath: rootCtx, childPath: childCtx} {
+		pd, err := d.Path(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		pathCtx.ReferenceTargets, err = pd.CollectReferenceTargets()
+		if err != nil {
+			t.Fatal(err)
+		}
+		pathCtx.ReferenceOrigins, err = pd.CollectReferenceOrigins()
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// module inputs target variables of the module path
+	originRange := hcl.Range{
+		Filename: "main.tf",
+		Start:    hcl.Pos{Line: 2, Column: 3, Byte: 19},
+		End:      hcl.Pos{Line: 2, Column: 7, Byte: 23},
+	}
+	rootCtx.ReferenceOrigins = append(rootCtx.ReferenceOrigins, reference.PathOrigin{
+		Range: originRange,
+		TargetAddr: lang.Address{
+			lang.RootStep{Name: "var"},
+			lang.AttrStep{Name: "size"},
+		},
+		TargetPath: childPath,
+		Constraints: reference.OriginConstraints{
+			{OfType: cty.DynamicPseudoType},
+		},
+	})
+
+	moduleItem := CallHierarchyItem{
+		Path: rootPath,
+		Address: lang.Address{
+			lang.RootStep{Name: "module"},
+			lang.AttrStep{Name: "child"},
+		},
+		BlockType: "module",
+		Range: hcl.Range{
+			Filename: "main.tf",
+			Start:    hcl.Pos{Line: 1, Column: 1, Byte: 0},
+			End:      hcl.Pos{Line: 3, Column: 2, Byte: 29},
+		},
+		DefRange: hcl.Range{
+			Filename: "main.tf",
+			Start:    hcl.Pos{Line: 1, Column: 1, Byte: 0},
+			End:      hcl.Pos{Line: 1, Column: 15, Byte: 14},
+		},
+	}
+	varItem := CallHierarchyItem{
+		Path: childPath,
+		Address: lang.Address{
+			lang.RootStep{Name: "var"},
+			lang.AttrStep{Name: "size"},
+		},
+		BlockType: "variable",
+		Range: hcl.Range{
+			Filename: "variables.tf",
+			Start:    hcl.Pos{Line: 1, Column: 1, Byte: 0},
+			End:      hcl.Pos{Line: 2, Column: 2, Byte: 19},
+		},
+		DefRange: hcl.Range{
+			Filename: "variables.tf",
+			Start:    hcl.Pos{Line: 1, Column: 1, Byte: 0},
+			End:      hcl.Pos{Line: 1, Column: 16, Byte: 15},
+		},
+	}
+
+	testCases := []struct {
+		name                  string
+		item                  CallHierarchyItem
+		expectedIncomingCalls []CallHierarchyIncomingCall
+		expectedOutgoingCalls []CallHierarchyOutgoingCall
+	}{
+		{
+			"module calling variable in another path",
+			moduleItem,
+			[]CallHierarchyIncomingCall{},
+			[]CallHierarchyOutgoingCall{
+				{
+					To:         varItem,
+					FromRanges: []hcl.Range{originRange},
+				},
+			},
+		},
+		{
+			"variable called from another path",
+			varItem,
+			[]CallHierarchyIncomingCall{
+				{
+					From:       moduleItem,
+					FromRanges: []hcl.Range{originRange},
+				},
+			},
+			[]CallHierarchyOutgoingCall{},
+		},
+	}
+
+	ctx := context.Background()
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("%2d-%s", i, tc.name), func(t *testing.T) {
+			incomingCalls, err := d.CallHierarchyIncomingCalls(ctx, tc.item)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(tc.expectedIncomingCalls, incomingCalls); diff != "" {
+				t.Fatalf("mismatch of incoming calls: %s", diff)
+			}
+
+			outgoingCalls, err := d.CallHierarchyOutgoingCalls(ctx, tc.item)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(tc.expectedOutgoingCalls, outgoingCalls); diff != "" {
+				t.Fatalf("mismatch of outgoing calls: %s", diff)
+			}
+		})
+	}
+}
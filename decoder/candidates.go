@@ -10,8 +10,10 @@ import (
 	"github.com/hashicorp/hcl-lang/decoder/internal/schemahelper"
 	"github.com/hashicorp/hcl-lang/lang"
 	"github.com/hashicorp/hcl-lang/schema"
+	"github.com/hashicorp/hcl-lang/schemacontext"
 	"github.com/hashicorp/hcl/v2"
 	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/hcl/v2/json"
 )
 
 // CompletionAtPos returns completion candidates for a given position in a file
@@ -43,6 +45,12 @@ func (d *PathDecoder) CompletionAtPos(ctx context.Context, filename string, pos
 	}
 
 	ctx = schema.WithPrefillRequiredFields(ctx, d.PrefillRequiredFields)
+	ctx = withPrefillDefaultValues(ctx, d.PrefillDefaultValues)
+	ctx = schemacontext.WithLookupEnv(ctx, d.decoderCtx.LookupEnv)
+
+	if json.IsJSONBody(f.Body) {
+		return d.jsonCompletionAtPos(ctx, rootBody, outerBodyRng, d.pathCtx.Schema, pos)
+	}
 
 	return d.completionAtPos(ctx, rootBody, outerBodyRng, d.pathCtx.Schema, pos)
 }
@@ -130,7 +138,7 @@ func (d *PathDecoder) completionAtPos(ctx context.Context, body *hclsyntax.Body,
 						return lang.ZeroCandidates(), nil
 					}
 
-					return d.labelCandidatesFromDependentSchema(i, blockSchema.DependentBody, prefixRng, rng, block, blockSchema.Labels)
+					return d.labelCandidatesFromDependentSchema(i, blockSchema, prefixRng, rng, block)
 				}
 			}
 
@@ -143,7 +151,7 @@ func (d *PathDecoder) completionAtPos(ctx context.Context, body *hclsyntax.Body,
 			}
 
 			if block.Body != nil && block.Body.Range().ContainsPos(pos) {
-				mergedSchema, _ := schemahelper.MergeBlockBodySchemas(block.AsHCLBlock(), blockSchema)
+				mergedSchema, _ := d.mergeBlockBodySchemas(block.AsHCLBlock(), blockSchema)
 				return d.completionAtPos(ctx, block.Body, outerBodyRng, mergedSchema, pos)
 			}
 		}
@@ -12,6 +12,7 @@ import (
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/hashicorp/hcl-lang/lang"
+	"github.com/hashicorp/hcl-lang/reference"
 	"github.com/hashicorp/hcl-lang/schema"
 	"github.com/hashicorp/hcl/v2"
 	"github.com/hashicorp/hcl/v2/hclsyntax"
@@ -62,24 +63,369 @@ func TestDecoder_CompletionAtPos_emptyBody(t *testing.T) {
 func TestDecoder_CompletionAtPos_json(t *testing.T) {
 	ctx := context.Background()
 	f, pDiags := json.Parse([]byte(`{
-	"customblock": {
-		"label1": {}
+  "customblock": {
+    "label1": {
+      "num": 42
+    }
+  }
+}`), "test.tf.json")
+	if len(pDiags) > 0 {
+		t.Fatal(pDiags)
 	}
+
+	d := testPathDecoder(t, &PathContext{
+		Schema: &schema.BodySchema{
+			Attributes: map[string]*schema.AttributeSchema{
+				"attr": {Constraint: schema.LiteralType{Type: cty.String}, IsOptional: true},
+			},
+			Blocks: map[string]*schema.BlockSchema{
+				"customblock": {
+					Labels: []*schema.LabelSchema{{Name: "name"}},
+					Body: &schema.BodySchema{
+						Attributes: map[string]*schema.AttributeSchema{
+							"num":  {Constraint: schema.LiteralType{Type: cty.Number}, IsOptional: true},
+							"list": {Constraint: schema.List{Elem: schema.LiteralType{Type: cty.String}}, IsOptional: true},
+						},
+					},
+				},
+			},
+		},
+		Files: map[string]*hcl.File{
+			"test.tf.json": f,
+		},
+	})
+
+	testCases := []struct {
+		name               string
+		pos                hcl.Pos
+		expectedCandidates lang.Candidates
+	}{
+		{
+			"root body",
+			hcl.Pos{Line: 1, Column: 2, Byte: 1},
+			lang.CompleteCandidates([]lang.Candidate{
+				{
+					Label:  "attr",
+					Detail: "optional, string",
+					Kind:   lang.AttributeCandidateKind,
+					TextEdit: lang.TextEdit{
+						Range: hcl.Range{
+							Filename: "test.tf.json",
+							Start:    hcl.Pos{Line: 1, Column: 2, Byte: 1},
+							End:      hcl.Pos{Line: 1, Column: 2, Byte: 1},
+						},
+						NewText: `"attr",`,
+						Snippet: `"attr": "${1}",`,
+					},
+				},
+				{
+					Label:  "customblock",
+					Detail: "Block",
+					Kind:   lang.BlockCandidateKind,
+					TextEdit: lang.TextEdit{
+						Range: hcl.Range{
+							Filename: "test.tf.json",
+							Start:    hcl.Pos{Line: 1, Column: 2, Byte: 1},
+							End:      hcl.Pos{Line: 1, Column: 2, Byte: 1},
+						},
+						NewText: `"customblock",`,
+						Snippet: "\"customblock\": {\n  \"${1:name}\": {\n    ${2}\n  }\n},",
+					},
+				},
+			}),
+		},
+		{
+			"nested body",
+			hcl.Pos{Line: 3, Column: 16, Byte: 35},
+			lang.CompleteCandidates([]lang.Candidate{
+				{
+					Label:  "list",
+					Detail: "optional, list of string",
+					Kind:   lang.AttributeCandidateKind,
+					TextEdit: lang.TextEdit{
+						Range: hcl.Range{
+							Filename: "test.tf.json",
+							Start:    hcl.Pos{Line: 3, Column: 16, Byte: 35},
+							End:      hcl.Pos{Line: 3, Column: 16, Byte: 35},
+						},
+						NewText: `"list",`,
+						Snippet: `"list": [ ${1} ],`,
+					},
+				},
+			}),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			candidates, err := d.CompletionAtPos(ctx, "test.tf.json", tc.pos)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(tc.expectedCandidates, candidates); diff != "" {
+				t.Fatalf("unexpected candidates: %s", diff)
+			}
+		})
+	}
+}
+
+func TestDecoder_CompletionAtPos_jsonValues(t *testing.T) {
+	ctx := context.Background()
+	f, pDiags := json.Parse([]byte(`{
+  "obj": { "b": 1 },
+  "list": [ "foo" ],
+  "str": "b",
+  "kw": "",
+  "refs": [ ],
+  "ref": ""
 }`), "test.tf.json")
 	if len(pDiags) > 0 {
 		t.Fatal(pDiags)
 	}
 
 	d := testPathDecoder(t, &PathContext{
+		Schema: &schema.BodySchema{
+			Attributes: map[string]*schema.AttributeSchema{
+				"obj": {
+					Constraint: schema.Object{
+						Attributes: schema.ObjectAttributes{
+							"a": {Constraint: schema.LiteralType{Type: cty.String}, IsOptional: true},
+							"b": {Constraint: schema.LiteralType{Type: cty.Number}, IsOptional: true},
+						},
+					},
+					IsOptional: true,
+				},
+				"list": {
+					Constraint: schema.List{
+						Elem: schema.OneOf{
+							schema.LiteralValue{Value: cty.StringVal("foo")},
+							schema.LiteralValue{Value: cty.StringVal("bar")},
+						},
+					},
+					IsOptional: true,
+				},
+				"str": {
+					Constraint: schema.OneOf{
+						schema.LiteralValue{Value: cty.StringVal("foo")},
+						schema.LiteralValue{Value: cty.StringVal("bar")},
+					},
+					IsOptional: true,
+				},
+				"kw": {
+					Constraint: schema.Keyword{Keyword: "foo"},
+					IsOptional: true,
+				},
+				"refs": {
+					Constraint: schema.List{
+						Elem: schema.Reference{OfType: cty.String},
+					},
+					IsOptional: true,
+				},
+				"ref": {
+					Constraint: schema.Reference{OfType: cty.String},
+					IsOptional: true,
+				},
+			},
+		},
 		Files: map[string]*hcl.File{
 			"test.tf.json": f,
 		},
+		ReferenceTargets: reference.Targets{
+			{
+				Addr: lang.Address{
+					lang.RootStep{Name: "var"},
+					lang.AttrStep{Name: "foo"},
+				},
+				Type: cty.String,
+			},
+		},
 	})
 
-	_, err := d.CompletionAtPos(ctx, "test.tf.json", hcl.InitialPos)
-	unknownFormatErr := &UnknownFileFormatError{}
-	if !errors.As(err, &unknownFormatErr) {
-		t.Fatal("expected UnknownFileFormatError for JSON body")
+	testCases := []struct {
+		name               string
+		pos                hcl.Pos
+		expectedCandidates lang.Candidates
+	}{
+		{
+			"object attribute",
+			hcl.Pos{Line: 2, Column: 11, Byte: 12},
+			lang.CompleteCandidates([]lang.Candidate{
+				{
+					Label:  "a",
+					Detail: "optional, string",
+					Kind:   lang.AttributeCandidateKind,
+					TextEdit: lang.TextEdit{
+						Range: hcl.Range{
+							Filename: "test.tf.json",
+							Start:    hcl.Pos{Line: 2, Column: 11, Byte: 12},
+							End:      hcl.Pos{Line: 2, Column: 11, Byte: 12},
+						},
+						NewText: `"a",`,
+						Snippet: `"a": "${1}",`,
+					},
+				},
+			}),
+		},
+		{
+			"object key",
+			hcl.Pos{Line: 2, Column: 13, Byte: 14},
+			lang.CompleteCandidates([]lang.Candidate{
+				{
+					Label:  "a",
+					Detail: "optional, string",
+					Kind:   lang.AttributeCandidateKind,
+					TextEdit: lang.TextEdit{
+						Range: hcl.Range{
+							Filename: "test.tf.json",
+							Start:    hcl.Pos{Line: 2, Column: 12, Byte: 13},
+							End:      hcl.Pos{Line: 2, Column: 15, Byte: 16},
+						},
+						NewText: `"a"`,
+						Snippet: `"a"`,
+					},
+				},
+				{
+					Label:  "b",
+					Detail: "optional, number",
+					Kind:   lang.AttributeCandidateKind,
+					TextEdit: lang.TextEdit{
+						Range: hcl.Range{
+							Filename: "test.tf.json",
+							Start:    hcl.Pos{Line: 2, Column: 12, Byte: 13},
+							End:      hcl.Pos{Line: 2, Column: 15, Byte: 16},
+						},
+						NewText: `"b"`,
+						Snippet: `"b"`,
+					},
+				},
+			}),
+		},
+		{
+			"list element",
+			hcl.Pos{Line: 3, Column: 19, Byte: 41},
+			lang.CompleteCandidates([]lang.Candidate{
+				{
+					Label:  "foo",
+					Detail: "string",
+					Kind:   lang.StringCandidateKind,
+					TextEdit: lang.TextEdit{
+						Range: hcl.Range{
+							Filename: "test.tf.json",
+							Start:    hcl.Pos{Line: 3, Column: 19, Byte: 41},
+							End:      hcl.Pos{Line: 3, Column: 19, Byte: 41},
+						},
+						NewText: `,"foo"`,
+						Snippet: `,"foo"`,
+					},
+				},
+				{
+					Label:  "bar",
+					Detail: "string",
+					Kind:   lang.StringCandidateKind,
+					TextEdit: lang.TextEdit{
+						Range: hcl.Range{
+							Filename: "test.tf.json",
+							Start:    hcl.Pos{Line: 3, Column: 19, Byte: 41},
+							End:      hcl.Pos{Line: 3, Column: 19, Byte: 41},
+						},
+						NewText: `,"bar"`,
+						Snippet: `,"bar"`,
+					},
+				},
+			}),
+		},
+		{
+			"string literal value",
+			hcl.Pos{Line: 4, Column: 12, Byte: 55},
+			lang.CompleteCandidates([]lang.Candidate{
+				{
+					Label:  "bar",
+					Detail: "string",
+					Kind:   lang.StringCandidateKind,
+					TextEdit: lang.TextEdit{
+						Range: hcl.Range{
+							Filename: "test.tf.json",
+							Start:    hcl.Pos{Line: 4, Column: 11, Byte: 54},
+							End:      hcl.Pos{Line: 4, Column: 12, Byte: 55},
+						},
+						NewText: `bar`,
+						Snippet: `bar`,
+					},
+				},
+			}),
+		},
+		{
+			"string keyword",
+			hcl.Pos{Line: 5, Column: 10, Byte: 67},
+			lang.CompleteCandidates([]lang.Candidate{
+				{
+					Label:  "foo",
+					Detail: "keyword",
+					Kind:   lang.KeywordCandidateKind,
+					TextEdit: lang.TextEdit{
+						Range: hcl.Range{
+							Filename: "test.tf.json",
+							Start:    hcl.Pos{Line: 5, Column: 10, Byte: 67},
+							End:      hcl.Pos{Line: 5, Column: 10, Byte: 67},
+						},
+						NewText: `foo`,
+						Snippet: `foo`,
+					},
+				},
+			}),
+		},
+		{
+			"reference in list",
+			hcl.Pos{Line: 6, Column: 13, Byte: 82},
+			lang.CompleteCandidates([]lang.Candidate{
+				{
+					Label:  "var.foo",
+					Detail: "string",
+					Kind:   lang.ReferenceCandidateKind,
+					TextEdit: lang.TextEdit{
+						Range: hcl.Range{
+							Filename: "test.tf.json",
+							Start:    hcl.Pos{Line: 6, Column: 13, Byte: 82},
+							End:      hcl.Pos{Line: 6, Column: 13, Byte: 82},
+						},
+						NewText: `"${var.foo}"`,
+						Snippet: `"\${var.foo}"`,
+					},
+				},
+			}),
+		},
+		{
+			"reference in string",
+			hcl.Pos{Line: 7, Column: 11, Byte: 95},
+			lang.CompleteCandidates([]lang.Candidate{
+				{
+					Label:  "var.foo",
+					Detail: "string",
+					Kind:   lang.ReferenceCandidateKind,
+					TextEdit: lang.TextEdit{
+						Range: hcl.Range{
+							Filename: "test.tf.json",
+							Start:    hcl.Pos{Line: 7, Column: 11, Byte: 95},
+							End:      hcl.Pos{Line: 7, Column: 11, Byte: 95},
+						},
+						NewText: `${var.foo}`,
+						Snippet: `\${var.foo}`,
+					},
+				},
+			}),
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("%2d-%s", i, tc.name), func(t *testing.T) {
+			candidates, err := d.CompletionAtPos(ctx, "test.tf.json", tc.pos)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(tc.expectedCandidates, candidates); diff != "" {
+				t.Fatalf("unexpected candidates: %s", diff)
+			}
+		})
 	}
 }
 
@@ -38,3 +38,25 @@ func (d *Decoder) CodeLensesForFile(ctx context.Context, path lang.Path, file st
 
 	return lenses, result.ErrorOrNil()
 }
+
+// ResolveCodeLens populates the command of a lazily resolved
+// code lens by executing its resolve hook.
+// This would be called as part of `codeLens/resolve` LSP method.
+func (d *Decoder) ResolveCodeLens(ctx context.Context, lens lang.CodeLens) (lang.CodeLens, error) {
+	if lens.ResolveHook == nil {
+		return lens, nil
+	}
+
+	resolveFunc, ok := d.ctx.CodeLensResolveHooks[lens.ResolveHook.Name]
+	if !ok {
+		return lens, nil
+	}
+
+	pathCtx, err := d.pathReader.PathContext(lens.ResolveHook.Path)
+	if err == nil {
+		ctx = withPathContext(ctx, pathCtx)
+	}
+	ctx = withPathReader(ctx, d.pathReader)
+
+	return resolveFunc(ctx, lens)
+}
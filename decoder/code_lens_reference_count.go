@@ -0,0 +1,210 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package decoder
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/hashicorp/hcl-lang/lang"
+	"github.com/hashicorp/hcl-lang/reference"
+	"github.com/hashicorp/hcl/v2"
+)
+
+// ReferenceCountResolveHookName is the name of the resolve hook
+// which needs to be registered in DecoderContext.CodeLensResolveHooks
+// when lazy reference count code lenses are used
+const ReferenceCountResolveHookName = "hcl.referenceCount"
+
+// ReferenceCountCodeLensOptions represents options
+// of the built-in reference count code lens
+type ReferenceCountCodeLensOptions struct {
+	// CommandID represents the ID of the command to attach to each lens
+	// (e.g. editor.action.showReferences), which receives
+	// the origin locations as arguments.
+	CommandID string
+
+	// Lazy defers counting of references until the lens
+	// is resolved via ResolveCodeLens.
+	Lazy bool
+}
+
+// OriginLocation represents location of a reference origin
+// which is passed as an argument of the reference count command
+type OriginLocation struct {
+	Path  lang.Path
+	Range hcl.Range
+}
+
+func (ol OriginLocation) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Path       string  `json:"path"`
+		LanguageID string  `json:"language_id,omitempty"`
+		Filename   string  `json:"filename"`
+		Start      posJSON `json:"start"`
+		End        posJSON `json:"end"`
+	}{
+		Path:       ol.Path.Path,
+		LanguageID: ol.Path.LanguageID,
+		Filename:   ol.Range.Filename,
+		Start:      posJSON{Line: ol.Range.Start.Line, Column: ol.Range.Start.Column, Byte: ol.Range.Start.Byte},
+		End:        posJSON{Line: ol.Range.End.Line, Column: ol.Range.End.Column, Byte: ol.Range.End.Byte},
+	})
+}
+
+type posJSON struct {
+	Line   int `json:"line"`
+	Column int `json:"column"`
+	Byte   int `json:"byte"`
+}
+
+// ReferenceCountCodeLens returns a code lens func which reports
+// the number of origins across all paths, which target each addressable
+// target declared in the file. The lens is placed at the declaration
+// (DefRangePtr) of the target, e.g. block header.
+//
+// The func is intended to be added to DecoderContext.CodeLenses.
+// Lazy lenses also require ReferenceCountResolveFunc to be registered
+// in DecoderContext.CodeLensResolveHooks under ReferenceCountResolveHookName.
+func ReferenceCountCodeLens(opts ReferenceCountCodeLensOptions) lang.CodeLensFunc {
+	return func(ctx context.Context, path lang.Path, file string) ([]lang.CodeLens, error) {
+		lenses := make([]lang.CodeLens, 0)
+
+		pathCtx, err := PathCtx(ctx)
+		if err != nil {
+			return lenses, err
+		}
+		pathReader, err := PathReaderFromContext(ctx)
+		if err != nil {
+			return lenses, err
+		}
+
+		for _, defRange := range targetDefRangesInFile(pathCtx.ReferenceTargets, file) {
+			if opts.Lazy {
+				lenses = append(lenses, lang.CodeLens{
+					Range: defRange,
+					ResolveHook: &lang.CodeLensResolveHook{
+						Name: ReferenceCountResolveHookName,
+						Path: path,
+					},
+				})
+				continue
+			}
+
+			origins := originsTargetingDefRange(ctx, pathReader, pathCtx, path, defRange)
+			lenses = append(lenses, lang.CodeLens{
+				Range:   defRange,
+				Command: referenceCountCommand(opts.CommandID, origins),
+			})
+		}
+
+		return lenses, nil
+	}
+}
+
+// ReferenceCountResolveFunc returns a resolve func which populates
+// the command of a lazy lens created by ReferenceCountCodeLens.
+func ReferenceCountResolveFunc(opts ReferenceCountCodeLensOptions) CodeLensResolveFunc {
+	return func(ctx context.Context, lens lang.CodeLens) (lang.CodeLens, error) {
+		if lens.ResolveHook == nil {
+			return lens, nil
+		}
+
+		pathCtx, err := PathCtx(ctx)
+		if err != nil {
+			return lens, err
+		}
+		pathReader, err := PathReaderFromContext(ctx)
+		if err != nil {
+			return lens, err
+		}
+
+		origins := originsTargetingDefRange(ctx, pathReader, pathCtx, lens.ResolveHook.Path, lens.Range)
+
+		return lang.CodeLens{
+			Range:   lens.Range,
+			Command: referenceCountCommand(opts.CommandID, origins),
+		}, nil
+	}
+}
+
+// targetDefRangesInFile returns unique declaration ranges
+// of addressable targets in the given file
+func targetDefRangesInFile(targets reference.Targets, file string) []hcl.Range {
+	ranges := make([]hcl.Range, 0)
+
+	for _, target := range targets.OutermostInFile(file) {
+		if target.DefRangePtr == nil || len(target.Addr) == 0 {
+			continue
+		}
+		ranges = appendUniqueRange(ranges, *target.DefRangePtr)
+	}
+	sortRanges(ranges)
+
+	return ranges
+}
+
+// originsTargetingDefRange returns locations of origins across all paths
+// which target any of the targets declared at the given range
+func originsTargetingDefRange(ctx context.Context, pathReader PathReader, pathCtx *PathContext, path lang.Path, defRange hcl.Range) []OriginLocation {
+	locations := make([]OriginLocation, 0)
+	seen := make(map[string]bool, 0)
+
+	for _, target := range pathCtx.ReferenceTargets.OutermostInFile(defRange.Filename) {
+		if target.DefRangePtr == nil || *target.DefRangePtr != defRange {
+			continue
+		}
+
+		for _, p := range pathReader.Paths(ctx) {
+			originCtx, err := pathReader.PathContext(p)
+			if err != nil {
+				continue
+			}
+
+			for _, origin := range originCtx.ReferenceOrigins.Match(p, target, path) {
+				key := p.Path + ":" + origin.OriginRange().String()
+				if seen[key] {
+					continue
+				}
+				seen[key] = true
+				locations = append(locations, OriginLocation{
+					Path:  p,
+					Range: origin.OriginRange(),
+				})
+			}
+		}
+	}
+
+	sort.SliceStable(locations, func(i, j int) bool {
+		if locations[i].Path.Path != locations[j].Path.Path {
+			return locations[i].Path.Path < locations[j].Path.Path
+		}
+		if locations[i].Range.Filename != locations[j].Range.Filename {
+			return locations[i].Range.Filename < locations[j].Range.Filename
+		}
+		return locations[i].Range.Start.Byte < locations[j].Range.Start.Byte
+	})
+
+	return locations
+}
+
+func referenceCountCommand(commandID string, origins []OriginLocation) lang.Command {
+	title := fmt.Sprintf("%d references", len(origins))
+	if len(origins) == 1 {
+		title = "1 reference"
+	}
+
+	args := make([]lang.CommandArgument, len(origins))
+	for i, origin := range origins {
+		args[i] = origin
+	}
+
+	return lang.Command{
+		Title:     title,
+		ID:        commandID,
+		Arguments: args,
+	}
+}
@@ -0,0 +1,206 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package decoder
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/hcl-lang/lang"
+	"github.com/hashicorp/hcl-lang/schema"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/zclconf/go-cty/cty"
+)
+
+func TestReferenceCountCodeLens(t *testing.T) {
+	bodySchema := &schema.BodySchema{
+		Blocks: map[string]*schema.BlockSchema{
+			"variable": {
+				Labels: []*schema.LabelSchema{
+					{Name: "name"},
+				},
+				Address: &schema.BlockAddrSchema{
+					Steps: []schema.AddrStep{
+						schema.StaticStep{Name: "var"},
+						schema.LabelStep{Index: 0},
+					},
+					AsTypeOf: &schema.BlockAsTypeOf{},
+				},
+				Body: &schema.BodySchema{},
+			},
+			"output": {
+				Labels: []*schema.LabelSchema{
+					{Name: "name"},
+				},
+				Body: &schema.BodySchema{
+					Attributes: map[string]*schema.AttributeSchema{
+						"value": {
+							IsOptional: true,
+							Constraint: schema.AnyExpression{OfType: cty.DynamicPseudoType},
+						},
+					},
+				},
+			},
+		},
+	}
+	cfg := `variable "foo" {
+}
+
+variable "unused" {
+}
+
+output "bar" {
+  value = [var.foo, var.foo]
+}
+`
+	f, _ := hclsyntax.ParseConfig([]byte(cfg), "test.tf", hcl.InitialPos)
+	dirPath := t.TempDir()
+	path := lang.Path{Path: dirPath}
+	pathCtx := &PathContext{
+		Schema: bodySchema,
+		Files: map[string]*hcl.File{
+			"test.tf": f,
+		},
+	}
+	d := NewDecoder(&testPathReader{
+		paths: map[string]*PathContext{
+			dirPath: pathCtx,
+		},
+	})
+
+	pd, err := d.Path(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pathCtx.ReferenceTargets, err = pd.CollectReferenceTargets()
+	if err != nil {
+		t.Fatal(err)
+	}
+	pathCtx.ReferenceOrigins, err = pd.CollectReferenceOrigins()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	opts := ReferenceCountCodeLensOptions{
+		CommandID: "editor.action.showReferences",
+	}
+	expectedLenses := []lang.CodeLens{
+		{
+			Range: hcl.Range{
+				Filename: "test.tf",
+				Start:    hcl.Pos{Line: 1, Column: 1, Byte: 0},
+				End:      hcl.Pos{Line: 1, Column: 15, Byte: 14},
+			},
+			Command: lang.Command{
+				Title: "2 references",
+				ID:    "editor.action.showReferences",
+				Arguments: []lang.CommandArgument{
+					OriginLocation{
+						Path: path,
+						Range: hcl.Range{
+							Filename: "test.tf",
+							Start:    hcl.Pos{Line: 8, Column: 12, Byte: 69},
+							End:      hcl.Pos{Line: 8, Column: 19, Byte: 76},
+						},
+					},
+					OriginLocation{
+						Path: path,
+						Range: hcl.Range{
+							Filename: "test.tf",
+							Start:    hcl.Pos{Line: 8, Column: 21, Byte: 78},
+							End:      hcl.Pos{Line: 8, Column: 28, Byte: 85},
+						},
+					},
+				},
+			},
+		},
+		{
+			Range: hcl.Range{
+				Filename: "test.tf",
+				Start:    hcl.Pos{Line: 4, Column: 1, Byte: 20},
+				End:      hcl.Pos{Line: 4, Column: 18, Byte: 37},
+			},
+			Command: lang.Command{
+				Title:     "0 references",
+				ID:        "editor.action.showReferences",
+				Arguments: []lang.CommandArgument{},
+			},
+		},
+	}
+
+	ctx := context.Background()
+
+	t.Run("eager", func(t *testing.T) {
+		decoderCtx := NewDecoderContext()
+		decoderCtx.CodeLenses = []lang.CodeLensFunc{ReferenceCountCodeLens(opts)}
+		d.SetContext(decoderCtx)
+
+		lenses, err := d.CodeLensesForFile(ctx, path, "test.tf")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff(expectedLenses, lenses); diff != "" {
+			t.Fatalf("unexpected lenses: %s", diff)
+		}
+	})
+
+	t.Run("lazy", func(t *testing.T) {
+		lazyOpts := opts
+		lazyOpts.Lazy = true
+
+		decoderCtx := NewDecoderContext()
+		decoderCtx.CodeLenses = []lang.CodeLensFunc{ReferenceCountCodeLens(lazyOpts)}
+		decoderCtx.CodeLensResolveHooks[ReferenceCountResolveHookName] = ReferenceCountResolveFunc(lazyOpts)
+		d.SetContext(decoderCtx)
+
+		lenses, err := d.CodeLensesForFile(ctx, path, "test.tf")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(lenses) != len(expectedLenses) {
+			t.Fatalf("expected %d lenses, given %d", len(expectedLenses), len(lenses))
+		}
+
+		for i, lens := range lenses {
+			expectedHook := &lang.CodeLensResolveHook{
+				Name: ReferenceCountResolveHookName,
+				Path: path,
+			}
+			if diff := cmp.Diff(expectedHook, lens.ResolveHook); diff != "" {
+				t.Fatalf("unexpected resolve hook: %s", diff)
+			}
+
+			resolvedLens, err := d.ResolveCodeLens(ctx, lens)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(expectedLenses[i], resolvedLens); diff != "" {
+				t.Fatalf("unexpected resolved lens: %s", diff)
+			}
+		}
+	})
+}
+
+func TestOriginLocation_MarshalJSON(t *testing.T) {
+	loc := OriginLocation{
+		Path: lang.Path{Path: "/test", LanguageID: "terraform"},
+		Range: hcl.Range{
+			Filename: "test.tf",
+			Start:    hcl.Pos{Line: 8, Column: 12, Byte: 72},
+			End:      hcl.Pos{Line: 8, Column: 19, Byte: 79},
+		},
+	}
+	b, err := json.Marshal(loc)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectedJSON := `{"path":"/test","language_id":"terraform","filename":"test.tf","start":{"line":8,"column":12,"byte":72},"end":{"line":8,"column":19,"byte":79}}`
+	if diff := cmp.Diff(expectedJSON, string(b)); diff != "" {
+		t.Fatalf("unexpected JSON: %s", diff)
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"fmt"
 
 	"github.com/hashicorp/hcl-lang/lang"
+	"github.com/hashicorp/hcl-lang/schema"
 	"github.com/zclconf/go-cty/cty"
 )
 
@@ -22,10 +23,36 @@ type DecoderContext struct {
 	// utm_content parameter, e.g. documentHover or documentLink
 	UseUtmContent bool
 
+	// EnabledSemanticTokenTypes represents token types
+	// from lang.OptionalSemanticTokenTypes which are reported
+	// in addition to lang.SupportedSemanticTokenTypes,
+	// typically those which the client declared support for.
+	EnabledSemanticTokenTypes lang.SemanticTokenTypes
+
+	// EnabledSemanticTokenModifiers represents modifiers
+	// from lang.OptionalSemanticTokenModifiers which are reported
+	// in addition to any modifiers declared in the schema.
+	EnabledSemanticTokenModifiers lang.SemanticTokenModifiers
+
+	// SemanticTokensLegend represents token types and modifiers
+	// used to encode results of PathDecoder.SemanticTokensFull
+	// and PathDecoder.SemanticTokensDelta. All reported types
+	// and modifiers are used if the legend is empty.
+	SemanticTokensLegend SemanticTokensLegend
+
+	// InlayHints represents categories of hints
+	// reported by PathDecoder.InlayHintsInRange
+	InlayHints InlayHintCategories
+
 	// CodeLenses represents a slice of executable lenses
 	// which will be executed in the exact order they're declared
 	CodeLenses []lang.CodeLensFunc
 
+	// CodeLensResolveHooks represents a map of available hooks for lazy
+	// resolution of code lenses. A lens with ResolveHook is resolved
+	// by the hook of the matching name via ResolveCodeLens.
+	CodeLensResolveHooks CodeLensResolveFuncMap
+
 	// CompletionHooks represents a map of available hooks for completion.
 	// One can register new hooks by adding an entry to this map. Inside the
 	// attribute schema, one can refer to the hooks map key to enable the hook
@@ -39,12 +66,39 @@ type DecoderContext struct {
 	// a resolve hook, ResolveCandidate will execute the hook and return
 	// additional (resolved) data for the completion item.
 	CompletionResolveHooks CompletionResolveFuncMap
+
+	// LookupEnv represents a function used to look up environment
+	// variables when resolving schema.DefaultEnvVar defaults,
+	// typically os.LookupEnv. Such defaults fall back if it's nil.
+	LookupEnv schema.LookupEnvFunc
+}
+
+// InlayHintCategories represents categories of inlay hints,
+// each of which can be enabled independently
+type InlayHintCategories struct {
+	// ParameterNames enables hints with names of parameters
+	// in front of function call arguments
+	ParameterNames bool
+
+	// ReferenceTypes enables hints with the type
+	// of the matched target after references
+	ReferenceTypes bool
+
+	// DefaultValues enables hints with the effective value
+	// of optional attributes omitted from a block
+	// which have DefaultValue declared in the schema
+	DefaultValues bool
 }
 
 func NewDecoderContext() DecoderContext {
 	return DecoderContext{
 		CompletionHooks:        make(CompletionFuncMap),
 		CompletionResolveHooks: make(CompletionResolveFuncMap),
+		CodeLensResolveHooks:   make(CodeLensResolveFuncMap),
+		InlayHints: InlayHintCategories{
+			ParameterNames: true,
+			ReferenceTypes: true,
+		},
 	}
 }
 
@@ -68,6 +122,14 @@ type CompletionFuncMap map[string]CompletionFunc
 type CompletionResolveFunc func(ctx context.Context, unresolvedCandidate UnresolvedCandidate) (*ResolvedCandidate, error)
 type CompletionResolveFuncMap map[string]CompletionResolveFunc
 
+// CodeLensResolveFunc is the function signature for code lens resolve hooks.
+//
+// The resolve func has access to path reader via context:
+//
+//	pathReader, err := decoder.PathReaderFromContext(ctx)
+type CodeLensResolveFunc func(ctx context.Context, lens lang.CodeLens) (lang.CodeLens, error)
+type CodeLensResolveFuncMap map[string]CodeLensResolveFunc
+
 // Candidate represents a completion candidate created and returned from a
 // completion hook.
 type Candidate struct {
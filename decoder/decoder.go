@@ -12,6 +12,8 @@ import (
 type Decoder struct {
 	ctx        DecoderContext
 	pathReader PathReader
+
+	semanticTokens *semanticTokensCache
 }
 
 // NewDecoder creates a new Decoder
@@ -20,7 +22,8 @@ type Decoder struct {
 // via LoadFile and (optionally) schema is set via SetSchema.
 func NewDecoder(pathReader PathReader) *Decoder {
 	return &Decoder{
-		pathReader: pathReader,
+		pathReader:     pathReader,
+		semanticTokens: newSemanticTokensCache(),
 	}
 }
 
@@ -0,0 +1,140 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package decoder
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/hcl-lang/schema"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/ext/typeexpr"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/zclconf/go-cty/cty"
+)
+
+type prefillDefaultValuesCtxKey struct{}
+
+func withPrefillDefaultValues(ctx context.Context, enabled bool) context.Context {
+	return context.WithValue(ctx, prefillDefaultValuesCtxKey{}, enabled)
+}
+
+func prefillDefaultValues(ctx context.Context) bool {
+	enabled, ok := ctx.Value(prefillDefaultValuesCtxKey{}).(bool)
+	if !ok {
+		return false
+	}
+	return enabled
+}
+
+// defaultContextForBody returns context for resolving defaults
+// of attributes omitted from the given body
+func (d *PathDecoder) defaultContextForBody(body *hclsyntax.Body) schema.DefaultContext {
+	attrs := make(hcl.Attributes, 0)
+	if body != nil {
+		for name, attr := range body.Attributes {
+			attrs[name] = attr.AsHCLAttribute()
+		}
+	}
+	return d.defaultContextForAttributes(attrs)
+}
+
+// defaultContextForAttributes returns context for resolving defaults
+// of attributes omitted from a body with the given attributes
+func (d *PathDecoder) defaultContextForAttributes(attrs hcl.Attributes) schema.DefaultContext {
+	return schema.DefaultContext{
+		AttributeValue: func(name string) (cty.Value, bool) {
+			attr, ok := attrs[name]
+			if !ok {
+				return cty.NilVal, false
+			}
+			value, diags := attr.Expr.Value(nil)
+			if diags.HasErrors() {
+				return cty.NilVal, false
+			}
+			return value, true
+		},
+		LookupEnv: d.decoderCtx.LookupEnv,
+	}
+}
+
+// defaultDescription returns Markdown describing the default,
+// such as `8080`, or false if there is no default to describe
+func defaultDescription(def schema.Default) (string, bool) {
+	switch d := def.(type) {
+	case schema.DefaultValue:
+		if !d.Value.IsWhollyKnown() {
+			return "", false
+		}
+		return fmt.Sprintf("`%s`", valueString(d.Value)), true
+	case schema.DefaultKeyword:
+		return fmt.Sprintf("`%s`", d.Keyword), true
+	case schema.DefaultTypeDeclaration:
+		return fmt.Sprintf("`%s`", typeexpr.TypeString(d.Type)), true
+	case schema.DefaultAttributeDependent:
+		descriptions := make([]string, 0)
+		for _, dc := range d.Cases {
+			caseDesc, ok := defaultDescription(dc.Default)
+			if !ok || !dc.Value.IsWhollyKnown() {
+				continue
+			}
+			descriptions = append(descriptions, fmt.Sprintf("%s if `%s` is `%s`",
+				caseDesc, d.Name, valueString(dc.Value)))
+		}
+		if fallbackDesc, ok := defaultDescription(d.Fallback); ok {
+			if len(descriptions) == 0 {
+				return fallbackDesc, true
+			}
+			descriptions = append(descriptions, "otherwise "+fallbackDesc)
+		}
+		if len(descriptions) == 0 {
+			return fmt.Sprintf("depends on `%s`", d.Name), true
+		}
+		return strings.Join(descriptions, ", "), true
+	case schema.DefaultEnvVar:
+		desc := fmt.Sprintf("value of `%s` environment variable", d.Name)
+		if fallbackDesc, ok := defaultDescription(d.Fallback); ok {
+			desc += ", otherwise " + fallbackDesc
+		}
+		return desc, true
+	}
+	return "", false
+}
+
+// snippetForDefault returns a snippet prefilled with the default
+// which applies in the given context, or false if the default
+// cannot be represented as a simple snippet
+func snippetForDefault(aSchema *schema.AttributeSchema, ctx schema.DefaultContext, placeholder int) (string, bool) {
+	switch d := aSchema.ResolveDefault(ctx).(type) {
+	case schema.DefaultValue:
+		if d.Value.IsNull() || !d.Value.IsKnown() {
+			return "", false
+		}
+		switch d.Value.Type() {
+		case cty.String:
+			quoted := valueString(d.Value)
+			return fmt.Sprintf(`"${%d:%s}"`, placeholder,
+				escapeSnippet(quoted[1:len(quoted)-1])), true
+		case cty.Number, cty.Bool:
+			return fmt.Sprintf("${%d:%s}", placeholder, escapeSnippet(valueString(d.Value))), true
+		}
+	case schema.DefaultKeyword:
+		return fmt.Sprintf("${%d:%s}", placeholder, escapeSnippet(d.Keyword)), true
+	case schema.DefaultTypeDeclaration:
+		return fmt.Sprintf("${%d:%s}", placeholder, escapeSnippet(typeexpr.TypeString(d.Type))), true
+	}
+	return "", false
+}
+
+func valueString(val cty.Value) string {
+	return strings.TrimSpace(string(hclwrite.TokensForValue(val).Bytes()))
+}
+
+var snippetEscaper = strings.NewReplacer(`\`, `\\`, `$`, `\$`, `}`, `\}`)
+
+func escapeSnippet(s string) string {
+	return snippetEscaper.Replace(s)
+}
@@ -0,0 +1,231 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package decoder
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/hcl-lang/lang"
+	"github.com/hashicorp/hcl-lang/schema"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/zclconf/go-cty-debug/ctydebug"
+	"github.com/zclconf/go-cty/cty"
+)
+
+var testSchemaWithDefaults = &schema.BodySchema{
+	Attributes: map[string]*schema.AttributeSchema{
+		"engine": {
+			IsOptional:   true,
+			Constraint:   schema.LiteralType{Type: cty.String},
+			DefaultValue: schema.DefaultValue{Value: cty.StringVal("mysql")},
+		},
+		"mode": {
+			IsOptional:   true,
+			Constraint:   schema.Keyword{Keyword: "auto"},
+			DefaultValue: schema.DefaultKeyword{Keyword: "auto"},
+		},
+		"type": {
+			IsOptional:   true,
+			Constraint:   schema.TypeDeclaration{},
+			DefaultValue: schema.DefaultTypeDeclaration{Type: cty.DynamicPseudoType},
+		},
+		"port": {
+			IsOptional: true,
+			Constraint: schema.LiteralType{Type: cty.Number},
+			DefaultValue: schema.DefaultAttributeDependent{
+				Name: "engine",
+				Cases: []schema.DefaultCase{
+					{
+						Value:   cty.StringVal("postgres"),
+						Default: schema.DefaultValue{Value: cty.NumberIntVal(5432)},
+					},
+				},
+				Fallback: schema.DefaultValue{Value: cty.NumberIntVal(3306)},
+			},
+		},
+		"region": {
+			IsOptional: true,
+			Constraint: schema.LiteralType{Type: cty.String},
+			DefaultValue: schema.DefaultEnvVar{
+				Name:     "TEST_REGION",
+				Fallback: schema.DefaultValue{Value: cty.StringVal("us-east-1")},
+			},
+		},
+	},
+}
+
+func TestDecoder_HoverAtPos_defaults(t *testing.T) {
+	testCases := []struct {
+		attrName        string
+		expectedContent string
+	}{
+		{"engine", "**engine** _optional, string_\n\ndefault: `\"mysql\"`"},
+		{"mode", "**mode** _optional, keyword_\n\ndefault: `auto`"},
+		{"type", "**type** _optional, type_\n\ndefault: `any`"},
+		{"port", "**port** _optional, number_\n\ndefault: `5432` if `engine` is `\"postgres\"`, otherwise `3306`"},
+		{"region", "**region** _optional, string_\n\ndefault: value of `TEST_REGION` environment variable, otherwise `\"us-east-1\"`"},
+	}
+
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("%2d-%s", i, tc.attrName), func(t *testing.T) {
+			config := fmt.Sprintf("%s = foo\n", tc.attrName)
+			f, _ := hclsyntax.ParseConfig([]byte(config), "test.tf", hcl.InitialPos)
+			d := testPathDecoder(t, &PathContext{
+				Schema: testSchemaWithDefaults,
+				Files: map[string]*hcl.File{
+					"test.tf": f,
+				},
+			})
+
+			data, err := d.HoverAtPos(context.Background(), "test.tf", hcl.InitialPos)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(lang.Markdown(tc.expectedContent), data.Content); diff != "" {
+				t.Fatalf("unexpected hover content: %s", diff)
+			}
+		})
+	}
+}
+
+func TestDecoder_CompletionAtPos_defaults(t *testing.T) {
+	testCases := []struct {
+		name            string
+		config          string
+		prefill         bool
+		lookupEnv       schema.LookupEnvFunc
+		expectedSnippet map[string]string
+	}{
+		{
+			"defaults not prefilled",
+			"\n",
+			false,
+			nil,
+			map[string]string{
+				"engine": `engine = "${1:value}"`,
+				"mode":   "mode = ",
+				"port":   "port = ${1:0}",
+				"region": `region = "${1:value}"`,
+				"type":   "type = ",
+			},
+		},
+		{
+			"static defaults",
+			"\n",
+			true,
+			nil,
+			map[string]string{
+				"engine": `engine = "${1:mysql}"`,
+				"mode":   "mode = ${1:auto}",
+				"port":   "port = ${1:3306}",
+				"region": `region = "${1:us-east-1}"`,
+				"type":   "type = ${1:any}",
+			},
+		},
+		{
+			"defaults depending on attribute and env",
+			"engine = \"postgres\"\n",
+			true,
+			func(name string) (string, bool) {
+				if name == "TEST_REGION" {
+					return "eu-west-${2}", true
+				}
+				return "", false
+			},
+			map[string]string{
+				"mode":   "mode = ${1:auto}",
+				"port":   "port = ${1:5432}",
+				"region": `region = "${1:eu-west-\$\${2\}}"`,
+				"type":   "type = ${1:any}",
+			},
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("%2d-%s", i, tc.name), func(t *testing.T) {
+			f, _ := hclsyntax.ParseConfig([]byte(tc.config), "test.tf", hcl.InitialPos)
+			d := testPathDecoder(t, &PathContext{
+				Schema: testSchemaWithDefaults,
+				Files: map[string]*hcl.File{
+					"test.tf": f,
+				},
+			})
+			d.decoderCtx.LookupEnv = tc.lookupEnv
+			d.PrefillDefaultValues = tc.prefill
+
+			pos := f.Body.(*hclsyntax.Body).SrcRange.End
+			candidates, err := d.CompletionAtPos(context.Background(), "test.tf", pos)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			snippets := make(map[string]string, 0)
+			for _, c := range candidates.List {
+				snippets[c.Label] = c.TextEdit.Snippet
+			}
+			if diff := cmp.Diff(tc.expectedSnippet, snippets); diff != "" {
+				t.Fatalf("unexpected snippets: %s", diff)
+			}
+		})
+	}
+}
+
+func TestCollectReferenceTargets_defaultTypeDeclaration(t *testing.T) {
+	bodySchema := &schema.BodySchema{
+		Blocks: map[string]*schema.BlockSchema{
+			"variable": {
+				Labels: []*schema.LabelSchema{
+					{Name: "name"},
+				},
+				Address: &schema.BlockAddrSchema{
+					Steps: []schema.AddrStep{
+						schema.LabelStep{Index: 0},
+					},
+					AsTypeOf: &schema.BlockAsTypeOf{
+						AttributeExpr: "type",
+					},
+				},
+				Type: schema.BlockTypeObject,
+				Body: &schema.BodySchema{
+					Attributes: map[string]*schema.AttributeSchema{
+						"type": {
+							IsOptional:   true,
+							Constraint:   schema.TypeDeclaration{},
+							DefaultValue: schema.DefaultTypeDeclaration{Type: cty.String},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	f, _ := hclsyntax.ParseConfig([]byte(`variable "test" {
+}
+`), "test.tf", hcl.InitialPos)
+	d := testPathDecoder(t, &PathContext{
+		Schema: bodySchema,
+		Files: map[string]*hcl.File{
+			"test.tf": f,
+		},
+	})
+
+	refs, err := d.CollectReferenceTargets()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(refs) != 1 {
+		t.Fatalf("expected exactly 1 target, given: %#v", refs)
+	}
+	if diff := cmp.Diff(cty.String, refs[0].Type, ctydebug.CmpOptions); diff != "" {
+		t.Fatalf("unexpected target type: %s", diff)
+	}
+	if refs[0].TypeDefRangePtr != nil {
+		t.Fatalf("expected no type definition range, given: %#v", refs[0].TypeDefRangePtr)
+	}
+}
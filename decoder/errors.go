@@ -42,6 +42,15 @@ func (e *UnknownFileFormatError) Error() string {
 	return fmt.Sprintf("%s: unknown file format", e.Filename)
 }
 
+type SemanticTokensResultNotFoundError struct {
+	Filename string
+	ResultID string
+}
+
+func (e *SemanticTokensResultNotFoundError) Error() string {
+	return fmt.Sprintf("%s: semantic tokens result %q not found", e.Filename, e.ResultID)
+}
+
 type PosOutOfRangeError struct {
 	Filename string
 	Pos      hcl.Pos
@@ -61,3 +70,12 @@ type PositionalError struct {
 func (e *PositionalError) Error() string {
 	return fmt.Sprintf("%s (%s): %s", e.Filename, stringPos(e.Pos), e.Msg)
 }
+
+type InvalidSyntaxError struct {
+	Filename    string
+	Diagnostics hcl.Diagnostics
+}
+
+func (e *InvalidSyntaxError) Error() string {
+	return fmt.Sprintf("%s: invalid syntax: %s", e.Filename, e.Diagnostics.Error())
+}
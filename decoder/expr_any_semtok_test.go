@@ -3629,8 +3629,8 @@ func TestSemanticTokens_exprAny_templates(t *testing.T) {
 					},
 					Range: hcl.Range{
 						Filename: "test.tf",
-						Start:    hcl.Pos{Line: 1, Column: 15, Byte: 14},
-						End:      hcl.Pos{Line: 1, Column: 24, Byte: 23},
+						Start:    hcl.Pos{Line: 3, Column: 3, Byte: 19},
+						End:      hcl.Pos{Line: 3, Column: 12, Byte: 28},
 					},
 					Constraints: reference.OriginConstraints{
 						{
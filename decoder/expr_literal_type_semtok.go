@@ -39,7 +39,8 @@ func (lt LiteralType) SemanticTokens(ctx context.Context) []lang.SemanticToken {
 		// We may however land here from within AnyExpression, in which case
 		// the embedded string is in fact LiteralValueExpr and it is handled below.
 
-		// TODO: consider reporting multiline/HEREDOC notation as a different token
+		// HEREDOC anchors are reported separately as TokenHeredocAnchor, if enabled
+		// via DecoderContext.EnabledSemanticTokenTypes
 	}
 
 	if typ.IsPrimitiveType() {
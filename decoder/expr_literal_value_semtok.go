@@ -13,6 +13,14 @@ import (
 )
 
 func (lv LiteralValue) SemanticTokens(ctx context.Context) []lang.SemanticToken {
+	tokens := lv.semanticTokens(ctx)
+	if lv.cons.IsDeprecated {
+		return withSemanticTokenModifier(ctx, tokens, lang.TokenModifierDeprecated)
+	}
+	return tokens
+}
+
+func (lv LiteralValue) semanticTokens(ctx context.Context) []lang.SemanticToken {
 	typ := lv.cons.Value.Type()
 
 	if typ == cty.DynamicPseudoType {
@@ -48,7 +56,8 @@ func (lv LiteralValue) SemanticTokens(ctx context.Context) []lang.SemanticToken
 			}
 		}
 
-		// TODO: consider reporting multiline/HEREDOC notation as a different token
+		// HEREDOC anchors are reported separately as TokenHeredocAnchor, if enabled
+		// via DecoderContext.EnabledSemanticTokenTypes
 
 		return []lang.SemanticToken{}
 	}
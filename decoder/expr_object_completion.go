@@ -274,7 +274,7 @@ func objectAttributesToCandidates(ctx context.Context, prefix string, attrs sche
 			continue
 		}
 
-		candidates = append(candidates, attributeSchemaToCandidate(ctx, name, attrs[name], editRange))
+		candidates = append(candidates, attributeSchemaToCandidate(ctx, name, attrs[name], schema.DefaultContext{}, editRange))
 	}
 
 	return candidates
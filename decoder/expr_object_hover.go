@@ -80,6 +80,9 @@ func hoverContentForAttribute(name string, aSchema *schema.AttributeSchema) lang
 	if aSchema.Description.Value != "" {
 		value += fmt.Sprintf("\n\n%s", aSchema.Description.Value)
 	}
+	if defaultDesc, ok := defaultDescription(aSchema.DefaultValue); ok {
+		value += fmt.Sprintf("\n\ndefault: %s", defaultDesc)
+	}
 	return lang.MarkupContent{
 		Kind:  lang.MarkdownKind,
 		Value: value,
@@ -48,7 +48,7 @@ func (obj Object) SemanticTokens(ctx context.Context) []lang.SemanticToken {
 		if isKnownAttr {
 			tokens = append(tokens, lang.SemanticToken{
 				Type:      lang.TokenObjectKey,
-				Modifiers: lang.SemanticTokenModifiers{},
+				Modifiers: attributeSemanticTokenModifiers(ctx, aSchema),
 				// TODO: Consider not reporting the quotes?
 				Range: item.KeyExpr.Range(),
 			})
@@ -8,8 +8,10 @@ import (
 
 	"github.com/hashicorp/hcl-lang/lang"
 	"github.com/hashicorp/hcl-lang/reference"
+	"github.com/hashicorp/hcl-lang/schemacontext"
 	"github.com/hashicorp/hcl/v2"
 	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/hcl/v2/json"
 )
 
 func (ref Reference) CompletionAtPos(ctx context.Context, pos hcl.Pos) []lang.Candidate {
@@ -25,7 +27,14 @@ func (ref Reference) CompletionAtPos(ctx context.Context, pos hcl.Pos) []lang.Ca
 	file := ref.pathCtx.Files[ref.expr.Range().Filename]
 	rootBody, ok := file.Body.(*hclsyntax.Body)
 	if !ok {
-		return []lang.Candidate{}
+		if !json.IsJSONBody(file.Body) {
+			return []lang.Candidate{}
+		}
+		rootBody = syntaxBodyForJSON(file.Body, file.Bytes, ref.pathCtx.Schema, hcl.Range{
+			Filename: ref.expr.Range().Filename,
+			Start:    hcl.InitialPos,
+			End:      endPosForBytes(file.Bytes),
+		}, schemacontext.LookupEnv(ctx))
 	}
 
 	outerBodyRng := rootBody.Range()
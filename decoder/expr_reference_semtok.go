@@ -7,38 +7,14 @@ import (
 	"context"
 
 	"github.com/hashicorp/hcl-lang/lang"
-	"github.com/hashicorp/hcl-lang/reference"
 	"github.com/hashicorp/hcl/v2"
-	"github.com/hashicorp/hcl/v2/hclsyntax"
 	"github.com/zclconf/go-cty/cty"
 )
 
 func (ref Reference) SemanticTokens(ctx context.Context) []lang.SemanticToken {
-	eType, ok := ref.expr.(*hclsyntax.ScopeTraversalExpr)
-	if !ok {
-		return []lang.SemanticToken{}
-	}
-
-	pos := ref.expr.Range().Start
-	origins, ok := ref.pathCtx.ReferenceOrigins.AtPos(eType.Range().Filename, pos)
-	if !ok {
-		return []lang.SemanticToken{}
-	}
-
-	for _, origin := range origins {
-		matchableOrigin, ok := origin.(reference.MatchableOrigin)
-		if !ok {
-			continue
-		}
-		_, ok = ref.pathCtx.ReferenceTargets.Match(matchableOrigin)
-		if !ok {
-			// target not found
-			continue
-		}
-
-		return semanticTokensForTraversal(eType.Traversal)
-	}
-
+	// Tokens for references are not reported while walking the AST.
+	// They are computed upfront from reference origins instead,
+	// see referenceTokensForFile.
 	return []lang.SemanticToken{}
 }
 
@@ -6,14 +6,68 @@ package decoder
 import (
 	"github.com/hashicorp/hcl-lang/schema"
 	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/ext/typeexpr"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/zclconf/go-cty/cty"
 )
 
 type TypeDeclaration struct {
 	expr    hcl.Expression
 	cons    schema.TypeDeclaration
 	pathCtx *PathContext
+
+	// isObjectAttrType indicates whether the expression represents
+	// type of an object attribute, i.e. where optional() may be used
+	isObjectAttrType bool
 }
 
 func isTypeNameWithElementOnly(name string) bool {
 	return name == "list" || name == "set" || name == "map"
 }
+
+// nestedTypeDeclaration returns declaration for a type nested
+// inside of the current one, such as list element type
+func (td TypeDeclaration) nestedTypeDeclaration(expr hcl.Expression) TypeDeclaration {
+	return TypeDeclaration{
+		expr:    expr,
+		cons:    td.cons,
+		pathCtx: td.pathCtx,
+	}
+}
+
+// objectAttrTypeDeclaration returns declaration for a type
+// of an object attribute
+func (td TypeDeclaration) objectAttrTypeDeclaration(expr hcl.Expression) TypeDeclaration {
+	nestedTd := td.nestedTypeDeclaration(expr)
+	nestedTd.isObjectAttrType = true
+	return nestedTd
+}
+
+// allowsOptional returns true if the optional() modifier
+// is valid in place of the declaration
+func (td TypeDeclaration) allowsOptional() bool {
+	return td.cons.OptionalAttrs && td.isObjectAttrType
+}
+
+// optionalModifier returns the optional() call wrapping
+// the given type of an object attribute, if any
+func (td TypeDeclaration) optionalModifier(expr hcl.Expression) (*hclsyntax.FunctionCallExpr, bool) {
+	if !td.cons.OptionalAttrs {
+		return nil, false
+	}
+	funcExpr, ok := expr.(*hclsyntax.FunctionCallExpr)
+	if !ok || funcExpr.Name != "optional" {
+		return nil, false
+	}
+	return funcExpr, true
+}
+
+// typeConstraint decodes the type declared by the given expression,
+// including any defaults of optional object attributes if enabled
+func (td TypeDeclaration) typeConstraint(expr hcl.Expression) (cty.Type, hcl.Diagnostics) {
+	if td.cons.OptionalAttrs {
+		typ, _, diags := typeexpr.TypeConstraintWithDefaults(expr)
+		return typ, diags
+	}
+	return typeexpr.TypeConstraint(expr)
+}
@@ -22,7 +22,7 @@ func (td TypeDeclaration) CompletionAtPos(ctx context.Context, pos hcl.Pos) []la
 			Start:    pos,
 			End:      pos,
 		}
-		return allTypeDeclarationsAsCandidates("", editRange)
+		return td.typeDeclarationsAsCandidates("", editRange)
 	}
 
 	switch eType := td.expr.(type) {
@@ -46,7 +46,7 @@ func (td TypeDeclaration) CompletionAtPos(ctx context.Context, pos hcl.Pos) []la
 			End:      eType.Range().End,
 		}
 
-		return allTypeDeclarationsAsCandidates(prefix, editRange)
+		return td.typeDeclarationsAsCandidates(prefix, editRange)
 	case *hclsyntax.FunctionCallExpr:
 		// position in complex type name
 		if eType.NameRange.ContainsPos(pos) {
@@ -54,7 +54,7 @@ func (td TypeDeclaration) CompletionAtPos(ctx context.Context, pos hcl.Pos) []la
 			prefix := eType.Name[0:prefixLen]
 
 			editRange := eType.Range()
-			return allTypeDeclarationsAsCandidates(prefix, editRange)
+			return td.typeDeclarationsAsCandidates(prefix, editRange)
 		}
 
 		// position inside paranthesis
@@ -71,16 +71,17 @@ func (td TypeDeclaration) CompletionAtPos(ctx context.Context, pos hcl.Pos) []la
 				}
 
 				if len(eType.Args) == 1 && eType.Args[0].Range().ContainsPos(pos) {
-					cons := TypeDeclaration{
-						expr:    eType.Args[0],
-						pathCtx: td.pathCtx,
-					}
+					cons := td.nestedTypeDeclaration(eType.Args[0])
 					return cons.CompletionAtPos(ctx, pos)
 				}
 
 				return []lang.Candidate{}
 			}
 
+			if eType.Name == "optional" && td.allowsOptional() {
+				return td.optionalCompletionAtPos(ctx, eType, pos)
+			}
+
 			if eType.Name == "object" {
 				return td.objectCompletionAtPos(ctx, eType, pos)
 			}
@@ -141,8 +142,8 @@ func (td TypeDeclaration) objectCompletionAtPos(ctx context.Context, funcExpr *h
 
 		// if last byte is =, then it's incomplete attribute
 		if remainingBytes[len(remainingBytes)-1] == '=' {
-			// TODO: object optional+default
-			return allTypeDeclarationsAsCandidates("", editRange)
+			cons := td.objectAttrTypeDeclaration(nil)
+			return cons.typeDeclarationsAsCandidates("", editRange)
 		}
 	}
 
@@ -176,10 +177,7 @@ func (td TypeDeclaration) objectCompletionAtPos(ctx context.Context, funcExpr *h
 			return []lang.Candidate{}
 		}
 		if item.ValueExpr.Range().ContainsPos(pos) || item.ValueExpr.Range().End.Byte == pos.Byte {
-			cons := TypeDeclaration{
-				expr:    item.ValueExpr,
-				pathCtx: td.pathCtx,
-			}
+			cons := td.objectAttrTypeDeclaration(item.ValueExpr)
 			return cons.CompletionAtPos(ctx, pos)
 		}
 	}
@@ -216,8 +214,8 @@ func (td TypeDeclaration) objectCompletionAtPos(ctx context.Context, funcExpr *h
 
 	// if last byte is =, then it's incomplete attribute
 	if trimmedBytes[len(trimmedBytes)-1] == '=' {
-		// TODO: object optional+default
-		return allTypeDeclarationsAsCandidates("", editRange)
+		cons := td.objectAttrTypeDeclaration(nil)
+		return cons.typeDeclarationsAsCandidates("", editRange)
 	}
 
 	return []lang.Candidate{}
@@ -246,10 +244,7 @@ func (td TypeDeclaration) tupleCompletionAtPos(ctx context.Context, funcExpr *hc
 
 	for _, expr := range tupleExpr.Exprs {
 		if expr.Range().ContainsPos(pos) || expr.Range().End.Byte == pos.Byte {
-			cons := TypeDeclaration{
-				expr:    expr,
-				pathCtx: td.pathCtx,
-			}
+			cons := td.nestedTypeDeclaration(expr)
 			return cons.CompletionAtPos(ctx, pos)
 		}
 	}
@@ -276,6 +271,38 @@ func (td TypeDeclaration) tupleCompletionAtPos(ctx context.Context, funcExpr *hc
 	return []lang.Candidate{}
 }
 
+func (td TypeDeclaration) optionalCompletionAtPos(ctx context.Context, funcExpr *hclsyntax.FunctionCallExpr, pos hcl.Pos) []lang.Candidate {
+	if len(funcExpr.Args) == 0 {
+		editRange := hcl.Range{
+			Filename: funcExpr.Range().Filename,
+			Start:    funcExpr.OpenParenRange.End,
+			End:      funcExpr.CloseParenRange.Start,
+		}
+
+		return allTypeDeclarationsAsCandidates("", editRange)
+	}
+
+	typeExpr := funcExpr.Args[0]
+	if typeExpr.Range().ContainsPos(pos) || typeExpr.Range().End.Byte == pos.Byte {
+		cons := td.nestedTypeDeclaration(typeExpr)
+		return cons.CompletionAtPos(ctx, pos)
+	}
+
+	// default value is an arbitrary expression
+	return []lang.Candidate{}
+}
+
+// typeDeclarationsAsCandidates returns all type declarations
+// valid in place of the declaration, including optional()
+// for types of object attributes
+func (td TypeDeclaration) typeDeclarationsAsCandidates(prefix string, editRange hcl.Range) []lang.Candidate {
+	candidates := allTypeDeclarationsAsCandidates(prefix, editRange)
+	if td.allowsOptional() && strings.HasPrefix("optional", prefix) {
+		candidates = append(candidates, optionalModifierAsCandidate(editRange))
+	}
+	return candidates
+}
+
 func allTypeDeclarationsAsCandidates(prefix string, editRange hcl.Range) []lang.Candidate {
 	candidates := make([]lang.Candidate, 0)
 	// TODO: any
@@ -399,6 +426,20 @@ func complexTypeDeclarationsAsCandidates(prefix string, editRange hcl.Range) []l
 	return candidates
 }
 
+func optionalModifierAsCandidate(editRange hcl.Range) lang.Candidate {
+	return lang.Candidate{
+		Label:  "optional(…)",
+		Detail: "optional",
+		Kind:   lang.KeywordCandidateKind,
+		TextEdit: lang.TextEdit{
+			NewText: "optional()",
+			Snippet: fmt.Sprintf("optional(${%d})", 0),
+			Range:   editRange,
+		},
+		TriggerSuggest: true,
+	}
+}
+
 func objectAttributeItemAsCompletionCandidate(editRange hcl.Range) lang.Candidate {
 	return lang.Candidate{
 		Label:  "name = type",
@@ -1026,6 +1026,138 @@ func TestCompletionAtPos_exprTypeDeclaration(t *testing.T) {
 			hcl.Pos{Line: 1, Column: 7, Byte: 6},
 			lang.CompleteCandidates([]lang.Candidate{}),
 		},
+		// optional object attributes
+		{
+			"all types with optional attributes enabled",
+			map[string]*schema.AttributeSchema{
+				"attr": {
+					Constraint: schema.TypeDeclaration{OptionalAttrs: true},
+				},
+			},
+			`attr = 
+`,
+			hcl.Pos{Line: 1, Column: 8, Byte: 7},
+			lang.CompleteCandidates(allTypeDeclarationsAsCandidates("", hcl.Range{
+				Filename: "test.tf",
+				Start:    hcl.Pos{Line: 1, Column: 8, Byte: 7},
+				End:      hcl.Pos{Line: 1, Column: 8, Byte: 7},
+			})),
+		},
+		{
+			"single-line object value with optional attributes",
+			map[string]*schema.AttributeSchema{
+				"attr": {
+					Constraint: schema.TypeDeclaration{OptionalAttrs: true},
+				},
+			},
+			`attr = object({ name =  })
+`,
+			hcl.Pos{Line: 1, Column: 24, Byte: 23},
+			lang.CompleteCandidates(append(allTypeDeclarationsAsCandidates("", hcl.Range{
+				Filename: "test.tf",
+				Start:    hcl.Pos{Line: 1, Column: 24, Byte: 23},
+				End:      hcl.Pos{Line: 1, Column: 24, Byte: 23},
+			}), optionalModifierAsCandidate(hcl.Range{
+				Filename: "test.tf",
+				Start:    hcl.Pos{Line: 1, Column: 24, Byte: 23},
+				End:      hcl.Pos{Line: 1, Column: 24, Byte: 23},
+			}))),
+		},
+		{
+			"single-line object partial optional",
+			map[string]*schema.AttributeSchema{
+				"attr": {
+					Constraint: schema.TypeDeclaration{OptionalAttrs: true},
+				},
+			},
+			`attr = object({ foo = opt })
+`,
+			hcl.Pos{Line: 1, Column: 26, Byte: 25},
+			lang.CompleteCandidates([]lang.Candidate{
+				{
+					Label:  "optional(…)",
+					Detail: "optional",
+					Kind:   lang.KeywordCandidateKind,
+					TextEdit: lang.TextEdit{
+						NewText: "optional()",
+						Snippet: "optional(${0})",
+						Range: hcl.Range{
+							Filename: "test.tf",
+							Start:    hcl.Pos{Line: 1, Column: 23, Byte: 22},
+							End:      hcl.Pos{Line: 1, Column: 26, Byte: 25},
+						},
+					},
+					TriggerSuggest: true,
+				},
+			}),
+		},
+		{
+			"single-line object partial optional without optional attributes",
+			map[string]*schema.AttributeSchema{
+				"attr": {
+					Constraint: schema.TypeDeclaration{},
+				},
+			},
+			`attr = object({ foo = opt })
+`,
+			hcl.Pos{Line: 1, Column: 26, Byte: 25},
+			lang.CompleteCandidates([]lang.Candidate{}),
+		},
+		{
+			"inside empty optional",
+			map[string]*schema.AttributeSchema{
+				"attr": {
+					Constraint: schema.TypeDeclaration{OptionalAttrs: true},
+				},
+			},
+			`attr = object({ foo = optional() })
+`,
+			hcl.Pos{Line: 1, Column: 32, Byte: 31},
+			lang.CompleteCandidates(allTypeDeclarationsAsCandidates("", hcl.Range{
+				Filename: "test.tf",
+				Start:    hcl.Pos{Line: 1, Column: 32, Byte: 31},
+				End:      hcl.Pos{Line: 1, Column: 32, Byte: 31},
+			})),
+		},
+		{
+			"inside optional partial type with default",
+			map[string]*schema.AttributeSchema{
+				"attr": {
+					Constraint: schema.TypeDeclaration{OptionalAttrs: true},
+				},
+			},
+			`attr = object({ foo = optional(st, "x") })
+`,
+			hcl.Pos{Line: 1, Column: 34, Byte: 33},
+			lang.CompleteCandidates([]lang.Candidate{
+				{
+					Label:  "string",
+					Detail: "string",
+					Kind:   lang.StringCandidateKind,
+					TextEdit: lang.TextEdit{
+						NewText: "string",
+						Snippet: "string",
+						Range: hcl.Range{
+							Filename: "test.tf",
+							Start:    hcl.Pos{Line: 1, Column: 32, Byte: 31},
+							End:      hcl.Pos{Line: 1, Column: 34, Byte: 33},
+						},
+					},
+				},
+			}),
+		},
+		{
+			"inside optional default value",
+			map[string]*schema.AttributeSchema{
+				"attr": {
+					Constraint: schema.TypeDeclaration{OptionalAttrs: true},
+				},
+			},
+			`attr = object({ foo = optional(string, "x") })
+`,
+			hcl.Pos{Line: 1, Column: 41, Byte: 40},
+			lang.CompleteCandidates([]lang.Candidate{}),
+		},
 	}
 
 	for i, tc := range testCases {
@@ -9,8 +9,8 @@ import (
 
 	"github.com/hashicorp/hcl-lang/lang"
 	"github.com/hashicorp/hcl/v2"
-	"github.com/hashicorp/hcl/v2/ext/typeexpr"
 	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/zclconf/go-cty/cty"
 )
 
 func (td TypeDeclaration) HoverAtPos(ctx context.Context, pos hcl.Pos) *lang.HoverData {
@@ -21,7 +21,7 @@ func (td TypeDeclaration) HoverAtPos(ctx context.Context, pos hcl.Pos) *lang.Hov
 		}
 
 		if eType.Range().ContainsPos(pos) {
-			typ, _ := typeexpr.TypeConstraint(eType)
+			typ, _ := td.typeConstraint(eType)
 			content, err := hoverContentForType(typ, 0)
 			if err != nil {
 				return nil
@@ -32,9 +32,13 @@ func (td TypeDeclaration) HoverAtPos(ctx context.Context, pos hcl.Pos) *lang.Hov
 			}
 		}
 	case *hclsyntax.FunctionCallExpr:
+		if eType.Name == "optional" && td.allowsOptional() {
+			return td.optionalHoverAtPos(ctx, eType, pos)
+		}
+
 		// position in complex type name
 		if eType.NameRange.ContainsPos(pos) {
-			typ, diags := typeexpr.TypeConstraint(eType)
+			typ, diags := td.typeConstraint(eType)
 			if len(diags) > 0 {
 				return nil
 			}
@@ -57,10 +61,7 @@ func (td TypeDeclaration) HoverAtPos(ctx context.Context, pos hcl.Pos) *lang.Hov
 				}
 
 				if len(eType.Args) == 1 && eType.Args[0].Range().ContainsPos(pos) {
-					cons := TypeDeclaration{
-						expr:    eType.Args[0],
-						pathCtx: td.pathCtx,
-					}
+					cons := td.nestedTypeDeclaration(eType.Args[0])
 					return cons.HoverAtPos(ctx, pos)
 				}
 
@@ -102,7 +103,7 @@ func (td TypeDeclaration) objectHoverAtPos(ctx context.Context, funcExpr *hclsyn
 		End: objExpr.Range().End,
 	}
 	if objExpr.OpenRange.ContainsPos(pos) || closeRange.ContainsPos(pos) {
-		typ, diags := typeexpr.TypeConstraint(funcExpr)
+		typ, diags := td.typeConstraint(funcExpr)
 		if len(diags) > 0 {
 			return nil
 		}
@@ -124,17 +125,13 @@ func (td TypeDeclaration) objectHoverAtPos(ctx context.Context, funcExpr *hclsyn
 				return nil
 			}
 
-			typ, _ := typeexpr.TypeConstraint(item.ValueExpr)
 			return &lang.HoverData{
-				Content: lang.Markdown(fmt.Sprintf("`%s` = _%s_", rawKey, typ.FriendlyNameForConstraint())),
+				Content: lang.Markdown(fmt.Sprintf("`%s` = %s", rawKey, td.objectAttrTypeContent(item.ValueExpr))),
 				Range:   hcl.RangeBetween(item.KeyExpr.Range(), item.ValueExpr.Range()),
 			}
 		}
 		if item.ValueExpr.Range().ContainsPos(pos) {
-			cons := TypeDeclaration{
-				expr:    item.ValueExpr,
-				pathCtx: td.pathCtx,
-			}
+			cons := td.objectAttrTypeDeclaration(item.ValueExpr)
 			return cons.HoverAtPos(ctx, pos)
 		}
 	}
@@ -164,7 +161,7 @@ func (td TypeDeclaration) tupleHoverAtPos(ctx context.Context, funcExpr *hclsynt
 		End: tupleExpr.Range().End,
 	}
 	if tupleExpr.OpenRange.ContainsPos(pos) || closeRange.ContainsPos(pos) {
-		typ, diags := typeexpr.TypeConstraint(funcExpr)
+		typ, diags := td.typeConstraint(funcExpr)
 		if len(diags) > 0 {
 			return nil
 		}
@@ -180,13 +177,52 @@ func (td TypeDeclaration) tupleHoverAtPos(ctx context.Context, funcExpr *hclsynt
 
 	for _, expr := range tupleExpr.Exprs {
 		if expr.Range().ContainsPos(pos) {
-			cons := TypeDeclaration{
-				expr:    expr,
-				pathCtx: td.pathCtx,
-			}
+			cons := td.nestedTypeDeclaration(expr)
 			return cons.HoverAtPos(ctx, pos)
 		}
 	}
 
 	return nil
 }
+
+func (td TypeDeclaration) optionalHoverAtPos(ctx context.Context, funcExpr *hclsyntax.FunctionCallExpr, pos hcl.Pos) *lang.HoverData {
+	if funcExpr.NameRange.ContainsPos(pos) {
+		return &lang.HoverData{
+			Content: lang.Markdown(td.objectAttrTypeContent(funcExpr)),
+			Range:   funcExpr.Range(),
+		}
+	}
+
+	if len(funcExpr.Args) > 0 && funcExpr.Args[0].Range().ContainsPos(pos) {
+		cons := td.nestedTypeDeclaration(funcExpr.Args[0])
+		return cons.HoverAtPos(ctx, pos)
+	}
+
+	return nil
+}
+
+// objectAttrTypeContent returns Markdown describing the type
+// of an object attribute, including the default value
+// if the type is wrapped in optional()
+func (td TypeDeclaration) objectAttrTypeContent(expr hcl.Expression) string {
+	funcExpr, ok := td.optionalModifier(expr)
+	if !ok {
+		typ, _ := td.typeConstraint(expr)
+		return fmt.Sprintf("_%s_", typ.FriendlyNameForConstraint())
+	}
+
+	typ := cty.DynamicPseudoType
+	if len(funcExpr.Args) > 0 {
+		typ, _ = td.typeConstraint(funcExpr.Args[0])
+	}
+	content := fmt.Sprintf("_optional, %s_", typ.FriendlyNameForConstraint())
+
+	if len(funcExpr.Args) == 2 {
+		defaultVal, diags := funcExpr.Args[1].Value(nil)
+		if !diags.HasErrors() && defaultVal.IsWhollyKnown() {
+			content += fmt.Sprintf("\n\ndefault: `%s`", valueString(defaultVal))
+		}
+	}
+
+	return content
+}
@@ -158,6 +158,90 @@ func TestHoverAtPos_exprTypeDeclaration(t *testing.T) {
 				},
 			},
 		},
+		{
+			"object type with optional attribute",
+			map[string]*schema.AttributeSchema{
+				"attr": {
+					Constraint: schema.TypeDeclaration{OptionalAttrs: true},
+				},
+			},
+			`attr = object({
+  foo = optional(string, "x")
+})
+`,
+			hcl.Pos{Line: 1, Column: 11, Byte: 10},
+			&lang.HoverData{
+				Content: lang.Markdown("```\n{\n  foo = optional, string\n}\n```\n_object_"),
+				Range: hcl.Range{
+					Filename: "test.tf",
+					Start:    hcl.Pos{Line: 1, Column: 8, Byte: 7},
+					End:      hcl.Pos{Line: 3, Column: 3, Byte: 48},
+				},
+			},
+		},
+		{
+			"optional attribute name",
+			map[string]*schema.AttributeSchema{
+				"attr": {
+					Constraint: schema.TypeDeclaration{OptionalAttrs: true},
+				},
+			},
+			`attr = object({
+  foo = optional(string, "x")
+})
+`,
+			hcl.Pos{Line: 2, Column: 5, Byte: 20},
+			&lang.HoverData{
+				Content: lang.Markdown("`foo` = _optional, string_\n\ndefault: `\"x\"`"),
+				Range: hcl.Range{
+					Filename: "test.tf",
+					Start:    hcl.Pos{Line: 2, Column: 3, Byte: 18},
+					End:      hcl.Pos{Line: 2, Column: 30, Byte: 45},
+				},
+			},
+		},
+		{
+			"optional modifier",
+			map[string]*schema.AttributeSchema{
+				"attr": {
+					Constraint: schema.TypeDeclaration{OptionalAttrs: true},
+				},
+			},
+			`attr = object({
+  foo = optional(string, "x")
+})
+`,
+			hcl.Pos{Line: 2, Column: 11, Byte: 26},
+			&lang.HoverData{
+				Content: lang.Markdown("_optional, string_\n\ndefault: `\"x\"`"),
+				Range: hcl.Range{
+					Filename: "test.tf",
+					Start:    hcl.Pos{Line: 2, Column: 9, Byte: 24},
+					End:      hcl.Pos{Line: 2, Column: 30, Byte: 45},
+				},
+			},
+		},
+		{
+			"optional attribute type",
+			map[string]*schema.AttributeSchema{
+				"attr": {
+					Constraint: schema.TypeDeclaration{OptionalAttrs: true},
+				},
+			},
+			`attr = object({
+  foo = optional(string, "x")
+})
+`,
+			hcl.Pos{Line: 2, Column: 20, Byte: 35},
+			&lang.HoverData{
+				Content: lang.Markdown("_string_"),
+				Range: hcl.Range{
+					Filename: "test.tf",
+					Start:    hcl.Pos{Line: 2, Column: 18, Byte: 33},
+					End:      hcl.Pos{Line: 2, Column: 24, Byte: 39},
+				},
+			},
+		},
 	}
 
 	for i, tc := range testCases {
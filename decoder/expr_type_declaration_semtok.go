@@ -7,6 +7,7 @@ import (
 	"context"
 
 	"github.com/hashicorp/hcl-lang/lang"
+	"github.com/hashicorp/hcl-lang/schema"
 	"github.com/hashicorp/hcl/v2/hclsyntax"
 )
 
@@ -41,10 +42,7 @@ func (td TypeDeclaration) SemanticTokens(ctx context.Context) []lang.SemanticTok
 			}
 
 			if len(eType.Args) == 1 {
-				cons := TypeDeclaration{
-					expr:    eType.Args[0],
-					pathCtx: td.pathCtx,
-				}
+				cons := td.nestedTypeDeclaration(eType.Args[0])
 				tokens = append(tokens, cons.SemanticTokens(ctx)...)
 
 				return tokens
@@ -60,6 +58,10 @@ func (td TypeDeclaration) SemanticTokens(ctx context.Context) []lang.SemanticTok
 		if eType.Name == "tuple" {
 			return td.tupleSemanticTokens(ctx, eType)
 		}
+
+		if eType.Name == "optional" && td.allowsOptional() {
+			return td.optionalSemanticTokens(ctx, eType)
+		}
 	}
 	return nil
 }
@@ -94,10 +96,7 @@ func (td TypeDeclaration) objectSemanticTokens(ctx context.Context, funcExpr *hc
 			Range:     item.KeyExpr.Range(),
 		})
 
-		cons := TypeDeclaration{
-			expr:    item.ValueExpr,
-			pathCtx: td.pathCtx,
-		}
+		cons := td.objectAttrTypeDeclaration(item.ValueExpr)
 		tokens = append(tokens, cons.SemanticTokens(ctx)...)
 	}
 
@@ -122,12 +121,36 @@ func (td TypeDeclaration) tupleSemanticTokens(ctx context.Context, funcExpr *hcl
 	}
 
 	for _, expr := range tupleExpr.Exprs {
-		cons := TypeDeclaration{
-			expr:    expr,
-			pathCtx: td.pathCtx,
-		}
+		cons := td.nestedTypeDeclaration(expr)
 		tokens = append(tokens, cons.SemanticTokens(ctx)...)
 	}
 
 	return tokens
 }
+
+func (td TypeDeclaration) optionalSemanticTokens(ctx context.Context, funcExpr *hclsyntax.FunctionCallExpr) []lang.SemanticToken {
+	tokens := make([]lang.SemanticToken, 0)
+	tokens = append(tokens, lang.SemanticToken{
+		Type:      lang.TokenKeyword,
+		Modifiers: []lang.SemanticTokenModifier{},
+		Range:     funcExpr.NameRange,
+	})
+
+	if len(funcExpr.Args) == 0 || len(funcExpr.Args) > 2 {
+		return tokens
+	}
+
+	cons := td.nestedTypeDeclaration(funcExpr.Args[0])
+	tokens = append(tokens, cons.SemanticTokens(ctx)...)
+
+	if len(funcExpr.Args) == 2 {
+		typ, diags := td.typeConstraint(funcExpr.Args[0])
+		if diags.HasErrors() {
+			return tokens
+		}
+		defaultExpr := newExpression(td.pathCtx, funcExpr.Args[1], schema.LiteralType{Type: typ})
+		tokens = append(tokens, defaultExpr.SemanticTokens(ctx)...)
+	}
+
+	return tokens
+}
@@ -0,0 +1,223 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package decoder
+
+import (
+	"bytes"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/hcl-lang/lang"
+	"github.com/hashicorp/hcl-lang/schema"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/hcl/v2/json"
+)
+
+// FoldingRangesInFile returns ranges of blocks, multi-line
+// object and tuple constructors, for expressions, heredocs
+// and runs of comments within the config file.
+//
+// Only ranges spanning multiple lines are returned.
+// Blocks known to the schema come with collapsed text
+// consisting of the block type and labels.
+func (d *PathDecoder) FoldingRangesInFile(filename string) ([]lang.FoldingRange, error) {
+	f, err := d.fileByName(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := d.bodyForFileAndPos(filename, f, hcl.InitialPos)
+	if err != nil {
+		return nil, err
+	}
+
+	ranges := d.foldingRangesForBody(body, d.pathCtx.Schema)
+
+	if !json.IsJSONBody(f.Body) {
+		lexTokens, _ := hclsyntax.LexConfig(f.Bytes, filename, hcl.InitialPos)
+		ranges = append(ranges, heredocFoldingRanges(lexTokens)...)
+		ranges = append(ranges, commentFoldingRanges(f.Bytes, lexTokens)...)
+	}
+
+	sort.SliceStable(ranges, func(i, j int) bool {
+		if ranges[i].Range.Start.Byte != ranges[j].Range.Start.Byte {
+			return ranges[i].Range.Start.Byte < ranges[j].Range.Start.Byte
+		}
+		return ranges[i].Range.End.Byte > ranges[j].Range.End.Byte
+	})
+
+	return ranges, nil
+}
+
+func (d *PathDecoder) foldingRangesForBody(body *hclsyntax.Body, bodySchema *schema.BodySchema) []lang.FoldingRange {
+	ranges := make([]lang.FoldingRange, 0)
+
+	for _, attr := range body.Attributes {
+		ranges = append(ranges, foldingRangesForExpr(attr.Expr)...)
+	}
+
+	for _, block := range body.Blocks {
+		var blockSchema *schema.BlockSchema
+		if bodySchema != nil {
+			blockSchema = bodySchema.Blocks[block.Type]
+		}
+
+		if isMultiLineRange(block.Range()) {
+			ranges = append(ranges, lang.FoldingRange{
+				Range:         block.Range(),
+				Kind:          lang.RegionFoldingRangeKind,
+				CollapsedText: blockCollapsedText(block, blockSchema),
+			})
+		}
+
+		var nestedSchema *schema.BodySchema
+		if blockSchema != nil {
+			nestedSchema, _ = d.mergeBlockBodySchemas(block.AsHCLBlock(), blockSchema)
+		}
+		if block.Body != nil {
+			ranges = append(ranges, d.foldingRangesForBody(block.Body, nestedSchema)...)
+		}
+	}
+
+	return ranges
+}
+
+// blockCollapsedText returns the block type followed by labels,
+// e.g. resource "aws_instance" "example" {...}
+// or empty string for blocks unknown to the schema
+func blockCollapsedText(block *hclsyntax.Block, blockSchema *schema.BlockSchema) string {
+	if blockSchema == nil {
+		return ""
+	}
+
+	parts := []string{block.Type}
+	for i, label := range block.Labels {
+		if i >= len(blockSchema.Labels) {
+			break
+		}
+		parts = append(parts, strconv.Quote(label))
+	}
+	parts = append(parts, "{...}")
+
+	return strings.Join(parts, " ")
+}
+
+func foldingRangesForExpr(expr hclsyntax.Expression) []lang.FoldingRange {
+	ranges := make([]lang.FoldingRange, 0)
+
+	hclsyntax.VisitAll(expr, func(node hclsyntax.Node) hcl.Diagnostics {
+		var rng hcl.Range
+		var collapsedText string
+
+		switch e := node.(type) {
+		case *hclsyntax.ObjectConsExpr:
+			rng, collapsedText = e.SrcRange, "{...}"
+		case *hclsyntax.TupleConsExpr:
+			rng, collapsedText = e.SrcRange, "[...]"
+		case *hclsyntax.ForExpr:
+			rng = e.SrcRange
+			if e.KeyExpr != nil {
+				collapsedText = "{for ...}"
+			} else {
+				collapsedText = "[for ...]"
+			}
+		default:
+			return nil
+		}
+
+		if isMultiLineRange(rng) {
+			ranges = append(ranges, lang.FoldingRange{
+				Range:         rng,
+				Kind:          lang.RegionFoldingRangeKind,
+				CollapsedText: collapsedText,
+			})
+		}
+		return nil
+	})
+
+	return ranges
+}
+
+// heredocFoldingRanges returns ranges of heredoc templates,
+// from the opening to the closing anchor
+func heredocFoldingRanges(lexTokens hclsyntax.Tokens) []lang.FoldingRange {
+	ranges := make([]lang.FoldingRange, 0)
+
+	var openTok *hclsyntax.Token
+	for i, tok := range lexTokens {
+		switch tok.Type {
+		case hclsyntax.TokenOHeredoc:
+			openTok = &lexTokens[i]
+		case hclsyntax.TokenCHeredoc:
+			if openTok == nil {
+				continue
+			}
+			rng := hcl.RangeBetween(openTok.Range, trimTrailingNewline(tok))
+			if isMultiLineRange(rng) {
+				ranges = append(ranges, lang.FoldingRange{
+					Range:         rng,
+					Kind:          lang.RegionFoldingRangeKind,
+					CollapsedText: string(bytes.TrimSpace(openTok.Bytes)) + "...",
+				})
+			}
+			openTok = nil
+		}
+	}
+
+	return ranges
+}
+
+// commentFoldingRanges returns ranges of multi-line comments
+// and runs of single-line comments on consecutive lines
+func commentFoldingRanges(src []byte, lexTokens hclsyntax.Tokens) []lang.FoldingRange {
+	ranges := make([]lang.FoldingRange, 0)
+
+	var run []hcl.Range
+	flushRun := func() {
+		if len(run) > 0 {
+			rng := hcl.RangeBetween(run[0], run[len(run)-1])
+			if isMultiLineRange(rng) {
+				ranges = append(ranges, lang.FoldingRange{
+					Range: rng,
+					Kind:  lang.CommentFoldingRangeKind,
+				})
+			}
+		}
+		run = nil
+	}
+
+	for _, tok := range lexTokens {
+		if tok.Type != hclsyntax.TokenComment {
+			continue
+		}
+		rng := trimTrailingNewline(tok)
+
+		// trailing comments do not form a run
+		isOwnLine := len(bytes.TrimSpace(src[lineStart(src, rng.Start.Byte):rng.Start.Byte])) == 0
+		isSingleLine := rng.Start.Line == rng.End.Line
+
+		if !isOwnLine || !isSingleLine {
+			flushRun()
+			if isOwnLine {
+				run = []hcl.Range{rng}
+				flushRun()
+			}
+			continue
+		}
+
+		if len(run) > 0 && run[len(run)-1].End.Line+1 != rng.Start.Line {
+			flushRun()
+		}
+		run = append(run, rng)
+	}
+	flushRun()
+
+	return ranges
+}
+
+func isMultiLineRange(rng hcl.Range) bool {
+	return rng.End.Line > rng.Start.Line
+}
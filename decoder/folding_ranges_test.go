@@ -0,0 +1,198 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package decoder
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/hcl-lang/lang"
+	"github.com/hashicorp/hcl-lang/schema"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/hcl/v2/json"
+	"github.com/zclconf/go-cty/cty"
+)
+
+func TestDecoder_FoldingRangesInFile(t *testing.T) {
+	cfg := `# first
+# second
+resource "aws_instance" "web" {
+  tags = {
+    Name = "web" # trailing
+  }
+  list = [for x in [1, 2] :
+    x
+  ]
+  doc = <<-EOT
+    hello
+  EOT
+  /* multi
+     line */
+  one = 1 // one
+}
+unknown {
+  a = 1
+}
+`
+	f, pDiags := hclsyntax.ParseConfig([]byte(cfg), "test.tf", hcl.InitialPos)
+	if len(pDiags) > 0 {
+		t.Fatal(pDiags)
+	}
+
+	d := testPathDecoder(t, &PathContext{
+		Schema: &schema.BodySchema{
+			Blocks: map[string]*schema.BlockSchema{
+				"resource": {
+					Labels: []*schema.LabelSchema{
+						{Name: "type"},
+						{Name: "name"},
+					},
+					Body: &schema.BodySchema{},
+				},
+			},
+		},
+		Files: map[string]*hcl.File{
+			"test.tf": f,
+		},
+	})
+
+	ranges, err := d.FoldingRangesInFile("test.tf")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectedRanges := []lang.FoldingRange{
+		{
+			Range: hcl.Range{
+				Filename: "test.tf",
+				Start:    hcl.Pos{Line: 1, Column: 1, Byte: 0},
+				End:      hcl.Pos{Line: 2, Column: 9, Byte: 16},
+			},
+			Kind: lang.CommentFoldingRangeKind,
+		},
+		{
+			Range: hcl.Range{
+				Filename: "test.tf",
+				Start:    hcl.Pos{Line: 3, Column: 1, Byte: 17},
+				End:      hcl.Pos{Line: 16, Column: 2, Byte: 203},
+			},
+			Kind:          lang.RegionFoldingRangeKind,
+			CollapsedText: `resource "aws_instance" "web" {...}`,
+		},
+		{
+			Range: hcl.Range{
+				Filename: "test.tf",
+				Start:    hcl.Pos{Line: 4, Column: 10, Byte: 58},
+				End:      hcl.Pos{Line: 6, Column: 4, Byte: 91},
+			},
+			Kind:          lang.RegionFoldingRangeKind,
+			CollapsedText: "{...}",
+		},
+		{
+			Range: hcl.Range{
+				Filename: "test.tf",
+				Start:    hcl.Pos{Line: 7, Column: 10, Byte: 101},
+				End:      hcl.Pos{Line: 9, Column: 4, Byte: 129},
+			},
+			Kind:          lang.RegionFoldingRangeKind,
+			CollapsedText: "[for ...]",
+		},
+		{
+			Range: hcl.Range{
+				Filename: "test.tf",
+				Start:    hcl.Pos{Line: 10, Column: 9, Byte: 138},
+				End:      hcl.Pos{Line: 12, Column: 6, Byte: 160},
+			},
+			Kind:          lang.RegionFoldingRangeKind,
+			CollapsedText: "<<-EOT...",
+		},
+		{
+			Range: hcl.Range{
+				Filename: "test.tf",
+				Start:    hcl.Pos{Line: 13, Column: 3, Byte: 163},
+				End:      hcl.Pos{Line: 14, Column: 13, Byte: 184},
+			},
+			Kind: lang.CommentFoldingRangeKind,
+		},
+		{
+			Range: hcl.Range{
+				Filename: "test.tf",
+				Start:    hcl.Pos{Line: 17, Column: 1, Byte: 204},
+				End:      hcl.Pos{Line: 19, Column: 2, Byte: 223},
+			},
+			Kind: lang.RegionFoldingRangeKind,
+		},
+	}
+	if diff := cmp.Diff(expectedRanges, ranges); diff != "" {
+		t.Fatalf("unexpected folding ranges: %s", diff)
+	}
+}
+
+func TestDecoder_FoldingRangesInFile_json(t *testing.T) {
+	f, pDiags := json.Parse([]byte(`{
+  "resource": {
+    "aws_instance": {
+      "web": {
+        "tags": {
+          "Name": "web"
+        }
+      }
+    }
+  }
+}`), "test.tf.json")
+	if len(pDiags) > 0 {
+		t.Fatal(pDiags)
+	}
+
+	d := testPathDecoder(t, &PathContext{
+		Schema: &schema.BodySchema{
+			Blocks: map[string]*schema.BlockSchema{
+				"resource": {
+					Labels: []*schema.LabelSchema{
+						{Name: "type"},
+						{Name: "name"},
+					},
+					Body: &schema.BodySchema{
+						Attributes: map[string]*schema.AttributeSchema{
+							"tags": {Constraint: schema.Map{Elem: schema.LiteralType{Type: cty.String}}},
+						},
+					},
+				},
+			},
+		},
+		Files: map[string]*hcl.File{
+			"test.tf.json": f,
+		},
+	})
+
+	ranges, err := d.FoldingRangesInFile("test.tf.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectedRanges := []lang.FoldingRange{
+		{
+			Range: hcl.Range{
+				Filename: "test.tf.json",
+				Start:    hcl.Pos{Line: 2, Column: 3, Byte: 4},
+				End:      hcl.Pos{Line: 8, Column: 8, Byte: 114},
+			},
+			Kind:          lang.RegionFoldingRangeKind,
+			CollapsedText: `resource "aws_instance" "web" {...}`,
+		},
+		{
+			Range: hcl.Range{
+				Filename: "test.tf.json",
+				Start:    hcl.Pos{Line: 5, Column: 17, Byte: 71},
+				End:      hcl.Pos{Line: 7, Column: 10, Byte: 106},
+			},
+			Kind:          lang.RegionFoldingRangeKind,
+			CollapsedText: "{...}",
+		},
+	}
+	if diff := cmp.Diff(expectedRanges, ranges); diff != "" {
+		t.Fatalf("unexpected folding ranges: %s", diff)
+	}
+}
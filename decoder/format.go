@@ -0,0 +1,200 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package decoder
+
+import (
+	"bytes"
+
+	"github.com/hashicorp/hcl-lang/lang"
+	"github.com/hashicorp/hcl-lang/schema"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+)
+
+// FormatOptions represents schema-aware formatting options.
+//
+// Canonical formatting, incl. alignment of equals signs
+// within attribute groups, is always applied.
+type FormatOptions struct {
+	// SortAttributes orders attributes within each attribute group
+	// (attributes not separated by a blank line or a block)
+	// by BodySchema.AttributeOrder, with meta-arguments
+	// (count and for_each) first.
+	SortAttributes bool
+
+	// BlankLinesBetweenBlocks ensures there is exactly one blank line
+	// between any two adjacent blocks.
+	BlankLinesBetweenBlocks bool
+}
+
+// FormatFile returns text edits which format the whole config file.
+//
+// Comments are preserved and moved along with the attribute
+// they precede, or which they trail on the same line.
+func (d *PathDecoder) FormatFile(filename string, opts FormatOptions) ([]lang.TextEdit, error) {
+	f, err := d.fileByName(filename)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := f.Body.(*hclsyntax.Body); !ok {
+		return nil, &UnknownFileFormatError{Filename: filename}
+	}
+
+	formatted, err := d.formatSource(f.Bytes, filename, d.pathCtx.Schema, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return formatTextEdits(filename, f.Bytes, formatted), nil
+}
+
+// FormatRange returns text edits which format the top-level
+// attributes and blocks overlapping the given range.
+//
+// Any attribute or block which overlaps the range is formatted
+// as a whole, along with any other attributes in the same group,
+// which means the edits may exceed the range. The formatted
+// items are the same as if the whole file was formatted.
+func (d *PathDecoder) FormatRange(filename string, rng hcl.Range, opts FormatOptions) ([]lang.TextEdit, error) {
+	f, err := d.fileByName(filename)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := f.Body.(*hclsyntax.Body); !ok {
+		return nil, &UnknownFileFormatError{Filename: filename}
+	}
+
+	src := f.Bytes
+	file, diags := hclsyntax.ParseConfig(src, filename, hcl.InitialPos)
+	if diags.HasErrors() {
+		return nil, &InvalidSyntaxError{Filename: filename, Diagnostics: diags}
+	}
+	body := file.Body.(*hclsyntax.Body)
+	comments := commentTokensForSource(src, filename)
+
+	items := bodyItems(body)
+	first, last := -1, -1
+	for i, item := range items {
+		if !rangesOverlap(item.rng, rng) {
+			continue
+		}
+		if first == -1 {
+			first = i
+		}
+		last = i
+	}
+	if first == -1 {
+		return []lang.TextEdit{}, nil
+	}
+
+	// widen the range to whole attribute groups, so that
+	// equals signs are aligned (and attributes sorted)
+	// the same way as when formatting the whole file
+	for first > 0 && attributesInSameGroup(src, comments, items[first-1], items[first]) {
+		first--
+	}
+	for last < len(items)-1 && attributesInSameGroup(src, comments, items[last], items[last+1]) {
+		last++
+	}
+
+	start := leadCommentsStart(src, comments, items[first].rng.Start.Byte)
+	end := lineEnd(src, items[last].rng.End.Byte)
+
+	formattedSegment, err := d.formatSource(src[start:end], filename, d.pathCtx.Schema, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	formatted := make([]byte, 0, len(src))
+	formatted = append(formatted, src[:start]...)
+	formatted = append(formatted, formattedSegment...)
+	formatted = append(formatted, src[end:]...)
+
+	return formatTextEdits(filename, src, formatted), nil
+}
+
+// attributesInSameGroup returns true if both items are attributes
+// on adjacent lines, i.e. not separated by a blank line
+func attributesInSameGroup(src []byte, comments hclsyntax.Tokens, prev, next bodyItem) bool {
+	if prev.attr == nil || next.attr == nil {
+		return false
+	}
+	_, prevEnd, ok := attributeChunk(src, comments, prev.attr)
+	if !ok {
+		return false
+	}
+	nextStart, _, ok := attributeChunk(src, comments, next.attr)
+	if !ok {
+		return false
+	}
+	return prevEnd == nextStart
+}
+
+// formatSource returns formatted source of a config file
+// (or a part of it containing top-level attributes and blocks)
+func (d *PathDecoder) formatSource(src []byte, filename string, bodySchema *schema.BodySchema, opts FormatOptions) ([]byte, error) {
+	file, diags := hclsyntax.ParseConfig(src, filename, hcl.InitialPos)
+	if diags.HasErrors() {
+		return nil, &InvalidSyntaxError{Filename: filename, Diagnostics: diags}
+	}
+
+	if opts.SortAttributes && bodySchema != nil {
+		comments := commentTokensForSource(src, filename)
+		replacements := d.sortedAttributesReplacements(src, comments, file.Body.(*hclsyntax.Body), bodySchema)
+		src = applyByteReplacements(src, replacements)
+	}
+
+	src = hclwrite.Format(src)
+
+	if opts.BlankLinesBetweenBlocks {
+		file, diags := hclsyntax.ParseConfig(src, filename, hcl.InitialPos)
+		if diags.HasErrors() {
+			return nil, &InvalidSyntaxError{Filename: filename, Diagnostics: diags}
+		}
+		comments := commentTokensForSource(src, filename)
+		replacements := blockSeparatorReplacements(src, comments, file.Body.(*hclsyntax.Body))
+		src = applyByteReplacements(src, replacements)
+	}
+
+	return src, nil
+}
+
+// formatTextEdits returns a single edit replacing the lines
+// which differ between the original and the formatted source
+// or no edits if there is no difference
+func formatTextEdits(filename string, original, formatted []byte) []lang.TextEdit {
+	if bytes.Equal(original, formatted) {
+		return []lang.TextEdit{}
+	}
+
+	oldLines := bytes.SplitAfter(original, []byte("\n"))
+	newLines := bytes.SplitAfter(formatted, []byte("\n"))
+
+	prefix := 0
+	for prefix < len(oldLines) && prefix < len(newLines) &&
+		bytes.Equal(oldLines[prefix], newLines[prefix]) {
+		prefix++
+	}
+
+	suffix := 0
+	for suffix < len(oldLines)-prefix && suffix < len(newLines)-prefix &&
+		bytes.Equal(oldLines[len(oldLines)-1-suffix], newLines[len(newLines)-1-suffix]) {
+		suffix++
+	}
+
+	startByte := len(bytes.Join(oldLines[:prefix], nil))
+	endByte := len(original) - len(bytes.Join(oldLines[len(oldLines)-suffix:], nil))
+
+	return []lang.TextEdit{
+		{
+			Range: hcl.Range{
+				Filename: filename,
+				Start:    endPosForBytes(original[:startByte]),
+				End:      endPosForBytes(original[:endByte]),
+			},
+			NewText: string(bytes.Join(newLines[prefix:len(newLines)-suffix], nil)),
+		},
+	}
+}
@@ -0,0 +1,294 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package decoder
+
+import (
+	"bytes"
+	"sort"
+
+	"github.com/hashicorp/hcl-lang/schema"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+)
+
+type bodyItem struct {
+	rng   hcl.Range
+	attr  *hclsyntax.Attribute
+	block *hclsyntax.Block
+}
+
+// bodyItems returns attributes and blocks of the body
+// in the order in which they appear in the source
+func bodyItems(body *hclsyntax.Body) []bodyItem {
+	items := make([]bodyItem, 0, len(body.Attributes)+len(body.Blocks))
+	for _, attr := range body.Attributes {
+		items = append(items, bodyItem{rng: attr.Range(), attr: attr})
+	}
+	for _, block := range body.Blocks {
+		items = append(items, bodyItem{rng: block.Range(), block: block})
+	}
+	sort.Slice(items, func(i, j int) bool {
+		return items[i].rng.Start.Byte < items[j].rng.Start.Byte
+	})
+	return items
+}
+
+type byteReplacement struct {
+	start, end int
+	text       []byte
+}
+
+// applyByteReplacements applies non-overlapping replacements to src
+func applyByteReplacements(src []byte, replacements []byteReplacement) []byte {
+	if len(replacements) == 0 {
+		return src
+	}
+
+	sort.Slice(replacements, func(i, j int) bool {
+		return replacements[i].start < replacements[j].start
+	})
+
+	result := make([]byte, 0, len(src))
+	lastEnd := 0
+	for _, r := range replacements {
+		result = append(result, src[lastEnd:r.start]...)
+		result = append(result, r.text...)
+		lastEnd = r.end
+	}
+	return append(result, src[lastEnd:]...)
+}
+
+// sortedAttributesReplacements returns replacements which order
+// attributes within each attribute group of the body (and any nested
+// bodies) as declared in the schema
+func (d *PathDecoder) sortedAttributesReplacements(src []byte, comments hclsyntax.Tokens, body *hclsyntax.Body, bodySchema *schema.BodySchema) []byteReplacement {
+	replacements := make([]byteReplacement, 0)
+
+	group := make([]*hclsyntax.Attribute, 0)
+	groupEnd := -1
+	flushGroup := func() {
+		if r, ok := sortedAttributeGroup(src, comments, group, bodySchema); ok {
+			replacements = append(replacements, r)
+		}
+		group = make([]*hclsyntax.Attribute, 0)
+		groupEnd = -1
+	}
+
+	for _, item := range bodyItems(body) {
+		if item.block != nil {
+			flushGroup()
+
+			bSchema, ok := bodySchema.Blocks[item.block.Type]
+			if !ok {
+				continue
+			}
+			mergedSchema, _ := d.mergeBlockBodySchemas(item.block.AsHCLBlock(), bSchema)
+			if mergedSchema == nil {
+				continue
+			}
+			replacements = append(replacements,
+				d.sortedAttributesReplacements(src, comments, item.block.Body, mergedSchema)...)
+			continue
+		}
+
+		start, end, ok := attributeChunk(src, comments, item.attr)
+		if !ok {
+			// attribute shares its line(s) with other syntax,
+			// e.g. a single-line block, so it cannot be moved
+			flushGroup()
+			continue
+		}
+		if groupEnd != -1 && start != groupEnd {
+			// blank line or a detached comment separates groups
+			flushGroup()
+		}
+		group = append(group, item.attr)
+		groupEnd = end
+	}
+	flushGroup()
+
+	return replacements
+}
+
+func sortedAttributeGroup(src []byte, comments hclsyntax.Tokens, group []*hclsyntax.Attribute, bodySchema *schema.BodySchema) (byteReplacement, bool) {
+	if len(group) < 2 {
+		return byteReplacement{}, false
+	}
+
+	sorted := make([]*hclsyntax.Attribute, len(group))
+	copy(sorted, group)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return attributeOrderRank(bodySchema, sorted[i].Name) < attributeOrderRank(bodySchema, sorted[j].Name)
+	})
+
+	isSorted := true
+	for i := range group {
+		if group[i] != sorted[i] {
+			isSorted = false
+			break
+		}
+	}
+	if isSorted {
+		return byteReplacement{}, false
+	}
+
+	groupStart, _, _ := attributeChunk(src, comments, group[0])
+	_, groupEnd, _ := attributeChunk(src, comments, group[len(group)-1])
+
+	text := make([]byte, 0, groupEnd-groupStart)
+	for _, attr := range sorted {
+		start, end, _ := attributeChunk(src, comments, attr)
+		chunk := src[start:end]
+		text = append(text, chunk...)
+		if !bytes.HasSuffix(chunk, []byte("\n")) {
+			text = append(text, '\n')
+		}
+	}
+	if !bytes.HasSuffix(src[groupStart:groupEnd], []byte("\n")) {
+		text = bytes.TrimSuffix(text, []byte("\n"))
+	}
+
+	return byteReplacement{
+		start: groupStart,
+		end:   groupEnd,
+		text:  text,
+	}, true
+}
+
+// attributeOrderRank returns the rank of the attribute,
+// where meta-arguments come first, followed by attributes
+// in the order declared in the schema
+func attributeOrderRank(bodySchema *schema.BodySchema, name string) int {
+	if bodySchema.Extensions != nil {
+		if bodySchema.Extensions.Count && name == "count" {
+			return 0
+		}
+		if bodySchema.Extensions.ForEach && name == "for_each" {
+			return 1
+		}
+	}
+	for i, orderedName := range bodySchema.AttributeOrder {
+		if orderedName == name {
+			return i + 2
+		}
+	}
+	return len(bodySchema.AttributeOrder) + 2
+}
+
+// attributeChunk returns byte offsets of the whole lines containing
+// the attribute along with its leading comments, or false if
+// the lines contain any other syntax
+func attributeChunk(src []byte, comments hclsyntax.Tokens, attr *hclsyntax.Attribute) (int, int, bool) {
+	rng := attr.Range()
+
+	if len(bytes.TrimSpace(src[lineStart(src, rng.Start.Byte):rng.Start.Byte])) > 0 {
+		return 0, 0, false
+	}
+
+	end := lineEnd(src, rng.End.Byte)
+	trailing := bytes.TrimSpace(src[rng.End.Byte:end])
+	if len(trailing) > 0 && !isCommentAt(comments, rng.End.Byte, end) {
+		return 0, 0, false
+	}
+
+	return leadCommentsStart(src, comments, rng.Start.Byte), end, true
+}
+
+// blockSeparatorReplacements returns replacements which ensure
+// exactly one blank line between adjacent blocks in the body
+// (and any nested bodies)
+func blockSeparatorReplacements(src []byte, comments hclsyntax.Tokens, body *hclsyntax.Body) []byteReplacement {
+	replacements := make([]byteReplacement, 0)
+
+	var previous *bodyItem
+	for _, item := range bodyItems(body) {
+		item := item
+		if item.block != nil {
+			replacements = append(replacements,
+				blockSeparatorReplacements(src, comments, item.block.Body)...)
+		}
+
+		if previous != nil && previous.block != nil && item.block != nil {
+			gapStart := lineEnd(src, previous.rng.End.Byte)
+			gapEnd := leadCommentsStart(src, comments, item.rng.Start.Byte)
+			if gapStart <= gapEnd && len(bytes.TrimSpace(src[gapStart:gapEnd])) == 0 &&
+				bytes.Count(src[gapStart:gapEnd], []byte("\n")) != 1 {
+				replacements = append(replacements, byteReplacement{
+					start: gapStart,
+					end:   gapEnd,
+					text:  []byte("\n"),
+				})
+			}
+		}
+		previous = &item
+	}
+
+	return replacements
+}
+
+// commentTokensForSource returns all comment tokens in the source
+func commentTokensForSource(src []byte, filename string) hclsyntax.Tokens {
+	tokens, _ := hclsyntax.LexConfig(src, filename, hcl.InitialPos)
+	comments := make(hclsyntax.Tokens, 0)
+	for _, token := range tokens {
+		if token.Type == hclsyntax.TokenComment {
+			comments = append(comments, token)
+		}
+	}
+	return comments
+}
+
+// isCommentAt returns true if the bytes between start and end
+// contain just a single comment (and whitespace)
+func isCommentAt(comments hclsyntax.Tokens, start, end int) bool {
+	for _, comment := range comments {
+		if comment.Range.Start.Byte >= start && comment.Range.End.Byte <= end {
+			return true
+		}
+	}
+	return false
+}
+
+// leadCommentsStart returns the start of the first line of comments
+// which immediately precede (without a blank line) the given offset,
+// or the start of the line containing the offset
+func leadCommentsStart(src []byte, comments hclsyntax.Tokens, offset int) int {
+	start := lineStart(src, offset)
+
+	for i := len(comments) - 1; i >= 0; i-- {
+		comment := comments[i]
+		commentEnd := comment.Range.End.Byte
+		if commentEnd > start {
+			continue
+		}
+		// line comments include the trailing newline, others do not
+		if commentEnd != start && !(commentEnd == start-1 && src[commentEnd] == '\n') {
+			break
+		}
+		commentLineStart := lineStart(src, comment.Range.Start.Byte)
+		if len(bytes.TrimSpace(src[commentLineStart:comment.Range.Start.Byte])) > 0 {
+			// trailing comment of a preceding line
+			break
+		}
+		start = commentLineStart
+	}
+
+	return start
+}
+
+// lineStart returns the offset of the start of the line
+// containing the given offset
+func lineStart(src []byte, offset int) int {
+	return bytes.LastIndexByte(src[:offset], '\n') + 1
+}
+
+// lineEnd returns the offset after the newline ending the line
+// containing the given offset, or the end of src
+func lineEnd(src []byte, offset int) int {
+	idx := bytes.IndexByte(src[offset:], '\n')
+	if idx == -1 {
+		return len(src)
+	}
+	return offset + idx + 1
+}
@@ -0,0 +1,274 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package decoder
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/hcl-lang/lang"
+	"github.com/hashicorp/hcl-lang/schema"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/zclconf/go-cty/cty"
+)
+
+var formatTestSchema = &schema.BodySchema{
+	Blocks: map[string]*schema.BlockSchema{
+		"resource": {
+			Labels: []*schema.LabelSchema{
+				{Name: "type"},
+				{Name: "name"},
+			},
+			Body: &schema.BodySchema{
+				Extensions:     &schema.BodyExtensions{Count: true},
+				AttributeOrder: []string{"instance_type", "ami"},
+				Attributes: map[string]*schema.AttributeSchema{
+					"ami":           {Constraint: schema.LiteralType{Type: cty.String}},
+					"instance_type": {Constraint: schema.LiteralType{Type: cty.String}},
+					"tags":          {Constraint: schema.Map{Elem: schema.LiteralType{Type: cty.String}}},
+				},
+			},
+		},
+	},
+}
+
+func TestDecoder_FormatFile(t *testing.T) {
+	testCases := []struct {
+		name          string
+		cfg           string
+		opts          FormatOptions
+		expectedEdits []lang.TextEdit
+	}{
+		{
+			"already formatted",
+			`resource "aws_instance" "web" {
+  ami = "ami-123"
+}
+`,
+			FormatOptions{SortAttributes: true, BlankLinesBetweenBlocks: true},
+			[]lang.TextEdit{},
+		},
+		{
+			"canonical formatting",
+			`resource "aws_instance" "web" {
+  ami = "ami-123"
+    instance_type="t2.micro"
+}
+`,
+			FormatOptions{},
+			[]lang.TextEdit{
+				{
+					Range: hcl.Range{
+						Filename: "test.tf",
+						Start:    hcl.Pos{Line: 2, Column: 1, Byte: 32},
+						End:      hcl.Pos{Line: 4, Column: 1, Byte: 79},
+					},
+					NewText: `  ami           = "ami-123"
+  instance_type = "t2.micro"
+`,
+				},
+			},
+		},
+		{
+			"attributes ordered by schema with comments",
+			`resource "aws_instance" "web" {
+  ami = "ami-123"
+  # the instance type
+  instance_type = "t2.micro" # trailing
+  count = 2
+
+  tags = {}
+}
+`,
+			FormatOptions{SortAttributes: true},
+			[]lang.TextEdit{
+				{
+					Range: hcl.Range{
+						Filename: "test.tf",
+						Start:    hcl.Pos{Line: 2, Column: 1, Byte: 32},
+						End:      hcl.Pos{Line: 6, Column: 1, Byte: 124},
+					},
+					NewText: `  count = 2
+  # the instance type
+  instance_type = "t2.micro" # trailing
+  ami           = "ami-123"
+`,
+				},
+			},
+		},
+		{
+			"attributes not ordered without option",
+			`resource "aws_instance" "web" {
+  ami           = "ami-123"
+  instance_type = "t2.micro"
+}
+`,
+			FormatOptions{BlankLinesBetweenBlocks: true},
+			[]lang.TextEdit{},
+		},
+		{
+			"blank lines between blocks",
+			`resource "aws_instance" "web" {
+}
+resource "aws_instance" "db" {
+}
+
+
+
+# the cache
+resource "aws_instance" "cache" {
+}
+`,
+			FormatOptions{BlankLinesBetweenBlocks: true},
+			[]lang.TextEdit{
+				{
+					Range: hcl.Range{
+						Filename: "test.tf",
+						Start:    hcl.Pos{Line: 3, Column: 1, Byte: 34},
+						End:      hcl.Pos{Line: 7, Column: 1, Byte: 69},
+					},
+					NewText: `
+resource "aws_instance" "db" {
+}
+`,
+				},
+			},
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("%d-%s", i, tc.name), func(t *testing.T) {
+			f, pDiags := hclsyntax.ParseConfig([]byte(tc.cfg), "test.tf", hcl.InitialPos)
+			if len(pDiags) > 0 {
+				t.Fatal(pDiags)
+			}
+			d := testPathDecoder(t, &PathContext{
+				Schema: formatTestSchema,
+				Files: map[string]*hcl.File{
+					"test.tf": f,
+				},
+			})
+
+			edits, err := d.FormatFile("test.tf", tc.opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(tc.expectedEdits, edits); diff != "" {
+				t.Fatalf("unexpected edits: %s", diff)
+			}
+		})
+	}
+}
+
+func TestDecoder_FormatRange(t *testing.T) {
+	cfg := `resource "aws_instance" "web" {
+  ami = "ami-123"
+}
+resource "aws_instance" "db" {
+  ami = "ami-456"
+  count = 1
+}
+`
+	f, _ := hclsyntax.ParseConfig([]byte(cfg), "test.tf", hcl.InitialPos)
+	d := testPathDecoder(t, &PathContext{
+		Schema: formatTestSchema,
+		Files: map[string]*hcl.File{
+			"test.tf": f,
+		},
+	})
+
+	edits, err := d.FormatRange("test.tf", hcl.Range{
+		Filename: "test.tf",
+		Start:    hcl.Pos{Line: 5, Column: 3, Byte: 85},
+		End:      hcl.Pos{Line: 5, Column: 6, Byte: 88},
+	}, FormatOptions{SortAttributes: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectedEdits := []lang.TextEdit{
+		{
+			Range: hcl.Range{
+				Filename: "test.tf",
+				Start:    hcl.Pos{Line: 5, Column: 1, Byte: 83},
+				End:      hcl.Pos{Line: 7, Column: 1, Byte: 113},
+			},
+			NewText: `  count = 1
+  ami   = "ami-456"
+`,
+		},
+	}
+	if diff := cmp.Diff(expectedEdits, edits); diff != "" {
+		t.Fatalf("unexpected edits: %s", diff)
+	}
+}
+
+func TestDecoder_FormatRange_attributeGroup(t *testing.T) {
+	cfg := `a = 1
+bbbbbb = 2
+c = 3
+
+dd = 4
+`
+	f, _ := hclsyntax.ParseConfig([]byte(cfg), "test.tf", hcl.InitialPos)
+	d := testPathDecoder(t, &PathContext{
+		Schema: &schema.BodySchema{},
+		Files: map[string]*hcl.File{
+			"test.tf": f,
+		},
+	})
+
+	rangeEdits, err := d.FormatRange("test.tf", hcl.Range{
+		Filename: "test.tf",
+		Start:    hcl.Pos{Line: 1, Column: 1, Byte: 0},
+		End:      hcl.Pos{Line: 1, Column: 6, Byte: 5},
+	}, FormatOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	fileEdits, err := d.FormatFile("test.tf", FormatOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectedEdits := []lang.TextEdit{
+		{
+			Range: hcl.Range{
+				Filename: "test.tf",
+				Start:    hcl.Pos{Line: 1, Column: 1, Byte: 0},
+				End:      hcl.Pos{Line: 4, Column: 1, Byte: 23},
+			},
+			NewText: `a      = 1
+bbbbbb = 2
+c      = 3
+`,
+		},
+	}
+	if diff := cmp.Diff(expectedEdits, rangeEdits); diff != "" {
+		t.Fatalf("unexpected range edits: %s", diff)
+	}
+	// the attribute group is formatted the same way as the whole file
+	if diff := cmp.Diff(fileEdits, rangeEdits); diff != "" {
+		t.Fatalf("range edits differ from file edits: %s", diff)
+	}
+}
+
+func TestDecoder_FormatFile_invalidSyntax(t *testing.T) {
+	f, _ := hclsyntax.ParseConfig([]byte(`resource "aws_instance" {`), "test.tf", hcl.InitialPos)
+	d := testPathDecoder(t, &PathContext{
+		Schema: formatTestSchema,
+		Files: map[string]*hcl.File{
+			"test.tf": f,
+		},
+	})
+
+	_, err := d.FormatFile("test.tf", FormatOptions{})
+	var syntaxErr *InvalidSyntaxError
+	if !errors.As(err, &syntaxErr) {
+		t.Fatalf("expected InvalidSyntaxError, given: %#v", err)
+	}
+}
@@ -62,25 +62,79 @@ func TestDecoder_HoverAtPos_emptyBody(t *testing.T) {
 
 func TestDecoder_HoverAtPos_json(t *testing.T) {
 	f, pDiags := json.Parse([]byte(`{
-	"customblock": {
-		"label1": {}
-	}
+  "attr": "hello",
+  "customblock": {
+    "label1": {}
+  }
 }`), "test.tf.json")
 	if len(pDiags) > 0 {
 		t.Fatal(pDiags)
 	}
 
 	d := testPathDecoder(t, &PathContext{
+		Schema: &schema.BodySchema{
+			Attributes: map[string]*schema.AttributeSchema{
+				"attr": {
+					Constraint:  schema.LiteralType{Type: cty.String},
+					IsOptional:  true,
+					Description: lang.PlainText("test attribute"),
+				},
+			},
+			Blocks: map[string]*schema.BlockSchema{
+				"customblock": {
+					Labels:      []*schema.LabelSchema{{Name: "name"}},
+					Description: lang.PlainText("test block"),
+				},
+			},
+		},
 		Files: map[string]*hcl.File{
 			"test.tf.json": f,
 		},
 	})
 
 	ctx := context.Background()
-	_, err := d.HoverAtPos(ctx, "test.tf.json", hcl.InitialPos)
-	unknownFormatErr := &UnknownFileFormatError{}
-	if !errors.As(err, &unknownFormatErr) {
-		t.Fatal("expected UnknownFileFormatError for JSON body")
+
+	testCases := []struct {
+		name         string
+		pos          hcl.Pos
+		expectedData *lang.HoverData
+	}{
+		{
+			"attribute name",
+			hcl.Pos{Line: 2, Column: 5, Byte: 6},
+			&lang.HoverData{
+				Content: lang.Markdown("**attr** _optional, string_\n\ntest attribute"),
+				Range: hcl.Range{
+					Filename: "test.tf.json",
+					Start:    hcl.Pos{Line: 2, Column: 3, Byte: 4},
+					End:      hcl.Pos{Line: 2, Column: 18, Byte: 19},
+				},
+			},
+		},
+		{
+			"block type",
+			hcl.Pos{Line: 3, Column: 5, Byte: 25},
+			&lang.HoverData{
+				Content: lang.Markdown("**customblock** _Block_\n\ntest block"),
+				Range: hcl.Range{
+					Filename: "test.tf.json",
+					Start:    hcl.Pos{Line: 3, Column: 3, Byte: 23},
+					End:      hcl.Pos{Line: 3, Column: 16, Byte: 36},
+				},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			data, err := d.HoverAtPos(ctx, "test.tf.json", tc.pos)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(tc.expectedData, data); diff != "" {
+				t.Fatalf("unexpected hover data: %s", diff)
+			}
+		})
 	}
 }
 
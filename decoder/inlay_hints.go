@@ -0,0 +1,245 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package decoder
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/hcl-lang/lang"
+	"github.com/hashicorp/hcl-lang/reference"
+	"github.com/hashicorp/hcl-lang/schema"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/function"
+)
+
+// InlayHintsInRange returns inlay hints within the given range
+// of the config file, for categories enabled via
+// DecoderContext.InlayHints.
+func (d *PathDecoder) InlayHintsInRange(ctx context.Context, filename string, rng hcl.Range) ([]lang.InlayHint, error) {
+	f, err := d.fileByName(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := d.bodyForFileAndPos(filename, f, hcl.InitialPos)
+	if err != nil {
+		return nil, err
+	}
+
+	categories := d.decoderCtx.InlayHints
+	hints := make([]lang.InlayHint, 0)
+
+	if categories.ParameterNames {
+		hints = append(hints, d.parameterNameHintsForBody(body, rng)...)
+	}
+	if categories.ReferenceTypes {
+		hints = append(hints, d.referenceTypeHintsInFile(filename, rng)...)
+	}
+	if categories.DefaultValues && d.pathCtx.Schema != nil {
+		hints = append(hints, d.defaultValueHintsForBody(body, d.pathCtx.Schema, rng)...)
+	}
+
+	sort.SliceStable(hints, func(i, j int) bool {
+		return hints[i].Pos.Byte < hints[j].Pos.Byte
+	})
+
+	return hints, nil
+}
+
+// parameterNameHintsForBody returns hints with parameter names
+// in front of arguments of any function calls within the body
+func (d *PathDecoder) parameterNameHintsForBody(body *hclsyntax.Body, rng hcl.Range) []lang.InlayHint {
+	hints := make([]lang.InlayHint, 0)
+
+	for _, attr := range body.Attributes {
+		if !rangesOverlap(attr.SrcRange, rng) {
+			continue
+		}
+		hclsyntax.VisitAll(attr.Expr, func(node hclsyntax.Node) hcl.Diagnostics {
+			funcCall, ok := node.(*hclsyntax.FunctionCallExpr)
+			if !ok {
+				return nil
+			}
+			signature, ok := d.pathCtx.Functions[funcCall.Name]
+			if !ok {
+				return nil
+			}
+			hints = append(hints, parameterNameHints(funcCall, signature, rng)...)
+			return nil
+		})
+	}
+
+	for _, block := range body.Blocks {
+		if block.Body != nil && rangesOverlap(block.Range(), rng) {
+			hints = append(hints, d.parameterNameHintsForBody(block.Body, rng)...)
+		}
+	}
+
+	return hints
+}
+
+func parameterNameHints(funcCall *hclsyntax.FunctionCallExpr, signature schema.FunctionSignature, rng hcl.Range) []lang.InlayHint {
+	hints := make([]lang.InlayHint, 0)
+
+	for i, arg := range funcCall.Args {
+		var param *function.Parameter
+		if i < len(signature.Params) {
+			param = &signature.Params[i]
+		} else if signature.VarParam != nil {
+			param = signature.VarParam
+		} else {
+			break
+		}
+
+		pos := arg.StartRange().Start
+		if !rangesOverlap(hcl.Range{Filename: rng.Filename, Start: pos, End: pos}, rng) {
+			continue
+		}
+		if isArgNamedAsParam(arg, param.Name) {
+			// avoid redundant hints, e.g. abs(num)
+			continue
+		}
+
+		hints = append(hints, lang.InlayHint{
+			Pos: pos,
+			Label: []lang.InlayHintLabelPart{
+				{Value: fmt.Sprintf("%s:", param.Name)},
+			},
+			Kind:         lang.ParameterInlayHintKind,
+			Tooltip:      parameterDescription(param),
+			PaddingRight: true,
+		})
+	}
+
+	return hints
+}
+
+// isArgNamedAsParam returns true if the argument is a reference
+// whose last step matches the parameter name
+func isArgNamedAsParam(arg hclsyntax.Expression, paramName string) bool {
+	traversalExpr, ok := arg.(*hclsyntax.ScopeTraversalExpr)
+	if !ok || len(traversalExpr.Traversal) == 0 {
+		return false
+	}
+	switch step := traversalExpr.Traversal[len(traversalExpr.Traversal)-1].(type) {
+	case hcl.TraverseRoot:
+		return step.Name == paramName
+	case hcl.TraverseAttr:
+		return step.Name == paramName
+	}
+	return false
+}
+
+func parameterDescription(param *function.Parameter) lang.MarkupContent {
+	if param.Description == "" {
+		return lang.MarkupContent{}
+	}
+	return lang.Markdown(param.Description)
+}
+
+// referenceTypeHintsInFile returns hints with the type of the target
+// after each reference (origin) within the range, which matches
+// a target of a known type
+func (d *PathDecoder) referenceTypeHintsInFile(filename string, rng hcl.Range) []lang.InlayHint {
+	hints := make([]lang.InlayHint, 0)
+	hintedRanges := make(map[hcl.Range]bool, 0)
+
+	var targets referenceTargetIndex
+
+	for _, origin := range d.pathCtx.ReferenceOrigins {
+		matchableOrigin, ok := origin.(reference.MatchableOrigin)
+		if !ok {
+			continue
+		}
+		originRng := origin.OriginRange()
+		if originRng.Filename != filename || !rangesOverlap(originRng, rng) || hintedRanges[originRng] {
+			continue
+		}
+
+		if targets == nil {
+			targets = newReferenceTargetIndex(d.pathCtx.ReferenceTargets)
+		}
+		target, ok := targets.match(matchableOrigin)
+		if !ok || target.Type == cty.NilType || target.Type == cty.DynamicPseudoType {
+			continue
+		}
+
+		hintedRanges[originRng] = true
+		hints = append(hints, lang.InlayHint{
+			Pos: originRng.End,
+			Label: []lang.InlayHintLabelPart{
+				{Value: fmt.Sprintf(": %s", target.Type.FriendlyName())},
+			},
+			Kind:        lang.TypeInlayHintKind,
+			Tooltip:     target.Description,
+			PaddingLeft: true,
+		})
+	}
+
+	return hints
+}
+
+// defaultValueHintsForBody returns hints with the effective values
+// of optional attributes which are omitted from any block
+// within the body and have a default value
+func (d *PathDecoder) defaultValueHintsForBody(body *hclsyntax.Body, bodySchema *schema.BodySchema, rng hcl.Range) []lang.InlayHint {
+	hints := make([]lang.InlayHint, 0)
+
+	for _, block := range body.Blocks {
+		if block.Body == nil || !rangesOverlap(block.Range(), rng) {
+			continue
+		}
+		bSchema, ok := bodySchema.Blocks[block.Type]
+		if !ok {
+			continue
+		}
+		mergedSchema, _ := d.mergeBlockBodySchemas(block.AsHCLBlock(), bSchema)
+		if mergedSchema == nil {
+			continue
+		}
+
+		if rangesOverlap(block.OpenBraceRange, rng) {
+			hints = append(hints, defaultValueHints(block, mergedSchema)...)
+		}
+		hints = append(hints, d.defaultValueHintsForBody(block.Body, mergedSchema, rng)...)
+	}
+
+	return hints
+}
+
+func defaultValueHints(block *hclsyntax.Block, bodySchema *schema.BodySchema) []lang.InlayHint {
+	hints := make([]lang.InlayHint, 0)
+
+	for _, name := range bodySchema.AttributeNames() {
+		aSchema := bodySchema.Attributes[name]
+		if !aSchema.IsOptional {
+			continue
+		}
+		if _, declared := block.Body.Attributes[name]; declared {
+			continue
+		}
+		defaultValue, ok := aSchema.DefaultValue.(schema.DefaultValue)
+		if !ok || !defaultValue.Value.IsWhollyKnown() {
+			continue
+		}
+
+		value := strings.TrimSpace(string(hclwrite.TokensForValue(defaultValue.Value).Bytes()))
+		hints = append(hints, lang.InlayHint{
+			Pos: block.OpenBraceRange.End,
+			Label: []lang.InlayHintLabelPart{
+				{Value: fmt.Sprintf("%s = %s", name, value)},
+			},
+			Tooltip:     aSchema.Description,
+			PaddingLeft: true,
+		})
+	}
+
+	return hints
+}
@@ -0,0 +1,197 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package decoder
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/hcl-lang/lang"
+	"github.com/hashicorp/hcl-lang/reference"
+	"github.com/hashicorp/hcl-lang/schema"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/function"
+)
+
+func TestDecoder_InlayHintsInRange(t *testing.T) {
+	cfg := `instance "web" {
+  count = max(var.size, 1)
+}
+`
+	f, pDiags := hclsyntax.ParseConfig([]byte(cfg), "test.tf", hcl.InitialPos)
+	if len(pDiags) > 0 {
+		t.Fatal(pDiags)
+	}
+
+	pathCtx := &PathContext{
+		Schema: &schema.BodySchema{
+			Blocks: map[string]*schema.BlockSchema{
+				"instance": {
+					Labels: []*schema.LabelSchema{{Name: "name"}},
+					Body: &schema.BodySchema{
+						Attributes: map[string]*schema.AttributeSchema{
+							"count": {
+								Constraint: schema.AnyExpression{OfType: cty.Number},
+								IsOptional: true,
+							},
+							"monitoring": {
+								Constraint:   schema.LiteralType{Type: cty.Bool},
+								IsOptional:   true,
+								DefaultValue: schema.DefaultValue{Value: cty.False},
+								Description:  lang.PlainText("Enables detailed monitoring"),
+							},
+							"type": {
+								Constraint: schema.LiteralType{Type: cty.String},
+								IsOptional: true,
+							},
+						},
+					},
+				},
+			},
+		},
+		Files: map[string]*hcl.File{
+			"test.tf": f,
+		},
+		Functions: map[string]schema.FunctionSignature{
+			"max": {
+				Params: []function.Parameter{
+					{Name: "first", Type: cty.Number, Description: "First number"},
+				},
+				VarParam:   &function.Parameter{Name: "rest", Type: cty.Number},
+				ReturnType: cty.Number,
+			},
+		},
+		ReferenceOrigins: reference.Origins{
+			reference.LocalOrigin{
+				Addr: lang.Address{
+					lang.RootStep{Name: "var"},
+					lang.AttrStep{Name: "size"},
+				},
+				Range: hcl.Range{
+					Filename: "test.tf",
+					Start:    hcl.Pos{Line: 2, Column: 15, Byte: 31},
+					End:      hcl.Pos{Line: 2, Column: 23, Byte: 39},
+				},
+				Constraints: reference.OriginConstraints{
+					{OfType: cty.Number},
+				},
+			},
+		},
+		ReferenceTargets: reference.Targets{
+			{
+				Addr: lang.Address{
+					lang.RootStep{Name: "var"},
+					lang.AttrStep{Name: "size"},
+				},
+				Type:        cty.Number,
+				Description: lang.PlainText("Size of the cluster"),
+			},
+		},
+	}
+
+	fileRange := hcl.Range{
+		Filename: "test.tf",
+		Start:    hcl.InitialPos,
+		End:      hcl.Pos{Line: 4, Column: 1, Byte: 45},
+	}
+
+	parameterHints := []lang.InlayHint{
+		{
+			Pos:          hcl.Pos{Line: 2, Column: 15, Byte: 31},
+			Label:        []lang.InlayHintLabelPart{{Value: "first:"}},
+			Kind:         lang.ParameterInlayHintKind,
+			Tooltip:      lang.Markdown("First number"),
+			PaddingRight: true,
+		},
+		{
+			Pos:          hcl.Pos{Line: 2, Column: 25, Byte: 41},
+			Label:        []lang.InlayHintLabelPart{{Value: "rest:"}},
+			Kind:         lang.ParameterInlayHintKind,
+			PaddingRight: true,
+		},
+	}
+	typeHint := lang.InlayHint{
+		Pos:         hcl.Pos{Line: 2, Column: 23, Byte: 39},
+		Label:       []lang.InlayHintLabelPart{{Value: ": number"}},
+		Kind:        lang.TypeInlayHintKind,
+		Tooltip:     lang.PlainText("Size of the cluster"),
+		PaddingLeft: true,
+	}
+	defaultHint := lang.InlayHint{
+		Pos:         hcl.Pos{Line: 1, Column: 17, Byte: 16},
+		Label:       []lang.InlayHintLabelPart{{Value: "monitoring = false"}},
+		Tooltip:     lang.PlainText("Enables detailed monitoring"),
+		PaddingLeft: true,
+	}
+
+	testCases := []struct {
+		name          string
+		categories    InlayHintCategories
+		rng           hcl.Range
+		expectedHints []lang.InlayHint
+	}{
+		{
+			"none enabled",
+			InlayHintCategories{},
+			fileRange,
+			[]lang.InlayHint{},
+		},
+		{
+			"all enabled",
+			InlayHintCategories{
+				ParameterNames: true,
+				ReferenceTypes: true,
+				DefaultValues:  true,
+			},
+			fileRange,
+			[]lang.InlayHint{
+				defaultHint,
+				parameterHints[0],
+				typeHint,
+				parameterHints[1],
+			},
+		},
+		{
+			"parameter names only",
+			InlayHintCategories{ParameterNames: true},
+			fileRange,
+			parameterHints,
+		},
+		{
+			"range limited",
+			InlayHintCategories{
+				ParameterNames: true,
+				ReferenceTypes: true,
+				DefaultValues:  true,
+			},
+			hcl.Range{
+				Filename: "test.tf",
+				Start:    hcl.Pos{Line: 2, Column: 24, Byte: 40},
+				End:      hcl.Pos{Line: 2, Column: 27, Byte: 43},
+			},
+			[]lang.InlayHint{
+				parameterHints[1],
+			},
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("%d-%s", i, tc.name), func(t *testing.T) {
+			d := testPathDecoder(t, pathCtx)
+			d.decoderCtx.InlayHints = tc.categories
+
+			hints, err := d.InlayHintsInRange(context.Background(), "test.tf", tc.rng)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(tc.expectedHints, hints); diff != "" {
+				t.Fatalf("unexpected hints: %s", diff)
+			}
+		})
+	}
+}
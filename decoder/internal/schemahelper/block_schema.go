@@ -4,11 +4,20 @@
 package schemahelper
 
 import (
+	"slices"
+
 	"github.com/hashicorp/hcl-lang/schema"
 	"github.com/hashicorp/hcl/v2"
 )
 
 func MergeBlockBodySchemas(block *hcl.Block, blockSchema *schema.BlockSchema) (*schema.BodySchema, LookupResult) {
+	return MergeBlockBodySchemasWithEnv(block, blockSchema, nil)
+}
+
+// MergeBlockBodySchemasWithEnv is like MergeBlockBodySchemas, where
+// the given lookupEnv is used to resolve any schema.DefaultEnvVar
+// defaults of attributes used as dependency keys.
+func MergeBlockBodySchemasWithEnv(block *hcl.Block, blockSchema *schema.BlockSchema, lookupEnv schema.LookupEnvFunc) (*schema.BodySchema, LookupResult) {
 	mergedSchema := &schema.BodySchema{}
 	if blockSchema.Body != nil {
 		mergedSchema = blockSchema.Body.Copy()
@@ -26,7 +35,7 @@ func MergeBlockBodySchemas(block *hcl.Block, blockSchema *schema.BlockSchema) (*
 		mergedSchema.ImpliedOrigins = make([]schema.ImpliedOrigin, 0)
 	}
 
-	depSchema, _, result := NewBlockSchema(blockSchema).DependentBodySchema(block)
+	depSchema, _, result := NewBlockSchema(blockSchema).WithLookupEnv(lookupEnv).DependentBodySchema(block)
 	if result == LookupSuccessful || result == LookupPartiallySuccessful {
 		for name, attr := range depSchema.Attributes {
 			mergedSchema.Attributes[name] = attr
@@ -50,6 +59,14 @@ func MergeBlockBodySchemas(block *hcl.Block, blockSchema *schema.BlockSchema) (*
 		mergedSchema.TargetableAs = append(mergedSchema.TargetableAs, depSchema.TargetableAs...)
 		mergedSchema.ImpliedOrigins = append(mergedSchema.ImpliedOrigins, depSchema.ImpliedOrigins...)
 
+		// attributes of the block body come first, followed
+		// by the ones declared in the dependent body
+		for _, name := range depSchema.AttributeOrder {
+			if !slices.Contains(mergedSchema.AttributeOrder, name) {
+				mergedSchema.AttributeOrder = append(mergedSchema.AttributeOrder, name)
+			}
+		}
+
 		// TODO: avoid resetting?
 		mergedSchema.Targets = depSchema.Targets.Copy()
 
@@ -8,6 +8,7 @@ import (
 	"github.com/hashicorp/hcl-lang/lang"
 	"github.com/hashicorp/hcl-lang/schema"
 	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/ext/typeexpr"
 	"github.com/hashicorp/hcl/v2/hclsyntax"
 	"github.com/zclconf/go-cty/cty"
 )
@@ -24,20 +25,28 @@ const (
 type blockSchema struct {
 	*schema.BlockSchema
 	seenNestedDepKeys bool
+	lookupEnv         schema.LookupEnvFunc
 }
 
 func NewBlockSchema(bs *schema.BlockSchema) blockSchema {
 	return blockSchema{BlockSchema: bs}
 }
 
+// WithLookupEnv returns a copy of the block schema which
+// uses the given function to resolve schema.DefaultEnvVar
+// defaults of attributes used as dependency keys
+func (bs blockSchema) WithLookupEnv(lookupEnv schema.LookupEnvFunc) blockSchema {
+	bs.lookupEnv = lookupEnv
+	return bs
+}
+
 // DependentBodySchema finds relevant BodySchema based on dependency keys
 // such as a label or an attribute (or combination of both).
 func (bs blockSchema) DependentBodySchema(block *hcl.Block) (*schema.BodySchema, schema.DependencyKeys, LookupResult) {
 	result := LookupFailed
 
 	dks := dependencyKeysFromBlock(block, bs)
-	b, err := dks.MarshalJSON()
-	if err != nil {
+	if _, err := dks.MarshalJSON(); err != nil {
 		return nil, schema.DependencyKeys{}, result
 	}
 
@@ -45,8 +54,7 @@ func (bs blockSchema) DependentBodySchema(block *hcl.Block) (*schema.BodySchema,
 		return bs.Body, schema.DependencyKeys{}, NoDependentKeys
 	}
 
-	key := schema.SchemaKey(string(b))
-	depBodySchema, ok := bs.DependentBody[key]
+	depBodySchema, ok := bs.LookupDependentBody(dks)
 	if ok {
 		result = LookupSuccessful
 		hasDepKeys := false
@@ -57,7 +65,7 @@ func (bs blockSchema) DependentBodySchema(block *hcl.Block) (*schema.BodySchema,
 		}
 
 		if hasDepKeys && !bs.seenNestedDepKeys {
-			mergedBlockSchema := NewBlockSchema(bs.Copy())
+			mergedBlockSchema := NewBlockSchema(bs.Copy()).WithLookupEnv(bs.lookupEnv)
 			mergedBlockSchema.seenNestedDepKeys = true
 			mergedBlockSchema.Body = depBodySchema
 			if depBodySchema, dks, nestedOk := mergedBlockSchema.DependentBodySchema(block); nestedOk == LookupSuccessful {
@@ -131,13 +139,38 @@ func dependencyKeysFromBlock(block *hcl.Block, blockSchema blockSchema) schema.D
 					continue
 				}
 			} else if attrSchema.DefaultValue != nil {
-				defaultValue, ok := attrSchema.DefaultValue.(schema.DefaultValue)
-				if !ok {
-					// TODO: DefaultKeyword
-					// TODO: DefaultTypeDeclaration
+				defaultCtx := schema.DefaultContext{
+					AttributeValue: staticAttributeValueFunc(content.Attributes),
+					LookupEnv:      blockSchema.lookupEnv,
+				}
+				switch def := attrSchema.ResolveDefault(defaultCtx).(type) {
+				case schema.DefaultValue:
+					value = def.Value
+				case schema.DefaultKeyword:
+					// declared keywords are represented as addresses
+					dk.Attributes = append(dk.Attributes, schema.AttributeDependent{
+						Name: name,
+						Expr: schema.ExpressionValue{
+							Address: lang.Address{lang.RootStep{Name: def.Keyword}},
+						},
+					})
+					continue
+				case schema.DefaultTypeDeclaration:
+					addr, ok := typeDeclarationAddress(def.Type)
+					if !ok {
+						// only type keywords are represented as addresses
+						continue
+					}
+					dk.Attributes = append(dk.Attributes, schema.AttributeDependent{
+						Name: name,
+						Expr: schema.ExpressionValue{
+							Address: addr,
+						},
+					})
+					continue
+				default:
 					continue
 				}
-				value = defaultValue.Value
 			} else {
 				// dependent attribute not present
 				continue
@@ -153,3 +186,29 @@ func dependencyKeysFromBlock(block *hcl.Block, blockSchema blockSchema) schema.D
 	}
 	return dk
 }
+
+// staticAttributeValueFunc returns a function which provides
+// static values of the given attributes
+func staticAttributeValueFunc(attrs hcl.Attributes) func(string) (cty.Value, bool) {
+	return func(name string) (cty.Value, bool) {
+		attr, ok := attrs[name]
+		if !ok {
+			return cty.NilVal, false
+		}
+		value, diags := attr.Expr.Value(nil)
+		if diags.HasErrors() {
+			return cty.NilVal, false
+		}
+		return value, true
+	}
+}
+
+// typeDeclarationAddress returns the address representing
+// the given type in a type declaration, such as "string",
+// if the type can be declared as a single keyword
+func typeDeclarationAddress(typ cty.Type) (lang.Address, bool) {
+	if !typ.IsPrimitiveType() && typ != cty.DynamicPseudoType {
+		return nil, false
+	}
+	return lang.Address{lang.RootStep{Name: typeexpr.TypeString(typ)}}, true
+}
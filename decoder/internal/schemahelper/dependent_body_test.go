@@ -430,6 +430,135 @@ func TestBodySchema_DependentBodySchema_attributes(t *testing.T) {
 	}
 }
 
+func TestBodySchema_DependentBodySchema_defaults(t *testing.T) {
+	depKey := func(expr schema.ExpressionValue) schema.SchemaKey {
+		return schema.NewSchemaKey(schema.DependencyKeys{
+			Attributes: []schema.AttributeDependent{
+				{Name: "depattr", Expr: expr},
+			},
+		})
+	}
+	lookupEnv := func(name string) (string, bool) {
+		if name == "TEST_DEP_ATTR" {
+			return "from-env", true
+		}
+		return "", false
+	}
+
+	testCases := []struct {
+		name         string
+		attributes   hclsyntax.Attributes
+		defaultValue schema.Default
+		lookupEnv    schema.LookupEnvFunc
+		expectedKey  schema.SchemaKey
+	}{
+		{
+			"keyword",
+			hclsyntax.Attributes{},
+			schema.DefaultKeyword{Keyword: "auto"},
+			nil,
+			depKey(schema.ExpressionValue{
+				Address: lang.Address{lang.RootStep{Name: "auto"}},
+			}),
+		},
+		{
+			"type declaration",
+			hclsyntax.Attributes{},
+			schema.DefaultTypeDeclaration{Type: cty.DynamicPseudoType},
+			nil,
+			depKey(schema.ExpressionValue{
+				Address: lang.Address{lang.RootStep{Name: "any"}},
+			}),
+		},
+		{
+			"env var",
+			hclsyntax.Attributes{},
+			schema.DefaultEnvVar{
+				Name:     "TEST_DEP_ATTR",
+				Fallback: schema.DefaultValue{Value: cty.StringVal("fallback")},
+			},
+			lookupEnv,
+			depKey(schema.ExpressionValue{Static: cty.StringVal("from-env")}),
+		},
+		{
+			"env var without lookup",
+			hclsyntax.Attributes{},
+			schema.DefaultEnvVar{
+				Name:     "TEST_DEP_ATTR",
+				Fallback: schema.DefaultValue{Value: cty.StringVal("fallback")},
+			},
+			nil,
+			depKey(schema.ExpressionValue{Static: cty.StringVal("fallback")}),
+		},
+		{
+			"attribute dependent",
+			hclsyntax.Attributes{
+				"engine": {
+					Name: "engine",
+					Expr: &hclsyntax.LiteralValueExpr{
+						Val: cty.StringVal("postgres"),
+					},
+				},
+			},
+			schema.DefaultAttributeDependent{
+				Name: "engine",
+				Cases: []schema.DefaultCase{
+					{
+						Value:   cty.StringVal("mysql"),
+						Default: schema.DefaultValue{Value: cty.StringVal("mysql-default")},
+					},
+					{
+						Value:   cty.StringVal("postgres"),
+						Default: schema.DefaultValue{Value: cty.StringVal("postgres-default")},
+					},
+				},
+			},
+			nil,
+			depKey(schema.ExpressionValue{Static: cty.StringVal("postgres-default")}),
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("%d-%s", i, tc.name), func(t *testing.T) {
+			expectedBody := &schema.BodySchema{
+				Attributes: map[string]*schema.AttributeSchema{
+					"matched": {Constraint: schema.LiteralType{Type: cty.String}},
+				},
+			}
+			testSchema := NewBlockSchema(&schema.BlockSchema{
+				Body: &schema.BodySchema{
+					Attributes: map[string]*schema.AttributeSchema{
+						"engine": {
+							Constraint: schema.LiteralType{Type: cty.String},
+						},
+						"depattr": {
+							Constraint:   schema.AnyExpression{OfType: cty.String},
+							IsDepKey:     true,
+							DefaultValue: tc.defaultValue,
+						},
+					},
+				},
+				DependentBody: map[schema.SchemaKey]*schema.BodySchema{
+					tc.expectedKey: expectedBody,
+				},
+			}).WithLookupEnv(tc.lookupEnv)
+
+			block := &hcl.Block{
+				Body: &hclsyntax.Body{
+					Attributes: tc.attributes,
+				},
+			}
+			bodySchema, _, result := testSchema.DependentBodySchema(block)
+			if result != LookupSuccessful {
+				t.Fatalf("expected to find body schema for key %s", tc.expectedKey)
+			}
+			if diff := cmp.Diff(expectedBody, bodySchema, ctydebug.CmpOptions); diff != "" {
+				t.Fatalf("unexpected body schema: %s", diff)
+			}
+		})
+	}
+}
+
 func TestBodySchema_DependentBodySchema_partialMergeFailure(t *testing.T) {
 	testSchema := NewBlockSchema(&schema.BlockSchema{
 		Labels: []*schema.LabelSchema{
@@ -892,3 +1021,111 @@ var testSchemaWithAttributesWithDefaultValue = NewBlockSchema(&schema.BlockSchem
 		},
 	},
 })
+
+type testDependentBodyResolver struct {
+	bodies   map[schema.SchemaKey]*schema.BodySchema
+	resolved []schema.SchemaKey
+}
+
+func (r *testDependentBodyResolver) ResolveDependentBody(keys schema.DependencyKeys) (*schema.BodySchema, bool) {
+	key := schema.NewSchemaKey(keys)
+	r.resolved = append(r.resolved, key)
+	bodySchema, ok := r.bodies[key]
+	return bodySchema, ok
+}
+
+func (r *testDependentBodyResolver) ListDependentBodies(int, string, uint) ([]schema.DependentBodySummary, bool) {
+	return nil, true
+}
+
+func TestBodySchema_DependentBodySchema_resolver(t *testing.T) {
+	labelKey := schema.NewSchemaKey(schema.DependencyKeys{
+		Labels: []schema.LabelDependent{
+			{Index: 0, Value: "theircloud"},
+		},
+	})
+	nestedKey := schema.NewSchemaKey(schema.DependencyKeys{
+		Labels: []schema.LabelDependent{
+			{Index: 0, Value: "theircloud"},
+		},
+		Attributes: []schema.AttributeDependent{
+			{
+				Name: "backend",
+				Expr: schema.ExpressionValue{Static: cty.StringVal("remote")},
+			},
+		},
+	})
+	resolver := &testDependentBodyResolver{
+		bodies: map[schema.SchemaKey]*schema.BodySchema{
+			labelKey: {
+				Attributes: map[string]*schema.AttributeSchema{
+					"backend": {
+						Constraint: schema.LiteralType{Type: cty.String},
+						IsDepKey:   true,
+					},
+				},
+			},
+			nestedKey: {
+				Attributes: map[string]*schema.AttributeSchema{
+					"url": {Constraint: schema.LiteralType{Type: cty.String}},
+				},
+			},
+		},
+	}
+	bSchema := &schema.BlockSchema{
+		Labels: []*schema.LabelSchema{
+			{
+				Name:     "type",
+				IsDepKey: true,
+			},
+			{
+				Name: "name",
+			},
+		},
+		DependentBody: map[schema.SchemaKey]*schema.BodySchema{
+			schema.NewSchemaKey(schema.DependencyKeys{
+				Labels: []schema.LabelDependent{
+					{Index: 0, Value: "mycloud"},
+				},
+			}): {},
+		},
+		DependentBodyResolver: resolver,
+	}
+
+	f, pDiags := hclsyntax.ParseConfig([]byte(`backend = "remote"
+`), "test.tf", hcl.InitialPos)
+	if len(pDiags) > 0 {
+		t.Fatal(pDiags)
+	}
+	block := &hcl.Block{
+		Labels: []string{"theircloud", "blah"},
+		Body:   f.Body,
+	}
+
+	bodySchema, _, result := NewBlockSchema(bSchema).DependentBodySchema(block)
+	if result != LookupSuccessful {
+		t.Fatal("expected to resolve body schema for 'theircloud' label")
+	}
+	expectedSchema := &schema.BodySchema{
+		Attributes: map[string]*schema.AttributeSchema{
+			"url": {Constraint: schema.LiteralType{Type: cty.String}},
+		},
+	}
+	if diff := cmp.Diff(expectedSchema, bodySchema, ctydebug.CmpOptions); diff != "" {
+		t.Fatalf("unexpected body schema: %s", diff)
+	}
+	if diff := cmp.Diff([]schema.SchemaKey{labelKey, nestedKey}, resolver.resolved); diff != "" {
+		t.Fatalf("unexpected resolved keys: %s", diff)
+	}
+
+	// bodies declared upfront take precedence over the resolver
+	resolver.resolved = nil
+	block.Labels = []string{"mycloud", "blah"}
+	_, _, result = NewBlockSchema(bSchema).DependentBodySchema(block)
+	if result != LookupSuccessful {
+		t.Fatal("expected to find body schema for 'mycloud' label")
+	}
+	if len(resolver.resolved) != 0 {
+		t.Fatalf("expected resolver not to be asked, given: %q", resolver.resolved)
+	}
+}
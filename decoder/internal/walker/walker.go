@@ -112,7 +112,7 @@ func Walk(ctx context.Context, node hclsyntax.Node, nodeSchema schema.Schema, w
 		var blockBodySchema schema.Schema = nil
 		bSchema, ok := nodeSchema.(*schema.BlockSchema)
 		if ok && bSchema.Body != nil {
-			mergedSchema, result := schemahelper.MergeBlockBodySchemas(nodeType.AsHCLBlock(), bSchema)
+			mergedSchema, result := schemahelper.MergeBlockBodySchemasWithEnv(nodeType.AsHCLBlock(), bSchema, schemacontext.LookupEnv(ctx))
 			if result == schemahelper.LookupFailed || result == schemahelper.LookupPartiallySuccessful {
 				blockCtx = schemacontext.WithUnknownSchema(blockCtx)
 			}
@@ -0,0 +1,259 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package decoder
+
+import (
+	"bytes"
+	encjson "encoding/json"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/hashicorp/hcl-lang/decoder/internal/ast"
+	"github.com/hashicorp/hcl-lang/decoder/internal/schemahelper"
+	"github.com/hashicorp/hcl-lang/schema"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/hcl/v2/json"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// syntaxBodyForJSONFile returns the JSON body of the file
+// represented as *hclsyntax.Body
+func (d *PathDecoder) syntaxBodyForJSONFile(filename string, f *hcl.File) *hclsyntax.Body {
+	rng := hcl.Range{
+		Filename: filename,
+		Start:    hcl.InitialPos,
+		End:      endPosForBytes(f.Bytes),
+	}
+	return syntaxBodyForJSON(f.Body, f.Bytes, d.pathCtx.Schema, rng, d.decoderCtx.LookupEnv)
+}
+
+// syntaxBodyForJSON represents the JSON body as *hclsyntax.Body,
+// so that the same logic can be applied to both syntaxes.
+//
+// Since JSON is ambiguous without schema, the body is decoded via
+// the schema and any properties unknown to the schema are omitted.
+// All ranges point to the original JSON, e.g. attribute names
+// include the quotes.
+func syntaxBodyForJSON(body hcl.Body, src []byte, bodySchema *schema.BodySchema, rng hcl.Range, lookupEnv schema.LookupEnvFunc) *hclsyntax.Body {
+	syntaxBody := &hclsyntax.Body{
+		Attributes: make(hclsyntax.Attributes, 0),
+		Blocks:     make(hclsyntax.Blocks, 0),
+		SrcRange:   rng,
+		EndRange: hcl.Range{
+			Filename: rng.Filename,
+			Start:    rng.End,
+			End:      rng.End,
+		},
+	}
+
+	if bodySchema == nil {
+		return syntaxBody
+	}
+
+	content := ast.DecodeBody(body, bodySchema)
+
+	for name, attr := range content.Attributes {
+		exprRng := attr.Expr.Range()
+		syntaxBody.Attributes[name] = &hclsyntax.Attribute{
+			Name:      name,
+			Expr:      syntaxExprForJSON(attr.Expr, src),
+			SrcRange:  attr.Range,
+			NameRange: attr.NameRange,
+			// JSON has colon in place of equals sign, which is not exposed
+			EqualsRange: hcl.Range{
+				Filename: exprRng.Filename,
+				Start:    exprRng.Start,
+				End:      exprRng.Start,
+			},
+		}
+	}
+
+	for _, block := range content.Blocks {
+		var nestedSchema *schema.BodySchema
+		if bSchema, ok := bodySchema.Blocks[block.Type]; ok {
+			nestedSchema, _ = schemahelper.MergeBlockBodySchemasWithEnv(block.Block, bSchema, lookupEnv)
+		}
+
+		// DefRange of JSON block points to the opening brace
+		// and MissingItemRange to the closing brace
+		closeRng := block.Body.MissingItemRange()
+		syntaxBody.Blocks = append(syntaxBody.Blocks, &hclsyntax.Block{
+			Type:            block.Type,
+			Labels:          block.Labels,
+			Body:            syntaxBodyForJSON(block.Body, src, nestedSchema, hcl.RangeBetween(block.DefRange, closeRng), lookupEnv),
+			TypeRange:       block.TypeRange,
+			LabelRanges:     block.LabelRanges,
+			OpenBraceRange:  block.DefRange,
+			CloseBraceRange: closeRng,
+		})
+	}
+
+	sort.SliceStable(syntaxBody.Blocks, func(i, j int) bool {
+		return syntaxBody.Blocks[i].TypeRange.Start.Byte < syntaxBody.Blocks[j].TypeRange.Start.Byte
+	})
+
+	return syntaxBody
+}
+
+// syntaxExprForJSON represents the JSON expression as hclsyntax.Expression.
+//
+// Arrays and objects are represented as tuple and object constructors.
+// Strings are parsed as templates, as they are interpreted by HCL.
+func syntaxExprForJSON(expr hcl.Expression, src []byte) hclsyntax.Expression {
+	rng := expr.Range()
+
+	jsonExpr, ok := expr.(jsonExpression)
+	if !ok || !json.IsJSONExpression(expr) {
+		return &hclsyntax.LiteralValueExpr{
+			Val:      cty.DynamicVal,
+			SrcRange: rng,
+		}
+	}
+
+	openRng := hcl.Range{
+		Filename: rng.Filename,
+		Start:    rng.Start,
+		End: hcl.Pos{
+			Line:   rng.Start.Line,
+			Column: rng.Start.Column + 1,
+			Byte:   rng.Start.Byte + 1,
+		},
+	}
+
+	if items := jsonExpr.ExprList(); items != nil {
+		exprs := make([]hclsyntax.Expression, len(items))
+		for i, item := range items {
+			exprs[i] = syntaxExprForJSON(item, src)
+		}
+		return &hclsyntax.TupleConsExpr{
+			Exprs:     exprs,
+			SrcRange:  rng,
+			OpenRange: openRng,
+		}
+	}
+
+	if items := jsonExpr.ExprMap(); items != nil {
+		objItems := make([]hclsyntax.ObjectConsItem, len(items))
+		for i, item := range items {
+			keyExpr := syntaxExprForJSON(item.Key, src)
+			objItems[i] = hclsyntax.ObjectConsItem{
+				KeyExpr: &hclsyntax.ObjectConsKeyExpr{
+					Wrapped: keyExpr,
+				},
+				ValueExpr: syntaxExprForJSON(item.Value, src),
+			}
+		}
+		return &hclsyntax.ObjectConsExpr{
+			Items:     objItems,
+			SrcRange:  rng,
+			OpenRange: openRng,
+		}
+	}
+
+	val, diags := jsonExpr.Value(nil)
+	if diags.HasErrors() {
+		return &hclsyntax.LiteralValueExpr{
+			Val:      cty.DynamicVal,
+			SrcRange: rng,
+		}
+	}
+
+	if val.Type() == cty.String && !val.IsNull() {
+		if tplExpr, ok := jsonStringTemplate(src, rng, val.AsString()); ok {
+			return tplExpr
+		}
+	}
+
+	return &hclsyntax.LiteralValueExpr{
+		Val:      val,
+		SrcRange: rng,
+	}
+}
+
+// jsonStringTemplate parses the JSON string as a template, the same way
+// HCL interprets it, with ranges pointing to the original JSON source.
+//
+// The template is parsed from the raw source between the quotes,
+// so that escape sequences do not shift ranges. Escape sequences
+// are only decoded within literal parts of the template and
+// false is returned if the string cannot be represented this way,
+// e.g. when interpolation itself contains escape sequences.
+func jsonStringTemplate(src []byte, rng hcl.Range, value string) (hclsyntax.Expression, bool) {
+	if rng.End.Byte-rng.Start.Byte < 2 || rng.End.Byte > len(src) {
+		return nil, false
+	}
+	raw := src[rng.Start.Byte+1 : rng.End.Byte-1]
+
+	tplExpr, diags := hclsyntax.ParseTemplate(raw, rng.Filename, hcl.Pos{
+		Line: rng.Start.Line,
+		// skip over the opening quote mark
+		Column: rng.Start.Column + 1,
+		Byte:   rng.Start.Byte + 1,
+	})
+	if diags.HasErrors() {
+		return nil, false
+	}
+
+	if string(raw) != value {
+		// escape sequences must not introduce or hide any interpolation
+		if strings.Count(string(raw), "${") != strings.Count(value, "${") ||
+			strings.Count(string(raw), "%{") != strings.Count(value, "%{") {
+			return nil, false
+		}
+		tpl, ok := tplExpr.(*hclsyntax.TemplateExpr)
+		if !ok {
+			return nil, false
+		}
+		for i, part := range tpl.Parts {
+			lit, ok := part.(*hclsyntax.LiteralValueExpr)
+			if !ok {
+				if bytes.ContainsRune(part.Range().SliceBytes(src), '\\') {
+					return nil, false
+				}
+				continue
+			}
+			if lit.Val.Type() != cty.String || lit.Val.IsNull() {
+				return nil, false
+			}
+			var decoded string
+			err := encjson.Unmarshal([]byte(`"`+lit.Val.AsString()+`"`), &decoded)
+			if err != nil {
+				return nil, false
+			}
+			tpl.Parts[i] = &hclsyntax.LiteralValueExpr{
+				Val:      cty.StringVal(decoded),
+				SrcRange: lit.SrcRange,
+			}
+		}
+	}
+
+	// quoted templates in native syntax include the quotes
+	switch tpl := tplExpr.(type) {
+	case *hclsyntax.TemplateExpr:
+		tpl.SrcRange = rng
+	case *hclsyntax.TemplateWrapExpr:
+		tpl.SrcRange = rng
+	}
+
+	return tplExpr, true
+}
+
+// endPosForBytes returns position after the last character
+func endPosForBytes(src []byte) hcl.Pos {
+	pos := hcl.InitialPos
+	for len(src) > 0 {
+		r, size := utf8.DecodeRune(src)
+		src = src[size:]
+		pos.Byte += size
+		if r == '\n' {
+			pos.Line++
+			pos.Column = 1
+			continue
+		}
+		pos.Column++
+	}
+	return pos
+}
@@ -0,0 +1,161 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package decoder
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/hcl/v2/json"
+	"github.com/zclconf/go-cty-debug/ctydebug"
+	"github.com/zclconf/go-cty/cty"
+)
+
+func TestSyntaxExprForJSON_template(t *testing.T) {
+	testCases := []struct {
+		name         string
+		src          string
+		expectedExpr hclsyntax.Expression
+	}{
+		{
+			"interpolation",
+			`{"attr": "a${var.foo}"}`,
+			&hclsyntax.TemplateExpr{
+				Parts: []hclsyntax.Expression{
+					&hclsyntax.LiteralValueExpr{
+						Val: cty.StringVal("a"),
+						SrcRange: hcl.Range{
+							Filename: "test.tf.json",
+							Start:    hcl.Pos{Line: 1, Column: 11, Byte: 10},
+							End:      hcl.Pos{Line: 1, Column: 12, Byte: 11},
+						},
+					},
+					&hclsyntax.ScopeTraversalExpr{
+						Traversal: hcl.Traversal{
+							hcl.TraverseRoot{
+								Name: "var",
+								SrcRange: hcl.Range{
+									Filename: "test.tf.json",
+									Start:    hcl.Pos{Line: 1, Column: 14, Byte: 13},
+									End:      hcl.Pos{Line: 1, Column: 17, Byte: 16},
+								},
+							},
+							hcl.TraverseAttr{
+								Name: "foo",
+								SrcRange: hcl.Range{
+									Filename: "test.tf.json",
+									Start:    hcl.Pos{Line: 1, Column: 17, Byte: 16},
+									End:      hcl.Pos{Line: 1, Column: 21, Byte: 20},
+								},
+							},
+						},
+						SrcRange: hcl.Range{
+							Filename: "test.tf.json",
+							Start:    hcl.Pos{Line: 1, Column: 14, Byte: 13},
+							End:      hcl.Pos{Line: 1, Column: 21, Byte: 20},
+						},
+					},
+				},
+				SrcRange: hcl.Range{
+					Filename: "test.tf.json",
+					Start:    hcl.Pos{Line: 1, Column: 10, Byte: 9},
+					End:      hcl.Pos{Line: 1, Column: 23, Byte: 22},
+				},
+			},
+		},
+		{
+			"escaped quote before interpolation",
+			`{"attr": "a\"b${var.foo}"}`,
+			&hclsyntax.TemplateExpr{
+				Parts: []hclsyntax.Expression{
+					&hclsyntax.LiteralValueExpr{
+						Val: cty.StringVal(`a"b`),
+						SrcRange: hcl.Range{
+							Filename: "test.tf.json",
+							Start:    hcl.Pos{Line: 1, Column: 11, Byte: 10},
+							End:      hcl.Pos{Line: 1, Column: 15, Byte: 14},
+						},
+					},
+					&hclsyntax.ScopeTraversalExpr{
+						Traversal: hcl.Traversal{
+							hcl.TraverseRoot{
+								Name: "var",
+								SrcRange: hcl.Range{
+									Filename: "test.tf.json",
+									Start:    hcl.Pos{Line: 1, Column: 17, Byte: 16},
+									End:      hcl.Pos{Line: 1, Column: 20, Byte: 19},
+								},
+							},
+							hcl.TraverseAttr{
+								Name: "foo",
+								SrcRange: hcl.Range{
+									Filename: "test.tf.json",
+									Start:    hcl.Pos{Line: 1, Column: 20, Byte: 19},
+									End:      hcl.Pos{Line: 1, Column: 24, Byte: 23},
+								},
+							},
+						},
+						SrcRange: hcl.Range{
+							Filename: "test.tf.json",
+							Start:    hcl.Pos{Line: 1, Column: 17, Byte: 16},
+							End:      hcl.Pos{Line: 1, Column: 24, Byte: 23},
+						},
+					},
+				},
+				SrcRange: hcl.Range{
+					Filename: "test.tf.json",
+					Start:    hcl.Pos{Line: 1, Column: 10, Byte: 9},
+					End:      hcl.Pos{Line: 1, Column: 26, Byte: 25},
+				},
+			},
+		},
+		{
+			"escaped quote within interpolation",
+			`{"attr": "${upper(\"foo\")}"}`,
+			&hclsyntax.LiteralValueExpr{
+				Val: cty.StringVal(`${upper("foo")}`),
+				SrcRange: hcl.Range{
+					Filename: "test.tf.json",
+					Start:    hcl.Pos{Line: 1, Column: 10, Byte: 9},
+					End:      hcl.Pos{Line: 1, Column: 29, Byte: 28},
+				},
+			},
+		},
+		{
+			"escaped interpolation sequence",
+			`{"attr": "\u0024{var.foo}"}`,
+			&hclsyntax.LiteralValueExpr{
+				Val: cty.StringVal(`${var.foo}`),
+				SrcRange: hcl.Range{
+					Filename: "test.tf.json",
+					Start:    hcl.Pos{Line: 1, Column: 10, Byte: 9},
+					End:      hcl.Pos{Line: 1, Column: 27, Byte: 26},
+				},
+			},
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("%2d-%s", i, tc.name), func(t *testing.T) {
+			f, diags := json.Parse([]byte(tc.src), "test.tf.json")
+			if len(diags) > 0 {
+				t.Fatal(diags)
+			}
+			attrs, diags := f.Body.JustAttributes()
+			if len(diags) > 0 {
+				t.Fatal(diags)
+			}
+
+			expr := syntaxExprForJSON(attrs["attr"].Expr, f.Bytes)
+			if diff := cmp.Diff(tc.expectedExpr, expr, ctydebug.CmpOptions,
+				cmpopts.IgnoreUnexported(hcl.TraverseRoot{}, hcl.TraverseAttr{})); diff != "" {
+				t.Fatalf("unexpected expression: %s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,252 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package decoder
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/hcl-lang/decoder/internal/schemahelper"
+	"github.com/hashicorp/hcl-lang/lang"
+	"github.com/hashicorp/hcl-lang/schema"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// jsonCompletionAtPos is the JSON equivalent of completionAtPos,
+// where the body is represented via syntaxBodyForJSON.
+//
+// Candidates for attributes and blocks are quoted and values
+// are represented as JSON, e.g. blocks as nested objects.
+func (d *PathDecoder) jsonCompletionAtPos(ctx context.Context, body *hclsyntax.Body, outerBodyRng hcl.Range, bodySchema *schema.BodySchema, pos hcl.Pos) (lang.Candidates, error) {
+	if bodySchema == nil {
+		return lang.ZeroCandidates(), nil
+	}
+
+	filename := body.Range().Filename
+
+	for _, attr := range body.Attributes {
+		if d.isPosInsideAttrExpr(attr, pos) {
+			if bodySchema.Extensions != nil && bodySchema.Extensions.SelfRefs {
+				ctx = schema.WithActiveSelfRefs(ctx)
+			}
+			aSchema, ok := jsonAttributeSchema(bodySchema, attr.Name)
+			if !ok {
+				return lang.ZeroCandidates(), nil
+			}
+			return d.jsonAttrValueCompletionAtPos(ctx, attr, aSchema, outerBodyRng, pos)
+		}
+		if attr.NameRange.ContainsPos(pos) {
+			return d.jsonBodySchemaCandidates(ctx, body, bodySchema, jsonKeyPrefixRange(attr.NameRange, pos), attr.Range()), nil
+		}
+	}
+
+	for _, block := range body.Blocks {
+		if !block.Range().ContainsPos(pos) {
+			continue
+		}
+
+		blockSchema, ok := bodySchema.Blocks[block.Type]
+		if !ok {
+			return lang.ZeroCandidates(), &PositionalError{
+				Filename: filename,
+				Pos:      pos,
+				Msg:      fmt.Sprintf("unknown block type %q", block.Type),
+			}
+		}
+
+		if block.TypeRange.ContainsPos(pos) {
+			return d.jsonBodySchemaCandidates(ctx, body, bodySchema, jsonKeyPrefixRange(block.TypeRange, pos), block.Range()), nil
+		}
+
+		for i, labelRange := range block.LabelRanges {
+			if !labelRange.ContainsPos(pos) {
+				continue
+			}
+			if i+1 > len(blockSchema.Labels) {
+				return lang.ZeroCandidates(), &PositionalError{
+					Filename: filename,
+					Pos:      pos,
+					Msg:      fmt.Sprintf("unexpected label (%d) %q", i, block.Labels[i]),
+				}
+			}
+			if !blockSchema.Labels[i].Completable {
+				return lang.ZeroCandidates(), nil
+			}
+
+			editRng := jsonKeyInnerRange(labelRange)
+			return d.labelCandidatesFromDependentSchema(i, blockSchema,
+				jsonKeyPrefixRange(labelRange, pos), editRng, block)
+		}
+
+		if block.Body != nil && block.Body.Range().ContainsPos(pos) {
+			mergedSchema, _ := d.mergeBlockBodySchemas(block.AsHCLBlock(), blockSchema)
+			return d.jsonCompletionAtPos(ctx, block.Body, outerBodyRng, mergedSchema, pos)
+		}
+
+		return lang.ZeroCandidates(), nil
+	}
+
+	rng := hcl.Range{
+		Filename: filename,
+		Start:    pos,
+		End:      pos,
+	}
+
+	return d.jsonBodySchemaCandidates(ctx, body, bodySchema, rng, rng), nil
+}
+
+// jsonBodySchemaCandidates returns the same candidates as bodySchemaCandidates
+// with text edits representing JSON properties
+func (d *PathDecoder) jsonBodySchemaCandidates(ctx context.Context, body *hclsyntax.Body, bodySchema *schema.BodySchema, prefixRng, editRng hcl.Range) lang.Candidates {
+	candidates := d.bodySchemaCandidates(ctx, body, bodySchema, prefixRng, editRng)
+
+	// new properties need to be separated from any neighbouring ones,
+	// unlike existing keys being replaced
+	before, after := "", ""
+	if editRng.Start.Byte == editRng.End.Byte {
+		itemRanges := make([]hcl.Range, 0, len(body.Attributes)+len(body.Blocks))
+		for _, attr := range body.Attributes {
+			itemRanges = append(itemRanges, hcl.RangeBetween(attr.NameRange, attr.Expr.Range()))
+		}
+		for _, block := range body.Blocks {
+			itemRanges = append(itemRanges, block.Range())
+		}
+		before, after = d.jsonItemSeparators(editRng.Filename, editRng.Start, itemRanges)
+	}
+
+	for i, candidate := range candidates.List {
+		switch candidate.Kind {
+		case lang.AttributeCandidateKind:
+			aSchema, ok := jsonAttributeSchema(bodySchema, candidate.Label)
+			if !ok {
+				continue
+			}
+			candidates.List[i].TextEdit.NewText = before + fmt.Sprintf("%q", candidate.Label) + after
+			candidates.List[i].TextEdit.Snippet = before + fmt.Sprintf("%q: %s",
+				candidate.Label, jsonSnippetForConstraint(aSchema.Constraint, 1)) + after
+		case lang.BlockCandidateKind:
+			bSchema, ok := bodySchema.Blocks[candidate.Label]
+			if !ok {
+				continue
+			}
+			candidates.List[i].TextEdit.NewText = before + fmt.Sprintf("%q", candidate.Label) + after
+			candidates.List[i].TextEdit.Snippet = before + jsonSnippetForBlock(candidate.Label, bSchema) + after
+		}
+	}
+
+	return candidates
+}
+
+// jsonAttributeSchema returns schema of the attribute, accounting
+// for any extensions and AnyAttribute (labelled "name" by bodySchemaCandidates)
+func jsonAttributeSchema(bodySchema *schema.BodySchema, name string) (*schema.AttributeSchema, bool) {
+	if aSchema, ok := bodySchema.Attributes[name]; ok {
+		return aSchema, true
+	}
+	if bodySchema.Extensions != nil && bodySchema.Extensions.Count && name == "count" {
+		return schemahelper.CountAttributeSchema(), true
+	}
+	if bodySchema.Extensions != nil && bodySchema.Extensions.ForEach && name == "for_each" {
+		return schemahelper.ForEachAttributeSchema(), true
+	}
+	if bodySchema.AnyAttribute != nil {
+		return bodySchema.AnyAttribute, true
+	}
+	return nil, false
+}
+
+// jsonSnippetForConstraint returns snippet of a JSON value
+// conforming to the constraint
+func jsonSnippetForConstraint(cons schema.Constraint, placeholder int) string {
+	switch cons.(type) {
+	case schema.List, schema.Set, schema.Tuple:
+		return fmt.Sprintf("[ ${%d} ]", placeholder)
+	case schema.Map, schema.Object:
+		return fmt.Sprintf("{ ${%d} }", placeholder)
+	}
+
+	if tc, ok := cons.(schema.TypeAwareConstraint); ok {
+		if typ, ok := tc.ConstraintType(); ok {
+			switch {
+			case typ == cty.Number:
+				return fmt.Sprintf("${%d:0}", placeholder)
+			case typ == cty.Bool:
+				return fmt.Sprintf("${%d:false}", placeholder)
+			case typ.IsListType(), typ.IsSetType(), typ.IsTupleType():
+				return fmt.Sprintf("[ ${%d} ]", placeholder)
+			case typ.IsMapType(), typ.IsObjectType():
+				return fmt.Sprintf("{ ${%d} }", placeholder)
+			}
+		}
+	}
+
+	// any other values (incl. references and keywords)
+	// are represented as strings in JSON
+	return fmt.Sprintf(`"${%d}"`, placeholder)
+}
+
+// jsonSnippetForBlock returns snippet of a JSON block,
+// where each label represents a nested object
+func jsonSnippetForBlock(blockType string, block *schema.BlockSchema) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%q: {", blockType)
+
+	placeholder := 1
+	indent := "\n"
+	for _, l := range block.Labels {
+		indent += "  "
+		if l.IsDepKey {
+			fmt.Fprintf(&sb, `%s"${%d}": {`, indent, placeholder)
+		} else {
+			fmt.Fprintf(&sb, `%s"${%d:%s}": {`, indent, placeholder, l.Name)
+		}
+		placeholder++
+	}
+
+	fmt.Fprintf(&sb, "%s  ${%d}", indent, placeholder)
+
+	for range block.Labels {
+		sb.WriteString(indent + "}")
+		indent = indent[:len(indent)-2]
+	}
+	sb.WriteString("\n}")
+
+	return sb.String()
+}
+
+// jsonKeyInnerRange returns range of the key without the quotes
+func jsonKeyInnerRange(rng hcl.Range) hcl.Range {
+	if rng.End.Byte-rng.Start.Byte < 2 {
+		return rng
+	}
+	return hcl.Range{
+		Filename: rng.Filename,
+		Start: hcl.Pos{
+			Line:   rng.Start.Line,
+			Column: rng.Start.Column + 1,
+			Byte:   rng.Start.Byte + 1,
+		},
+		End: hcl.Pos{
+			Line:   rng.End.Line,
+			Column: rng.End.Column - 1,
+			Byte:   rng.End.Byte - 1,
+		},
+	}
+}
+
+// jsonKeyPrefixRange returns range of the key between
+// the opening quote and the given position
+func jsonKeyPrefixRange(rng hcl.Range, pos hcl.Pos) hcl.Range {
+	prefixRng := jsonKeyInnerRange(rng)
+	if pos.Byte < prefixRng.End.Byte {
+		prefixRng.End = pos
+	}
+	if prefixRng.End.Byte < prefixRng.Start.Byte {
+		prefixRng.End = prefixRng.Start
+	}
+	return prefixRng
+}
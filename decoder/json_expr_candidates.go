@@ -0,0 +1,387 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package decoder
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/hcl-lang/lang"
+	"github.com/hashicorp/hcl-lang/schema"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/zclconf/go-cty/cty"
+	ctyjson "github.com/zclconf/go-cty/cty/json"
+)
+
+// jsonAttrValueCompletionAtPos is the JSON equivalent of attrValueCompletionAtPos,
+// where candidates are represented as JSON values, e.g. references
+// as "${var.foo}" strings and objects as quoted "key": value pairs.
+func (d *PathDecoder) jsonAttrValueCompletionAtPos(ctx context.Context, attr *hclsyntax.Attribute, aSchema *schema.AttributeSchema, outerBodyRng hcl.Range, pos hcl.Pos) (lang.Candidates, error) {
+	candidates := lang.NewCandidates()
+	candidates.IsComplete = true
+
+	inString := isPosInsideJSONString(attr.Expr, pos)
+
+	if len(aSchema.CompletionHooks) > 0 {
+		candidates.IsComplete = false
+		for _, candidate := range d.candidatesFromHooks(ctx, attr, aSchema, outerBodyRng, pos) {
+			candidate, ok := jsonValueCandidate(candidate)
+			if ok && inString {
+				candidate, ok = jsonStringContentCandidate(candidate)
+			}
+			if ok {
+				candidates.List = append(candidates.List, candidate)
+			}
+		}
+	}
+
+	for _, candidate := range d.jsonExprCandidates(ctx, attr.Expr, aSchema.Constraint, pos) {
+		if uint(len(candidates.List)) >= d.maxCandidates {
+			candidates.IsComplete = false
+			break
+		}
+		candidates.List = append(candidates.List, candidate)
+	}
+
+	return candidates, nil
+}
+
+// jsonExprCandidates returns candidates for the given position
+// within the JSON expression (as represented by syntaxExprForJSON)
+func (d *PathDecoder) jsonExprCandidates(ctx context.Context, expr hclsyntax.Expression, cons schema.Constraint, pos hcl.Pos) []lang.Candidate {
+	filename := expr.Range().Filename
+
+	switch e := expr.(type) {
+	case *hclsyntax.ObjectConsExpr:
+		itemRanges := make([]hcl.Range, 0, len(e.Items))
+		declared := make(declaredAttributes, 0)
+		for _, item := range e.Items {
+			key, ok := jsonObjectKey(item.KeyExpr)
+			itemRng := hcl.RangeBetween(item.KeyExpr.Range(), item.ValueExpr.Range())
+			itemRanges = append(itemRanges, itemRng)
+			if ok {
+				declared[key] = itemRng
+			}
+
+			if item.ValueExpr.Range().ContainsPos(pos) {
+				elemCons, ok := jsonObjectElemConstraint(cons, key)
+				if !ok {
+					return []lang.Candidate{}
+				}
+				return d.jsonExprCandidates(ctx, item.ValueExpr, elemCons, pos)
+			}
+			if item.KeyExpr.Range().ContainsPos(pos) {
+				obj, ok := jsonObjectConstraint(cons)
+				if !ok {
+					return []lang.Candidate{}
+				}
+				keyRng := item.KeyExpr.Range()
+				prefix, _ := d.bytesFromRange(jsonKeyPrefixRange(keyRng, pos))
+				candidates := objectAttributesToCandidates(ctx, string(prefix), obj.Attributes, declared, keyRng)
+				for i, candidate := range candidates {
+					candidates[i].TextEdit.NewText = fmt.Sprintf("%q", candidate.Label)
+					candidates[i].TextEdit.Snippet = fmt.Sprintf("%q", candidate.Label)
+				}
+				return candidates
+			}
+		}
+
+		obj, ok := jsonObjectConstraint(cons)
+		if !ok || !isPosBetweenBrackets(e.SrcRange, pos) {
+			return []lang.Candidate{}
+		}
+		editRng := hcl.Range{Filename: filename, Start: pos, End: pos}
+		before, after := d.jsonItemSeparators(filename, pos, itemRanges)
+
+		candidates := objectAttributesToCandidates(ctx, "", obj.Attributes, declared, editRng)
+		for i, candidate := range candidates {
+			aSchema := obj.Attributes[candidate.Label]
+			candidates[i].TextEdit.NewText = before + fmt.Sprintf("%q", candidate.Label) + after
+			candidates[i].TextEdit.Snippet = before + fmt.Sprintf("%q: %s",
+				candidate.Label, jsonSnippetForConstraint(aSchema.Constraint, 1)) + after
+		}
+		return candidates
+
+	case *hclsyntax.TupleConsExpr:
+		itemRanges := make([]hcl.Range, 0, len(e.Exprs))
+		for i, elemExpr := range e.Exprs {
+			itemRanges = append(itemRanges, elemExpr.Range())
+			if elemExpr.Range().ContainsPos(pos) {
+				elemCons, ok := jsonArrayElemConstraint(cons, i)
+				if !ok {
+					return []lang.Candidate{}
+				}
+				return d.jsonExprCandidates(ctx, elemExpr, elemCons, pos)
+			}
+		}
+
+		if !isPosBetweenBrackets(e.SrcRange, pos) {
+			return []lang.Candidate{}
+		}
+		elemCons, ok := jsonArrayElemConstraint(cons, len(e.Exprs))
+		if !ok {
+			return []lang.Candidate{}
+		}
+		before, after := d.jsonItemSeparators(filename, pos, itemRanges)
+
+		candidates := make([]lang.Candidate, 0)
+		for _, candidate := range d.jsonValueCandidates(ctx, filename, elemCons, pos) {
+			candidate.TextEdit.NewText = before + candidate.TextEdit.NewText + after
+			candidate.TextEdit.Snippet = before + candidate.TextEdit.Snippet + after
+			candidates = append(candidates, candidate)
+		}
+		return candidates
+	}
+
+	if !isPosInsideJSONString(expr, pos) {
+		return []lang.Candidate{}
+	}
+
+	if tplExpr, ok := expr.(*hclsyntax.TemplateExpr); ok {
+		for _, part := range tplExpr.Parts {
+			if _, ok := part.(*hclsyntax.LiteralValueExpr); ok {
+				continue
+			}
+			if part.Range().ContainsPos(pos) || part.Range().End.Byte == pos.Byte {
+				// interpolated expressions are native syntax
+				// and only need escaping as part of JSON string
+				candidates := d.newExpression(expr, cons).CompletionAtPos(ctx, pos)
+				for i, candidate := range candidates {
+					candidates[i].TextEdit.NewText = jsonStringEscaper.Replace(candidate.TextEdit.NewText)
+					candidates[i].TextEdit.Snippet = jsonStringEscaper.Replace(candidate.TextEdit.Snippet)
+				}
+				return candidates
+			}
+		}
+	}
+
+	contentRng := jsonKeyInnerRange(expr.Range())
+	prefixRng := jsonKeyPrefixRange(expr.Range(), pos)
+	prefix, _ := d.bytesFromRange(prefixRng)
+
+	candidates := make([]lang.Candidate, 0)
+	for _, candidate := range d.jsonValueCandidates(ctx, filename, cons, pos) {
+		candidate, ok := jsonStringContentCandidate(candidate)
+		if !ok || !strings.HasPrefix(candidate.TextEdit.NewText, string(prefix)) {
+			continue
+		}
+		candidate.TextEdit.Range = contentRng
+		candidates = append(candidates, candidate)
+	}
+	return candidates
+}
+
+// jsonValueCandidates returns candidates for a JSON value
+// conforming to the constraint, to be inserted at the given position
+func (d *PathDecoder) jsonValueCandidates(ctx context.Context, filename string, cons schema.Constraint, pos hcl.Pos) []lang.Candidate {
+	candidates := make([]lang.Candidate, 0)
+
+	if oneOf, ok := cons.(schema.OneOf); ok {
+		for _, elemCons := range oneOf {
+			candidates = append(candidates, d.jsonValueCandidates(ctx, filename, elemCons, pos)...)
+		}
+		return candidates
+	}
+
+	emptyExpr := newEmptyExpressionAtPos(filename, pos)
+	for _, candidate := range d.newExpression(emptyExpr, cons).CompletionAtPos(ctx, pos) {
+		if lv, ok := cons.(schema.LiteralValue); ok {
+			// represent the value itself, e.g. lists of strings
+			b, err := ctyjson.SimpleJSONValue{Value: lv.Value}.MarshalJSON()
+			if err == nil {
+				candidate.TextEdit.NewText = string(b)
+				candidate.TextEdit.Snippet = string(b)
+				candidates = append(candidates, candidate)
+				continue
+			}
+		}
+
+		candidate, ok := jsonValueCandidate(candidate)
+		if ok {
+			candidates = append(candidates, candidate)
+		}
+	}
+
+	return candidates
+}
+
+// jsonValueCandidate converts the text of a candidate
+// in native syntax to JSON value
+func jsonValueCandidate(candidate lang.Candidate) (lang.Candidate, bool) {
+	te := candidate.TextEdit
+
+	switch candidate.Kind {
+	case lang.ReferenceCandidateKind, lang.FunctionCandidateKind:
+		te.NewText = `"${` + jsonStringEscaper.Replace(te.NewText) + `}"`
+		te.Snippet = `"\${` + jsonStringEscaper.Replace(te.Snippet) + `}"`
+	case lang.ListCandidateKind, lang.SetCandidateKind, lang.TupleCandidateKind:
+		te.NewText = "[ ]"
+		te.Snippet = "[ ${1} ]"
+	case lang.MapCandidateKind, lang.ObjectCandidateKind:
+		te.NewText = "{ }"
+		te.Snippet = "{ ${1} }"
+	case lang.BoolCandidateKind, lang.NumberCandidateKind:
+		if !json.Valid([]byte(te.NewText)) {
+			return candidate, false
+		}
+	default:
+		if !strings.HasPrefix(te.NewText, `"`) {
+			// keywords and any other bare values are strings in JSON
+			te.NewText = `"` + jsonStringEscaper.Replace(te.NewText) + `"`
+			te.Snippet = `"` + jsonStringEscaper.Replace(te.Snippet) + `"`
+		}
+	}
+
+	candidate.TextEdit = te
+	return candidate, true
+}
+
+// jsonStringContentCandidate converts the text of a JSON value candidate
+// to the content of JSON string, or returns false if the value
+// cannot be represented as a string
+func jsonStringContentCandidate(candidate lang.Candidate) (lang.Candidate, bool) {
+	te := candidate.TextEdit
+	if !isQuotedJSON(te.NewText) || !isQuotedJSON(te.Snippet) {
+		return candidate, false
+	}
+
+	te.NewText = te.NewText[1 : len(te.NewText)-1]
+	te.Snippet = te.Snippet[1 : len(te.Snippet)-1]
+	candidate.TextEdit = te
+	return candidate, true
+}
+
+func isQuotedJSON(s string) bool {
+	return len(s) >= 2 && strings.HasPrefix(s, `"`) && strings.HasSuffix(s, `"`)
+}
+
+var jsonStringEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`)
+
+// isPosInsideJSONString returns true if the position
+// is between the quotes of a JSON string
+func isPosInsideJSONString(expr hclsyntax.Expression, pos hcl.Pos) bool {
+	switch e := expr.(type) {
+	case *hclsyntax.TemplateExpr, *hclsyntax.TemplateWrapExpr:
+	case *hclsyntax.LiteralValueExpr:
+		if e.Val.Type() != cty.String {
+			return false
+		}
+	default:
+		return false
+	}
+	return isPosBetweenBrackets(expr.Range(), pos)
+}
+
+// isPosBetweenBrackets returns true if the position is after
+// the first and before the last character of the range,
+// i.e. between quotes, braces or square brackets
+func isPosBetweenBrackets(rng hcl.Range, pos hcl.Pos) bool {
+	return rng.End.Byte-rng.Start.Byte >= 2 &&
+		pos.Byte > rng.Start.Byte && pos.Byte < rng.End.Byte
+}
+
+// jsonItemSeparators returns commas to be inserted before and after
+// a new item (property or array element) at the given position,
+// so that the item is separated from any existing neighbouring items
+func (d *PathDecoder) jsonItemSeparators(filename string, pos hcl.Pos, itemRanges []hcl.Range) (string, string) {
+	src, err := d.bytesForFile(filename)
+	if err != nil {
+		return "", ""
+	}
+
+	prevEnd, nextStart := -1, -1
+	for _, rng := range itemRanges {
+		if rng.End.Byte <= pos.Byte && rng.End.Byte > prevEnd {
+			prevEnd = rng.End.Byte
+		}
+		if rng.Start.Byte >= pos.Byte && (nextStart == -1 || rng.Start.Byte < nextStart) {
+			nextStart = rng.Start.Byte
+		}
+	}
+
+	before, after := "", ""
+	if prevEnd >= 0 && prevEnd <= len(src) && !bytes.ContainsRune(src[prevEnd:pos.Byte], ',') {
+		before = ","
+	}
+	if nextStart >= 0 && nextStart <= len(src) && !bytes.ContainsRune(src[pos.Byte:nextStart], ',') {
+		after = ","
+	}
+	return before, after
+}
+
+// jsonObjectKey returns the static value of the object key
+func jsonObjectKey(keyExpr hclsyntax.Expression) (string, bool) {
+	if ke, ok := keyExpr.(*hclsyntax.ObjectConsKeyExpr); ok {
+		keyExpr = ke.Wrapped
+	}
+	val, diags := keyExpr.Value(nil)
+	if diags.HasErrors() || val.Type() != cty.String || !val.IsKnown() || val.IsNull() {
+		return "", false
+	}
+	return val.AsString(), true
+}
+
+// jsonObjectConstraint returns the object constraint, if the given
+// constraint represents an object (possibly as part of OneOf)
+func jsonObjectConstraint(cons schema.Constraint) (schema.Object, bool) {
+	switch c := cons.(type) {
+	case schema.Object:
+		return c, true
+	case schema.OneOf:
+		for _, elemCons := range c {
+			if obj, ok := jsonObjectConstraint(elemCons); ok {
+				return obj, true
+			}
+		}
+	}
+	return schema.Object{}, false
+}
+
+// jsonObjectElemConstraint returns constraint of the value
+// of the given key within an object or map
+func jsonObjectElemConstraint(cons schema.Constraint, key string) (schema.Constraint, bool) {
+	switch c := cons.(type) {
+	case schema.Object:
+		aSchema, ok := c.Attributes[key]
+		if !ok {
+			return nil, false
+		}
+		return aSchema.Constraint, true
+	case schema.Map:
+		return c.Elem, c.Elem != nil
+	case schema.OneOf:
+		for _, elemCons := range c {
+			if elem, ok := jsonObjectElemConstraint(elemCons, key); ok {
+				return elem, true
+			}
+		}
+	}
+	return nil, false
+}
+
+// jsonArrayElemConstraint returns constraint of the element
+// at the given index within a list, set or tuple
+func jsonArrayElemConstraint(cons schema.Constraint, idx int) (schema.Constraint, bool) {
+	switch c := cons.(type) {
+	case schema.List:
+		return c.Elem, c.Elem != nil
+	case schema.Set:
+		return c.Elem, c.Elem != nil
+	case schema.Tuple:
+		if idx < len(c.Elems) {
+			return c.Elems[idx], true
+		}
+	case schema.OneOf:
+		for _, elemCons := range c {
+			if elem, ok := jsonArrayElemConstraint(elemCons, idx); ok {
+				return elem, true
+			}
+		}
+	}
+	return nil, false
+}
@@ -16,7 +16,7 @@ import (
 	"github.com/hashicorp/hcl/v2/hclsyntax"
 )
 
-func (d *PathDecoder) labelCandidatesFromDependentSchema(idx int, db map[schema.SchemaKey]*schema.BodySchema, prefixRng, editRng hcl.Range, block *hclsyntax.Block, labelSchemas []*schema.LabelSchema) (lang.Candidates, error) {
+func (d *PathDecoder) labelCandidatesFromDependentSchema(idx int, bSchema *schema.BlockSchema, prefixRng, editRng hcl.Range, block *hclsyntax.Block) (lang.Candidates, error) {
 	candidates := lang.NewCandidates()
 	candidates.IsComplete = true
 	count := 0
@@ -24,6 +24,7 @@ func (d *PathDecoder) labelCandidatesFromDependentSchema(idx int, db map[schema.
 	foundCandidateNames := make(map[string]bool, 0)
 
 	prefix, _ := d.bytesFromRange(prefixRng)
+	db := bSchema.DependentBody
 
 	for _, schemaKey := range sortedSchemaKeys(db) {
 		depKeys, err := decodeSchemaKey(schemaKey)
@@ -64,27 +65,11 @@ func (d *PathDecoder) labelCandidatesFromDependentSchema(idx int, db map[schema.
 				continue
 			}
 
-			te := lang.TextEdit{}
-			if d.PrefillRequiredFields {
-				snippet := generateRequiredFieldsSnippet(label.Value, bodySchema, labelSchemas, 2, 0)
-				te = lang.TextEdit{
-					NewText: label.Value,
-					Snippet: snippet,
-					Range:   hcl.RangeBetween(editRng, block.OpenBraceRange),
-				}
-			} else {
-				te = lang.TextEdit{
-					NewText: label.Value,
-					Snippet: label.Value,
-					Range:   editRng,
-				}
-			}
-
 			candidates.List = append(candidates.List, lang.Candidate{
 				Label:        label.Value,
 				Kind:         lang.LabelCandidateKind,
 				IsDeprecated: bodySchema.IsDeprecated,
-				TextEdit:     te,
+				TextEdit:     d.labelTextEdit(label.Value, bodySchema, editRng, block, bSchema.Labels),
 				Detail:       bodySchema.Detail,
 				Description:  bodySchema.Description,
 			})
@@ -94,11 +79,58 @@ func (d *PathDecoder) labelCandidatesFromDependentSchema(idx int, db map[schema.
 		}
 	}
 
+	if bSchema.DependentBodyResolver != nil && candidates.IsComplete {
+		summaries, complete := bSchema.DependentBodyResolver.ListDependentBodies(idx, string(prefix), d.maxCandidates-uint(count))
+		if !complete {
+			candidates.IsComplete = false
+		}
+
+		for _, summary := range summaries {
+			if uint(count) >= d.maxCandidates {
+				candidates.IsComplete = false
+				break
+			}
+			if _, ok := foundCandidateNames[summary.Label]; ok {
+				continue
+			}
+
+			// only the block header is prefilled here, as required
+			// fields would require resolving every listed body
+			candidates.List = append(candidates.List, lang.Candidate{
+				Label:        summary.Label,
+				Kind:         lang.LabelCandidateKind,
+				IsDeprecated: summary.IsDeprecated,
+				TextEdit:     d.labelTextEdit(summary.Label, nil, editRng, block, bSchema.Labels),
+				Detail:       summary.Detail,
+				Description:  summary.Description,
+			})
+
+			foundCandidateNames[summary.Label] = true
+			count++
+		}
+	}
+
 	sort.Sort(candidates)
 
 	return candidates, nil
 }
 
+func (d *PathDecoder) labelTextEdit(label string, bodySchema *schema.BodySchema, editRng hcl.Range, block *hclsyntax.Block, labelSchemas []*schema.LabelSchema) lang.TextEdit {
+	if d.PrefillRequiredFields {
+		return lang.TextEdit{
+			NewText: label,
+			Snippet: generateRequiredFieldsSnippet(label, bodySchema, labelSchemas, 2, 0),
+			Range:   hcl.RangeBetween(editRng, block.OpenBraceRange),
+		}
+	}
+
+	return lang.TextEdit{
+		NewText: label,
+		Snippet: label,
+		Range:   editRng,
+	}
+}
+
 // generateRequiredFieldsSnippet returns a properly formatted snippet of all required
 // fields (attributes, blocks, etc). It handles the main stanza declaration and calls
 // `requiredFieldsSnippet` to handle recursing through the body schema
@@ -5,6 +5,8 @@ package decoder
 
 import (
 	"context"
+	"sort"
+	"strings"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
@@ -631,3 +633,266 @@ func TestCompletionAtPos_prefillRequiredFields(t *testing.T) {
 		})
 	}
 }
+
+type testDependentBodyResolver struct {
+	bodies   map[string]*schema.BodySchema
+	resolved []string
+}
+
+func (r *testDependentBodyResolver) ResolveDependentBody(keys schema.DependencyKeys) (*schema.BodySchema, bool) {
+	if len(keys.Labels) != 1 || len(keys.Attributes) != 0 {
+		return nil, false
+	}
+	bodySchema, ok := r.bodies[keys.Labels[0].Value]
+	if ok {
+		r.resolved = append(r.resolved, keys.Labels[0].Value)
+	}
+	return bodySchema, ok
+}
+
+func (r *testDependentBodyResolver) ListDependentBodies(labelIndex int, prefix string, limit uint) ([]schema.DependentBodySummary, bool) {
+	names := make([]string, 0)
+	for name := range r.bodies {
+		if labelIndex == 0 && strings.HasPrefix(name, prefix) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	complete := true
+	if uint(len(names)) > limit {
+		names = names[:limit]
+		complete = false
+	}
+
+	summaries := make([]schema.DependentBodySummary, 0, len(names))
+	for _, name := range names {
+		summaries = append(summaries, schema.DependentBodySummary{
+			Label:        name,
+			Detail:       r.bodies[name].Detail,
+			Description:  r.bodies[name].Description,
+			IsDeprecated: r.bodies[name].IsDeprecated,
+		})
+	}
+	return summaries, complete
+}
+
+func TestCompletionAtPos_labelsFromResolver(t *testing.T) {
+	ctx := context.Background()
+
+	resolver := &testDependentBodyResolver{
+		bodies: map[string]*schema.BodySchema{
+			"aws_instance": {
+				Detail: "aws",
+				Attributes: map[string]*schema.AttributeSchema{
+					"ami": {Constraint: schema.LiteralType{Type: cty.String}, IsRequired: true},
+				},
+			},
+			"aws_vpc": {
+				Description:  lang.PlainText("VPC"),
+				IsDeprecated: true,
+			},
+			"azurerm_vm": {},
+		},
+	}
+	bodySchema := &schema.BodySchema{
+		Blocks: map[string]*schema.BlockSchema{
+			"resource": {
+				Labels: []*schema.LabelSchema{
+					{
+						Name:        "type",
+						IsDepKey:    true,
+						Completable: true,
+					},
+				},
+				DependentBody: map[schema.SchemaKey]*schema.BodySchema{
+					schema.NewSchemaKey(schema.DependencyKeys{
+						Labels: []schema.LabelDependent{
+							{Index: 0, Value: "aws_ami"},
+						},
+					}): {},
+				},
+				DependentBodyResolver: resolver,
+			},
+		},
+	}
+
+	f, _ := hclsyntax.ParseConfig([]byte(`resource "aws" {
+}
+`), "test.tf", hcl.InitialPos)
+
+	d := testPathDecoder(t, &PathContext{
+		Schema: bodySchema,
+		Files: map[string]*hcl.File{
+			"test.tf": f,
+		},
+	})
+
+	candidates, err := d.CompletionAtPos(ctx, "test.tf", hcl.Pos{
+		Line:   1,
+		Column: 14,
+		Byte:   13,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	editRng := hcl.Range{
+		Filename: "test.tf",
+		Start:    hcl.Pos{Line: 1, Column: 11, Byte: 10},
+		End:      hcl.Pos{Line: 1, Column: 14, Byte: 13},
+	}
+	expectedCandidates := lang.Candidates{
+		List: []lang.Candidate{
+			{
+				Label: "aws_ami",
+				Kind:  lang.LabelCandidateKind,
+				TextEdit: lang.TextEdit{
+					Range:   editRng,
+					NewText: "aws_ami",
+					Snippet: "aws_ami",
+				},
+			},
+			{
+				Label:  "aws_instance",
+				Kind:   lang.LabelCandidateKind,
+				Detail: "aws",
+				TextEdit: lang.TextEdit{
+					Range:   editRng,
+					NewText: "aws_instance",
+					Snippet: "aws_instance",
+				},
+			},
+			{
+				Label:        "aws_vpc",
+				Kind:         lang.LabelCandidateKind,
+				Description:  lang.PlainText("VPC"),
+				IsDeprecated: true,
+				TextEdit: lang.TextEdit{
+					Range:   editRng,
+					NewText: "aws_vpc",
+					Snippet: "aws_vpc",
+				},
+			},
+		},
+		IsComplete: true,
+	}
+	if diff := cmp.Diff(expectedCandidates, candidates); diff != "" {
+		t.Fatalf("unexpected candidates: %s", diff)
+	}
+
+	// listing labels must not resolve any bodies
+	if len(resolver.resolved) != 0 {
+		t.Fatalf("expected no bodies to be resolved, given: %q", resolver.resolved)
+	}
+
+	d.maxCandidates = 2
+	candidates, err = d.CompletionAtPos(ctx, "test.tf", hcl.Pos{
+		Line:   1,
+		Column: 14,
+		Byte:   13,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(candidates.List) != 2 || candidates.IsComplete {
+		t.Fatalf("expected 2 incomplete candidates, given %d (complete: %t)",
+			len(candidates.List), candidates.IsComplete)
+	}
+}
+
+func TestCompletionAtPos_labelsFromResolverPrefill(t *testing.T) {
+	ctx := context.Background()
+
+	resolver := &testDependentBodyResolver{
+		bodies: map[string]*schema.BodySchema{
+			"aws_instance": {
+				Attributes: map[string]*schema.AttributeSchema{
+					"ami": {Constraint: schema.LiteralType{Type: cty.String}, IsRequired: true},
+				},
+			},
+		},
+	}
+	bodySchema := &schema.BodySchema{
+		Blocks: map[string]*schema.BlockSchema{
+			"resource": {
+				Labels: []*schema.LabelSchema{
+					{
+						Name:        "type",
+						IsDepKey:    true,
+						Completable: true,
+					},
+				},
+				DependentBody: map[schema.SchemaKey]*schema.BodySchema{
+					schema.NewSchemaKey(schema.DependencyKeys{
+						Labels: []schema.LabelDependent{
+							{Index: 0, Value: "aws_ami"},
+						},
+					}): {
+						Attributes: map[string]*schema.AttributeSchema{
+							"name": {Constraint: schema.LiteralType{Type: cty.String}, IsRequired: true},
+						},
+					},
+				},
+				DependentBodyResolver: resolver,
+			},
+		},
+	}
+
+	f, _ := hclsyntax.ParseConfig([]byte(`resource "aws" {
+}
+`), "test.tf", hcl.InitialPos)
+
+	d := testPathDecoder(t, &PathContext{
+		Schema: bodySchema,
+		Files: map[string]*hcl.File{
+			"test.tf": f,
+		},
+	})
+	d.PrefillRequiredFields = true
+
+	candidates, err := d.CompletionAtPos(ctx, "test.tf", hcl.Pos{
+		Line:   1,
+		Column: 14,
+		Byte:   13,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// both kinds of candidates replace the whole block header,
+	// but only static bodies have their required fields prefilled
+	editRng := hcl.Range{
+		Filename: "test.tf",
+		Start:    hcl.Pos{Line: 1, Column: 11, Byte: 10},
+		End:      hcl.Pos{Line: 1, Column: 17, Byte: 16},
+	}
+	expectedCandidates := lang.CompleteCandidates([]lang.Candidate{
+		{
+			Label: "aws_ami",
+			Kind:  lang.LabelCandidateKind,
+			TextEdit: lang.TextEdit{
+				Range:   editRng,
+				NewText: "aws_ami",
+				Snippet: "aws_ami\" {\n\tname = \"${2:value}\"\n\t${0}",
+			},
+		},
+		{
+			Label: "aws_instance",
+			Kind:  lang.LabelCandidateKind,
+			TextEdit: lang.TextEdit{
+				Range:   editRng,
+				NewText: "aws_instance",
+				Snippet: "aws_instance\" {\n\t${0}",
+			},
+		},
+	})
+	if diff := cmp.Diff(expectedCandidates, candidates); diff != "" {
+		t.Fatalf("unexpected candidates: %s", diff)
+	}
+
+	// listing labels must not resolve any bodies, even when prefilling
+	if len(resolver.resolved) != 0 {
+		t.Fatalf("expected no bodies to be resolved, given: %q", resolver.resolved)
+	}
+}
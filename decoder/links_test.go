@@ -4,7 +4,6 @@
 package decoder
 
 import (
-	"errors"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
@@ -191,24 +190,57 @@ func TestLinksInFileAttribute(t *testing.T) {
 
 func TestLinksInFile_json(t *testing.T) {
 	f, pDiags := json.Parse([]byte(`{
-	"customblock": {
-		"label1": {}
-	}
+  "myblock": {
+    "sushi": {
+      "salmon": {}
+    }
+  }
 }`), "test.tf.json")
 	if len(pDiags) > 0 {
 		t.Fatal(pDiags)
 	}
 
 	d := testPathDecoder(t, &PathContext{
+		Schema: &schema.BodySchema{
+			Blocks: map[string]*schema.BlockSchema{
+				"myblock": {
+					Labels: []*schema.LabelSchema{
+						{Name: "type", IsDepKey: true},
+						{Name: "name"},
+					},
+					DependentBody: map[schema.SchemaKey]*schema.BodySchema{
+						schema.NewSchemaKey(schema.DependencyKeys{
+							Labels: []schema.LabelDependent{
+								{Index: 0, Value: "sushi"},
+							},
+						}): {
+							DocsLink: &schema.DocsLink{URL: "https://en.wikipedia.org/wiki/Sushi"},
+						},
+					},
+				},
+			},
+		},
 		Files: map[string]*hcl.File{
 			"test.tf.json": f,
 		},
 	})
 
-	// We never want to provide links in JSON configs
-	_, err := d.LinksInFile("test.tf.json")
-	unknownFormatErr := &UnknownFileFormatError{}
-	if !errors.As(err, &unknownFormatErr) {
-		t.Fatal("expected UnknownFileFormatError for JSON body")
+	links, err := d.LinksInFile("test.tf.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectedLinks := []lang.Link{
+		{
+			URI: "https://en.wikipedia.org/wiki/Sushi",
+			Range: hcl.Range{
+				Filename: "test.tf.json",
+				Start:    hcl.Pos{Line: 3, Column: 5, Byte: 21},
+				End:      hcl.Pos{Line: 3, Column: 12, Byte: 28},
+			},
+		},
+	}
+	if diff := cmp.Diff(expectedLinks, links); diff != "" {
+		t.Fatalf("unexpected links: %s", diff)
 	}
 }
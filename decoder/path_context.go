@@ -22,6 +22,11 @@ type PathContext struct {
 	Files            map[string]*hcl.File
 	Functions        map[string]schema.FunctionSignature
 	Validators       []validator.Validator
+
+	// Version represents the declared target version of the language
+	// for the path, which is used to validate versioned schema
+	// (see schema.VersionInfo). Zero value represents unknown version.
+	Version schema.Version
 }
 
 type pathCtxKey struct{}
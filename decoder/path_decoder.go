@@ -6,9 +6,12 @@ package decoder
 import (
 	"sort"
 
+	"github.com/hashicorp/hcl-lang/decoder/internal/schemahelper"
 	"github.com/hashicorp/hcl-lang/lang"
+	"github.com/hashicorp/hcl-lang/schema"
 	"github.com/hashicorp/hcl/v2"
 	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/hcl/v2/json"
 )
 
 type PathDecoder struct {
@@ -16,6 +19,10 @@ type PathDecoder struct {
 	pathCtx    *PathContext
 	decoderCtx DecoderContext
 
+	// semanticTokens holds previous results of SemanticTokensFull
+	// to compute deltas from
+	semanticTokens *semanticTokensCache
+
 	// maxCandidates defines maximum number of completion candidates returned
 	maxCandidates uint
 
@@ -23,16 +30,21 @@ type PathDecoder struct {
 	// with required attributes and blocks
 	// TODO: Move under DecoderContext
 	PrefillRequiredFields bool
+
+	// PrefillDefaultValues prefills attribute completion candidates
+	// with the default value which applies when the attribute is omitted
+	PrefillDefaultValues bool
 }
 
 func (d *Decoder) Path(path lang.Path) (*PathDecoder, error) {
 	pathCtx, err := d.pathReader.PathContext(path)
 
 	return &PathDecoder{
-		path:          path,
-		pathCtx:       pathCtx,
-		decoderCtx:    d.ctx,
-		maxCandidates: 100,
+		path:           path,
+		pathCtx:        pathCtx,
+		decoderCtx:     d.ctx,
+		semanticTokens: d.semanticTokens,
+		maxCandidates:  100,
 	}, err
 }
 
@@ -78,7 +90,10 @@ func (d *PathDecoder) fileByName(name string) (*hcl.File, error) {
 func (d *PathDecoder) bodyForFileAndPos(name string, f *hcl.File, pos hcl.Pos) (*hclsyntax.Body, error) {
 	body, isHcl := f.Body.(*hclsyntax.Body)
 	if !isHcl {
-		return nil, &UnknownFileFormatError{Filename: name}
+		if !json.IsJSONBody(f.Body) {
+			return nil, &UnknownFileFormatError{Filename: name}
+		}
+		body = d.syntaxBodyForJSONFile(name, f)
 	}
 
 	if !body.Range().ContainsPos(pos) &&
@@ -94,3 +109,16 @@ func (d *PathDecoder) bodyForFileAndPos(name string, f *hcl.File, pos hcl.Pos) (
 
 	return body, nil
 }
+
+// mergeBlockBodySchemas returns the body schema of the block merged
+// with any matching dependent body, where defaults of dependency keys
+// are resolved using the DecoderContext
+func (d *PathDecoder) mergeBlockBodySchemas(block *hcl.Block, bSchema *schema.BlockSchema) (*schema.BodySchema, schemahelper.LookupResult) {
+	return schemahelper.MergeBlockBodySchemasWithEnv(block, bSchema, d.decoderCtx.LookupEnv)
+}
+
+// dependentBodySchema returns the dependent body matching the block,
+// where defaults of dependency keys are resolved using the DecoderContext
+func (d *PathDecoder) dependentBodySchema(block *hcl.Block, bSchema *schema.BlockSchema) (*schema.BodySchema, schema.DependencyKeys, schemahelper.LookupResult) {
+	return schemahelper.NewBlockSchema(bSchema).WithLookupEnv(d.decoderCtx.LookupEnv).DependentBodySchema(block)
+}
@@ -186,7 +186,7 @@ func (d *PathDecoder) referenceOriginsInBody(body hcl.Body, bodySchema *schema.B
 				// skip unknown blocks
 				continue
 			}
-			mergedSchema, _ := schemahelper.MergeBlockBodySchemas(block.Block, bSchema)
+			mergedSchema, _ := d.mergeBlockBodySchemas(block.Block, bSchema)
 
 			os, ios := d.referenceOriginsInBody(block.Body, mergedSchema)
 			origins = append(origins, os...)
@@ -20,6 +20,9 @@ import (
 
 type ReferenceTargets []*ReferenceTarget
 
+// ReferenceTargetsForOriginAtPos returns targets of any origins at the given
+// position, where Range of each target represents the whole definition,
+// i.e. the whole block or attribute.
 func (d *Decoder) ReferenceTargetsForOriginAtPos(path lang.Path, file string, pos hcl.Pos) (ReferenceTargets, error) {
 	pathCtx, err := d.pathReader.PathContext(path)
 	if err != nil {
@@ -81,6 +84,88 @@ func (d *Decoder) ReferenceTargetsForOriginAtPos(path lang.Path, file string, po
 	return matchingTargets, nil
 }
 
+// ReferenceDefinitionsForOriginAtPos is an alias of ReferenceTargetsForOriginAtPos
+// which makes the distinction from declarations and type definitions explicit.
+func (d *Decoder) ReferenceDefinitionsForOriginAtPos(path lang.Path, file string, pos hcl.Pos) (ReferenceTargets, error) {
+	return d.ReferenceTargetsForOriginAtPos(path, file, pos)
+}
+
+// ReferenceDeclarationsForOriginAtPos returns targets of any origins
+// at the given position, where Range of each target represents
+// the declaration (DefRangePtr), such as block header or attribute name.
+//
+// Targets without declaration range (e.g. direct origins or targets
+// representing multiple blocks) fall back to the full range.
+func (d *Decoder) ReferenceDeclarationsForOriginAtPos(path lang.Path, file string, pos hcl.Pos) (ReferenceTargets, error) {
+	targets, err := d.ReferenceTargetsForOriginAtPos(path, file, pos)
+	if err != nil {
+		return targets, err
+	}
+
+	for _, target := range targets {
+		if target.DefRangePtr != nil {
+			target.Range = *target.DefRangePtr
+		}
+	}
+
+	return targets, nil
+}
+
+// ReferenceTypeDefinitionsForOriginAtPos returns the declarations which
+// define the type of targets of any origins at the given position,
+// such as the name of the type attribute declared via BlockAsTypeOf
+// (e.g. type = object({...}) inside of variable block).
+//
+// Origins referring to nested attributes of such targets are matched
+// too, e.g. var.foo.attr lands on type attribute of variable "foo".
+func (d *Decoder) ReferenceTypeDefinitionsForOriginAtPos(path lang.Path, file string, pos hcl.Pos) (ReferenceTargets, error) {
+	pathCtx, err := d.pathReader.PathContext(path)
+	if err != nil {
+		return nil, err
+	}
+
+	matchingTargets := make(ReferenceTargets, 0)
+
+	origins, ok := pathCtx.ReferenceOrigins.AtPos(file, pos)
+	if !ok {
+		return matchingTargets, &reference.NoOriginFound{}
+	}
+
+	for _, origin := range origins {
+		targetCtx := pathCtx
+		targetPath := path
+
+		if pathOrigin, ok := origin.(reference.PathOrigin); ok {
+			ctx, err := d.pathReader.PathContext(pathOrigin.TargetPath)
+			if err != nil {
+				continue
+			}
+			targetCtx = ctx
+			targetPath = pathOrigin.TargetPath
+		}
+
+		matchableOrigin, ok := origin.(reference.MatchableOrigin)
+		if !ok {
+			continue
+		}
+		targets, ok := targetCtx.ReferenceTargets.MatchTypeDefinitions(matchableOrigin)
+		if !ok {
+			// type definition not found
+			continue
+		}
+		for _, target := range targets {
+			matchingTargets = append(matchingTargets, &ReferenceTarget{
+				OriginRange: origin.OriginRange(),
+				Path:        targetPath,
+				Range:       *target.TypeDefRangePtr,
+				DefRangePtr: target.TypeDefRangePtr.Ptr(),
+			})
+		}
+	}
+
+	return matchingTargets, nil
+}
+
 func (d *PathDecoder) CollectReferenceTargets() (reference.Targets, error) {
 	if d.pathCtx.Schema == nil {
 		// unable to collect reference targets without schema
@@ -140,7 +225,7 @@ func (d *PathDecoder) decodeReferenceTargetsForBody(body hcl.Body, parentBlock *
 			continue
 		}
 
-		mergedSchema, _ := schemahelper.MergeBlockBodySchemas(blk.Block, bSchema)
+		mergedSchema, _ := d.mergeBlockBodySchemas(blk.Block, bSchema)
 
 		iRefs := d.decodeReferenceTargetsForBody(blk.Body, blk, mergedSchema)
 		refs = append(refs, iRefs...)
@@ -163,7 +248,7 @@ func (d *PathDecoder) decodeReferenceTargetsForBody(body hcl.Body, parentBlock *
 		}
 
 		if bSchema.Address.AsTypeOf != nil {
-			refs = append(refs, referenceAsTypeOf(blk.Block, blk.Range.Ptr(), bSchema, addr)...)
+			refs = append(refs, d.referenceAsTypeOf(blk.Block, blk.Range.Ptr(), bSchema, addr)...)
 		}
 
 		var bodyRef reference.Target
@@ -207,18 +292,18 @@ func (d *PathDecoder) decodeReferenceTargetsForBody(body hcl.Body, parentBlock *
 				}
 			}
 
-			depSchema, _, result := schemahelper.NewBlockSchema(bSchema).DependentBodySchema(blk.Block)
+			depSchema, _, result := d.dependentBodySchema(blk.Block, bSchema)
 			if result == schemahelper.LookupSuccessful {
 				fullSchema := depSchema
 				if bSchema.Address.BodyAsData {
-					mergedSchema, _ := schemahelper.MergeBlockBodySchemas(blk.Block, bSchema)
+					mergedSchema, _ := d.mergeBlockBodySchemas(blk.Block, bSchema)
 					bodyRef.NestedTargets = make(reference.Targets, 0)
 					fullSchema = mergedSchema
 				}
 
 				bodyRef.Type = bodyToDataType(bSchema.Type, fullSchema)
 
-				if bSchema.Address.InferDependentBody && len(bSchema.DependentBody) > 0 {
+				if bSchema.Address.InferDependentBody && bSchema.HasDependentBody() {
 					if bSchema.Address.DependentBodySelfRef {
 						bodyRef.LocalAddr = lang.Address{
 							lang.RootStep{Name: "self"},
@@ -311,7 +396,7 @@ func (d *PathDecoder) decodeReferenceTargetsForAttribute(attr *hcl.Attribute, at
 	return refs
 }
 
-func referenceAsTypeOf(block *hcl.Block, rngPtr *hcl.Range, bSchema *schema.BlockSchema, addr lang.Address) reference.Targets {
+func (d *PathDecoder) referenceAsTypeOf(block *hcl.Block, rngPtr *hcl.Range, bSchema *schema.BlockSchema, addr lang.Address) reference.Targets {
 	ref := reference.Target{
 		Addr:        addr,
 		ScopeId:     bSchema.Address.ScopeId,
@@ -332,10 +417,14 @@ func referenceAsTypeOf(block *hcl.Block, rngPtr *hcl.Range, bSchema *schema.Bloc
 	if bSchema.Address.AsTypeOf.AttributeExpr != "" {
 		typeDecl, ok := asTypeOfAttrExpr(attrs, bSchema)
 		if !ok {
-			// nothing to fall back to, exit early
+			// fall back to the default type, if any
+			if typeDecl, ok := d.asTypeOfAttrDefault(attrs, bSchema); ok {
+				ref.Type = typeDecl
+			}
 			return reference.Targets{ref}
 		}
 		ref.Type = typeDecl
+		ref.TypeDefRangePtr = attrs[bSchema.Address.AsTypeOf.AttributeExpr].NameRange.Ptr()
 	}
 
 	return reference.Targets{ref}
@@ -349,12 +438,21 @@ func asTypeOfAttrExpr(attrs hcl.Attributes, bSchema *schema.BlockSchema) (cty.Ty
 	}
 
 	aSchema := bSchema.Body.Attributes[attrName]
-	_, ok = aSchema.Constraint.(schema.TypeDeclaration)
+	cons, ok := aSchema.Constraint.(schema.TypeDeclaration)
 	if !ok {
 		return cty.DynamicPseudoType, false
 	}
 
-	// TODO: TypeConstraintWithDefaults
+	if cons.OptionalAttrs {
+		typeDecl, _, diags := typeexpr.TypeConstraintWithDefaults(attr.Expr)
+		if diags.HasErrors() {
+			return cty.DynamicPseudoType, false
+		}
+		// Defaults are applied before the value is converted to the type,
+		// so optional attributes are always present in the final value
+		return typeDecl.WithoutOptionalAttributesDeep(), true
+	}
+
 	typeDecl, diags := typeexpr.TypeConstraint(attr.Expr)
 	if diags.HasErrors() {
 		return cty.DynamicPseudoType, false
@@ -363,6 +461,27 @@ func asTypeOfAttrExpr(attrs hcl.Attributes, bSchema *schema.BlockSchema) (cty.Ty
 	return typeDecl, true
 }
 
+// asTypeOfAttrDefault returns the type declared as default
+// of the AsTypeOf attribute, if the attribute is omitted
+func (d *PathDecoder) asTypeOfAttrDefault(attrs hcl.Attributes, bSchema *schema.BlockSchema) (cty.Type, bool) {
+	attrName := bSchema.Address.AsTypeOf.AttributeExpr
+	if _, ok := attrs[attrName]; ok {
+		return cty.DynamicPseudoType, false
+	}
+
+	aSchema, ok := bSchema.Body.Attributes[attrName]
+	if !ok {
+		return cty.DynamicPseudoType, false
+	}
+
+	def, ok := aSchema.ResolveDefault(d.defaultContextForAttributes(attrs)).(schema.DefaultTypeDeclaration)
+	if !ok {
+		return cty.DynamicPseudoType, false
+	}
+
+	return def.Type, true
+}
+
 func bodySchemaAsAttrTypes(bodySchema *schema.BodySchema) map[string]cty.Type {
 	attrTypes := make(map[string]cty.Type, 0)
 
@@ -426,6 +545,12 @@ func (d *PathDecoder) collectInferredReferenceTargetsForBody(addr lang.Address,
 					attrType = typ
 				}
 			}
+		} else if attrType == cty.DynamicPseudoType && aSchema.DefaultValue != nil {
+			// use type of the default value if attribute is omitted
+			def, ok := aSchema.ResolveDefault(d.defaultContextForAttributes(rawAttributes)).(schema.DefaultValue)
+			if ok && !def.Value.IsNull() {
+				attrType = def.Value.Type()
+			}
 		}
 
 		if attrType == cty.NilType {
@@ -3794,6 +3794,101 @@ provider "test" {
 							Byte:   15,
 						},
 					},
+					TypeDefRangePtr: &hcl.Range{
+						Filename: "test.tf",
+						Start: hcl.Pos{
+							Line:   2,
+							Column: 3,
+							Byte:   20,
+						},
+						End: hcl.Pos{
+							Line:   2,
+							Column: 7,
+							Byte:   24,
+						},
+					},
+				},
+			},
+		},
+		{
+			"block as data type per attribute - optional attribute with default",
+			&schema.BodySchema{
+				Blocks: map[string]*schema.BlockSchema{
+					"variable": {
+						Labels: []*schema.LabelSchema{
+							{Name: "name"},
+						},
+						Address: &schema.BlockAddrSchema{
+							Steps: []schema.AddrStep{
+								schema.LabelStep{Index: 0},
+							},
+							AsTypeOf: &schema.BlockAsTypeOf{
+								AttributeExpr: "type",
+							},
+						},
+						Type: schema.BlockTypeObject,
+						Body: &schema.BodySchema{
+							Attributes: map[string]*schema.AttributeSchema{
+								"type": {
+									IsOptional: true,
+									Constraint: schema.TypeDeclaration{OptionalAttrs: true},
+								},
+							},
+						},
+					},
+				},
+			},
+			`variable "test" {
+  type = object({ foo = optional(string, "x") })
+}
+`,
+			reference.Targets{
+				{
+					Addr: lang.Address{
+						lang.RootStep{Name: "test"},
+					},
+					Type: cty.Object(map[string]cty.Type{
+						"foo": cty.String,
+					}),
+					RangePtr: &hcl.Range{
+						Filename: "test.tf",
+						Start: hcl.Pos{
+							Line:   1,
+							Column: 1,
+							Byte:   0,
+						},
+						End: hcl.Pos{
+							Line:   3,
+							Column: 2,
+							Byte:   68,
+						},
+					},
+					DefRangePtr: &hcl.Range{
+						Filename: "test.tf",
+						Start: hcl.Pos{
+							Line:   1,
+							Column: 1,
+							Byte:   0,
+						},
+						End: hcl.Pos{
+							Line:   1,
+							Column: 16,
+							Byte:   15,
+						},
+					},
+					TypeDefRangePtr: &hcl.Range{
+						Filename: "test.tf",
+						Start: hcl.Pos{
+							Line:   2,
+							Column: 3,
+							Byte:   20,
+						},
+						End: hcl.Pos{
+							Line:   2,
+							Column: 7,
+							Byte:   24,
+						},
+					},
 				},
 			},
 		},
@@ -3939,6 +4034,19 @@ provider "test" {
 							Byte:   15,
 						},
 					},
+					TypeDefRangePtr: &hcl.Range{
+						Filename: "test.tf",
+						Start: hcl.Pos{
+							Line:   2,
+							Column: 3,
+							Byte:   20,
+						},
+						End: hcl.Pos{
+							Line:   2,
+							Column: 7,
+							Byte:   24,
+						},
+					},
 				},
 			},
 		},
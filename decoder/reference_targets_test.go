@@ -355,6 +355,282 @@ func TestReferenceTargetForOriginAtPos(t *testing.T) {
 	}
 }
 
+func TestReferenceDeclarationsForOriginAtPos(t *testing.T) {
+	dirPath := t.TempDir()
+
+	d := NewDecoder(&testPathReader{
+		paths: map[string]*PathContext{
+			dirPath: {
+				ReferenceOrigins: reference.Origins{
+					reference.LocalOrigin{
+						Addr: lang.Address{
+							lang.RootStep{Name: "var"},
+							lang.AttrStep{Name: "foo"},
+						},
+						Constraints: reference.OriginConstraints{
+							{OfType: cty.String},
+						},
+						Range: hcl.Range{
+							Filename: "test.tf",
+							Start:    hcl.Pos{Line: 1, Column: 1, Byte: 0},
+							End:      hcl.Pos{Line: 1, Column: 8, Byte: 7},
+						},
+					},
+				},
+				ReferenceTargets: reference.Targets{
+					{
+						Addr: lang.Address{
+							lang.RootStep{Name: "var"},
+							lang.AttrStep{Name: "foo"},
+						},
+						Type: cty.String,
+						RangePtr: &hcl.Range{
+							Filename: "test.tf",
+							Start:    hcl.Pos{Line: 2, Column: 1, Byte: 10},
+							End:      hcl.Pos{Line: 4, Column: 2, Byte: 40},
+						},
+						DefRangePtr: &hcl.Range{
+							Filename: "test.tf",
+							Start:    hcl.Pos{Line: 2, Column: 1, Byte: 10},
+							End:      hcl.Pos{Line: 2, Column: 15, Byte: 24},
+						},
+					},
+				},
+			},
+		},
+	})
+
+	targets, err := d.ReferenceDeclarationsForOriginAtPos(lang.Path{Path: dirPath}, "test.tf", hcl.InitialPos)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectedTargets := ReferenceTargets{
+		{
+			OriginRange: hcl.Range{
+				Filename: "test.tf",
+				Start:    hcl.Pos{Line: 1, Column: 1, Byte: 0},
+				End:      hcl.Pos{Line: 1, Column: 8, Byte: 7},
+			},
+			Path: lang.Path{Path: dirPath},
+			Range: hcl.Range{
+				Filename: "test.tf",
+				Start:    hcl.Pos{Line: 2, Column: 1, Byte: 10},
+				End:      hcl.Pos{Line: 2, Column: 15, Byte: 24},
+			},
+			DefRangePtr: &hcl.Range{
+				Filename: "test.tf",
+				Start:    hcl.Pos{Line: 2, Column: 1, Byte: 10},
+				End:      hcl.Pos{Line: 2, Column: 15, Byte: 24},
+			},
+		},
+	}
+	if diff := cmp.Diff(expectedTargets, targets); diff != "" {
+		t.Fatalf("mismatch of reference targets: %s", diff)
+	}
+}
+
+func TestReferenceTypeDefinitionsForOriginAtPos(t *testing.T) {
+	dirPath := t.TempDir()
+
+	bodySchema := &schema.BodySchema{
+		Blocks: map[string]*schema.BlockSchema{
+			"variable": {
+				Labels: []*schema.LabelSchema{
+					{Name: "name"},
+				},
+				Address: &schema.BlockAddrSchema{
+					Steps: []schema.AddrStep{
+						schema.StaticStep{Name: "var"},
+						schema.LabelStep{Index: 0},
+					},
+					ScopeId: lang.ScopeId("variable"),
+					AsTypeOf: &schema.BlockAsTypeOf{
+						AttributeExpr: "type",
+					},
+				},
+				Body: &schema.BodySchema{
+					Attributes: map[string]*schema.AttributeSchema{
+						"type": {
+							IsOptional: true,
+							Constraint: schema.TypeDeclaration{},
+						},
+					},
+				},
+			},
+			"output": {
+				Labels: []*schema.LabelSchema{
+					{Name: "name"},
+				},
+				Body: &schema.BodySchema{
+					Attributes: map[string]*schema.AttributeSchema{
+						"value": {
+							IsOptional: true,
+							Constraint: schema.AnyExpression{OfType: cty.DynamicPseudoType},
+						},
+					},
+				},
+			},
+		},
+	}
+	cfg := `variable "foo" {
+  type = object({
+    attr = string
+  })
+}
+output "bar" {
+  value = var.foo.attr
+}
+`
+	f, _ := hclsyntax.ParseConfig([]byte(cfg), "test.tf", hcl.InitialPos)
+	pathCtx := &PathContext{
+		Schema: bodySchema,
+		Files: map[string]*hcl.File{
+			"test.tf": f,
+		},
+	}
+	d := NewDecoder(&testPathReader{
+		paths: map[string]*PathContext{
+			dirPath: pathCtx,
+		},
+	})
+
+	pd, err := d.Path(lang.Path{Path: dirPath})
+	if err != nil {
+		t.Fatal(err)
+	}
+	pathCtx.ReferenceTargets, err = pd.CollectReferenceTargets()
+	if err != nil {
+		t.Fatal(err)
+	}
+	pathCtx.ReferenceOrigins, err = pd.CollectReferenceOrigins()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	targets, err := d.ReferenceTypeDefinitionsForOriginAtPos(lang.Path{Path: dirPath}, "test.tf", hcl.Pos{
+		Line:   7,
+		Column: 20,
+		Byte:   94,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	typeDefRange := hcl.Range{
+		Filename: "test.tf",
+		Start:    hcl.Pos{Line: 2, Column: 3, Byte: 19},
+		End:      hcl.Pos{Line: 2, Column: 7, Byte: 23},
+	}
+	expectedTargets := ReferenceTargets{
+		{
+			OriginRange: hcl.Range{
+				Filename: "test.tf",
+				Start:    hcl.Pos{Line: 7, Column: 11, Byte: 85},
+				End:      hcl.Pos{Line: 7, Column: 23, Byte: 97},
+			},
+			Path:        lang.Path{Path: dirPath},
+			Range:       typeDefRange,
+			DefRangePtr: typeDefRange.Ptr(),
+		},
+	}
+	if diff := cmp.Diff(expectedTargets, targets); diff != "" {
+		t.Fatalf("mismatch of reference targets: %s", diff)
+	}
+}
+
+func TestReferenceTypeDefinitionsForOriginAtPos_unresolvedType(t *testing.T) {
+	dirPath := t.TempDir()
+
+	bodySchema := &schema.BodySchema{
+		Blocks: map[string]*schema.BlockSchema{
+			"variable": {
+				Labels: []*schema.LabelSchema{
+					{Name: "name"},
+				},
+				Address: &schema.BlockAddrSchema{
+					Steps: []schema.AddrStep{
+						schema.StaticStep{Name: "var"},
+						schema.LabelStep{Index: 0},
+					},
+					ScopeId: lang.ScopeId("variable"),
+					AsTypeOf: &schema.BlockAsTypeOf{
+						AttributeExpr: "type",
+					},
+				},
+				Body: &schema.BodySchema{
+					Attributes: map[string]*schema.AttributeSchema{
+						"type": {
+							IsOptional: true,
+							Constraint: schema.TypeDeclaration{},
+						},
+					},
+				},
+			},
+			"output": {
+				Labels: []*schema.LabelSchema{
+					{Name: "name"},
+				},
+				Body: &schema.BodySchema{
+					Attributes: map[string]*schema.AttributeSchema{
+						"value": {
+							IsOptional: true,
+							Constraint: schema.AnyExpression{OfType: cty.DynamicPseudoType},
+						},
+					},
+				},
+			},
+		},
+	}
+	cfg := `variable "foo" {
+  type = invalid
+}
+output "bar" {
+  value = var.foo
+}
+`
+	f, _ := hclsyntax.ParseConfig([]byte(cfg), "test.tf", hcl.InitialPos)
+	pathCtx := &PathContext{
+		Schema: bodySchema,
+		Files: map[string]*hcl.File{
+			"test.tf": f,
+		},
+	}
+	d := NewDecoder(&testPathReader{
+		paths: map[string]*PathContext{
+			dirPath: pathCtx,
+		},
+	})
+
+	pd, err := d.Path(lang.Path{Path: dirPath})
+	if err != nil {
+		t.Fatal(err)
+	}
+	pathCtx.ReferenceTargets, err = pd.CollectReferenceTargets()
+	if err != nil {
+		t.Fatal(err)
+	}
+	pathCtx.ReferenceOrigins, err = pd.CollectReferenceOrigins()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	targets, err := d.ReferenceTypeDefinitionsForOriginAtPos(lang.Path{Path: dirPath}, "test.tf", hcl.Pos{
+		Line:   5,
+		Column: 14,
+		Byte:   64,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the type attribute does not declare any valid type
+	expectedTargets := ReferenceTargets{}
+	if diff := cmp.Diff(expectedTargets, targets); diff != "" {
+		t.Fatalf("mismatch of reference targets: %s", diff)
+	}
+}
+
 func TestCollectReferenceTargets_nil_expr(t *testing.T) {
 	// provider:: is not a traversal expression, so hcl will return a ExprSyntaxError which needs to be handled
 	f, _ := hclsyntax.ParseConfig([]byte(`attr = provider::`), "test.tf", hcl.InitialPos)
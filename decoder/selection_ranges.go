@@ -0,0 +1,153 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package decoder
+
+import (
+	"errors"
+
+	"github.com/hashicorp/hcl-lang/lang"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+)
+
+// SelectionRangesAtPos returns a selection range for each
+// of the given positions, starting from the innermost node
+// (e.g. a traversal step) and expanding outwards through
+// expressions, attributes and blocks up to the whole file.
+//
+// Positions outside of the file are represented by an empty range
+// at the position, so that the result still corresponds to the
+// given positions.
+func (d *PathDecoder) SelectionRangesAtPos(filename string, positions []hcl.Pos) ([]*lang.SelectionRange, error) {
+	f, err := d.fileByName(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	selectionRanges := make([]*lang.SelectionRange, 0, len(positions))
+	for _, pos := range positions {
+		body, err := d.bodyForFileAndPos(filename, f, pos)
+		if err != nil {
+			var posErr *PosOutOfRangeError
+			if !errors.As(err, &posErr) {
+				return nil, err
+			}
+			selectionRanges = append(selectionRanges, &lang.SelectionRange{
+				Range: hcl.Range{
+					Filename: filename,
+					Start:    pos,
+					End:      pos,
+				},
+			})
+			continue
+		}
+
+		ranges := []hcl.Range{body.Range()}
+		ranges = append(ranges, selectionRangesForBody(body, pos)...)
+
+		selectionRanges = append(selectionRanges, newSelectionRange(ranges))
+	}
+
+	return selectionRanges, nil
+}
+
+// selectionRangesForBody returns ranges of nodes within
+// the body which contain the position, outermost first
+func selectionRangesForBody(body *hclsyntax.Body, pos hcl.Pos) []hcl.Range {
+	for _, attr := range body.Attributes {
+		if !selectionContainsPos(attr.SrcRange, pos) {
+			continue
+		}
+
+		ranges := []hcl.Range{attr.SrcRange}
+		if selectionContainsPos(attr.NameRange, pos) {
+			return append(ranges, attr.NameRange)
+		}
+		return append(ranges, selectionRangesForExpr(attr.Expr, pos)...)
+	}
+
+	for _, block := range body.Blocks {
+		if !selectionContainsPos(block.Range(), pos) {
+			continue
+		}
+
+		ranges := []hcl.Range{block.Range()}
+		if selectionContainsPos(block.TypeRange, pos) {
+			return append(ranges, block.TypeRange)
+		}
+		for _, labelRange := range block.LabelRanges {
+			if selectionContainsPos(labelRange, pos) {
+				return append(ranges, labelRange)
+			}
+		}
+		if block.Body != nil && selectionContainsPos(block.Body.Range(), pos) {
+			ranges = append(ranges, block.Body.Range())
+			ranges = append(ranges, selectionRangesForBody(block.Body, pos)...)
+		}
+		return ranges
+	}
+
+	return []hcl.Range{}
+}
+
+// selectionRangesForExpr returns ranges of the expression
+// and any nested expressions containing the position,
+// down to an individual traversal step, outermost first
+func selectionRangesForExpr(expr hclsyntax.Expression, pos hcl.Pos) []hcl.Range {
+	ranges := make([]hcl.Range, 0)
+
+	hclsyntax.VisitAll(expr, func(node hclsyntax.Node) hcl.Diagnostics {
+		rng := node.Range()
+		if !selectionContainsPos(rng, pos) {
+			return nil
+		}
+		if len(ranges) > 0 && !rangeContainsRange(ranges[len(ranges)-1], rng) {
+			return nil
+		}
+		ranges = append(ranges, rng)
+
+		var traversal hcl.Traversal
+		switch e := node.(type) {
+		case *hclsyntax.ScopeTraversalExpr:
+			traversal = e.Traversal
+		case *hclsyntax.RelativeTraversalExpr:
+			traversal = e.Traversal
+		}
+		for _, step := range traversal {
+			if selectionContainsPos(step.SourceRange(), pos) {
+				ranges = append(ranges, step.SourceRange())
+				break
+			}
+		}
+
+		return nil
+	})
+
+	return ranges
+}
+
+// newSelectionRange returns the innermost selection range
+// linked to its parents, given ranges ordered outermost first.
+// Duplicate ranges (e.g. a template wrapping a single
+// interpolation) are represented just once.
+func newSelectionRange(ranges []hcl.Range) *lang.SelectionRange {
+	var selectionRange *lang.SelectionRange
+	for _, rng := range ranges {
+		if selectionRange != nil && rangesEqual(selectionRange.Range, rng) {
+			continue
+		}
+		selectionRange = &lang.SelectionRange{
+			Range:  rng,
+			Parent: selectionRange,
+		}
+	}
+	return selectionRange
+}
+
+// selectionContainsPos returns true if the range contains the position,
+// including the end position, such that a cursor placed right after
+// a node (e.g. at the end of a word) still selects that node
+func selectionContainsPos(rng hcl.Range, pos hcl.Pos) bool {
+	return rng.Start.Byte <= pos.Byte && pos.Byte <= rng.End.Byte
+}
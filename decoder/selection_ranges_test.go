@@ -0,0 +1,158 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package decoder
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/hcl-lang/lang"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+)
+
+func TestDecoder_SelectionRangesAtPos(t *testing.T) {
+	f, pDiags := hclsyntax.ParseConfig([]byte(`resource "aws_instance" "web" {
+  ami = lookup(var.amis, "us")
+}
+`), "test.tf", hcl.InitialPos)
+	if len(pDiags) > 0 {
+		t.Fatal(pDiags)
+	}
+
+	d := testPathDecoder(t, &PathContext{
+		Files: map[string]*hcl.File{
+			"test.tf": f,
+		},
+	})
+
+	selectionRanges, err := d.SelectionRangesAtPos("test.tf", []hcl.Pos{
+		{Line: 2, Column: 22, Byte: 53},
+		{Line: 1, Column: 12, Byte: 11},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fileRange := &lang.SelectionRange{
+		Range: hcl.Range{
+			Filename: "test.tf",
+			Start:    hcl.Pos{Line: 1, Column: 1, Byte: 0},
+			End:      hcl.Pos{Line: 4, Column: 1, Byte: 65},
+		},
+	}
+	blockRange := &lang.SelectionRange{
+		Range: hcl.Range{
+			Filename: "test.tf",
+			Start:    hcl.Pos{Line: 1, Column: 1, Byte: 0},
+			End:      hcl.Pos{Line: 3, Column: 2, Byte: 64},
+		},
+		Parent: fileRange,
+	}
+
+	expectedRanges := []*lang.SelectionRange{
+		{
+			// traversal step
+			Range: hcl.Range{
+				Filename: "test.tf",
+				Start:    hcl.Pos{Line: 2, Column: 19, Byte: 50},
+				End:      hcl.Pos{Line: 2, Column: 24, Byte: 55},
+			},
+			Parent: &lang.SelectionRange{
+				// traversal (function argument)
+				Range: hcl.Range{
+					Filename: "test.tf",
+					Start:    hcl.Pos{Line: 2, Column: 16, Byte: 47},
+					End:      hcl.Pos{Line: 2, Column: 24, Byte: 55},
+				},
+				Parent: &lang.SelectionRange{
+					// function call (attribute value)
+					Range: hcl.Range{
+						Filename: "test.tf",
+						Start:    hcl.Pos{Line: 2, Column: 9, Byte: 40},
+						End:      hcl.Pos{Line: 2, Column: 31, Byte: 62},
+					},
+					Parent: &lang.SelectionRange{
+						// attribute
+						Range: hcl.Range{
+							Filename: "test.tf",
+							Start:    hcl.Pos{Line: 2, Column: 3, Byte: 34},
+							End:      hcl.Pos{Line: 2, Column: 31, Byte: 62},
+						},
+						Parent: &lang.SelectionRange{
+							// block body
+							Range: hcl.Range{
+								Filename: "test.tf",
+								Start:    hcl.Pos{Line: 1, Column: 31, Byte: 30},
+								End:      hcl.Pos{Line: 3, Column: 2, Byte: 64},
+							},
+							Parent: blockRange,
+						},
+					},
+				},
+			},
+		},
+		{
+			// block label
+			Range: hcl.Range{
+				Filename: "test.tf",
+				Start:    hcl.Pos{Line: 1, Column: 10, Byte: 9},
+				End:      hcl.Pos{Line: 1, Column: 24, Byte: 23},
+			},
+			Parent: blockRange,
+		},
+	}
+
+	if diff := cmp.Diff(expectedRanges, selectionRanges); diff != "" {
+		t.Fatalf("unexpected selection ranges: %s", diff)
+	}
+}
+
+func TestDecoder_SelectionRangesAtPos_outOfRange(t *testing.T) {
+	f, _ := hclsyntax.ParseConfig([]byte(`attr = "foo"`), "test.tf", hcl.InitialPos)
+	d := testPathDecoder(t, &PathContext{
+		Files: map[string]*hcl.File{
+			"test.tf": f,
+		},
+	})
+
+	selectionRanges, err := d.SelectionRangesAtPos("test.tf", []hcl.Pos{
+		{Line: 5, Column: 1, Byte: 42},
+		{Line: 1, Column: 2, Byte: 1},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectedRanges := []*lang.SelectionRange{
+		{
+			// position out of range
+			Range: hcl.Range{
+				Filename: "test.tf",
+				Start:    hcl.Pos{Line: 5, Column: 1, Byte: 42},
+				End:      hcl.Pos{Line: 5, Column: 1, Byte: 42},
+			},
+		},
+		{
+			// attribute name
+			Range: hcl.Range{
+				Filename: "test.tf",
+				Start:    hcl.Pos{Line: 1, Column: 1, Byte: 0},
+				End:      hcl.Pos{Line: 1, Column: 5, Byte: 4},
+			},
+			Parent: &lang.SelectionRange{
+				// attribute
+				Range: hcl.Range{
+					Filename: "test.tf",
+					Start:    hcl.Pos{Line: 1, Column: 1, Byte: 0},
+					End:      hcl.Pos{Line: 1, Column: 13, Byte: 12},
+				},
+			},
+		},
+	}
+
+	if diff := cmp.Diff(expectedRanges, selectionRanges); diff != "" {
+		t.Fatalf("unexpected selection ranges: %s", diff)
+	}
+}
@@ -12,11 +12,25 @@ import (
 	"github.com/hashicorp/hcl-lang/schema"
 	"github.com/hashicorp/hcl/v2"
 	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/hcl/v2/json"
 )
 
 // SemanticTokensInFile returns a sequence of semantic tokens
 // within the config file.
 func (d *PathDecoder) SemanticTokensInFile(ctx context.Context, filename string) ([]lang.SemanticToken, error) {
+	return d.semanticTokensInFile(ctx, filename, nil)
+}
+
+// SemanticTokensInRange returns a sequence of semantic tokens
+// within the given range of the config file.
+//
+// Only blocks and attributes overlapping the range are walked,
+// which makes it cheaper than SemanticTokensInFile for large files.
+func (d *PathDecoder) SemanticTokensInRange(ctx context.Context, filename string, rng hcl.Range) ([]lang.SemanticToken, error) {
+	return d.semanticTokensInFile(ctx, filename, &rng)
+}
+
+func (d *PathDecoder) semanticTokensInFile(ctx context.Context, filename string, rng *hcl.Range) ([]lang.SemanticToken, error) {
 	f, err := d.fileByName(filename)
 	if err != nil {
 		return nil, err
@@ -31,13 +45,21 @@ func (d *PathDecoder) SemanticTokensInFile(ctx context.Context, filename string)
 		return []lang.SemanticToken{}, nil
 	}
 
-	tokens := d.tokensForBody(ctx, body, d.pathCtx.Schema, []lang.SemanticTokenModifier{})
+	ctx = withSemanticTokenModifiers(ctx, d.decoderCtx.EnabledSemanticTokenModifiers)
 
-	// TODO decouple semantic tokens for valid references from AST walking
-	//   instead of matching targets and origins when encountering a traversal expression,
-	//   we can do this way earlier by comparing pathCtx.ReferenceTargets and
-	//   d.pathCtx.ReferenceOrigins, to build a list of tokens.
-	//   Be sure to sort them afterward!
+	bodyTokens := d.tokensForBody(ctx, body, d.pathCtx.Schema, []lang.SemanticTokenModifier{}, rng)
+	bodyTokens = withDeclarationModifiers(ctx, bodyTokens, d.pathCtx.ReferenceTargets)
+	bodyTokens = append(bodyTokens, d.referenceTokensForFile(ctx, f, filename, rng)...)
+	if !json.IsJSONBody(f.Body) {
+		bodyTokens = mergeSyntaxTokens(bodyTokens, d.syntaxTokensForFile(filename, body, rng))
+	}
+
+	tokens := make([]lang.SemanticToken, 0, len(bodyTokens))
+	for _, token := range bodyTokens {
+		if rng == nil || rangesOverlap(token.Range, *rng) {
+			tokens = append(tokens, token)
+		}
+	}
 
 	sort.Slice(tokens, func(i, j int) bool {
 		return tokens[i].Range.Start.Byte < tokens[j].Range.Start.Byte
@@ -46,7 +68,9 @@ func (d *PathDecoder) SemanticTokensInFile(ctx context.Context, filename string)
 	return tokens, nil
 }
 
-func (d *PathDecoder) tokensForBody(ctx context.Context, body *hclsyntax.Body, bodySchema *schema.BodySchema, parentModifiers []lang.SemanticTokenModifier) []lang.SemanticToken {
+// tokensForBody returns tokens for the given body. If rng is not nil,
+// only attributes and blocks overlapping the range are walked.
+func (d *PathDecoder) tokensForBody(ctx context.Context, body *hclsyntax.Body, bodySchema *schema.BodySchema, parentModifiers []lang.SemanticTokenModifier, rng *hcl.Range) []lang.SemanticToken {
 	tokens := make([]lang.SemanticToken, 0)
 
 	if bodySchema == nil {
@@ -54,6 +78,10 @@ func (d *PathDecoder) tokensForBody(ctx context.Context, body *hclsyntax.Body, b
 	}
 
 	for name, attr := range body.Attributes {
+		if rng != nil && !rangesOverlap(attr.SrcRange, *rng) {
+			continue
+		}
+
 		attrSchema, ok := bodySchema.Attributes[name]
 		if !ok {
 			if bodySchema.Extensions != nil && name == "count" && bodySchema.Extensions.Count {
@@ -72,6 +100,7 @@ func (d *PathDecoder) tokensForBody(ctx context.Context, body *hclsyntax.Body, b
 		attrModifiers := make([]lang.SemanticTokenModifier, 0)
 		attrModifiers = append(attrModifiers, parentModifiers...)
 		attrModifiers = append(attrModifiers, attrSchema.SemanticTokenModifiers...)
+		attrModifiers = append(attrModifiers, attributeSemanticTokenModifiers(ctx, attrSchema)...)
 
 		tokens = append(tokens, lang.SemanticToken{
 			Type:      lang.TokenAttrName,
@@ -83,6 +112,10 @@ func (d *PathDecoder) tokensForBody(ctx context.Context, body *hclsyntax.Body, b
 	}
 
 	for _, block := range body.Blocks {
+		if rng != nil && !rangesOverlap(block.Range(), *rng) {
+			continue
+		}
+
 		blockSchema, hasDepSchema := bodySchema.Blocks[block.Type]
 		if !hasDepSchema {
 			// unknown block
@@ -93,9 +126,17 @@ func (d *PathDecoder) tokensForBody(ctx context.Context, body *hclsyntax.Body, b
 		blockModifiers = append(blockModifiers, parentModifiers...)
 		blockModifiers = append(blockModifiers, blockSchema.SemanticTokenModifiers...)
 
+		mergedSchema, _ := d.mergeBlockBodySchemas(block.AsHCLBlock(), blockSchema)
+		// built-in modifiers are not inherited by the nested body
+		builtinModifiers := blockSemanticTokenModifiers(ctx, blockSchema, mergedSchema)
+
+		blockTypeModifiers := make([]lang.SemanticTokenModifier, 0)
+		blockTypeModifiers = append(blockTypeModifiers, blockModifiers...)
+		blockTypeModifiers = append(blockTypeModifiers, builtinModifiers...)
+
 		tokens = append(tokens, lang.SemanticToken{
 			Type:      lang.TokenBlockType,
-			Modifiers: blockModifiers,
+			Modifiers: blockTypeModifiers,
 			Range:     block.TypeRange,
 		})
 
@@ -111,6 +152,7 @@ func (d *PathDecoder) tokensForBody(ctx context.Context, body *hclsyntax.Body, b
 			labelModifiers = append(labelModifiers, parentModifiers...)
 			labelModifiers = append(labelModifiers, blockSchema.SemanticTokenModifiers...)
 			labelModifiers = append(labelModifiers, labelSchema.SemanticTokenModifiers...)
+			labelModifiers = append(labelModifiers, builtinModifiers...)
 
 			tokens = append(tokens, lang.SemanticToken{
 				Type:      lang.TokenBlockLabel,
@@ -120,15 +162,26 @@ func (d *PathDecoder) tokensForBody(ctx context.Context, body *hclsyntax.Body, b
 		}
 
 		if block.Body != nil {
-			mergedSchema, _ := schemahelper.MergeBlockBodySchemas(block.AsHCLBlock(), blockSchema)
-
-			tokens = append(tokens, d.tokensForBody(ctx, block.Body, mergedSchema, blockModifiers)...)
+			tokens = append(tokens, d.tokensForBody(ctx, block.Body, mergedSchema, blockModifiers, rng)...)
 		}
 	}
 
 	return tokens
 }
 
+// rangesOverlap reports whether the two ranges within the same file
+// share at least one byte, or whether an empty range is positioned
+// within the other range
+func rangesOverlap(one, other hcl.Range) bool {
+	if one.Filename != other.Filename {
+		return false
+	}
+	if one.Empty() || other.Empty() {
+		return one.Start.Byte <= other.End.Byte && other.Start.Byte <= one.End.Byte
+	}
+	return one.Start.Byte < other.End.Byte && other.Start.Byte < one.End.Byte
+}
+
 func isPrimitiveTypeDeclaration(kw string) bool {
 	switch kw {
 	case "bool":
@@ -0,0 +1,407 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package decoder
+
+import (
+	"bytes"
+	"context"
+	"slices"
+	"strconv"
+	"sync"
+	"unicode/utf8"
+
+	"github.com/hashicorp/hcl-lang/lang"
+	"github.com/hashicorp/hcl/v2"
+)
+
+// SemanticTokensResult represents semantic tokens of a file
+// identified by ResultID, which can be passed to SemanticTokensDelta
+// to obtain changes since this result
+type SemanticTokensResult struct {
+	ResultID string
+	Tokens   []lang.SemanticToken
+
+	// Data represents Tokens in the relative encoding used by LSP,
+	// see SemanticTokensLegend
+	Data []uint32
+}
+
+// SemanticTokensDelta represents changes to semantic tokens of a file
+// since a previous result
+type SemanticTokensDelta struct {
+	ResultID string
+	Edits    []SemanticTokensEdit
+}
+
+// SemanticTokensEdit represents a single edit of the previous
+// encoded data, as in LSP's SemanticTokensEdit, i.e. Start
+// and DeleteCount are expressed in integers, not in tokens.
+type SemanticTokensEdit struct {
+	Start       uint
+	DeleteCount uint
+	Data        []uint32
+}
+
+// SemanticTokensLegend represents token types and modifiers
+// whose indexes are used when encoding semantic tokens,
+// typically those the server declared to the client.
+//
+// Tokens of types missing from the legend are not encoded
+// and modifiers missing from the legend are ignored.
+type SemanticTokensLegend struct {
+	TokenTypes     lang.SemanticTokenTypes
+	TokenModifiers lang.SemanticTokenModifiers
+
+	// MultilineTokenSupport indicates that the client supports
+	// tokens spanning multiple lines (e.g. heredocs), which are
+	// otherwise encoded as one token per line.
+	MultilineTokenSupport bool
+}
+
+// semanticTokenInts is the number of integers representing
+// a single token in the relative encoding
+const semanticTokenInts = 5
+
+// Encode returns the tokens in the relative encoding used by LSP, i.e.
+// five integers per token: deltaLine, deltaStart, length, token type index
+// and bitset of modifier indexes.
+//
+// Columns and lengths are expressed in UTF-16 code units of src,
+// the content of the file the tokens belong to.
+func (l SemanticTokensLegend) Encode(src []byte, tokens []lang.SemanticToken) []uint32 {
+	data := make([]uint32, 0, len(tokens)*semanticTokenInts)
+
+	prevLine, prevStart := 0, 0
+	for _, token := range tokens {
+		typeIdx, ok := semanticTokenTypeIndex(l.TokenTypes, token.Type)
+		if !ok {
+			continue
+		}
+		modifiers := semanticTokenModifiersBitset(l.TokenModifiers, token.Modifiers)
+
+		for _, segment := range l.semanticTokenSegments(src, token.Range) {
+			deltaStart := segment.start
+			if segment.line == prevLine {
+				deltaStart = segment.start - prevStart
+			}
+
+			data = append(data,
+				uint32(segment.line-prevLine),
+				uint32(deltaStart),
+				uint32(segment.length),
+				uint32(typeIdx),
+				modifiers)
+
+			prevLine, prevStart = segment.line, segment.start
+		}
+	}
+
+	return data
+}
+
+// semanticTokenSegment represents (part of) a token
+// as a zero-based line, start and length in UTF-16 code units
+type semanticTokenSegment struct {
+	line, start, length int
+}
+
+// semanticTokenSegments returns segments representing the token,
+// i.e. one segment per line, unless the token is within a single
+// line or the client supports multiline tokens.
+func (l SemanticTokensLegend) semanticTokenSegments(src []byte, rng hcl.Range) []semanticTokenSegment {
+	if rng.Start.Byte < 0 || rng.End.Byte > len(src) || rng.Start.Byte > rng.End.Byte {
+		// the range does not match the source, so we
+		// can only rely on lines and columns as counted by HCL
+		return []semanticTokenSegment{
+			{
+				line:   rng.Start.Line - 1,
+				start:  rng.Start.Column - 1,
+				length: rng.End.Column - rng.Start.Column,
+			},
+		}
+	}
+
+	lineStart := bytes.LastIndexByte(src[:rng.Start.Byte], '\n') + 1
+
+	if rng.Start.Line == rng.End.Line || l.MultilineTokenSupport {
+		return []semanticTokenSegment{
+			{
+				line:   rng.Start.Line - 1,
+				start:  utf16Len(src[lineStart:rng.Start.Byte]),
+				length: utf16Len(src[rng.Start.Byte:rng.End.Byte]),
+			},
+		}
+	}
+
+	segments := make([]semanticTokenSegment, 0)
+	line, startByte := rng.Start.Line-1, rng.Start.Byte
+	for startByte < rng.End.Byte {
+		endByte := rng.End.Byte
+		nextStart := rng.End.Byte
+		if i := bytes.IndexByte(src[startByte:rng.End.Byte], '\n'); i >= 0 {
+			endByte = startByte + i
+			nextStart = endByte + 1
+			if endByte > startByte && src[endByte-1] == '\r' {
+				endByte--
+			}
+		}
+
+		if endByte > startByte {
+			segments = append(segments, semanticTokenSegment{
+				line:   line,
+				start:  utf16Len(src[lineStart:startByte]),
+				length: utf16Len(src[startByte:endByte]),
+			})
+		}
+
+		line++
+		lineStart, startByte = nextStart, nextStart
+	}
+
+	return segments
+}
+
+// utf16Len returns the number of UTF-16 code units representing b
+func utf16Len(b []byte) int {
+	n := 0
+	for len(b) > 0 {
+		r, size := utf8.DecodeRune(b)
+		b = b[size:]
+		if r >= 0x10000 {
+			n += 2
+			continue
+		}
+		n++
+	}
+	return n
+}
+
+func semanticTokenTypeIndex(types lang.SemanticTokenTypes, tokenType lang.SemanticTokenType) (int, bool) {
+	for i, t := range types {
+		if t == tokenType {
+			return i, true
+		}
+	}
+	return 0, false
+}
+
+func semanticTokenModifiersBitset(legend, modifiers lang.SemanticTokenModifiers) uint32 {
+	var bitset uint32
+	for i, m := range legend {
+		if i >= 32 {
+			break
+		}
+		if modifiers.Contains(m) {
+			bitset |= 1 << i
+		}
+	}
+	return bitset
+}
+
+// semanticTokensLegend returns the legend declared in the decoder context,
+// or a legend of all token types and modifiers which may be reported
+func (d *PathDecoder) semanticTokensLegend() SemanticTokensLegend {
+	legend := d.decoderCtx.SemanticTokensLegend
+	if len(legend.TokenTypes) > 0 {
+		return legend
+	}
+
+	tokenTypes := make(lang.SemanticTokenTypes, 0)
+	tokenTypes = append(tokenTypes, lang.SupportedSemanticTokenTypes...)
+	tokenTypes = append(tokenTypes, d.decoderCtx.EnabledSemanticTokenTypes...)
+
+	tokenModifiers := lang.SemanticTokenModifiers{lang.TokenModifierDependent}
+	tokenModifiers = append(tokenModifiers, d.decoderCtx.EnabledSemanticTokenModifiers...)
+
+	return SemanticTokensLegend{
+		TokenTypes:            tokenTypes,
+		TokenModifiers:        tokenModifiers,
+		MultilineTokenSupport: legend.MultilineTokenSupport,
+	}
+}
+
+// SemanticTokensFull returns all semantic tokens in the file,
+// along with a result ID which can be used to request a delta
+// via SemanticTokensDelta later.
+func (d *PathDecoder) SemanticTokensFull(ctx context.Context, filename string) (*SemanticTokensResult, error) {
+	tokens, err := d.SemanticTokensInFile(ctx, filename)
+	if err != nil {
+		return nil, err
+	}
+	src, err := d.bytesForFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	data := d.semanticTokensLegend().Encode(src, tokens)
+
+	d.semanticTokens.prune(d.path, d.filenames())
+
+	return &SemanticTokensResult{
+		ResultID: d.semanticTokens.put(d.path, filename, data),
+		Tokens:   tokens,
+		Data:     data,
+	}, nil
+}
+
+// SemanticTokensDelta returns edits which transform encoded data of the result
+// identified by previousResultID into the current encoded data of the file.
+//
+// Only the latest result of each file is retained, so older IDs return
+// SemanticTokensResultNotFoundError, in which case the caller is expected
+// to fall back to SemanticTokensFull.
+func (d *PathDecoder) SemanticTokensDelta(ctx context.Context, filename string, previousResultID string) (*SemanticTokensDelta, error) {
+	d.semanticTokens.prune(d.path, d.filenames())
+
+	previous, ok := d.semanticTokens.get(d.path, filename, previousResultID)
+	if !ok {
+		return nil, &SemanticTokensResultNotFoundError{
+			Filename: filename,
+			ResultID: previousResultID,
+		}
+	}
+
+	tokens, err := d.SemanticTokensInFile(ctx, filename)
+	if err != nil {
+		return nil, err
+	}
+	src, err := d.bytesForFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	data := d.semanticTokensLegend().Encode(src, tokens)
+
+	return &SemanticTokensDelta{
+		ResultID: d.semanticTokens.put(d.path, filename, data),
+		Edits:    semanticTokensEdits(previous, data),
+	}, nil
+}
+
+// semanticTokensEdits returns a single edit replacing encoded tokens
+// between the common prefix and common suffix of both sequences,
+// or no edits if the sequences are equal.
+//
+// Since the encoding is relative, tokens shifted by an insertion
+// or deletion are still matched as part of the common suffix.
+func semanticTokensEdits(previous, current []uint32) []SemanticTokensEdit {
+	edits := make([]SemanticTokensEdit, 0)
+
+	prevCount := len(previous) / semanticTokenInts
+	curCount := len(current) / semanticTokenInts
+
+	prefix := 0
+	for prefix < prevCount && prefix < curCount &&
+		encodedTokensEqual(previous, prefix, current, prefix) {
+		prefix++
+	}
+
+	suffix := 0
+	for suffix < prevCount-prefix && suffix < curCount-prefix &&
+		encodedTokensEqual(previous, prevCount-1-suffix, current, curCount-1-suffix) {
+		suffix++
+	}
+
+	deleteCount := prevCount - prefix - suffix
+	inserted := current[prefix*semanticTokenInts : (curCount-suffix)*semanticTokenInts]
+	if deleteCount == 0 && len(inserted) == 0 {
+		return edits
+	}
+
+	return append(edits, SemanticTokensEdit{
+		Start:       uint(prefix * semanticTokenInts),
+		DeleteCount: uint(deleteCount * semanticTokenInts),
+		Data:        inserted,
+	})
+}
+
+func encodedTokensEqual(a []uint32, i int, b []uint32, j int) bool {
+	for k := 0; k < semanticTokenInts; k++ {
+		if a[i*semanticTokenInts+k] != b[j*semanticTokenInts+k] {
+			return false
+		}
+	}
+	return true
+}
+
+// semanticTokensCache retains the latest result of SemanticTokensFull
+// or SemanticTokensDelta for each file
+type semanticTokensCache struct {
+	mu      sync.Mutex
+	lastID  uint64
+	results map[semanticTokensCacheKey]semanticTokensCacheEntry
+}
+
+type semanticTokensCacheKey struct {
+	path     string
+	filename string
+}
+
+type semanticTokensCacheEntry struct {
+	resultID string
+	data     []uint32
+}
+
+func newSemanticTokensCache() *semanticTokensCache {
+	return &semanticTokensCache{
+		results: make(map[semanticTokensCacheKey]semanticTokensCacheEntry, 0),
+	}
+}
+
+func (c *semanticTokensCache) put(path lang.Path, filename string, data []uint32) string {
+	if c == nil {
+		return ""
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.lastID++
+	resultID := strconv.FormatUint(c.lastID, 10)
+	c.results[newSemanticTokensCacheKey(path, filename)] = semanticTokensCacheEntry{
+		resultID: resultID,
+		data:     data,
+	}
+
+	return resultID
+}
+
+func (c *semanticTokensCache) get(path lang.Path, filename, resultID string) ([]uint32, bool) {
+	if c == nil {
+		return nil, false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.results[newSemanticTokensCacheKey(path, filename)]
+	if !ok || entry.resultID != resultID {
+		return nil, false
+	}
+	return entry.data, true
+}
+
+// prune drops results of files which are no longer part of the path
+func (c *semanticTokensCache) prune(path lang.Path, filenames []string) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	pathKey := newSemanticTokensCacheKey(path, "").path
+	for key := range c.results {
+		if key.path != pathKey {
+			continue
+		}
+		if !slices.Contains(filenames, key.filename) {
+			delete(c.results, key)
+		}
+	}
+}
+
+func newSemanticTokensCacheKey(path lang.Path, filename string) semanticTokensCacheKey {
+	return semanticTokensCacheKey{
+		path:     path.LanguageID + ":" + path.Path,
+		filename: filename,
+	}
+}
@@ -0,0 +1,343 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package decoder
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/hcl-lang/lang"
+	"github.com/hashicorp/hcl-lang/schema"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/zclconf/go-cty/cty"
+)
+
+var semanticTokensDeltaSchema = &schema.BodySchema{
+	Attributes: map[string]*schema.AttributeSchema{
+		"foo": {Constraint: schema.LiteralType{Type: cty.Number}},
+		"bar": {Constraint: schema.LiteralType{Type: cty.Number}},
+		"baz": {Constraint: schema.LiteralType{Type: cty.Number}},
+	},
+	Blocks: map[string]*schema.BlockSchema{
+		"block": {
+			Body: &schema.BodySchema{
+				Attributes: map[string]*schema.AttributeSchema{
+					"foo": {Constraint: schema.LiteralType{Type: cty.Number}},
+				},
+			},
+		},
+	},
+}
+
+func TestDecoder_SemanticTokensInRange(t *testing.T) {
+	testCfg := []byte(`foo = 1
+block {
+  foo = 2
+}
+bar = 3
+`)
+	f, pDiags := hclsyntax.ParseConfig(testCfg, "test.tf", hcl.InitialPos)
+	if len(pDiags) > 0 {
+		t.Fatal(pDiags)
+	}
+
+	d := testPathDecoder(t, &PathContext{
+		Schema: semanticTokensDeltaSchema,
+		Files: map[string]*hcl.File{
+			"test.tf": f,
+		},
+	})
+
+	ctx := context.Background()
+
+	tokens, err := d.SemanticTokensInRange(ctx, "test.tf", hcl.Range{
+		Filename: "test.tf",
+		Start:    hcl.Pos{Line: 3, Column: 1, Byte: 16},
+		End:      hcl.Pos{Line: 4, Column: 1, Byte: 26},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectedTokens := []lang.SemanticToken{
+		{ // foo
+			Type:      lang.TokenAttrName,
+			Modifiers: []lang.SemanticTokenModifier{},
+			Range: hcl.Range{
+				Filename: "test.tf",
+				Start:    hcl.Pos{Line: 3, Column: 3, Byte: 18},
+				End:      hcl.Pos{Line: 3, Column: 6, Byte: 21},
+			},
+		},
+		{ // 2
+			Type:      lang.TokenNumber,
+			Modifiers: []lang.SemanticTokenModifier{},
+			Range: hcl.Range{
+				Filename: "test.tf",
+				Start:    hcl.Pos{Line: 3, Column: 9, Byte: 24},
+				End:      hcl.Pos{Line: 3, Column: 10, Byte: 25},
+			},
+		},
+	}
+	if diff := cmp.Diff(expectedTokens, tokens); diff != "" {
+		t.Fatalf("unexpected tokens: %s", diff)
+	}
+}
+
+func TestDecoder_SemanticTokensDelta(t *testing.T) {
+	f, pDiags := hclsyntax.ParseConfig([]byte(`foo = 1
+baz = 3
+`), "test.tf", hcl.InitialPos)
+	if len(pDiags) > 0 {
+		t.Fatal(pDiags)
+	}
+
+	pathCtx := &PathContext{
+		Schema: semanticTokensDeltaSchema,
+		Files: map[string]*hcl.File{
+			"test.tf": f,
+		},
+	}
+	d := testPathDecoder(t, pathCtx)
+
+	ctx := context.Background()
+
+	full, err := d.SemanticTokensFull(ctx, "test.tf")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectedData := []uint32{
+		0, 0, 3, 0, 0, // foo
+		0, 6, 1, 5, 0, // 1
+		1, 0, 3, 0, 0, // baz
+		0, 6, 1, 5, 0, // 3
+	}
+	if diff := cmp.Diff(expectedData, full.Data); diff != "" {
+		t.Fatalf("unexpected data: %s", diff)
+	}
+
+	f, pDiags = hclsyntax.ParseConfig([]byte(`bar = 22
+foo = 1
+baz = 3
+`), "test.tf", hcl.InitialPos)
+	if len(pDiags) > 0 {
+		t.Fatal(pDiags)
+	}
+	pathCtx.Files["test.tf"] = f
+
+	delta, err := d.SemanticTokensDelta(ctx, "test.tf", full.ResultID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if delta.ResultID == full.ResultID {
+		t.Fatalf("expected new result ID, %q given", delta.ResultID)
+	}
+
+	// tokens shifted by the inserted line remain untouched
+	expectedEdits := []SemanticTokensEdit{
+		{
+			Start:       5,
+			DeleteCount: 0,
+			Data: []uint32{
+				0, 6, 2, 5, 0, // 22
+				1, 0, 3, 0, 0, // foo
+			},
+		},
+	}
+	if diff := cmp.Diff(expectedEdits, delta.Edits); diff != "" {
+		t.Fatalf("unexpected edits: %s", diff)
+	}
+
+	// no changes since last delta
+	delta, err = d.SemanticTokensDelta(ctx, "test.tf", delta.ResultID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff([]SemanticTokensEdit{}, delta.Edits); diff != "" {
+		t.Fatalf("unexpected edits: %s", diff)
+	}
+
+	// the original result is no longer retained
+	_, err = d.SemanticTokensDelta(ctx, "test.tf", full.ResultID)
+	notFoundErr := &SemanticTokensResultNotFoundError{}
+	if !errors.As(err, &notFoundErr) {
+		t.Fatalf("expected SemanticTokensResultNotFoundError, %#v given", err)
+	}
+
+	// results of files removed from the path are dropped
+	delete(pathCtx.Files, "test.tf")
+	_, err = d.SemanticTokensDelta(ctx, "test.tf", delta.ResultID)
+	if !errors.As(err, &notFoundErr) {
+		t.Fatalf("expected SemanticTokensResultNotFoundError, %#v given", err)
+	}
+	if len(d.semanticTokens.results) != 0 {
+		t.Fatalf("expected no cached results, %d given", len(d.semanticTokens.results))
+	}
+}
+
+func TestSemanticTokensLegend_Encode(t *testing.T) {
+	src := []byte("blk \"😀\" \"x\" {\n  attr = <<EOT\n  😀 x\nEOT\n}\n")
+	tokens := []lang.SemanticToken{
+		{
+			Type: lang.TokenBlockType,
+			Range: hcl.Range{
+				Start: hcl.Pos{Line: 1, Column: 1, Byte: 0},
+				End:   hcl.Pos{Line: 1, Column: 4, Byte: 3},
+			},
+		},
+		{
+			Type: lang.TokenBlockLabel,
+			Range: hcl.Range{
+				Start: hcl.Pos{Line: 1, Column: 5, Byte: 4},
+				End:   hcl.Pos{Line: 1, Column: 8, Byte: 10},
+			},
+		},
+		{
+			// preceded by a character outside of the Basic Multilingual Plane
+			Type:      lang.TokenBlockLabel,
+			Modifiers: lang.SemanticTokenModifiers{lang.TokenModifierDeprecated, lang.TokenModifierReadonly},
+			Range: hcl.Range{
+				Start: hcl.Pos{Line: 1, Column: 9, Byte: 11},
+				End:   hcl.Pos{Line: 1, Column: 12, Byte: 14},
+			},
+		},
+		{
+			// not in legend
+			Type: lang.TokenKeyword,
+			Range: hcl.Range{
+				Start: hcl.Pos{Line: 1, Column: 13, Byte: 15},
+				End:   hcl.Pos{Line: 1, Column: 14, Byte: 16},
+			},
+		},
+		{
+			Type:      lang.TokenAttrName,
+			Modifiers: lang.SemanticTokenModifiers{lang.TokenModifierDependent},
+			Range: hcl.Range{
+				Start: hcl.Pos{Line: 2, Column: 3, Byte: 19},
+				End:   hcl.Pos{Line: 2, Column: 7, Byte: 23},
+			},
+		},
+		{
+			Type: lang.TokenString,
+			Range: hcl.Range{
+				Start: hcl.Pos{Line: 2, Column: 10, Byte: 26},
+				End:   hcl.Pos{Line: 4, Column: 4, Byte: 44},
+			},
+		},
+	}
+
+	testCases := []struct {
+		name                  string
+		multilineTokenSupport bool
+		expectedData          []uint32
+	}{
+		{
+			"multiline tokens split per line",
+			false,
+			[]uint32{
+				0, 0, 3, 0, 0,
+				0, 4, 4, 2, 0,
+				0, 5, 3, 2, 2,
+				1, 2, 4, 1, 1,
+				0, 7, 5, 3, 0,
+				1, 0, 6, 3, 0,
+				1, 0, 3, 3, 0,
+			},
+		},
+		{
+			"multiline token support",
+			true,
+			[]uint32{
+				0, 0, 3, 0, 0,
+				0, 4, 4, 2, 0,
+				0, 5, 3, 2, 2,
+				1, 2, 4, 1, 1,
+				0, 7, 16, 3, 0,
+			},
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("%2d-%s", i, tc.name), func(t *testing.T) {
+			legend := SemanticTokensLegend{
+				TokenTypes: lang.SemanticTokenTypes{
+					lang.TokenBlockType,
+					lang.TokenAttrName,
+					lang.TokenBlockLabel,
+					lang.TokenString,
+				},
+				TokenModifiers: lang.SemanticTokenModifiers{
+					lang.TokenModifierDependent,
+					lang.TokenModifierDeprecated,
+				},
+				MultilineTokenSupport: tc.multilineTokenSupport,
+			}
+
+			if diff := cmp.Diff(tc.expectedData, legend.Encode(src, tokens)); diff != "" {
+				t.Fatalf("unexpected data: %s", diff)
+			}
+		})
+	}
+}
+
+func TestSemanticTokensEdits(t *testing.T) {
+	testCases := []struct {
+		name          string
+		previous      []uint32
+		current       []uint32
+		expectedEdits []SemanticTokensEdit
+	}{
+		{
+			"equal",
+			[]uint32{0, 0, 3, 0, 0, 0, 4, 1, 1, 0},
+			[]uint32{0, 0, 3, 0, 0, 0, 4, 1, 1, 0},
+			[]SemanticTokensEdit{},
+		},
+		{
+			"removal",
+			[]uint32{0, 0, 3, 0, 0, 0, 4, 1, 1, 0, 1, 0, 3, 0, 0},
+			[]uint32{0, 0, 3, 0, 0, 1, 0, 3, 0, 0},
+			[]SemanticTokensEdit{
+				{Start: 5, DeleteCount: 5, Data: []uint32{}},
+			},
+		},
+		{
+			"insertion at end",
+			[]uint32{0, 0, 3, 0, 0},
+			[]uint32{0, 0, 3, 0, 0, 0, 4, 1, 1, 0},
+			[]SemanticTokensEdit{
+				{Start: 5, DeleteCount: 0, Data: []uint32{0, 4, 1, 1, 0}},
+			},
+		},
+		{
+			"replacement",
+			[]uint32{0, 0, 3, 0, 0, 0, 4, 1, 1, 0, 1, 0, 3, 0, 0},
+			[]uint32{0, 0, 3, 0, 0, 0, 4, 2, 1, 0, 1, 0, 3, 0, 0},
+			[]SemanticTokensEdit{
+				{Start: 5, DeleteCount: 5, Data: []uint32{0, 4, 2, 1, 0}},
+			},
+		},
+		{
+			"shifted by inserted line",
+			[]uint32{0, 0, 3, 0, 0, 1, 0, 3, 0, 0, 0, 4, 1, 1, 0},
+			[]uint32{0, 0, 3, 0, 0, 1, 0, 5, 1, 0, 1, 0, 3, 0, 0, 0, 4, 1, 1, 0},
+			[]SemanticTokensEdit{
+				{Start: 5, DeleteCount: 0, Data: []uint32{1, 0, 5, 1, 0}},
+			},
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("%2d-%s", i, tc.name), func(t *testing.T) {
+			edits := semanticTokensEdits(tc.previous, tc.current)
+			if diff := cmp.Diff(tc.expectedEdits, edits); diff != "" {
+				t.Fatalf("unexpected edits: %s", diff)
+			}
+		})
+	}
+}
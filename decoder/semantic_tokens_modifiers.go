@@ -0,0 +1,115 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package decoder
+
+import (
+	"context"
+
+	"github.com/hashicorp/hcl-lang/lang"
+	"github.com/hashicorp/hcl-lang/reference"
+	"github.com/hashicorp/hcl-lang/schema"
+	"github.com/hashicorp/hcl/v2"
+)
+
+type semanticTokenModifiersKey struct{}
+
+func withSemanticTokenModifiers(ctx context.Context, modifiers lang.SemanticTokenModifiers) context.Context {
+	return context.WithValue(ctx, semanticTokenModifiersKey{}, modifiers)
+}
+
+// enabledSemanticTokenModifiers returns built-in modifiers
+// enabled via DecoderContext.EnabledSemanticTokenModifiers
+func enabledSemanticTokenModifiers(ctx context.Context) lang.SemanticTokenModifiers {
+	modifiers, ok := ctx.Value(semanticTokenModifiersKey{}).(lang.SemanticTokenModifiers)
+	if !ok {
+		return lang.SemanticTokenModifiers{}
+	}
+	return modifiers
+}
+
+// attributeSemanticTokenModifiers returns enabled built-in modifiers
+// which apply to the attribute name
+func attributeSemanticTokenModifiers(ctx context.Context, aSchema *schema.AttributeSchema) lang.SemanticTokenModifiers {
+	modifiers := lang.SemanticTokenModifiers{}
+	enabled := enabledSemanticTokenModifiers(ctx)
+
+	if aSchema.IsDeprecated && enabled.Contains(lang.TokenModifierDeprecated) {
+		modifiers = append(modifiers, lang.TokenModifierDeprecated)
+	}
+	if aSchema.IsComputed && enabled.Contains(lang.TokenModifierReadonly) {
+		modifiers = append(modifiers, lang.TokenModifierReadonly)
+	}
+	if aSchema.IsSensitive && enabled.Contains(lang.TokenModifierSensitive) {
+		modifiers = append(modifiers, lang.TokenModifierSensitive)
+	}
+
+	return modifiers
+}
+
+// blockSemanticTokenModifiers returns enabled built-in modifiers
+// which apply to the block type and labels, but not to the block body.
+// Dependent body may deprecate the block, e.g. a particular resource type.
+func blockSemanticTokenModifiers(ctx context.Context, bSchema *schema.BlockSchema, mergedSchema *schema.BodySchema) lang.SemanticTokenModifiers {
+	modifiers := lang.SemanticTokenModifiers{}
+	enabled := enabledSemanticTokenModifiers(ctx)
+
+	isDeprecated := bSchema.IsDeprecated || (mergedSchema != nil && mergedSchema.IsDeprecated)
+	if isDeprecated && enabled.Contains(lang.TokenModifierDeprecated) {
+		modifiers = append(modifiers, lang.TokenModifierDeprecated)
+	}
+
+	return modifiers
+}
+
+// withSemanticTokenModifier returns tokens with the given built-in
+// modifier appended, if the modifier is enabled
+func withSemanticTokenModifier(ctx context.Context, tokens []lang.SemanticToken, modifier lang.SemanticTokenModifier) []lang.SemanticToken {
+	if !enabledSemanticTokenModifiers(ctx).Contains(modifier) {
+		return tokens
+	}
+
+	for i, token := range tokens {
+		modifiers := make(lang.SemanticTokenModifiers, 0, len(token.Modifiers)+1)
+		modifiers = append(modifiers, token.Modifiers...)
+		tokens[i].Modifiers = append(modifiers, modifier)
+	}
+	return tokens
+}
+
+// withDeclarationModifiers marks attribute names and block labels
+// which declare any of the targets with the declaration modifier
+func withDeclarationModifiers(ctx context.Context, tokens []lang.SemanticToken, targets reference.Targets) []lang.SemanticToken {
+	if !enabledSemanticTokenModifiers(ctx).Contains(lang.TokenModifierDeclaration) {
+		return tokens
+	}
+
+	defRanges := make([]hcl.Range, 0)
+	collectTargetDefRanges(targets, &defRanges)
+	if len(defRanges) == 0 {
+		return tokens
+	}
+
+	for i, token := range tokens {
+		if token.Type != lang.TokenAttrName && token.Type != lang.TokenBlockLabel {
+			continue
+		}
+		for _, defRange := range defRanges {
+			if rangeContainsRange(defRange, token.Range) {
+				tokens[i] = withSemanticTokenModifier(ctx,
+					[]lang.SemanticToken{token}, lang.TokenModifierDeclaration)[0]
+				break
+			}
+		}
+	}
+	return tokens
+}
+
+func collectTargetDefRanges(targets reference.Targets, defRanges *[]hcl.Range) {
+	for _, target := range targets {
+		if target.DefRangePtr != nil {
+			*defRanges = append(*defRanges, *target.DefRangePtr)
+		}
+		collectTargetDefRanges(target.NestedTargets, defRanges)
+	}
+}
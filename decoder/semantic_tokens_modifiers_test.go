@@ -0,0 +1,301 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package decoder
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/hcl-lang/lang"
+	"github.com/hashicorp/hcl-lang/reference"
+	"github.com/hashicorp/hcl-lang/schema"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/zclconf/go-cty/cty"
+)
+
+func TestDecoder_SemanticTokensInFile_builtinModifiers(t *testing.T) {
+	bodySchema := &schema.BodySchema{
+		Attributes: map[string]*schema.AttributeSchema{
+			"mode": {
+				Constraint: schema.OneOf{
+					schema.LiteralValue{Value: cty.StringVal("old"), IsDeprecated: true},
+					schema.LiteralValue{Value: cty.StringVal("new")},
+				},
+			},
+			"ref":   {Constraint: schema.Reference{OfType: cty.String}},
+			"unres": {Constraint: schema.Reference{OfType: cty.String}},
+		},
+		Blocks: map[string]*schema.BlockSchema{
+			"variable": {
+				Labels: []*schema.LabelSchema{
+					{Name: "name"},
+				},
+			},
+			"legacy": {
+				IsDeprecated: true,
+				Labels: []*schema.LabelSchema{
+					{Name: "name"},
+				},
+				Body: &schema.BodySchema{
+					Attributes: map[string]*schema.AttributeSchema{
+						"old":      {Constraint: schema.LiteralType{Type: cty.String}, IsDeprecated: true},
+						"computed": {Constraint: schema.LiteralType{Type: cty.String}, IsComputed: true},
+						"secret":   {Constraint: schema.LiteralType{Type: cty.String}, IsSensitive: true},
+					},
+				},
+			},
+		},
+	}
+
+	testCfg := []byte(`variable "foo" {
+}
+legacy "a" {
+  old      = "x"
+  computed = "y"
+  secret   = "z"
+}
+mode = "old"
+ref = var.foo
+unres = var.bar
+`)
+
+	f, pDiags := hclsyntax.ParseConfig(testCfg, "test.tf", hcl.InitialPos)
+	if len(pDiags) > 0 {
+		t.Fatal(pDiags)
+	}
+
+	d := testPathDecoder(t, &PathContext{
+		Schema: bodySchema,
+		Files: map[string]*hcl.File{
+			"test.tf": f,
+		},
+		ReferenceTargets: reference.Targets{
+			{
+				Addr: lang.Address{
+					lang.RootStep{Name: "var"},
+					lang.AttrStep{Name: "foo"},
+				},
+				Type: cty.String,
+				RangePtr: &hcl.Range{
+					Filename: "test.tf",
+					Start:    hcl.Pos{Line: 1, Column: 1, Byte: 0},
+					End:      hcl.Pos{Line: 2, Column: 2, Byte: 18},
+				},
+				DefRangePtr: &hcl.Range{
+					Filename: "test.tf",
+					Start:    hcl.Pos{Line: 1, Column: 1, Byte: 0},
+					End:      hcl.Pos{Line: 1, Column: 15, Byte: 14},
+				},
+			},
+		},
+		ReferenceOrigins: reference.Origins{
+			reference.LocalOrigin{
+				Addr: lang.Address{
+					lang.RootStep{Name: "var"},
+					lang.AttrStep{Name: "foo"},
+				},
+				Range: hcl.Range{
+					Filename: "test.tf",
+					Start:    hcl.Pos{Line: 9, Column: 7, Byte: 104},
+					End:      hcl.Pos{Line: 9, Column: 14, Byte: 111},
+				},
+				Constraints: reference.OriginConstraints{
+					{OfType: cty.String},
+				},
+			},
+			reference.LocalOrigin{
+				Addr: lang.Address{
+					lang.RootStep{Name: "var"},
+					lang.AttrStep{Name: "bar"},
+				},
+				Range: hcl.Range{
+					Filename: "test.tf",
+					Start:    hcl.Pos{Line: 10, Column: 9, Byte: 120},
+					End:      hcl.Pos{Line: 10, Column: 16, Byte: 127},
+				},
+				Constraints: reference.OriginConstraints{
+					{OfType: cty.String},
+				},
+			},
+		},
+	})
+	d.decoderCtx.EnabledSemanticTokenModifiers = lang.OptionalSemanticTokenModifiers
+
+	ctx := context.Background()
+
+	tokens, err := d.SemanticTokensInFile(ctx, "test.tf")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectedTokens := []lang.SemanticToken{
+		{
+			Type:      lang.TokenBlockType,
+			Modifiers: lang.SemanticTokenModifiers{},
+			Range: hcl.Range{
+				Filename: "test.tf",
+				Start:    hcl.Pos{Line: 1, Column: 1, Byte: 0},
+				End:      hcl.Pos{Line: 1, Column: 9, Byte: 8},
+			},
+		},
+		{
+			Type:      lang.TokenBlockLabel,
+			Modifiers: lang.SemanticTokenModifiers{lang.TokenModifierDeclaration},
+			Range: hcl.Range{
+				Filename: "test.tf",
+				Start:    hcl.Pos{Line: 1, Column: 10, Byte: 9},
+				End:      hcl.Pos{Line: 1, Column: 15, Byte: 14},
+			},
+		},
+		{
+			Type:      lang.TokenBlockType,
+			Modifiers: lang.SemanticTokenModifiers{lang.TokenModifierDeprecated},
+			Range: hcl.Range{
+				Filename: "test.tf",
+				Start:    hcl.Pos{Line: 3, Column: 1, Byte: 19},
+				End:      hcl.Pos{Line: 3, Column: 7, Byte: 25},
+			},
+		},
+		{
+			Type:      lang.TokenBlockLabel,
+			Modifiers: lang.SemanticTokenModifiers{lang.TokenModifierDeprecated},
+			Range: hcl.Range{
+				Filename: "test.tf",
+				Start:    hcl.Pos{Line: 3, Column: 8, Byte: 26},
+				End:      hcl.Pos{Line: 3, Column: 11, Byte: 29},
+			},
+		},
+		{
+			Type:      lang.TokenAttrName,
+			Modifiers: lang.SemanticTokenModifiers{lang.TokenModifierDeprecated},
+			Range: hcl.Range{
+				Filename: "test.tf",
+				Start:    hcl.Pos{Line: 4, Column: 3, Byte: 34},
+				End:      hcl.Pos{Line: 4, Column: 6, Byte: 37},
+			},
+		},
+		{
+			Type:      lang.TokenString,
+			Modifiers: lang.SemanticTokenModifiers{},
+			Range: hcl.Range{
+				Filename: "test.tf",
+				Start:    hcl.Pos{Line: 4, Column: 14, Byte: 45},
+				End:      hcl.Pos{Line: 4, Column: 17, Byte: 48},
+			},
+		},
+		{
+			Type:      lang.TokenAttrName,
+			Modifiers: lang.SemanticTokenModifiers{lang.TokenModifierReadonly},
+			Range: hcl.Range{
+				Filename: "test.tf",
+				Start:    hcl.Pos{Line: 5, Column: 3, Byte: 51},
+				End:      hcl.Pos{Line: 5, Column: 11, Byte: 59},
+			},
+		},
+		{
+			Type:      lang.TokenString,
+			Modifiers: lang.SemanticTokenModifiers{},
+			Range: hcl.Range{
+				Filename: "test.tf",
+				Start:    hcl.Pos{Line: 5, Column: 14, Byte: 62},
+				End:      hcl.Pos{Line: 5, Column: 17, Byte: 65},
+			},
+		},
+		{
+			Type:      lang.TokenAttrName,
+			Modifiers: lang.SemanticTokenModifiers{lang.TokenModifierSensitive},
+			Range: hcl.Range{
+				Filename: "test.tf",
+				Start:    hcl.Pos{Line: 6, Column: 3, Byte: 68},
+				End:      hcl.Pos{Line: 6, Column: 9, Byte: 74},
+			},
+		},
+		{
+			Type:      lang.TokenString,
+			Modifiers: lang.SemanticTokenModifiers{},
+			Range: hcl.Range{
+				Filename: "test.tf",
+				Start:    hcl.Pos{Line: 6, Column: 14, Byte: 79},
+				End:      hcl.Pos{Line: 6, Column: 17, Byte: 82},
+			},
+		},
+		{
+			Type:      lang.TokenAttrName,
+			Modifiers: lang.SemanticTokenModifiers{},
+			Range: hcl.Range{
+				Filename: "test.tf",
+				Start:    hcl.Pos{Line: 8, Column: 1, Byte: 85},
+				End:      hcl.Pos{Line: 8, Column: 5, Byte: 89},
+			},
+		},
+		{
+			Type:      lang.TokenString,
+			Modifiers: lang.SemanticTokenModifiers{lang.TokenModifierDeprecated},
+			Range: hcl.Range{
+				Filename: "test.tf",
+				Start:    hcl.Pos{Line: 8, Column: 8, Byte: 92},
+				End:      hcl.Pos{Line: 8, Column: 13, Byte: 97},
+			},
+		},
+		{
+			Type:      lang.TokenAttrName,
+			Modifiers: lang.SemanticTokenModifiers{},
+			Range: hcl.Range{
+				Filename: "test.tf",
+				Start:    hcl.Pos{Line: 9, Column: 1, Byte: 98},
+				End:      hcl.Pos{Line: 9, Column: 4, Byte: 101},
+			},
+		},
+		{
+			Type:      lang.TokenReferenceStep,
+			Modifiers: lang.SemanticTokenModifiers{lang.TokenModifierReference},
+			Range: hcl.Range{
+				Filename: "test.tf",
+				Start:    hcl.Pos{Line: 9, Column: 7, Byte: 104},
+				End:      hcl.Pos{Line: 9, Column: 10, Byte: 107},
+			},
+		},
+		{
+			Type:      lang.TokenReferenceStep,
+			Modifiers: lang.SemanticTokenModifiers{lang.TokenModifierReference},
+			Range: hcl.Range{
+				Filename: "test.tf",
+				Start:    hcl.Pos{Line: 9, Column: 11, Byte: 108},
+				End:      hcl.Pos{Line: 9, Column: 14, Byte: 111},
+			},
+		},
+		{
+			Type:      lang.TokenAttrName,
+			Modifiers: lang.SemanticTokenModifiers{},
+			Range: hcl.Range{
+				Filename: "test.tf",
+				Start:    hcl.Pos{Line: 10, Column: 1, Byte: 112},
+				End:      hcl.Pos{Line: 10, Column: 6, Byte: 117},
+			},
+		},
+		{
+			Type:      lang.TokenReferenceStep,
+			Modifiers: lang.SemanticTokenModifiers{lang.TokenModifierReference, lang.TokenModifierUnresolved},
+			Range: hcl.Range{
+				Filename: "test.tf",
+				Start:    hcl.Pos{Line: 10, Column: 9, Byte: 120},
+				End:      hcl.Pos{Line: 10, Column: 12, Byte: 123},
+			},
+		},
+		{
+			Type:      lang.TokenReferenceStep,
+			Modifiers: lang.SemanticTokenModifiers{lang.TokenModifierReference, lang.TokenModifierUnresolved},
+			Range: hcl.Range{
+				Filename: "test.tf",
+				Start:    hcl.Pos{Line: 10, Column: 13, Byte: 124},
+				End:      hcl.Pos{Line: 10, Column: 16, Byte: 127},
+			},
+		},
+	}
+	if diff := cmp.Diff(expectedTokens, tokens); diff != "" {
+		t.Fatalf("unexpected tokens: %s", diff)
+	}
+}
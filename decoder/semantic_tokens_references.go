@@ -0,0 +1,165 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package decoder
+
+import (
+	"context"
+
+	"github.com/hashicorp/hcl-lang/lang"
+	"github.com/hashicorp/hcl-lang/reference"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+)
+
+// referenceTokensForFile returns tokens for all reference origins
+// within the file. Origins are resolved against reference targets
+// the same way as for go-to-definition, so only references
+// which can be resolved are reported (or marked as unresolved).
+//
+// The traversals are parsed from the origin ranges, which avoids
+// the need to match origins while walking the AST.
+func (d *PathDecoder) referenceTokensForFile(ctx context.Context, f *hcl.File, filename string, rng *hcl.Range) []lang.SemanticToken {
+	tokens := make([]lang.SemanticToken, 0)
+
+	origins := d.referenceOriginsStateInFile(filename, rng)
+	if len(origins) == 0 {
+		return tokens
+	}
+
+	reportUnresolved := enabledSemanticTokenModifiers(ctx).Contains(lang.TokenModifierUnresolved)
+
+	for _, origin := range origins {
+		// origins from other paths (e.g. PathOrigin) cannot be resolved here
+		// and so only local origins are reported as unresolved
+		if !origin.isResolved && !(origin.isLocal && reportUnresolved) {
+			continue
+		}
+
+		traversal, ok := traversalForRange(f, origin.rng)
+		if !ok {
+			continue
+		}
+
+		originTokens := semanticTokensForTraversal(traversal)
+		originTokens = withSemanticTokenModifier(ctx, originTokens, lang.TokenModifierReference)
+		if !origin.isResolved {
+			originTokens = withSemanticTokenModifier(ctx, originTokens, lang.TokenModifierUnresolved)
+		}
+		tokens = append(tokens, originTokens...)
+	}
+
+	return tokens
+}
+
+type originState struct {
+	rng        hcl.Range
+	isLocal    bool
+	isResolved bool
+}
+
+// referenceOriginsStateInFile returns matchable origins within the file
+// (and range, if provided) grouped by their range, along with
+// the information whether any of them resolve to a target.
+func (d *PathDecoder) referenceOriginsStateInFile(filename string, rng *hcl.Range) []*originState {
+	origins := make([]*originState, 0)
+	originsByRange := make(map[hcl.Range]*originState, 0)
+
+	var targets referenceTargetIndex
+
+	for _, origin := range d.pathCtx.ReferenceOrigins {
+		matchableOrigin, ok := origin.(reference.MatchableOrigin)
+		if !ok {
+			continue
+		}
+		originRng := origin.OriginRange()
+		if originRng.Filename != filename {
+			continue
+		}
+		if rng != nil && !rangesOverlap(originRng, *rng) {
+			continue
+		}
+
+		state, ok := originsByRange[originRng]
+		if !ok {
+			state = &originState{rng: originRng}
+			originsByRange[originRng] = state
+			origins = append(origins, state)
+		}
+		if _, ok := origin.(reference.LocalOrigin); ok {
+			state.isLocal = true
+		}
+		if state.isResolved {
+			continue
+		}
+
+		if targets == nil {
+			targets = newReferenceTargetIndex(d.pathCtx.ReferenceTargets)
+		}
+		state.isResolved = targets.resolves(matchableOrigin)
+	}
+
+	return origins
+}
+
+// referenceTargetIndex represents targets (including nested ones)
+// indexed by their absolute and local address
+type referenceTargetIndex map[string]reference.Targets
+
+func newReferenceTargetIndex(targets reference.Targets) referenceTargetIndex {
+	index := make(referenceTargetIndex, 0)
+	index.add(targets)
+	return index
+}
+
+func (index referenceTargetIndex) add(targets reference.Targets) {
+	for _, target := range targets {
+		if len(target.Addr) > 0 {
+			key := target.Addr.String()
+			index[key] = append(index[key], target)
+		}
+		if len(target.LocalAddr) > 0 {
+			key := target.LocalAddr.String()
+			if key != target.Addr.String() {
+				index[key] = append(index[key], target)
+			}
+		}
+		index.add(target.NestedTargets)
+	}
+}
+
+// resolves returns true if the origin matches any of the targets
+func (index referenceTargetIndex) resolves(origin reference.MatchableOrigin) bool {
+	_, ok := index.match(origin)
+	return ok
+}
+
+// match returns the first target which the origin matches.
+//
+// Targets of any type may also match origins pointing to nested
+// segments (e.g. self.foo target matches self.foo.bar), so targets
+// at all address prefixes are considered, the longest first.
+func (index referenceTargetIndex) match(origin reference.MatchableOrigin) (reference.Target, bool) {
+	addr := origin.Address()
+	for steps := len(addr); steps > 0; steps-- {
+		for _, target := range index[addr.FirstSteps(uint(steps)).String()] {
+			if target.Matches(origin) {
+				return target, true
+			}
+		}
+	}
+	return reference.Target{}, false
+}
+
+// traversalForRange parses the traversal at the given range of the file
+func traversalForRange(f *hcl.File, rng hcl.Range) (hcl.Traversal, bool) {
+	if rng.Start.Byte < 0 || rng.End.Byte > len(f.Bytes) || rng.Start.Byte >= rng.End.Byte {
+		return nil, false
+	}
+
+	traversal, diags := hclsyntax.ParseTraversalAbs(f.Bytes[rng.Start.Byte:rng.End.Byte], rng.Filename, rng.Start)
+	if diags.HasErrors() {
+		return nil, false
+	}
+	return traversal, true
+}
@@ -0,0 +1,207 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package decoder
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/hcl-lang/lang"
+	"github.com/hashicorp/hcl-lang/reference"
+	"github.com/hashicorp/hcl-lang/schema"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/zclconf/go-cty/cty"
+)
+
+func TestDecoder_SemanticTokensInFile_references(t *testing.T) {
+	cfg := `first = var.foo.bar
+second = local.obj.attr
+third = local.missing
+`
+	f, pDiags := hclsyntax.ParseConfig([]byte(cfg), "test.tf", hcl.InitialPos)
+	if len(pDiags) > 0 {
+		t.Fatal(pDiags)
+	}
+
+	refCons := schema.Reference{OfType: cty.String}
+	originCons := reference.OriginConstraints{{OfType: cty.String}}
+
+	d := testPathDecoder(t, &PathContext{
+		Schema: &schema.BodySchema{
+			Attributes: map[string]*schema.AttributeSchema{
+				"first":  {Constraint: refCons},
+				"second": {Constraint: refCons},
+				"third":  {Constraint: refCons},
+			},
+		},
+		Files: map[string]*hcl.File{
+			"test.tf": f,
+		},
+		ReferenceTargets: reference.Targets{
+			{
+				// target of any type matching nested segments
+				Addr: lang.Address{
+					lang.RootStep{Name: "var"},
+					lang.AttrStep{Name: "foo"},
+				},
+				Type: cty.DynamicPseudoType,
+			},
+			{
+				Addr: lang.Address{
+					lang.RootStep{Name: "local"},
+					lang.AttrStep{Name: "obj"},
+				},
+				Type: cty.Object(map[string]cty.Type{
+					"attr": cty.String,
+				}),
+				NestedTargets: reference.Targets{
+					{
+						Addr: lang.Address{
+							lang.RootStep{Name: "local"},
+							lang.AttrStep{Name: "obj"},
+							lang.AttrStep{Name: "attr"},
+						},
+						Type: cty.String,
+					},
+				},
+			},
+		},
+		ReferenceOrigins: reference.Origins{
+			reference.LocalOrigin{
+				Addr: lang.Address{
+					lang.RootStep{Name: "var"},
+					lang.AttrStep{Name: "foo"},
+					lang.AttrStep{Name: "bar"},
+				},
+				Range: hcl.Range{
+					Filename: "test.tf",
+					Start:    hcl.Pos{Line: 1, Column: 9, Byte: 8},
+					End:      hcl.Pos{Line: 1, Column: 20, Byte: 19},
+				},
+				Constraints: originCons,
+			},
+			reference.LocalOrigin{
+				Addr: lang.Address{
+					lang.RootStep{Name: "local"},
+					lang.AttrStep{Name: "obj"},
+					lang.AttrStep{Name: "attr"},
+				},
+				Range: hcl.Range{
+					Filename: "test.tf",
+					Start:    hcl.Pos{Line: 2, Column: 10, Byte: 29},
+					End:      hcl.Pos{Line: 2, Column: 24, Byte: 43},
+				},
+				Constraints: originCons,
+			},
+			reference.LocalOrigin{
+				Addr: lang.Address{
+					lang.RootStep{Name: "local"},
+					lang.AttrStep{Name: "missing"},
+				},
+				Range: hcl.Range{
+					Filename: "test.tf",
+					Start:    hcl.Pos{Line: 3, Column: 9, Byte: 52},
+					End:      hcl.Pos{Line: 3, Column: 22, Byte: 65},
+				},
+				Constraints: originCons,
+			},
+		},
+	})
+
+	ctx := context.Background()
+
+	tokens, err := d.SemanticTokensInFile(ctx, "test.tf")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectedTokens := []lang.SemanticToken{
+		{
+			Type:      lang.TokenAttrName,
+			Modifiers: lang.SemanticTokenModifiers{},
+			Range: hcl.Range{
+				Filename: "test.tf",
+				Start:    hcl.Pos{Line: 1, Column: 1, Byte: 0},
+				End:      hcl.Pos{Line: 1, Column: 6, Byte: 5},
+			},
+		},
+		{
+			Type:      lang.TokenReferenceStep,
+			Modifiers: lang.SemanticTokenModifiers{},
+			Range: hcl.Range{
+				Filename: "test.tf",
+				Start:    hcl.Pos{Line: 1, Column: 9, Byte: 8},
+				End:      hcl.Pos{Line: 1, Column: 12, Byte: 11},
+			},
+		},
+		{
+			Type:      lang.TokenReferenceStep,
+			Modifiers: lang.SemanticTokenModifiers{},
+			Range: hcl.Range{
+				Filename: "test.tf",
+				Start:    hcl.Pos{Line: 1, Column: 13, Byte: 12},
+				End:      hcl.Pos{Line: 1, Column: 16, Byte: 15},
+			},
+		},
+		{
+			Type:      lang.TokenReferenceStep,
+			Modifiers: lang.SemanticTokenModifiers{},
+			Range: hcl.Range{
+				Filename: "test.tf",
+				Start:    hcl.Pos{Line: 1, Column: 17, Byte: 16},
+				End:      hcl.Pos{Line: 1, Column: 20, Byte: 19},
+			},
+		},
+		{
+			Type:      lang.TokenAttrName,
+			Modifiers: lang.SemanticTokenModifiers{},
+			Range: hcl.Range{
+				Filename: "test.tf",
+				Start:    hcl.Pos{Line: 2, Column: 1, Byte: 20},
+				End:      hcl.Pos{Line: 2, Column: 7, Byte: 26},
+			},
+		},
+		{
+			Type:      lang.TokenReferenceStep,
+			Modifiers: lang.SemanticTokenModifiers{},
+			Range: hcl.Range{
+				Filename: "test.tf",
+				Start:    hcl.Pos{Line: 2, Column: 10, Byte: 29},
+				End:      hcl.Pos{Line: 2, Column: 15, Byte: 34},
+			},
+		},
+		{
+			Type:      lang.TokenReferenceStep,
+			Modifiers: lang.SemanticTokenModifiers{},
+			Range: hcl.Range{
+				Filename: "test.tf",
+				Start:    hcl.Pos{Line: 2, Column: 16, Byte: 35},
+				End:      hcl.Pos{Line: 2, Column: 19, Byte: 38},
+			},
+		},
+		{
+			Type:      lang.TokenReferenceStep,
+			Modifiers: lang.SemanticTokenModifiers{},
+			Range: hcl.Range{
+				Filename: "test.tf",
+				Start:    hcl.Pos{Line: 2, Column: 20, Byte: 39},
+				End:      hcl.Pos{Line: 2, Column: 24, Byte: 43},
+			},
+		},
+		{
+			Type:      lang.TokenAttrName,
+			Modifiers: lang.SemanticTokenModifiers{},
+			Range: hcl.Range{
+				Filename: "test.tf",
+				Start:    hcl.Pos{Line: 3, Column: 1, Byte: 44},
+				End:      hcl.Pos{Line: 3, Column: 6, Byte: 49},
+			},
+		},
+	}
+	if diff := cmp.Diff(expectedTokens, tokens); diff != "" {
+		t.Fatalf("unexpected tokens: %s", diff)
+	}
+}
@@ -0,0 +1,390 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package decoder
+
+import (
+	"bytes"
+	"sort"
+	"unicode/utf8"
+
+	"github.com/hashicorp/hcl-lang/lang"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+)
+
+var operatorTokenTypes = map[hclsyntax.TokenType]bool{
+	hclsyntax.TokenPlus:          true,
+	hclsyntax.TokenMinus:         true,
+	hclsyntax.TokenStar:          true,
+	hclsyntax.TokenSlash:         true,
+	hclsyntax.TokenPercent:       true,
+	hclsyntax.TokenEqualOp:       true,
+	hclsyntax.TokenNotEqual:      true,
+	hclsyntax.TokenLessThan:      true,
+	hclsyntax.TokenLessThanEq:    true,
+	hclsyntax.TokenGreaterThan:   true,
+	hclsyntax.TokenGreaterThanEq: true,
+	hclsyntax.TokenAnd:           true,
+	hclsyntax.TokenOr:            true,
+	hclsyntax.TokenBang:          true,
+}
+
+var templateDirectiveKeywords = map[string]bool{
+	"if":     true,
+	"else":   true,
+	"endif":  true,
+	"for":    true,
+	"endfor": true,
+}
+
+// syntaxTokensForFile returns tokens of types enabled
+// via DecoderContext.EnabledSemanticTokenTypes, which are derived
+// from the syntax alone, such as comments or operators.
+//
+// If rng is not nil, only the source of top-level attributes
+// and blocks overlapping the range is lexed and walked.
+func (d *PathDecoder) syntaxTokensForFile(filename string, body *hclsyntax.Body, rng *hcl.Range) []lang.SemanticToken {
+	tokens := make([]lang.SemanticToken, 0)
+
+	enabled := d.decoderCtx.EnabledSemanticTokenTypes
+	if len(enabled) == 0 {
+		return tokens
+	}
+
+	src, err := d.bytesForFile(filename)
+	if err != nil {
+		return tokens
+	}
+	startPos, endByte := hcl.InitialPos, len(src)
+	if rng != nil {
+		startPos, endByte = syntaxSourceBounds(body, *rng, endByte)
+	}
+	lexTokens, _ := hclsyntax.LexConfig(src[startPos.Byte:endByte], filename, startPos)
+
+	if enabled.Contains(lang.TokenComment) {
+		tokens = append(tokens, commentTokens(lexTokens)...)
+	}
+	if enabled.Contains(lang.TokenHeredocAnchor) {
+		tokens = append(tokens, heredocAnchorTokens(lexTokens)...)
+	}
+	if enabled.Contains(lang.TokenTemplateInterpolation) || enabled.Contains(lang.TokenTemplateDirective) {
+		tokens = append(tokens, templateDelimiterTokens(lexTokens, enabled)...)
+	}
+	if enabled.Contains(lang.TokenOperator) || enabled.Contains(lang.TokenConditionalOperator) ||
+		enabled.Contains(lang.TokenIteratorVariable) {
+		tokens = append(tokens, exprSyntaxTokens(body, rng, lexTokens, enabled)...)
+	}
+
+	return tokens
+}
+
+// syntaxSourceBounds returns the start position and end byte offset
+// of the source covering the given range, extended to the nearest
+// boundaries of top-level attributes and blocks, so that lexing
+// never starts or ends in the middle of an expression
+func syntaxSourceBounds(body *hclsyntax.Body, rng hcl.Range, srcLen int) (hcl.Pos, int) {
+	startPos, endByte := hcl.InitialPos, srcLen
+
+	itemRanges := make([]hcl.Range, 0, len(body.Attributes)+len(body.Blocks))
+	for _, attr := range body.Attributes {
+		itemRanges = append(itemRanges, attr.SrcRange)
+	}
+	for _, block := range body.Blocks {
+		itemRanges = append(itemRanges, block.Range())
+	}
+
+	for _, itemRng := range itemRanges {
+		if itemRng.End.Byte <= rng.Start.Byte && itemRng.End.Byte > startPos.Byte {
+			startPos = itemRng.End
+		}
+		if itemRng.Start.Byte >= rng.End.Byte && itemRng.Start.Byte < endByte {
+			endByte = itemRng.Start.Byte
+		}
+	}
+
+	return startPos, endByte
+}
+
+func commentTokens(lexTokens hclsyntax.Tokens) []lang.SemanticToken {
+	tokens := make([]lang.SemanticToken, 0)
+	for _, tok := range lexTokens {
+		if tok.Type == hclsyntax.TokenComment {
+			tokens = append(tokens, newSyntaxToken(lang.TokenComment, trimTrailingNewline(tok)))
+		}
+	}
+	return tokens
+}
+
+func heredocAnchorTokens(lexTokens hclsyntax.Tokens) []lang.SemanticToken {
+	tokens := make([]lang.SemanticToken, 0)
+	for _, tok := range lexTokens {
+		switch tok.Type {
+		case hclsyntax.TokenOHeredoc:
+			tokens = append(tokens, newSyntaxToken(lang.TokenHeredocAnchor, trimTrailingNewline(tok)))
+		case hclsyntax.TokenCHeredoc:
+			// closing anchor of indented heredoc (<<-) includes the indentation
+			rng := tok.Range
+			indent := len(tok.Bytes) - len(bytes.TrimLeft(tok.Bytes, " \t"))
+			rng.Start.Byte += indent
+			rng.Start.Column += indent
+			tokens = append(tokens, newSyntaxToken(lang.TokenHeredocAnchor, rng))
+		}
+	}
+	return tokens
+}
+
+// templateDelimiterTokens returns tokens for ${ and %{ along
+// with their closing braces and keywords of %{} directives
+func templateDelimiterTokens(lexTokens hclsyntax.Tokens, enabled lang.SemanticTokenTypes) []lang.SemanticToken {
+	tokens := make([]lang.SemanticToken, 0)
+
+	appendToken := func(tokenType lang.SemanticTokenType, rng hcl.Range) {
+		if enabled.Contains(tokenType) {
+			tokens = append(tokens, newSyntaxToken(tokenType, rng))
+		}
+	}
+
+	// stack tracks types of open sequences, which may be nested
+	// e.g. ${"${foo}"}
+	stack := make([]lang.SemanticTokenType, 0)
+	expectKeyword := false
+	inForDirective := false
+
+	for _, tok := range lexTokens {
+		switch tok.Type {
+		case hclsyntax.TokenTemplateInterp:
+			stack = append(stack, lang.TokenTemplateInterpolation)
+			appendToken(lang.TokenTemplateInterpolation, tok.Range)
+			expectKeyword = false
+			continue
+		case hclsyntax.TokenTemplateControl:
+			stack = append(stack, lang.TokenTemplateDirective)
+			appendToken(lang.TokenTemplateDirective, tok.Range)
+			expectKeyword = true
+			continue
+		case hclsyntax.TokenTemplateSeqEnd:
+			if len(stack) > 0 {
+				appendToken(stack[len(stack)-1], tok.Range)
+				stack = stack[:len(stack)-1]
+			}
+			expectKeyword = false
+			inForDirective = false
+			continue
+		case hclsyntax.TokenIdent:
+			if len(stack) > 0 && stack[len(stack)-1] == lang.TokenTemplateDirective {
+				keyword := string(tok.Bytes)
+				if expectKeyword && templateDirectiveKeywords[keyword] {
+					appendToken(lang.TokenTemplateDirective, tok.Range)
+					inForDirective = keyword == "for"
+				} else if inForDirective && keyword == "in" {
+					appendToken(lang.TokenTemplateDirective, tok.Range)
+					inForDirective = false
+				}
+			}
+		}
+		expectKeyword = false
+	}
+
+	return tokens
+}
+
+// exprSyntaxTokens returns tokens for operators, conditional operators
+// and iterator variables of for expressions within the body
+func exprSyntaxTokens(body *hclsyntax.Body, rng *hcl.Range, lexTokens hclsyntax.Tokens, enabled lang.SemanticTokenTypes) []lang.SemanticToken {
+	tokens := make([]lang.SemanticToken, 0)
+
+	appendTokensBetween := func(tokenType lang.SemanticTokenType, start, end hcl.Pos, match func(tok hclsyntax.Token) bool) {
+		for _, tok := range lexTokensBetween(lexTokens, start.Byte, end.Byte) {
+			if match(tok) {
+				tokens = append(tokens, newSyntaxToken(tokenType, tok.Range))
+			}
+		}
+	}
+	isOperator := func(tok hclsyntax.Token) bool {
+		return operatorTokenTypes[tok.Type]
+	}
+
+	visitBodyInRange(body, rng, func(node hclsyntax.Node) hcl.Diagnostics {
+		switch expr := node.(type) {
+		case *hclsyntax.BinaryOpExpr:
+			if enabled.Contains(lang.TokenOperator) {
+				appendTokensBetween(lang.TokenOperator, expr.LHS.Range().End, expr.RHS.Range().Start, isOperator)
+			}
+		case *hclsyntax.UnaryOpExpr:
+			if enabled.Contains(lang.TokenOperator) {
+				appendTokensBetween(lang.TokenOperator, expr.SrcRange.Start, expr.Val.Range().Start, isOperator)
+			}
+		case *hclsyntax.ConditionalExpr:
+			if enabled.Contains(lang.TokenConditionalOperator) {
+				appendTokensBetween(lang.TokenConditionalOperator, expr.Condition.Range().End, expr.TrueResult.Range().Start,
+					func(tok hclsyntax.Token) bool {
+						return tok.Type == hclsyntax.TokenQuestion
+					})
+				appendTokensBetween(lang.TokenConditionalOperator, expr.TrueResult.Range().End, expr.FalseResult.Range().Start,
+					func(tok hclsyntax.Token) bool {
+						return tok.Type == hclsyntax.TokenColon
+					})
+			}
+		case *hclsyntax.ForExpr:
+			if enabled.Contains(lang.TokenIteratorVariable) {
+				tokens = append(tokens, iteratorVariableTokens(expr, lexTokens)...)
+			}
+		}
+		return nil
+	})
+
+	return tokens
+}
+
+// visitBodyInRange visits all nodes of attributes and blocks
+// overlapping the given range, or all nodes if rng is nil
+func visitBodyInRange(body *hclsyntax.Body, rng *hcl.Range, fn hclsyntax.VisitFunc) {
+	for _, attr := range body.Attributes {
+		if rng == nil || rangesOverlap(attr.SrcRange, *rng) {
+			hclsyntax.VisitAll(attr, fn)
+		}
+	}
+	for _, block := range body.Blocks {
+		if rng == nil || rangesOverlap(block.Range(), *rng) {
+			visitBodyInRange(block.Body, rng, fn)
+		}
+	}
+}
+
+// iteratorVariableTokens returns tokens for declarations of key and value
+// variables of the for expression, as well as any references to them
+func iteratorVariableTokens(expr *hclsyntax.ForExpr, lexTokens hclsyntax.Tokens) []lang.SemanticToken {
+	tokens := make([]lang.SemanticToken, 0)
+
+	isIteratorVar := func(name string) bool {
+		return name != "" && (name == expr.KeyVar || name == expr.ValVar)
+	}
+
+	// declarations are between the "for" and "in" keywords
+	seenFor := false
+	for _, tok := range lexTokensBetween(lexTokens, expr.OpenRange.End.Byte, expr.CollExpr.Range().Start.Byte) {
+		if tok.Type != hclsyntax.TokenIdent {
+			continue
+		}
+		name := string(tok.Bytes)
+		if !seenFor {
+			seenFor = name == "for"
+			continue
+		}
+		if name == "in" {
+			break
+		}
+		if isIteratorVar(name) {
+			tokens = append(tokens, newSyntaxToken(lang.TokenIteratorVariable, tok.Range))
+		}
+	}
+
+	for _, scopedExpr := range []hclsyntax.Expression{expr.KeyExpr, expr.ValExpr, expr.CondExpr} {
+		if scopedExpr == nil {
+			continue
+		}
+		hclsyntax.VisitAll(scopedExpr, func(node hclsyntax.Node) hcl.Diagnostics {
+			traversalExpr, ok := node.(*hclsyntax.ScopeTraversalExpr)
+			if !ok || len(traversalExpr.Traversal) == 0 {
+				return nil
+			}
+			if isIteratorVar(traversalExpr.Traversal.RootName()) {
+				tokens = append(tokens, newSyntaxToken(lang.TokenIteratorVariable,
+					traversalExpr.Traversal[0].SourceRange()))
+			}
+			return nil
+		})
+	}
+
+	return tokens
+}
+
+// mergeSyntaxTokens merges syntax tokens into tokens derived from the schema.
+//
+// Strings are trimmed to exclude any heredoc anchors and syntax tokens
+// overlapping any other tokens (e.g. an iterator variable which also
+// matches a reference target) are discarded.
+func mergeSyntaxTokens(tokens, syntaxTokens []lang.SemanticToken) []lang.SemanticToken {
+	if len(syntaxTokens) == 0 {
+		return tokens
+	}
+
+	for _, anchor := range syntaxTokens {
+		if anchor.Type != lang.TokenHeredocAnchor {
+			continue
+		}
+		for i, token := range tokens {
+			if token.Type != lang.TokenString || !rangesOverlap(token.Range, anchor.Range) {
+				continue
+			}
+			if token.Range.Start.Byte >= anchor.Range.Start.Byte {
+				tokens[i].Range.Start = anchor.Range.End
+			} else {
+				tokens[i].Range.End = anchor.Range.Start
+			}
+		}
+	}
+
+	sort.Slice(tokens, func(i, j int) bool {
+		return tokens[i].Range.Start.Byte < tokens[j].Range.Start.Byte
+	})
+
+	seen := make(map[hcl.Range]bool, 0)
+	merged := tokens
+	for _, token := range syntaxTokens {
+		if seen[token.Range] {
+			continue
+		}
+		seen[token.Range] = true
+
+		idx := sort.Search(len(tokens), func(i int) bool {
+			return tokens[i].Range.End.Byte > token.Range.Start.Byte
+		})
+		if idx < len(tokens) && tokens[idx].Range.Start.Byte < token.Range.End.Byte {
+			continue
+		}
+		merged = append(merged, token)
+	}
+
+	return merged
+}
+
+// lexTokensBetween returns tokens which start within the given byte offsets
+func lexTokensBetween(lexTokens hclsyntax.Tokens, startByte, endByte int) hclsyntax.Tokens {
+	startIdx := sort.Search(len(lexTokens), func(i int) bool {
+		return lexTokens[i].Range.Start.Byte >= startByte
+	})
+	endIdx := sort.Search(len(lexTokens), func(i int) bool {
+		return lexTokens[i].Range.Start.Byte >= endByte
+	})
+	if startIdx >= endIdx {
+		return hclsyntax.Tokens{}
+	}
+	return lexTokens[startIdx:endIdx]
+}
+
+// trimTrailingNewline returns range of the token without any trailing
+// newline, which is part of single-line comments and heredoc openers
+func trimTrailingNewline(tok hclsyntax.Token) hcl.Range {
+	trimmed := bytes.TrimRight(tok.Bytes, "\r\n")
+	if len(trimmed) == len(tok.Bytes) {
+		return tok.Range
+	}
+
+	rng := tok.Range
+	rng.End = hcl.Pos{
+		Line:   rng.Start.Line,
+		Column: rng.Start.Column + utf8.RuneCount(trimmed),
+		Byte:   rng.Start.Byte + len(trimmed),
+	}
+	return rng
+}
+
+func newSyntaxToken(tokenType lang.SemanticTokenType, rng hcl.Range) lang.SemanticToken {
+	return lang.SemanticToken{
+		Type:      tokenType,
+		Modifiers: lang.SemanticTokenModifiers{},
+		Range:     rng,
+	}
+}
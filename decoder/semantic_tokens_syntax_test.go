@@ -0,0 +1,506 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package decoder
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/hcl-lang/lang"
+	"github.com/hashicorp/hcl-lang/schema"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/zclconf/go-cty/cty"
+)
+
+func TestDecoder_SemanticTokensInFile_syntaxTokens(t *testing.T) {
+	bodySchema := &schema.BodySchema{
+		Attributes: map[string]*schema.AttributeSchema{
+			"heredoc": {Constraint: schema.LiteralType{Type: cty.String}},
+			"tpl":     {Constraint: schema.AnyExpression{OfType: cty.String}},
+			"cond":    {Constraint: schema.AnyExpression{OfType: cty.Number}},
+			"list":    {Constraint: schema.AnyExpression{OfType: cty.List(cty.Number)}},
+		},
+	}
+
+	testCfg := []byte(`# comment
+heredoc = <<-EOT
+  foo
+  EOT
+tpl = "%{ if true }${1}%{ endif }"
+cond = !true ? 1 : 2
+list = [for k, v in [1] : v]
+`)
+
+	f, pDiags := hclsyntax.ParseConfig(testCfg, "test.tf", hcl.InitialPos)
+	if len(pDiags) > 0 {
+		t.Fatal(pDiags)
+	}
+
+	d := testPathDecoder(t, &PathContext{
+		Schema: bodySchema,
+		Files: map[string]*hcl.File{
+			"test.tf": f,
+		},
+	})
+	d.decoderCtx.EnabledSemanticTokenTypes = lang.OptionalSemanticTokenTypes
+
+	ctx := context.Background()
+
+	tokens, err := d.SemanticTokensInFile(ctx, "test.tf")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectedTokens := []lang.SemanticToken{
+		{
+			Type:      lang.TokenComment,
+			Modifiers: lang.SemanticTokenModifiers{},
+			Range: hcl.Range{
+				Filename: "test.tf",
+				Start:    hcl.Pos{Line: 1, Column: 1, Byte: 0},
+				End:      hcl.Pos{Line: 1, Column: 10, Byte: 9},
+			},
+		},
+		{
+			Type:      lang.TokenAttrName,
+			Modifiers: lang.SemanticTokenModifiers{},
+			Range: hcl.Range{
+				Filename: "test.tf",
+				Start:    hcl.Pos{Line: 2, Column: 1, Byte: 10},
+				End:      hcl.Pos{Line: 2, Column: 8, Byte: 17},
+			},
+		},
+		{
+			Type:      lang.TokenHeredocAnchor,
+			Modifiers: lang.SemanticTokenModifiers{},
+			Range: hcl.Range{
+				Filename: "test.tf",
+				Start:    hcl.Pos{Line: 2, Column: 11, Byte: 20},
+				End:      hcl.Pos{Line: 2, Column: 17, Byte: 26},
+			},
+		},
+		{
+			Type:      lang.TokenString,
+			Modifiers: lang.SemanticTokenModifiers{},
+			Range: hcl.Range{
+				Filename: "test.tf",
+				Start:    hcl.Pos{Line: 2, Column: 17, Byte: 26},
+				End:      hcl.Pos{Line: 4, Column: 3, Byte: 35},
+			},
+		},
+		{
+			Type:      lang.TokenHeredocAnchor,
+			Modifiers: lang.SemanticTokenModifiers{},
+			Range: hcl.Range{
+				Filename: "test.tf",
+				Start:    hcl.Pos{Line: 4, Column: 3, Byte: 35},
+				End:      hcl.Pos{Line: 4, Column: 6, Byte: 38},
+			},
+		},
+		{
+			Type:      lang.TokenAttrName,
+			Modifiers: lang.SemanticTokenModifiers{},
+			Range: hcl.Range{
+				Filename: "test.tf",
+				Start:    hcl.Pos{Line: 5, Column: 1, Byte: 39},
+				End:      hcl.Pos{Line: 5, Column: 4, Byte: 42},
+			},
+		},
+		{
+			Type:      lang.TokenTemplateDirective,
+			Modifiers: lang.SemanticTokenModifiers{},
+			Range: hcl.Range{
+				Filename: "test.tf",
+				Start:    hcl.Pos{Line: 5, Column: 8, Byte: 46},
+				End:      hcl.Pos{Line: 5, Column: 10, Byte: 48},
+			},
+		},
+		{
+			Type:      lang.TokenTemplateDirective,
+			Modifiers: lang.SemanticTokenModifiers{},
+			Range: hcl.Range{
+				Filename: "test.tf",
+				Start:    hcl.Pos{Line: 5, Column: 11, Byte: 49},
+				End:      hcl.Pos{Line: 5, Column: 13, Byte: 51},
+			},
+		},
+		{
+			Type:      lang.TokenBool,
+			Modifiers: lang.SemanticTokenModifiers{},
+			Range: hcl.Range{
+				Filename: "test.tf",
+				Start:    hcl.Pos{Line: 5, Column: 14, Byte: 52},
+				End:      hcl.Pos{Line: 5, Column: 18, Byte: 56},
+			},
+		},
+		{
+			Type:      lang.TokenTemplateDirective,
+			Modifiers: lang.SemanticTokenModifiers{},
+			Range: hcl.Range{
+				Filename: "test.tf",
+				Start:    hcl.Pos{Line: 5, Column: 19, Byte: 57},
+				End:      hcl.Pos{Line: 5, Column: 20, Byte: 58},
+			},
+		},
+		{
+			Type:      lang.TokenTemplateInterpolation,
+			Modifiers: lang.SemanticTokenModifiers{},
+			Range: hcl.Range{
+				Filename: "test.tf",
+				Start:    hcl.Pos{Line: 5, Column: 20, Byte: 58},
+				End:      hcl.Pos{Line: 5, Column: 22, Byte: 60},
+			},
+		},
+		{
+			Type:      lang.TokenString,
+			Modifiers: lang.SemanticTokenModifiers{},
+			Range: hcl.Range{
+				Filename: "test.tf",
+				Start:    hcl.Pos{Line: 5, Column: 22, Byte: 60},
+				End:      hcl.Pos{Line: 5, Column: 23, Byte: 61},
+			},
+		},
+		{
+			Type:      lang.TokenTemplateInterpolation,
+			Modifiers: lang.SemanticTokenModifiers{},
+			Range: hcl.Range{
+				Filename: "test.tf",
+				Start:    hcl.Pos{Line: 5, Column: 23, Byte: 61},
+				End:      hcl.Pos{Line: 5, Column: 24, Byte: 62},
+			},
+		},
+		{
+			Type:      lang.TokenTemplateDirective,
+			Modifiers: lang.SemanticTokenModifiers{},
+			Range: hcl.Range{
+				Filename: "test.tf",
+				Start:    hcl.Pos{Line: 5, Column: 24, Byte: 62},
+				End:      hcl.Pos{Line: 5, Column: 26, Byte: 64},
+			},
+		},
+		{
+			Type:      lang.TokenString,
+			Modifiers: lang.SemanticTokenModifiers{},
+			Range: hcl.Range{
+				Filename: "test.tf",
+				Start:    hcl.Pos{Line: 5, Column: 24, Byte: 62},
+				End:      hcl.Pos{Line: 5, Column: 24, Byte: 62},
+			},
+		},
+		{
+			Type:      lang.TokenTemplateDirective,
+			Modifiers: lang.SemanticTokenModifiers{},
+			Range: hcl.Range{
+				Filename: "test.tf",
+				Start:    hcl.Pos{Line: 5, Column: 27, Byte: 65},
+				End:      hcl.Pos{Line: 5, Column: 32, Byte: 70},
+			},
+		},
+		{
+			Type:      lang.TokenTemplateDirective,
+			Modifiers: lang.SemanticTokenModifiers{},
+			Range: hcl.Range{
+				Filename: "test.tf",
+				Start:    hcl.Pos{Line: 5, Column: 33, Byte: 71},
+				End:      hcl.Pos{Line: 5, Column: 34, Byte: 72},
+			},
+		},
+		{
+			Type:      lang.TokenAttrName,
+			Modifiers: lang.SemanticTokenModifiers{},
+			Range: hcl.Range{
+				Filename: "test.tf",
+				Start:    hcl.Pos{Line: 6, Column: 1, Byte: 74},
+				End:      hcl.Pos{Line: 6, Column: 5, Byte: 78},
+			},
+		},
+		{
+			Type:      lang.TokenOperator,
+			Modifiers: lang.SemanticTokenModifiers{},
+			Range: hcl.Range{
+				Filename: "test.tf",
+				Start:    hcl.Pos{Line: 6, Column: 8, Byte: 81},
+				End:      hcl.Pos{Line: 6, Column: 9, Byte: 82},
+			},
+		},
+		{
+			Type:      lang.TokenBool,
+			Modifiers: lang.SemanticTokenModifiers{},
+			Range: hcl.Range{
+				Filename: "test.tf",
+				Start:    hcl.Pos{Line: 6, Column: 9, Byte: 82},
+				End:      hcl.Pos{Line: 6, Column: 13, Byte: 86},
+			},
+		},
+		{
+			Type:      lang.TokenConditionalOperator,
+			Modifiers: lang.SemanticTokenModifiers{},
+			Range: hcl.Range{
+				Filename: "test.tf",
+				Start:    hcl.Pos{Line: 6, Column: 14, Byte: 87},
+				End:      hcl.Pos{Line: 6, Column: 15, Byte: 88},
+			},
+		},
+		{
+			Type:      lang.TokenNumber,
+			Modifiers: lang.SemanticTokenModifiers{},
+			Range: hcl.Range{
+				Filename: "test.tf",
+				Start:    hcl.Pos{Line: 6, Column: 16, Byte: 89},
+				End:      hcl.Pos{Line: 6, Column: 17, Byte: 90},
+			},
+		},
+		{
+			Type:      lang.TokenConditionalOperator,
+			Modifiers: lang.SemanticTokenModifiers{},
+			Range: hcl.Range{
+				Filename: "test.tf",
+				Start:    hcl.Pos{Line: 6, Column: 18, Byte: 91},
+				End:      hcl.Pos{Line: 6, Column: 19, Byte: 92},
+			},
+		},
+		{
+			Type:      lang.TokenNumber,
+			Modifiers: lang.SemanticTokenModifiers{},
+			Range: hcl.Range{
+				Filename: "test.tf",
+				Start:    hcl.Pos{Line: 6, Column: 20, Byte: 93},
+				End:      hcl.Pos{Line: 6, Column: 21, Byte: 94},
+			},
+		},
+		{
+			Type:      lang.TokenAttrName,
+			Modifiers: lang.SemanticTokenModifiers{},
+			Range: hcl.Range{
+				Filename: "test.tf",
+				Start:    hcl.Pos{Line: 7, Column: 1, Byte: 95},
+				End:      hcl.Pos{Line: 7, Column: 5, Byte: 99},
+			},
+		},
+		{
+			Type:      lang.TokenIteratorVariable,
+			Modifiers: lang.SemanticTokenModifiers{},
+			Range: hcl.Range{
+				Filename: "test.tf",
+				Start:    hcl.Pos{Line: 7, Column: 13, Byte: 107},
+				End:      hcl.Pos{Line: 7, Column: 14, Byte: 108},
+			},
+		},
+		{
+			Type:      lang.TokenIteratorVariable,
+			Modifiers: lang.SemanticTokenModifiers{},
+			Range: hcl.Range{
+				Filename: "test.tf",
+				Start:    hcl.Pos{Line: 7, Column: 16, Byte: 110},
+				End:      hcl.Pos{Line: 7, Column: 17, Byte: 111},
+			},
+		},
+		{
+			Type:      lang.TokenNumber,
+			Modifiers: lang.SemanticTokenModifiers{},
+			Range: hcl.Range{
+				Filename: "test.tf",
+				Start:    hcl.Pos{Line: 7, Column: 22, Byte: 116},
+				End:      hcl.Pos{Line: 7, Column: 23, Byte: 117},
+			},
+		},
+		{
+			Type:      lang.TokenIteratorVariable,
+			Modifiers: lang.SemanticTokenModifiers{},
+			Range: hcl.Range{
+				Filename: "test.tf",
+				Start:    hcl.Pos{Line: 7, Column: 27, Byte: 121},
+				End:      hcl.Pos{Line: 7, Column: 28, Byte: 122},
+			},
+		},
+	}
+	if diff := cmp.Diff(expectedTokens, tokens); diff != "" {
+		t.Fatalf("unexpected tokens: %s", diff)
+	}
+}
+
+func TestDecoder_SemanticTokensInFile_syntaxTokensOptIn(t *testing.T) {
+	bodySchema := &schema.BodySchema{
+		Attributes: map[string]*schema.AttributeSchema{
+			"num": {Constraint: schema.AnyExpression{OfType: cty.Number}},
+		},
+	}
+
+	testCfg := []byte(`num = 1 + 2 // comment
+`)
+
+	f, pDiags := hclsyntax.ParseConfig(testCfg, "test.tf", hcl.InitialPos)
+	if len(pDiags) > 0 {
+		t.Fatal(pDiags)
+	}
+
+	d := testPathDecoder(t, &PathContext{
+		Schema: bodySchema,
+		Files: map[string]*hcl.File{
+			"test.tf": f,
+		},
+	})
+	d.decoderCtx.EnabledSemanticTokenTypes = lang.SemanticTokenTypes{
+		lang.TokenComment,
+	}
+
+	ctx := context.Background()
+
+	tokens, err := d.SemanticTokensInFile(ctx, "test.tf")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectedTokens := []lang.SemanticToken{
+		{
+			Type:      lang.TokenAttrName,
+			Modifiers: lang.SemanticTokenModifiers{},
+			Range: hcl.Range{
+				Filename: "test.tf",
+				Start:    hcl.Pos{Line: 1, Column: 1, Byte: 0},
+				End:      hcl.Pos{Line: 1, Column: 4, Byte: 3},
+			},
+		},
+		{
+			Type:      lang.TokenNumber,
+			Modifiers: lang.SemanticTokenModifiers{},
+			Range: hcl.Range{
+				Filename: "test.tf",
+				Start:    hcl.Pos{Line: 1, Column: 7, Byte: 6},
+				End:      hcl.Pos{Line: 1, Column: 8, Byte: 7},
+			},
+		},
+		{
+			Type:      lang.TokenNumber,
+			Modifiers: lang.SemanticTokenModifiers{},
+			Range: hcl.Range{
+				Filename: "test.tf",
+				Start:    hcl.Pos{Line: 1, Column: 11, Byte: 10},
+				End:      hcl.Pos{Line: 1, Column: 12, Byte: 11},
+			},
+		},
+		{
+			Type:      lang.TokenComment,
+			Modifiers: lang.SemanticTokenModifiers{},
+			Range: hcl.Range{
+				Filename: "test.tf",
+				Start:    hcl.Pos{Line: 1, Column: 13, Byte: 12},
+				End:      hcl.Pos{Line: 1, Column: 23, Byte: 22},
+			},
+		},
+	}
+	if diff := cmp.Diff(expectedTokens, tokens); diff != "" {
+		t.Fatalf("unexpected tokens: %s", diff)
+	}
+}
+
+func TestDecoder_SemanticTokensInRange_syntaxTokens(t *testing.T) {
+	bodySchema := &schema.BodySchema{
+		Attributes: map[string]*schema.AttributeSchema{
+			"a": {Constraint: schema.AnyExpression{OfType: cty.Number}},
+			"b": {Constraint: schema.AnyExpression{OfType: cty.Number}},
+		},
+	}
+
+	testCfg := []byte(`# first
+a = 1 + 2
+# second
+b = 3 * 4
+`)
+
+	f, pDiags := hclsyntax.ParseConfig(testCfg, "test.tf", hcl.InitialPos)
+	if len(pDiags) > 0 {
+		t.Fatal(pDiags)
+	}
+
+	d := testPathDecoder(t, &PathContext{
+		Schema: bodySchema,
+		Files: map[string]*hcl.File{
+			"test.tf": f,
+		},
+	})
+	d.decoderCtx.EnabledSemanticTokenTypes = lang.OptionalSemanticTokenTypes
+
+	ctx := context.Background()
+
+	tokens, err := d.SemanticTokensInRange(ctx, "test.tf", hcl.Range{
+		Filename: "test.tf",
+		Start:    hcl.Pos{Line: 4, Column: 1, Byte: 27},
+		End:      hcl.Pos{Line: 5, Column: 1, Byte: 37},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectedTokens := []lang.SemanticToken{
+		{
+			Type:      lang.TokenAttrName,
+			Modifiers: lang.SemanticTokenModifiers{},
+			Range: hcl.Range{
+				Filename: "test.tf",
+				Start:    hcl.Pos{Line: 4, Column: 1, Byte: 27},
+				End:      hcl.Pos{Line: 4, Column: 2, Byte: 28},
+			},
+		},
+		{
+			Type:      lang.TokenNumber,
+			Modifiers: lang.SemanticTokenModifiers{},
+			Range: hcl.Range{
+				Filename: "test.tf",
+				Start:    hcl.Pos{Line: 4, Column: 5, Byte: 31},
+				End:      hcl.Pos{Line: 4, Column: 6, Byte: 32},
+			},
+		},
+		{
+			Type:      lang.TokenOperator,
+			Modifiers: lang.SemanticTokenModifiers{},
+			Range: hcl.Range{
+				Filename: "test.tf",
+				Start:    hcl.Pos{Line: 4, Column: 7, Byte: 33},
+				End:      hcl.Pos{Line: 4, Column: 8, Byte: 34},
+			},
+		},
+		{
+			Type:      lang.TokenNumber,
+			Modifiers: lang.SemanticTokenModifiers{},
+			Range: hcl.Range{
+				Filename: "test.tf",
+				Start:    hcl.Pos{Line: 4, Column: 9, Byte: 35},
+				End:      hcl.Pos{Line: 4, Column: 10, Byte: 36},
+			},
+		},
+	}
+	if diff := cmp.Diff(expectedTokens, tokens); diff != "" {
+		t.Fatalf("unexpected tokens: %s", diff)
+	}
+}
+
+func TestSyntaxSourceBounds(t *testing.T) {
+	src := []byte(`a = 1
+b = "${2}"
+c = 3
+`)
+	f, pDiags := hclsyntax.ParseConfig(src, "test.tf", hcl.InitialPos)
+	if len(pDiags) > 0 {
+		t.Fatal(pDiags)
+	}
+	body := f.Body.(*hclsyntax.Body)
+
+	// range starting inside of the template of b
+	startPos, endByte := syntaxSourceBounds(body, hcl.Range{
+		Filename: "test.tf",
+		Start:    hcl.Pos{Line: 2, Column: 7, Byte: 12},
+		End:      hcl.Pos{Line: 2, Column: 9, Byte: 14},
+	}, len(src))
+
+	expectedStartPos := hcl.Pos{Line: 1, Column: 6, Byte: 5}
+	if diff := cmp.Diff(expectedStartPos, startPos); diff != "" {
+		t.Fatalf("unexpected start position: %s", diff)
+	}
+	if endByte != 17 {
+		t.Fatalf("expected end byte 17, %d given", endByte)
+	}
+}
@@ -41,15 +41,33 @@ func TestDecoder_SemanticTokensInFile_emptyBody(t *testing.T) {
 
 func TestDecoder_SemanticTokensInFile_json(t *testing.T) {
 	f, pDiags := json.Parse([]byte(`{
-	"customblock": {
-		"label1": {}
-	}
+  "attr": "hello",
+  "customblock": {
+    "label1": {
+      "num": 42
+    }
+  }
 }`), "test.tf.json")
 	if len(pDiags) > 0 {
 		t.Fatal(pDiags)
 	}
 
 	d := testPathDecoder(t, &PathContext{
+		Schema: &schema.BodySchema{
+			Attributes: map[string]*schema.AttributeSchema{
+				"attr": {Constraint: schema.LiteralType{Type: cty.String}},
+			},
+			Blocks: map[string]*schema.BlockSchema{
+				"customblock": {
+					Labels: []*schema.LabelSchema{{Name: "name"}},
+					Body: &schema.BodySchema{
+						Attributes: map[string]*schema.AttributeSchema{
+							"num": {Constraint: schema.LiteralType{Type: cty.Number}},
+						},
+					},
+				},
+			},
+		},
 		Files: map[string]*hcl.File{
 			"test.tf.json": f,
 		},
@@ -57,10 +75,69 @@ func TestDecoder_SemanticTokensInFile_json(t *testing.T) {
 
 	ctx := context.Background()
 
-	_, err := d.SemanticTokensInFile(ctx, "test.tf.json")
-	unknownFormatErr := &UnknownFileFormatError{}
-	if !errors.As(err, &unknownFormatErr) {
-		t.Fatal("expected UnknownFileFormatError for JSON body")
+	tokens, err := d.SemanticTokensInFile(ctx, "test.tf.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectedTokens := []lang.SemanticToken{
+		{
+			Type:      lang.TokenAttrName,
+			Modifiers: lang.SemanticTokenModifiers{},
+			Range: hcl.Range{
+				Filename: "test.tf.json",
+				Start:    hcl.Pos{Line: 2, Column: 3, Byte: 4},
+				End:      hcl.Pos{Line: 2, Column: 9, Byte: 10},
+			},
+		},
+		{
+			Type:      lang.TokenString,
+			Modifiers: lang.SemanticTokenModifiers{},
+			Range: hcl.Range{
+				Filename: "test.tf.json",
+				Start:    hcl.Pos{Line: 2, Column: 11, Byte: 12},
+				End:      hcl.Pos{Line: 2, Column: 18, Byte: 19},
+			},
+		},
+		{
+			Type:      lang.TokenBlockType,
+			Modifiers: lang.SemanticTokenModifiers{},
+			Range: hcl.Range{
+				Filename: "test.tf.json",
+				Start:    hcl.Pos{Line: 3, Column: 3, Byte: 23},
+				End:      hcl.Pos{Line: 3, Column: 16, Byte: 36},
+			},
+		},
+		{
+			Type:      lang.TokenBlockLabel,
+			Modifiers: lang.SemanticTokenModifiers{},
+			Range: hcl.Range{
+				Filename: "test.tf.json",
+				Start:    hcl.Pos{Line: 4, Column: 5, Byte: 44},
+				End:      hcl.Pos{Line: 4, Column: 13, Byte: 52},
+			},
+		},
+		{
+			Type:      lang.TokenAttrName,
+			Modifiers: lang.SemanticTokenModifiers{},
+			Range: hcl.Range{
+				Filename: "test.tf.json",
+				Start:    hcl.Pos{Line: 5, Column: 7, Byte: 62},
+				End:      hcl.Pos{Line: 5, Column: 12, Byte: 67},
+			},
+		},
+		{
+			Type:      lang.TokenNumber,
+			Modifiers: lang.SemanticTokenModifiers{},
+			Range: hcl.Range{
+				Filename: "test.tf.json",
+				Start:    hcl.Pos{Line: 5, Column: 14, Byte: 69},
+				End:      hcl.Pos{Line: 5, Column: 16, Byte: 71},
+			},
+		},
+	}
+	if diff := cmp.Diff(expectedTokens, tokens); diff != "" {
+		t.Fatalf("unexpected tokens: %s", diff)
 	}
 }
 
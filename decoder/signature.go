@@ -12,10 +12,12 @@ import (
 	"github.com/hashicorp/hcl-lang/schema"
 	"github.com/hashicorp/hcl/v2"
 	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/hcl/v2/json"
 )
 
 // SignatureAtPos returns a function signature for the given pos if pos
-// is inside a FunctionCallExpr
+// is inside a FunctionCallExpr, or a signature of the block header
+// (with labels as parameters) if pos is inside a block header.
 func (d *PathDecoder) SignatureAtPos(filename string, pos hcl.Pos) (*lang.FunctionSignature, error) {
 	file, err := d.fileByName(filename)
 	if err != nil {
@@ -124,6 +126,11 @@ func (d *PathDecoder) SignatureAtPos(filename string, pos hcl.Pos) (*lang.Functi
 		return nil // We don't want to add any diagnostics
 	})
 
+	if signature == nil && !json.IsJSONBody(file.Body) && d.pathCtx.Schema != nil {
+		bodySchema := d.bodySchemaAtPos(body, d.pathCtx.Schema, pos)
+		signature = blockSignatureAtPos(file.Bytes, filename, bodySchema, pos)
+	}
+
 	return signature, nil
 }
 
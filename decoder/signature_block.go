@@ -0,0 +1,190 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package decoder
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/hcl-lang/lang"
+	"github.com/hashicorp/hcl-lang/schema"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+)
+
+// blockHeaderLabel represents a label in a (possibly incomplete)
+// block header as found in the source
+type blockHeaderLabel struct {
+	value string
+	rng   hcl.Range
+}
+
+// blockSignatureAtPos returns a signature of the block header
+// at the given position, with labels represented as parameters.
+//
+// The header is lexed from the source rather than taken from
+// the AST, so that incomplete headers (e.g. resource ") are
+// recognized before the block can be parsed.
+func blockSignatureAtPos(src []byte, filename string, bodySchema *schema.BodySchema, pos hcl.Pos) *lang.FunctionSignature {
+	if bodySchema == nil {
+		return nil
+	}
+
+	typeRng, blockType, labels, ok := blockHeaderAtPos(src, filename, pos)
+	if !ok {
+		return nil
+	}
+	bSchema, ok := bodySchema.Blocks[blockType]
+	if !ok || len(bSchema.Labels) == 0 {
+		return nil
+	}
+
+	activeLabel := 0
+	if pos.Byte > typeRng.End.Byte {
+		activeLabel = len(labels)
+		for i, label := range labels {
+			if pos.Byte <= label.rng.End.Byte {
+				activeLabel = i
+				break
+			}
+		}
+	}
+	if activeLabel >= len(bSchema.Labels) {
+		return nil // too many labels
+	}
+
+	labelNames := make([]string, 0, len(bSchema.Labels))
+	parameters := make([]lang.FunctionParameter, 0, len(bSchema.Labels))
+	for i, lSchema := range bSchema.Labels {
+		labelNames = append(labelNames, fmt.Sprintf("%q", lSchema.Name))
+		parameters = append(parameters, lang.FunctionParameter{
+			Name:        lSchema.Name,
+			Description: labelDescription(bSchema, i, labels),
+		})
+	}
+
+	return &lang.FunctionSignature{
+		Name:            fmt.Sprintf("%s %s", blockType, strings.Join(labelNames, " ")),
+		Description:     bSchema.Description,
+		Parameters:      parameters,
+		ActiveParameter: uint32(activeLabel),
+	}
+}
+
+// labelDescription returns description of the label, preferring
+// description of the dependent body matching the label value,
+// if the label is a dependency key and its value is known
+func labelDescription(bSchema *schema.BlockSchema, idx int, labels []blockHeaderLabel) lang.MarkupContent {
+	lSchema := bSchema.Labels[idx]
+	description := lSchema.Description
+
+	if !lSchema.IsDepKey {
+		return description
+	}
+
+	if idx < len(labels) && labels[idx].value != "" {
+		depBody, ok := bSchema.LookupDependentBody(schema.DependencyKeys{
+			Labels: []schema.LabelDependent{
+				{Index: idx, Value: labels[idx].value},
+			},
+		})
+		if ok && depBody.Description.Value != "" {
+			return depBody.Description
+		}
+	}
+
+	note := "The value determines the schema of the block body."
+	if description.Value == "" {
+		return lang.PlainText(note)
+	}
+	return lang.MarkupContent{
+		Value: fmt.Sprintf("%s\n\n%s", description.Value, note),
+		Kind:  description.Kind,
+	}
+}
+
+// blockHeaderAtPos lexes the line at the given position and returns
+// the block type and labels, if the line represents a block header
+// and the position is not past its opening brace
+func blockHeaderAtPos(src []byte, filename string, pos hcl.Pos) (hcl.Range, string, []blockHeaderLabel, bool) {
+	if pos.Byte > len(src) {
+		return hcl.Range{}, "", nil, false
+	}
+
+	start := lineStart(src, pos.Byte)
+	end := lineEnd(src, pos.Byte)
+	tokens, _ := hclsyntax.LexConfig(src[start:end], filename, hcl.Pos{
+		Line:   pos.Line,
+		Column: 1,
+		Byte:   start,
+	})
+
+	if len(tokens) == 0 || tokens[0].Type != hclsyntax.TokenIdent {
+		return hcl.Range{}, "", nil, false
+	}
+	typeRng := tokens[0].Range
+	blockType := string(tokens[0].Bytes)
+
+	labels := make([]blockHeaderLabel, 0)
+	for i := 1; i < len(tokens); i++ {
+		tok := tokens[i]
+		switch tok.Type {
+		case hclsyntax.TokenIdent:
+			labels = append(labels, blockHeaderLabel{
+				value: string(tok.Bytes),
+				rng:   tok.Range,
+			})
+		case hclsyntax.TokenOQuote:
+			label := blockHeaderLabel{rng: tok.Range}
+			for i+1 < len(tokens) {
+				i++
+				inner := tokens[i]
+				if inner.Type == hclsyntax.TokenQuotedLit {
+					label.value += string(inner.Bytes)
+				}
+				if inner.Type == hclsyntax.TokenNewline || inner.Type == hclsyntax.TokenEOF {
+					// unterminated label
+					break
+				}
+				label.rng = hcl.RangeBetween(label.rng, inner.Range)
+				if inner.Type == hclsyntax.TokenCQuote {
+					break
+				}
+			}
+			labels = append(labels, label)
+		case hclsyntax.TokenOBrace:
+			if pos.Byte > tok.Range.Start.Byte {
+				return hcl.Range{}, "", nil, false
+			}
+			return typeRng, blockType, labels, true
+		case hclsyntax.TokenNewline, hclsyntax.TokenEOF:
+			return typeRng, blockType, labels, true
+		default:
+			// e.g. attribute
+			return hcl.Range{}, "", nil, false
+		}
+	}
+
+	return typeRng, blockType, labels, true
+}
+
+// bodySchemaAtPos returns schema of the innermost body
+// containing the position
+func (d *PathDecoder) bodySchemaAtPos(body *hclsyntax.Body, bodySchema *schema.BodySchema, pos hcl.Pos) *schema.BodySchema {
+	for _, block := range body.Blocks {
+		if block.Body == nil || !block.Body.Range().ContainsPos(pos) {
+			continue
+		}
+		bSchema, ok := bodySchema.Blocks[block.Type]
+		if !ok {
+			return nil
+		}
+		mergedSchema, _ := d.mergeBlockBodySchemas(block.AsHCLBlock(), bSchema)
+		if mergedSchema == nil {
+			return nil
+		}
+		return d.bodySchemaAtPos(block.Body, mergedSchema, pos)
+	}
+	return bodySchema
+}
@@ -4,7 +4,6 @@
 package decoder
 
 import (
-	"errors"
 	"fmt"
 	"testing"
 
@@ -501,8 +500,8 @@ func TestSignatureAtPos(t *testing.T) {
 
 func TestSignatureAtPos_json(t *testing.T) {
 	f, pDiags := json.Parse([]byte(`{
-		"attribute": "${abs(-1)}"
-	}`), "test.tf.json")
+  "attribute": "${abs(-1)}"
+}`), "test.tf.json")
 	if len(pDiags) > 0 {
 		t.Fatal(pDiags)
 	}
@@ -511,11 +510,222 @@ func TestSignatureAtPos_json(t *testing.T) {
 		Files: map[string]*hcl.File{
 			"test.tf.json": f,
 		},
+		Schema: &schema.BodySchema{
+			Attributes: map[string]*schema.AttributeSchema{
+				"attribute": {Constraint: schema.AnyExpression{OfType: cty.Number}},
+			},
+		},
+		Functions: map[string]schema.FunctionSignature{
+			"abs": {
+				Params: []function.Parameter{
+					{
+						Name: "num",
+						Type: cty.Number,
+					},
+				},
+				ReturnType:  cty.Number,
+				Description: "`abs` returns the absolute value of the given number.",
+			},
+		},
 	})
 
-	_, err := d.SignatureAtPos("test.tf.json", hcl.InitialPos)
-	unknownFormatErr := &UnknownFileFormatError{}
-	if !errors.As(err, &unknownFormatErr) {
-		t.Fatal("expected UnknownFileFormatError for JSON body")
+	signature, err := d.SignatureAtPos("test.tf.json", hcl.Pos{Line: 2, Column: 23, Byte: 24})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectedSignature := &lang.FunctionSignature{
+		Name:        "abs(num number) number",
+		Description: lang.Markdown("`abs` returns the absolute value of the given number."),
+		Parameters: []lang.FunctionParameter{
+			{
+				Name:        "num",
+				Description: lang.Markdown(""),
+			},
+		},
+		ActiveParameter: 0,
+	}
+	if diff := cmp.Diff(expectedSignature, signature); diff != "" {
+		t.Fatalf("unexpected signature: %s", diff)
+	}
+}
+
+func TestSignatureAtPos_blockLabels(t *testing.T) {
+	bodySchema := &schema.BodySchema{
+		Blocks: map[string]*schema.BlockSchema{
+			"resource": {
+				Description: lang.PlainText("Resource block"),
+				Labels: []*schema.LabelSchema{
+					{
+						Name:        "type",
+						Description: lang.PlainText("Resource type"),
+						IsDepKey:    true,
+					},
+					{
+						Name:        "name",
+						Description: lang.PlainText("Resource name"),
+					},
+				},
+				Body: &schema.BodySchema{
+					Blocks: map[string]*schema.BlockSchema{
+						"provisioner": {
+							Labels: []*schema.LabelSchema{
+								{Name: "type"},
+							},
+						},
+					},
+				},
+				DependentBody: map[schema.SchemaKey]*schema.BodySchema{
+					schema.NewSchemaKey(schema.DependencyKeys{
+						Labels: []schema.LabelDependent{
+							{Index: 0, Value: "aws_instance"},
+						},
+					}): {
+						Description: lang.Markdown("An **EC2** instance"),
+					},
+				},
+			},
+			"locals": {},
+		},
+	}
+
+	testCases := []struct {
+		testName          string
+		cfg               string
+		pos               hcl.Pos
+		expectedSignature *lang.FunctionSignature
+	}{
+		{
+			"block without labels",
+			`locals {
+}
+`,
+			hcl.Pos{Line: 1, Column: 4, Byte: 3},
+			nil,
+		},
+		{
+			"unknown block",
+			`foo "bar" {
+}
+`,
+			hcl.Pos{Line: 1, Column: 7, Byte: 6},
+			nil,
+		},
+		{
+			"incomplete header",
+			`resource "`,
+			hcl.Pos{Line: 1, Column: 11, Byte: 10},
+			&lang.FunctionSignature{
+				Name:        `resource "type" "name"`,
+				Description: lang.PlainText("Resource block"),
+				Parameters: []lang.FunctionParameter{
+					{
+						Name:        "type",
+						Description: lang.PlainText("Resource type\n\nThe value determines the schema of the block body."),
+					},
+					{
+						Name:        "name",
+						Description: lang.PlainText("Resource name"),
+					},
+				},
+				ActiveParameter: 0,
+			},
+		},
+		{
+			"after dependency key",
+			`resource "aws_instance" `,
+			hcl.Pos{Line: 1, Column: 25, Byte: 24},
+			&lang.FunctionSignature{
+				Name:        `resource "type" "name"`,
+				Description: lang.PlainText("Resource block"),
+				Parameters: []lang.FunctionParameter{
+					{
+						Name:        "type",
+						Description: lang.Markdown("An **EC2** instance"),
+					},
+					{
+						Name:        "name",
+						Description: lang.PlainText("Resource name"),
+					},
+				},
+				ActiveParameter: 1,
+			},
+		},
+		{
+			"inside complete header",
+			`resource "aws_instance" "web" {
+}
+`,
+			hcl.Pos{Line: 1, Column: 27, Byte: 26},
+			&lang.FunctionSignature{
+				Name:        `resource "type" "name"`,
+				Description: lang.PlainText("Resource block"),
+				Parameters: []lang.FunctionParameter{
+					{
+						Name:        "type",
+						Description: lang.Markdown("An **EC2** instance"),
+					},
+					{
+						Name:        "name",
+						Description: lang.PlainText("Resource name"),
+					},
+				},
+				ActiveParameter: 1,
+			},
+		},
+		{
+			"too many labels",
+			`resource "aws_instance" "web" "extra" {
+}
+`,
+			hcl.Pos{Line: 1, Column: 33, Byte: 32},
+			nil,
+		},
+		{
+			"inside body",
+			`resource "aws_instance" "web" {
+  foo = "bar"
+}
+`,
+			hcl.Pos{Line: 2, Column: 5, Byte: 36},
+			nil,
+		},
+		{
+			"nested block",
+			`resource "aws_instance" "web" {
+  provisioner "" {
+  }
+}
+`,
+			hcl.Pos{Line: 2, Column: 16, Byte: 47},
+			&lang.FunctionSignature{
+				Name: `provisioner "type"`,
+				Parameters: []lang.FunctionParameter{
+					{Name: "type"},
+				},
+				ActiveParameter: 0,
+			},
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("%2d-%s", i, tc.testName), func(t *testing.T) {
+			f, _ := hclsyntax.ParseConfig([]byte(tc.cfg), "test.tf", hcl.InitialPos)
+			d := testPathDecoder(t, &PathContext{
+				Files: map[string]*hcl.File{
+					"test.tf": f,
+				},
+				Schema: bodySchema,
+			})
+
+			signature, err := d.SignatureAtPos("test.tf", tc.pos)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if diff := cmp.Diff(tc.expectedSignature, signature); diff != "" {
+				t.Fatalf("unexpected signature: %s", diff)
+			}
+		})
 	}
 }
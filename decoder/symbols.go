@@ -10,11 +10,11 @@ import (
 	"strings"
 
 	"github.com/hashicorp/hcl-lang/decoder/internal/ast"
-	"github.com/hashicorp/hcl-lang/decoder/internal/schemahelper"
 	"github.com/hashicorp/hcl-lang/lang"
 	"github.com/hashicorp/hcl-lang/schema"
 	"github.com/hashicorp/hcl/v2"
 	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/hcl/v2/json"
 	"github.com/zclconf/go-cty/cty"
 )
 
@@ -23,6 +23,8 @@ import (
 // A symbol is typically represented by a block or an attribute.
 //
 // Symbols within JSON files require schema to be present for decoding.
+// Expression kinds in JSON are inferred from JSON values and any
+// templates within strings, such as "${var.foo}".
 func (d *PathDecoder) SymbolsInFile(filename string) ([]Symbol, error) {
 	f, err := d.fileByName(filename)
 	if err != nil {
@@ -30,11 +32,11 @@ func (d *PathDecoder) SymbolsInFile(filename string) ([]Symbol, error) {
 	}
 
 	_, isHcl := f.Body.(*hclsyntax.Body)
-	if !isHcl {
+	if !isHcl && !json.IsJSONBody(f.Body) {
 		return nil, &UnknownFileFormatError{Filename: filename}
 	}
 
-	return d.symbolsForBody(f.Body, d.pathCtx.Schema), nil
+	return d.symbolsForBody(f.Body, f.Bytes, d.pathCtx.Schema), nil
 }
 
 func (d *PathDecoder) symbolsInFile(filename string) ([]Symbol, error) {
@@ -43,7 +45,7 @@ func (d *PathDecoder) symbolsInFile(filename string) ([]Symbol, error) {
 		return nil, err
 	}
 
-	return d.symbolsForBody(f.Body, d.pathCtx.Schema), nil
+	return d.symbolsForBody(f.Body, f.Bytes, d.pathCtx.Schema), nil
 }
 
 // Symbols returns a hierarchy of symbols matching the query in all paths.
@@ -92,7 +94,7 @@ func (d *PathDecoder) symbols(query string) ([]Symbol, error) {
 	return symbols, nil
 }
 
-func (d *PathDecoder) symbolsForBody(body hcl.Body, bodySchema *schema.BodySchema) []Symbol {
+func (d *PathDecoder) symbolsForBody(body hcl.Body, src []byte, bodySchema *schema.BodySchema) []Symbol {
 	symbols := make([]Symbol, 0)
 	if body == nil {
 		return symbols
@@ -103,10 +105,10 @@ func (d *PathDecoder) symbolsForBody(body hcl.Body, bodySchema *schema.BodySchem
 	for name, attr := range content.Attributes {
 		symbols = append(symbols, &AttributeSymbol{
 			AttrName:      name,
-			ExprKind:      symbolExprKind(attr.Expr),
+			ExprKind:      symbolExprKind(attr.Expr, src),
 			path:          d.path,
 			rng:           attr.Range,
-			nestedSymbols: d.nestedSymbolsForExpr(attr.Expr),
+			nestedSymbols: d.nestedSymbolsForExpr(attr.Expr, src),
 		})
 	}
 
@@ -116,7 +118,7 @@ func (d *PathDecoder) symbolsForBody(body hcl.Body, bodySchema *schema.BodySchem
 			bs, ok := bodySchema.Blocks[block.Type]
 			if ok {
 				bSchema = bs.Body
-				mergedSchema, _ := schemahelper.MergeBlockBodySchemas(block.Block, bs)
+				mergedSchema, _ := d.mergeBlockBodySchemas(block.Block, bs)
 				bSchema = mergedSchema
 			}
 		}
@@ -126,7 +128,7 @@ func (d *PathDecoder) symbolsForBody(body hcl.Body, bodySchema *schema.BodySchem
 			Labels:        block.Labels,
 			path:          d.path,
 			rng:           block.Range,
-			nestedSymbols: d.symbolsForBody(block.Body, bSchema),
+			nestedSymbols: d.symbolsForBody(block.Body, src, bSchema),
 		})
 	}
 
@@ -137,7 +139,7 @@ func (d *PathDecoder) symbolsForBody(body hcl.Body, bodySchema *schema.BodySchem
 	return symbols
 }
 
-func symbolExprKind(expr hcl.Expression) lang.SymbolExprKind {
+func symbolExprKind(expr hcl.Expression, src []byte) lang.SymbolExprKind {
 	switch e := expr.(type) {
 	case *hclsyntax.ScopeTraversalExpr:
 		return lang.ReferenceExprKind{}
@@ -156,13 +158,67 @@ func symbolExprKind(expr hcl.Expression) lang.SymbolExprKind {
 		return lang.TupleConsExprKind{}
 	case *hclsyntax.ObjectConsExpr:
 		return lang.ObjectConsExprKind{}
+	case hclsyntax.Expression:
+		// other native expressions are currently not recognized
 	default:
-		// TODO Determine expression types for JSON
+		return jsonSymbolExprKind(expr, src)
 	}
 	return nil
 }
 
-func (d *PathDecoder) nestedSymbolsForExpr(expr hcl.Expression) []Symbol {
+// jsonExpression represents the methods implemented
+// by (unexported) expressions of the JSON syntax
+type jsonExpression interface {
+	hcl.Expression
+	ExprList() []hcl.Expression
+	ExprMap() []hcl.KeyValuePair
+}
+
+// jsonSymbolExprKind infers the kind of expression from a JSON value
+// and any template (interpolation) within a JSON string.
+func jsonSymbolExprKind(expr hcl.Expression, src []byte) lang.SymbolExprKind {
+	if !json.IsJSONExpression(expr) {
+		return nil
+	}
+	jsonExpr, ok := expr.(jsonExpression)
+	if !ok {
+		return nil
+	}
+	if jsonExpr.ExprList() != nil {
+		return lang.TupleConsExprKind{}
+	}
+	if jsonExpr.ExprMap() != nil {
+		return lang.ObjectConsExprKind{}
+	}
+
+	val, diags := jsonExpr.Value(nil)
+	if diags.HasErrors() {
+		return nil
+	}
+	if val.Type() != cty.String || val.IsNull() {
+		return lang.LiteralTypeKind{Type: val.Type()}
+	}
+
+	tplExpr, ok := jsonStringTemplate(src, jsonExpr.Range(), val.AsString())
+	if !ok {
+		return lang.LiteralTypeKind{Type: cty.String}
+	}
+
+	switch e := tplExpr.(type) {
+	case *hclsyntax.TemplateWrapExpr:
+		if _, ok := e.Wrapped.(*hclsyntax.ScopeTraversalExpr); ok {
+			return lang.ReferenceExprKind{}
+		}
+	case *hclsyntax.TemplateExpr:
+		if e.IsStringLiteral() {
+			return lang.LiteralTypeKind{Type: cty.String}
+		}
+	}
+
+	return nil
+}
+
+func (d *PathDecoder) nestedSymbolsForExpr(expr hcl.Expression, src []byte) []Symbol {
 	symbols := make([]Symbol, 0)
 
 	switch e := expr.(type) {
@@ -170,10 +226,10 @@ func (d *PathDecoder) nestedSymbolsForExpr(expr hcl.Expression) []Symbol {
 		for i, item := range e.ExprList() {
 			symbols = append(symbols, &ExprSymbol{
 				ExprName:      fmt.Sprintf("%d", i),
-				ExprKind:      symbolExprKind(item),
+				ExprKind:      symbolExprKind(item, src),
 				path:          d.path,
 				rng:           item.Range(),
-				nestedSymbols: d.nestedSymbolsForExpr(item),
+				nestedSymbols: d.nestedSymbolsForExpr(item, src),
 			})
 		}
 	case *hclsyntax.ObjectConsExpr:
@@ -186,10 +242,35 @@ func (d *PathDecoder) nestedSymbolsForExpr(expr hcl.Expression) []Symbol {
 			}
 			symbols = append(symbols, &ExprSymbol{
 				ExprName:      key.AsString(),
-				ExprKind:      symbolExprKind(item.ValueExpr),
+				ExprKind:      symbolExprKind(item.ValueExpr, src),
 				path:          d.path,
 				rng:           hcl.RangeBetween(item.KeyExpr.Range(), item.ValueExpr.Range()),
-				nestedSymbols: d.nestedSymbolsForExpr(item.ValueExpr),
+				nestedSymbols: d.nestedSymbolsForExpr(item.ValueExpr, src),
+			})
+		}
+	case hclsyntax.Expression:
+		// other native expressions have no nested symbols
+	case jsonExpression:
+		for i, item := range e.ExprList() {
+			symbols = append(symbols, &ExprSymbol{
+				ExprName:      fmt.Sprintf("%d", i),
+				ExprKind:      symbolExprKind(item, src),
+				path:          d.path,
+				rng:           item.Range(),
+				nestedSymbols: d.nestedSymbolsForExpr(item, src),
+			})
+		}
+		for _, item := range e.ExprMap() {
+			key, diags := item.Key.Value(nil)
+			if diags.HasErrors() || key.IsNull() || !key.IsWhollyKnown() || key.Type() != cty.String {
+				continue
+			}
+			symbols = append(symbols, &ExprSymbol{
+				ExprName:      key.AsString(),
+				ExprKind:      symbolExprKind(item.Value, src),
+				path:          d.path,
+				rng:           hcl.RangeBetween(item.Key.Range(), item.Value.Range()),
+				nestedSymbols: d.nestedSymbolsForExpr(item.Value, src),
 			})
 		}
 	}
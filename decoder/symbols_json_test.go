@@ -12,6 +12,7 @@ import (
 	"github.com/hashicorp/hcl-lang/schema"
 	"github.com/hashicorp/hcl/v2"
 	"github.com/hashicorp/hcl/v2/json"
+	"github.com/zclconf/go-cty-debug/ctydebug"
 	"github.com/zclconf/go-cty/cty"
 )
 
@@ -182,6 +183,7 @@ func TestDecoder_Symbols_json_basic(t *testing.T) {
 			nestedSymbols: []Symbol{
 				&AttributeSymbol{
 					AttrName: "cidr_block",
+					ExprKind: lang.LiteralTypeKind{Type: cty.String},
 					path:     lang.Path{Path: dirPath},
 					rng: hcl.Range{
 						Filename: "first.tf.json",
@@ -206,6 +208,7 @@ func TestDecoder_Symbols_json_basic(t *testing.T) {
 			nestedSymbols: []Symbol{
 				&AttributeSymbol{
 					AttrName: "project",
+					ExprKind: lang.LiteralTypeKind{Type: cty.String},
 					path:     lang.Path{Path: dirPath},
 					rng: hcl.Range{
 						Filename: "second.tf.json",
@@ -216,6 +219,7 @@ func TestDecoder_Symbols_json_basic(t *testing.T) {
 				},
 				&AttributeSymbol{
 					AttrName: "region",
+					ExprKind: lang.LiteralTypeKind{Type: cty.String},
 					path:     lang.Path{Path: dirPath},
 					rng: hcl.Range{
 						Filename: "second.tf.json",
@@ -332,13 +336,37 @@ func TestDecoder_Symbols_json_dependentBody(t *testing.T) {
 			nestedSymbols: []Symbol{
 				&AttributeSymbol{
 					AttrName: "subnet_ids",
+					ExprKind: lang.TupleConsExprKind{},
 					path:     lang.Path{Path: dirPath},
 					rng: hcl.Range{
 						Filename: "test.tf.json",
 						Start:    hcl.Pos{Line: 5, Column: 9, Byte: 64},
 						End:      hcl.Pos{Line: 5, Column: 43, Byte: 98},
 					},
-					nestedSymbols: []Symbol{},
+					nestedSymbols: []Symbol{
+						&ExprSymbol{
+							ExprName: "0",
+							ExprKind: lang.LiteralTypeKind{Type: cty.String},
+							path:     lang.Path{Path: dirPath},
+							rng: hcl.Range{
+								Filename: "test.tf.json",
+								Start:    hcl.Pos{Line: 5, Column: 25, Byte: 80},
+								End:      hcl.Pos{Line: 5, Column: 32, Byte: 87},
+							},
+							nestedSymbols: []Symbol{},
+						},
+						&ExprSymbol{
+							ExprName: "1",
+							ExprKind: lang.LiteralTypeKind{Type: cty.String},
+							path:     lang.Path{Path: dirPath},
+							rng: hcl.Range{
+								Filename: "test.tf.json",
+								Start:    hcl.Pos{Line: 5, Column: 34, Byte: 89},
+								End:      hcl.Pos{Line: 5, Column: 41, Byte: 96},
+							},
+							nestedSymbols: []Symbol{},
+						},
+					},
 				},
 				&BlockSymbol{
 					Type:   "configuration",
@@ -352,6 +380,7 @@ func TestDecoder_Symbols_json_dependentBody(t *testing.T) {
 					nestedSymbols: []Symbol{
 						&AttributeSymbol{
 							AttrName: "name",
+							ExprKind: lang.LiteralTypeKind{Type: cty.String},
 							path:     lang.Path{Path: dirPath},
 							rng: hcl.Range{
 								Filename: "test.tf.json",
@@ -362,6 +391,7 @@ func TestDecoder_Symbols_json_dependentBody(t *testing.T) {
 						},
 						&AttributeSymbol{
 							AttrName: "num",
+							ExprKind: lang.LiteralTypeKind{Type: cty.Number},
 							path:     lang.Path{Path: dirPath},
 							rng: hcl.Range{
 								Filename: "test.tf.json",
@@ -372,6 +402,7 @@ func TestDecoder_Symbols_json_dependentBody(t *testing.T) {
 						},
 						&AttributeSymbol{
 							AttrName: "boolattr",
+							ExprKind: lang.LiteralTypeKind{Type: cty.Bool},
 							path:     lang.Path{Path: dirPath},
 							rng: hcl.Range{
 								Filename: "test.tf.json",
@@ -384,6 +415,7 @@ func TestDecoder_Symbols_json_dependentBody(t *testing.T) {
 				},
 				&AttributeSymbol{
 					AttrName: "random_kw",
+					ExprKind: lang.ReferenceExprKind{},
 					path:     lang.Path{Path: dirPath},
 					rng: hcl.Range{
 						Filename: "test.tf.json",
@@ -491,13 +523,37 @@ func TestDecoder_Symbols_json_unknownExpression(t *testing.T) {
 			nestedSymbols: []Symbol{
 				&AttributeSymbol{
 					AttrName: "subnet_ids",
+					ExprKind: lang.TupleConsExprKind{},
 					path:     lang.Path{Path: dirPath},
 					rng: hcl.Range{
 						Filename: "test.tf.json",
 						Start:    hcl.Pos{Line: 5, Column: 9, Byte: 64},
 						End:      hcl.Pos{Line: 5, Column: 49, Byte: 104},
 					},
-					nestedSymbols: []Symbol{},
+					nestedSymbols: []Symbol{
+						&ExprSymbol{
+							ExprName: "0",
+							ExprKind: lang.ReferenceExprKind{},
+							path:     lang.Path{Path: dirPath},
+							rng: hcl.Range{
+								Filename: "test.tf.json",
+								Start:    hcl.Pos{Line: 5, Column: 25, Byte: 80},
+								End:      hcl.Pos{Line: 5, Column: 38, Byte: 93},
+							},
+							nestedSymbols: []Symbol{},
+						},
+						&ExprSymbol{
+							ExprName: "1",
+							ExprKind: lang.LiteralTypeKind{Type: cty.String},
+							path:     lang.Path{Path: dirPath},
+							rng: hcl.Range{
+								Filename: "test.tf.json",
+								Start:    hcl.Pos{Line: 5, Column: 40, Byte: 95},
+								End:      hcl.Pos{Line: 5, Column: 47, Byte: 102},
+							},
+							nestedSymbols: []Symbol{},
+						},
+					},
 				},
 				&BlockSymbol{
 					Type:   "configuration",
@@ -511,6 +567,7 @@ func TestDecoder_Symbols_json_unknownExpression(t *testing.T) {
 					nestedSymbols: []Symbol{
 						&AttributeSymbol{
 							AttrName: "num",
+							ExprKind: lang.ReferenceExprKind{},
 							path:     lang.Path{Path: dirPath},
 							rng: hcl.Range{
 								Filename: "test.tf.json",
@@ -523,6 +580,7 @@ func TestDecoder_Symbols_json_unknownExpression(t *testing.T) {
 				},
 				&AttributeSymbol{
 					AttrName: "random_kw",
+					ExprKind: lang.ReferenceExprKind{},
 					path:     lang.Path{Path: dirPath},
 					rng: hcl.Range{
 						Filename: "test.tf.json",
@@ -540,3 +598,145 @@ func TestDecoder_Symbols_json_unknownExpression(t *testing.T) {
 		t.Fatalf("unexpected symbols:\n%s", diff)
 	}
 }
+
+func TestDecoder_SymbolsInFile_json(t *testing.T) {
+	bodySchema := &schema.BodySchema{
+		Attributes: map[string]*schema.AttributeSchema{
+			"tags": {
+				IsOptional: true,
+				Constraint: schema.Map{Elem: schema.LiteralType{Type: cty.String}},
+			},
+			"enabled": {
+				IsOptional: true,
+				Constraint: schema.LiteralType{Type: cty.Bool},
+			},
+		},
+	}
+
+	testCfg := []byte(`{
+  "tags": {
+    "env": "${var.env}",
+    "team": "core"
+  },
+  "enabled": false
+}`)
+	f, pDiags := json.Parse(testCfg, "test.tf.json")
+	if len(pDiags) > 0 {
+		t.Fatal(pDiags)
+	}
+
+	d := testPathDecoder(t, &PathContext{
+		Schema: bodySchema,
+		Files: map[string]*hcl.File{
+			"test.tf.json": f,
+		},
+	})
+
+	symbols, err := d.SymbolsInFile("test.tf.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectedSymbols := []Symbol{
+		&AttributeSymbol{
+			AttrName: "tags",
+			ExprKind: lang.ObjectConsExprKind{},
+			path:     d.path,
+			rng: hcl.Range{
+				Filename: "test.tf.json",
+				Start:    hcl.Pos{Line: 2, Column: 3, Byte: 4},
+				End:      hcl.Pos{Line: 5, Column: 4, Byte: 61},
+			},
+			nestedSymbols: []Symbol{
+				&ExprSymbol{
+					ExprName: "env",
+					ExprKind: lang.ReferenceExprKind{},
+					path:     d.path,
+					rng: hcl.Range{
+						Filename: "test.tf.json",
+						Start:    hcl.Pos{Line: 3, Column: 5, Byte: 18},
+						End:      hcl.Pos{Line: 3, Column: 24, Byte: 37},
+					},
+					nestedSymbols: []Symbol{},
+				},
+				&ExprSymbol{
+					ExprName: "team",
+					ExprKind: lang.LiteralTypeKind{Type: cty.String},
+					path:     d.path,
+					rng: hcl.Range{
+						Filename: "test.tf.json",
+						Start:    hcl.Pos{Line: 4, Column: 5, Byte: 43},
+						End:      hcl.Pos{Line: 4, Column: 19, Byte: 57},
+					},
+					nestedSymbols: []Symbol{},
+				},
+			},
+		},
+		&AttributeSymbol{
+			AttrName: "enabled",
+			ExprKind: lang.LiteralTypeKind{Type: cty.Bool},
+			path:     d.path,
+			rng: hcl.Range{
+				Filename: "test.tf.json",
+				Start:    hcl.Pos{Line: 6, Column: 3, Byte: 65},
+				End:      hcl.Pos{Line: 6, Column: 19, Byte: 81},
+			},
+			nestedSymbols: []Symbol{},
+		},
+	}
+
+	diff := cmp.Diff(expectedSymbols, symbols, ctydebug.CmpOptions)
+	if diff != "" {
+		t.Fatalf("unexpected symbols:\n%s", diff)
+	}
+}
+
+func TestDecoder_SymbolsInFile_json_escapedTemplate(t *testing.T) {
+	testCfg := []byte(`{
+  "quoted": "say \"hi\"",
+  "ref": "${var.foo}",
+  "prefixed_ref": "\"${var.foo}",
+  "escaped_call": "${upper(\"foo\")}"
+}`)
+	f, pDiags := json.Parse(testCfg, "test.tf.json")
+	if len(pDiags) > 0 {
+		t.Fatal(pDiags)
+	}
+
+	d := testPathDecoder(t, &PathContext{
+		Schema: &schema.BodySchema{
+			AnyAttribute: &schema.AttributeSchema{
+				Constraint: schema.AnyExpression{OfType: cty.String},
+			},
+		},
+		Files: map[string]*hcl.File{
+			"test.tf.json": f,
+		},
+	})
+
+	symbols, err := d.SymbolsInFile("test.tf.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectedKinds := map[string]lang.SymbolExprKind{
+		"quoted": lang.LiteralTypeKind{Type: cty.String},
+		"ref":    lang.ReferenceExprKind{},
+		// template with interpolation
+		"prefixed_ref": nil,
+		// escape sequences within interpolation are not supported
+		"escaped_call": lang.LiteralTypeKind{Type: cty.String},
+	}
+	kinds := make(map[string]lang.SymbolExprKind, 0)
+	for _, symbol := range symbols {
+		attrSymbol, ok := symbol.(*AttributeSymbol)
+		if !ok {
+			t.Fatalf("unexpected symbol: %#v", symbol)
+		}
+		kinds[attrSymbol.AttrName] = attrSymbol.ExprKind
+	}
+
+	if diff := cmp.Diff(expectedKinds, kinds, ctydebug.CmpOptions); diff != "" {
+		t.Fatalf("unexpected expression kinds: %s", diff)
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/hashicorp/hcl-lang/decoder/internal/walker"
 	"github.com/hashicorp/hcl-lang/lang"
 	"github.com/hashicorp/hcl-lang/schema"
+	"github.com/hashicorp/hcl-lang/schemacontext"
 	"github.com/hashicorp/hcl-lang/validator"
 	"github.com/hashicorp/hcl/v2"
 	"github.com/hashicorp/hcl/v2/hclsyntax"
@@ -25,6 +26,8 @@ func (d *PathDecoder) Validate(ctx context.Context) (lang.DiagnosticsMap, error)
 		return diags, nil
 	}
 
+	ctx = d.validationContext(ctx)
+
 	// Validate module files per schema
 	for filename, f := range d.pathCtx.Files {
 		body, ok := f.Body.(*hclsyntax.Body)
@@ -51,6 +54,8 @@ func (d *PathDecoder) ValidateFile(ctx context.Context, filename string) (hcl.Di
 		return hcl.Diagnostics{}, nil
 	}
 
+	ctx = d.validationContext(ctx)
+
 	f, err := d.fileByName(filename)
 	if err != nil {
 		return hcl.Diagnostics{}, err
@@ -66,6 +71,18 @@ func (d *PathDecoder) ValidateFile(ctx context.Context, filename string) (hcl.Di
 	}), nil
 }
 
+// validationContext returns context carrying any path data
+// which validators may need, such as the target version
+func (d *PathDecoder) validationContext(ctx context.Context) context.Context {
+	if !d.pathCtx.Version.IsZero() {
+		ctx = schemacontext.WithTargetVersion(ctx, d.pathCtx.Version)
+	}
+	if d.decoderCtx.LookupEnv != nil {
+		ctx = schemacontext.WithLookupEnv(ctx, d.decoderCtx.LookupEnv)
+	}
+	return ctx
+}
+
 type validationWalker struct {
 	validators []validator.Validator
 }
@@ -44,6 +44,32 @@ func TestValidate_schema(t *testing.T) {
 			`test = 1`,
 			map[string]hcl.Diagnostics{},
 		},
+		{
+			"invalid default of optional attribute",
+			&schema.BodySchema{
+				Attributes: map[string]*schema.AttributeSchema{
+					"attr": {
+						Constraint: schema.TypeDeclaration{OptionalAttrs: true},
+						IsRequired: true,
+					},
+				},
+			},
+			`attr = object({ foo = optional(number, "x"), bar = list(object({ baz = optional(bool, true) })) })`,
+			map[string]hcl.Diagnostics{
+				"test.tf": {
+					&hcl.Diagnostic{
+						Severity: hcl.DiagError,
+						Summary:  "Invalid default value for optional attribute",
+						Detail:   "This default value is not compatible with the attribute's type constraint: a number is required.",
+						Subject: &hcl.Range{
+							Filename: "test.tf",
+							Start:    hcl.Pos{Line: 1, Column: 40, Byte: 39},
+							End:      hcl.Pos{Line: 1, Column: 43, Byte: 42},
+						},
+					},
+				},
+			},
+		},
 		// attributes
 		{
 			"unknown attribute",
@@ -1057,9 +1083,241 @@ var testValidators = []validator.Validator{
 	validator.BlockLabelsLength{},
 	validator.DeprecatedAttribute{},
 	validator.DeprecatedBlock{},
+	validator.InvalidOptionalAttrDefault{},
 	validator.MaxBlocks{},
 	validator.MinBlocks{},
 	validator.MissingRequiredAttribute{},
 	validator.UnexpectedAttribute{},
 	validator.UnexpectedBlock{},
 }
+
+func TestValidate_versions(t *testing.T) {
+	bodySchema := &schema.BodySchema{
+		Attributes: map[string]*schema.AttributeSchema{
+			"timeout": {
+				IsOptional: true,
+				Constraint: schema.LiteralType{Type: cty.Number},
+				Versions: schema.VersionInfo{
+					IntroducedIn: schema.MustParseVersion("1.3"),
+					DeprecatedIn: schema.MustParseVersion("1.6"),
+				},
+			},
+			"mode": {
+				IsOptional: true,
+				Constraint: schema.OneOf{
+					schema.LiteralValue{Value: cty.StringVal("fast")},
+					schema.LiteralValue{
+						Value: cty.StringVal("turbo"),
+						Versions: schema.VersionInfo{
+							IntroducedIn: schema.MustParseVersion("1.6"),
+						},
+					},
+				},
+			},
+		},
+		Blocks: map[string]*schema.BlockSchema{
+			"legacy": {
+				Body: schema.NewBodySchema(),
+				Versions: schema.VersionInfo{
+					RemovedIn: schema.MustParseVersion("1.5"),
+				},
+			},
+		},
+	}
+	cfg := `timeout = 10
+mode = "turbo"
+legacy {}
+`
+
+	testCases := []struct {
+		version             schema.Version
+		expectedDiagnostics hcl.Diagnostics
+	}{
+		{
+			schema.Version{},
+			nil,
+		},
+		{
+			schema.MustParseVersion("1.2.0"),
+			hcl.Diagnostics{
+				{
+					Severity: hcl.DiagError,
+					Summary:  "\"timeout\" requires version >= 1.3.0",
+					Detail:   "The declared target version is 1.2.0",
+					Subject: &hcl.Range{
+						Filename: "test.tf",
+						Start:    hcl.Pos{Line: 1, Column: 1, Byte: 0},
+						End:      hcl.Pos{Line: 1, Column: 13, Byte: 12},
+					},
+				},
+				{
+					Severity: hcl.DiagError,
+					Summary:  "Value \"turbo\" requires version >= 1.6.0",
+					Detail:   "The declared target version is 1.2.0",
+					Subject: &hcl.Range{
+						Filename: "test.tf",
+						Start:    hcl.Pos{Line: 2, Column: 8, Byte: 20},
+						End:      hcl.Pos{Line: 2, Column: 15, Byte: 27},
+					},
+				},
+			},
+		},
+		{
+			schema.MustParseVersion("1.6.0"),
+			hcl.Diagnostics{
+				{
+					Severity: hcl.DiagError,
+					Summary:  "\"legacy\" was removed in version 1.5.0",
+					Detail:   "The declared target version is 1.6.0",
+					Subject: &hcl.Range{
+						Filename: "test.tf",
+						Start:    hcl.Pos{Line: 3, Column: 1, Byte: 28},
+						End:      hcl.Pos{Line: 3, Column: 7, Byte: 34},
+					},
+				},
+				{
+					Severity: hcl.DiagWarning,
+					Summary:  "\"timeout\" is deprecated since version 1.6.0",
+					Detail:   "The declared target version is 1.6.0",
+					Subject: &hcl.Range{
+						Filename: "test.tf",
+						Start:    hcl.Pos{Line: 1, Column: 1, Byte: 0},
+						End:      hcl.Pos{Line: 1, Column: 13, Byte: 12},
+					},
+				},
+			},
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("%2d-%s", i, tc.version), func(t *testing.T) {
+			f, _ := hclsyntax.ParseConfig([]byte(cfg), "test.tf", hcl.InitialPos)
+			d := testPathDecoder(t, &PathContext{
+				Schema: bodySchema,
+				Files: map[string]*hcl.File{
+					"test.tf": f,
+				},
+				Validators: []validator.Validator{
+					validator.VersionedAttribute{},
+					validator.VersionedBlock{},
+				},
+				Version: tc.version,
+			})
+
+			diags, err := d.ValidateFile(context.Background(), "test.tf")
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			sortDiagnostics(diags)
+
+			if diff := cmp.Diff(tc.expectedDiagnostics, diags); diff != "" {
+				t.Fatalf("unexpected diagnostics: %s", diff)
+			}
+		})
+	}
+}
+
+func TestValidate_envSelectedDependentBody(t *testing.T) {
+	bodySchema := &schema.BodySchema{
+		Blocks: map[string]*schema.BlockSchema{
+			"backend": {
+				Body: &schema.BodySchema{
+					Attributes: map[string]*schema.AttributeSchema{
+						"type": {
+							IsOptional: true,
+							IsDepKey:   true,
+							Constraint: schema.LiteralType{Type: cty.String},
+							DefaultValue: schema.DefaultEnvVar{
+								Name:     "TEST_BACKEND",
+								Fallback: schema.DefaultValue{Value: cty.StringVal("local")},
+							},
+						},
+					},
+				},
+				DependentBody: map[schema.SchemaKey]*schema.BodySchema{
+					schema.NewSchemaKey(schema.DependencyKeys{
+						Attributes: []schema.AttributeDependent{
+							{Name: "type", Expr: schema.ExpressionValue{Static: cty.StringVal("local")}},
+						},
+					}): {
+						Attributes: map[string]*schema.AttributeSchema{
+							"path": {IsOptional: true, Constraint: schema.LiteralType{Type: cty.String}},
+						},
+					},
+					schema.NewSchemaKey(schema.DependencyKeys{
+						Attributes: []schema.AttributeDependent{
+							{Name: "type", Expr: schema.ExpressionValue{Static: cty.StringVal("s3")}},
+						},
+					}): {
+						Attributes: map[string]*schema.AttributeSchema{
+							"bucket": {IsOptional: true, Constraint: schema.LiteralType{Type: cty.String}},
+						},
+					},
+				},
+			},
+		},
+	}
+	cfg := `backend {
+  bucket = "foo"
+}
+`
+
+	testCases := []struct {
+		name                string
+		lookupEnv           schema.LookupEnvFunc
+		expectedDiagnostics hcl.Diagnostics
+	}{
+		{
+			"env var selecting body",
+			func(name string) (string, bool) {
+				if name == "TEST_BACKEND" {
+					return "s3", true
+				}
+				return "", false
+			},
+			nil,
+		},
+		{
+			"fallback selecting body",
+			nil,
+			hcl.Diagnostics{
+				{
+					Severity: hcl.DiagError,
+					Summary:  "Unexpected attribute",
+					Detail:   "An attribute named \"bucket\" is not expected here",
+					Subject: &hcl.Range{
+						Filename: "test.tf",
+						Start:    hcl.Pos{Line: 2, Column: 3, Byte: 12},
+						End:      hcl.Pos{Line: 2, Column: 17, Byte: 26},
+					},
+				},
+			},
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("%2d-%s", i, tc.name), func(t *testing.T) {
+			f, _ := hclsyntax.ParseConfig([]byte(cfg), "test.tf", hcl.InitialPos)
+			d := testPathDecoder(t, &PathContext{
+				Schema: bodySchema,
+				Files: map[string]*hcl.File{
+					"test.tf": f,
+				},
+				Validators: testValidators,
+			})
+			d.decoderCtx.LookupEnv = tc.lookupEnv
+
+			diags, err := d.ValidateFile(context.Background(), "test.tf")
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			sortDiagnostics(diags)
+
+			if diff := cmp.Diff(tc.expectedDiagnostics, diags); diff != "" {
+				t.Fatalf("unexpected diagnostics: %s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,283 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package decoder
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/hashicorp/hcl-lang/decoder/internal/ast"
+	"github.com/hashicorp/hcl-lang/decoder/internal/schemahelper"
+	"github.com/hashicorp/hcl-lang/lang"
+	"github.com/hashicorp/hcl-lang/schema"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// WorkspaceSymbol represents a block or an attribute
+// found via WorkspaceSymbols
+type WorkspaceSymbol struct {
+	// Name represents the name of the symbol, as returned
+	// from Symbol.Name(), e.g. resource "aws_instance" "foo"
+	Name string
+
+	Kind lang.SymbolKind
+
+	// ContainerName represents names of any parent blocks
+	// separated by dot, e.g. resource "aws_instance" "foo".ebs_block_device
+	ContainerName string
+
+	// Detail represents the description of the block
+	// or attribute from the schema
+	Detail string
+
+	// Address represents address of the block
+	// as resolved via BlockAddrSchema (if any)
+	Address lang.Address
+
+	Path  lang.Path
+	Range hcl.Range
+
+	// score represents the result of fuzzy matching against the query
+	score int
+}
+
+// WorkspaceSymbols returns blocks and attributes in all paths
+// which fuzzy-match the query, ordered by relevance.
+//
+// Query is matched against both the symbol name and address of any blocks.
+// Query can be empty, as per LSP's workspace/symbol request,
+// in which case all symbols are returned.
+//
+// Up to limit symbols are returned, or all symbols if limit is 0.
+func (d *Decoder) WorkspaceSymbols(ctx context.Context, query string, limit uint) ([]WorkspaceSymbol, error) {
+	symbols := make([]WorkspaceSymbol, 0)
+
+	for _, path := range d.pathReader.Paths(ctx) {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		pathDecoder, err := d.Path(path)
+		if err != nil {
+			continue
+		}
+
+		for _, filename := range pathDecoder.filenames() {
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+
+			f, err := pathDecoder.fileByName(filename)
+			if err != nil {
+				continue
+			}
+
+			fSymbols := pathDecoder.workspaceSymbolsForBody(f.Body, f.Bytes, pathDecoder.pathCtx.Schema, "")
+			for _, symbol := range fSymbols {
+				score, ok := fuzzyMatchScore(query, symbol.Name)
+				if len(symbol.Address) > 0 {
+					addrScore, addrOk := fuzzyMatchScore(query, symbol.Address.String())
+					if addrOk && (!ok || addrScore > score) {
+						score, ok = addrScore, true
+					}
+				}
+				if !ok {
+					continue
+				}
+				symbol.score = score
+				symbols = append(symbols, symbol)
+			}
+		}
+	}
+
+	sort.SliceStable(symbols, func(i, j int) bool {
+		if symbols[i].score != symbols[j].score {
+			return symbols[i].score > symbols[j].score
+		}
+		if symbols[i].Path.Path != symbols[j].Path.Path {
+			return symbols[i].Path.Path < symbols[j].Path.Path
+		}
+		if symbols[i].Range.Filename != symbols[j].Range.Filename {
+			return symbols[i].Range.Filename < symbols[j].Range.Filename
+		}
+		return symbols[i].Range.Start.Byte < symbols[j].Range.Start.Byte
+	})
+
+	if limit > 0 && uint(len(symbols)) > limit {
+		symbols = symbols[:limit]
+	}
+
+	return symbols, nil
+}
+
+func (d *PathDecoder) workspaceSymbolsForBody(body hcl.Body, src []byte, bodySchema *schema.BodySchema, containerName string) []WorkspaceSymbol {
+	symbols := make([]WorkspaceSymbol, 0)
+	if body == nil {
+		return symbols
+	}
+
+	content := ast.DecodeBody(body, bodySchema)
+
+	for name, attr := range content.Attributes {
+		symbol := WorkspaceSymbol{
+			Name:          name,
+			Kind:          attributeSymbolKind(symbolExprKind(attr.Expr, src)),
+			ContainerName: containerName,
+			Path:          d.path,
+			Range:         attr.Range,
+		}
+		if bodySchema != nil {
+			if aSchema, ok := bodySchema.Attributes[name]; ok {
+				symbol.Detail = aSchema.Description.Value
+			}
+		}
+		symbols = append(symbols, symbol)
+	}
+
+	for _, block := range content.Blocks {
+		blockSymbol := &BlockSymbol{
+			Type:   block.Type,
+			Labels: block.Labels,
+		}
+		symbol := WorkspaceSymbol{
+			Name:          blockSymbol.Name(),
+			Kind:          blockSymbolKind(nil, block.Labels),
+			ContainerName: containerName,
+			Path:          d.path,
+			Range:         block.Range,
+		}
+
+		var nestedSchema *schema.BodySchema
+		if bSchema, ok := blockSchemaForSymbol(block, bodySchema); ok {
+			symbol.Kind = blockSymbolKind(bSchema, block.Labels)
+			symbol.Detail = bSchema.Description.Value
+
+			depSchema, _, result := d.dependentBodySchema(block.Block, bSchema)
+			if result == schemahelper.LookupSuccessful && depSchema.Description.Value != "" {
+				symbol.Detail = depSchema.Description.Value
+			}
+
+			if addr, ok := resolveBlockAddress(block.Block, bSchema); ok {
+				symbol.Address = addr
+			}
+
+			nestedSchema, _ = d.mergeBlockBodySchemas(block.Block, bSchema)
+		}
+		symbols = append(symbols, symbol)
+
+		nestedContainerName := symbol.Name
+		if containerName != "" {
+			nestedContainerName = containerName + "." + symbol.Name
+		}
+		symbols = append(symbols, d.workspaceSymbolsForBody(block.Body, src, nestedSchema, nestedContainerName)...)
+	}
+
+	sort.SliceStable(symbols, func(i, j int) bool {
+		return symbols[i].Range.Start.Byte < symbols[j].Range.Start.Byte
+	})
+
+	return symbols
+}
+
+func blockSchemaForSymbol(block *ast.BlockContent, bodySchema *schema.BodySchema) (*schema.BlockSchema, bool) {
+	if bodySchema == nil {
+		return nil, false
+	}
+	bSchema, ok := bodySchema.Blocks[block.Type]
+	return bSchema, ok
+}
+
+// blockSymbolKind returns the kind declared in the schema or one
+// derived from the schema and labels
+func blockSymbolKind(bSchema *schema.BlockSchema, labels []string) lang.SymbolKind {
+	if bSchema != nil && bSchema.SymbolKind != lang.NilSymbolKind {
+		return bSchema.SymbolKind
+	}
+
+	if len(labels) > 0 || (bSchema != nil && len(bSchema.Labels) > 0) {
+		return lang.ClassSymbolKind
+	}
+	return lang.StructSymbolKind
+}
+
+func attributeSymbolKind(exprKind lang.SymbolExprKind) lang.SymbolKind {
+	switch kind := exprKind.(type) {
+	case lang.LiteralTypeKind:
+		switch kind.Type {
+		case cty.String:
+			return lang.StringSymbolKind
+		case cty.Number:
+			return lang.NumberSymbolKind
+		case cty.Bool:
+			return lang.BooleanSymbolKind
+		}
+	case lang.TupleConsExprKind:
+		return lang.ArraySymbolKind
+	case lang.ObjectConsExprKind:
+		return lang.ObjectSymbolKind
+	case lang.ReferenceExprKind:
+		return lang.VariableSymbolKind
+	}
+	return lang.PropertySymbolKind
+}
+
+// fuzzyMatchScore reports whether all characters of the query
+// appear in the candidate in the same order (case-insensitive)
+// and returns score of the match, which is higher for matches
+// which are consecutive, at word boundaries or at the beginning.
+func fuzzyMatchScore(query, candidate string) (int, bool) {
+	if query == "" {
+		return 0, true
+	}
+
+	query = strings.ToLower(query)
+	lowerCandidate := strings.ToLower(candidate)
+
+	score := 0
+	prevMatchIdx := -1
+	prevRune := rune(0)
+	queryIdx := 0
+
+	for candIdx, r := range lowerCandidate {
+		if queryIdx >= len(query) {
+			break
+		}
+		qr, size := utf8.DecodeRuneInString(query[queryIdx:])
+		if r == qr {
+			score += 1
+			if candIdx == 0 {
+				score += 8
+			} else if isSymbolWordBoundary(prevRune) {
+				score += 4
+			}
+			if prevMatchIdx >= 0 && prevMatchIdx+utf8.RuneLen(prevRune) == candIdx {
+				score += 3
+			} else if prevMatchIdx >= 0 {
+				// penalize gaps between matched characters
+				score -= 1
+			}
+			prevMatchIdx = candIdx
+			queryIdx += size
+		}
+		prevRune = r
+	}
+
+	if queryIdx < len(query) {
+		return 0, false
+	}
+
+	if lowerCandidate == query {
+		score += 16
+	}
+
+	return score, true
+}
+
+func isSymbolWordBoundary(r rune) bool {
+	return r == '.' || r == '_' || r == '-' || r == '"' || unicode.IsSpace(r)
+}
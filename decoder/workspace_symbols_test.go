@@ -0,0 +1,224 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package decoder
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/hashicorp/hcl-lang/lang"
+	"github.com/hashicorp/hcl-lang/schema"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/zclconf/go-cty/cty"
+)
+
+func TestDecoder_WorkspaceSymbols(t *testing.T) {
+	bodySchema := &schema.BodySchema{
+		Blocks: map[string]*schema.BlockSchema{
+			"resource": {
+				Labels: []*schema.LabelSchema{
+					{Name: "type"},
+					{Name: "name"},
+				},
+				Address: &schema.BlockAddrSchema{
+					Steps: []schema.AddrStep{
+						schema.LabelStep{Index: 0},
+						schema.LabelStep{Index: 1},
+					},
+				},
+				Description: lang.PlainText("A resource"),
+				Body: &schema.BodySchema{
+					Attributes: map[string]*schema.AttributeSchema{
+						"count": {
+							IsOptional:  true,
+							Constraint:  schema.LiteralType{Type: cty.Number},
+							Description: lang.PlainText("Number of instances"),
+						},
+					},
+					Blocks: map[string]*schema.BlockSchema{
+						"setting": {
+							Body: &schema.BodySchema{},
+						},
+					},
+				},
+			},
+			"variable": {
+				Labels: []*schema.LabelSchema{
+					{Name: "name"},
+				},
+				SymbolKind: lang.VariableSymbolKind,
+				Address: &schema.BlockAddrSchema{
+					Steps: []schema.AddrStep{
+						schema.StaticStep{Name: "var"},
+						schema.LabelStep{Index: 0},
+					},
+				},
+				Body: &schema.BodySchema{},
+			},
+		},
+	}
+	cfg := `resource "aws_instance" "web" {
+  count = 1
+  setting {
+  }
+}
+
+variable "instance_name" {
+}
+`
+	f, _ := hclsyntax.ParseConfig([]byte(cfg), "main.tf", hcl.InitialPos)
+	dirPath := t.TempDir()
+	path := lang.Path{Path: dirPath}
+	d := NewDecoder(&testPathReader{
+		paths: map[string]*PathContext{
+			dirPath: {
+				Schema: bodySchema,
+				Files: map[string]*hcl.File{
+					"main.tf": f,
+				},
+			},
+		},
+	})
+
+	resourceSymbol := WorkspaceSymbol{
+		Name:   `resource "aws_instance" "web"`,
+		Kind:   lang.ClassSymbolKind,
+		Detail: "A resource",
+		Address: lang.Address{
+			lang.RootStep{Name: "aws_instance"},
+			lang.AttrStep{Name: "web"},
+		},
+		Path: path,
+		Range: hcl.Range{
+			Filename: "main.tf",
+			Start:    hcl.Pos{Line: 1, Column: 1, Byte: 0},
+			End:      hcl.Pos{Line: 5, Column: 2, Byte: 61},
+		},
+	}
+	countSymbol := WorkspaceSymbol{
+		Name:          "count",
+		Kind:          lang.NumberSymbolKind,
+		ContainerName: `resource "aws_instance" "web"`,
+		Detail:        "Number of instances",
+		Path:          path,
+		Range: hcl.Range{
+			Filename: "main.tf",
+			Start:    hcl.Pos{Line: 2, Column: 3, Byte: 34},
+			End:      hcl.Pos{Line: 2, Column: 12, Byte: 43},
+		},
+	}
+	settingSymbol := WorkspaceSymbol{
+		Name:          "setting",
+		Kind:          lang.StructSymbolKind,
+		ContainerName: `resource "aws_instance" "web"`,
+		Path:          path,
+		Range: hcl.Range{
+			Filename: "main.tf",
+			Start:    hcl.Pos{Line: 3, Column: 3, Byte: 46},
+			End:      hcl.Pos{Line: 4, Column: 4, Byte: 59},
+		},
+	}
+	variableSymbol := WorkspaceSymbol{
+		Name: `variable "instance_name"`,
+		Kind: lang.VariableSymbolKind,
+		Address: lang.Address{
+			lang.RootStep{Name: "var"},
+			lang.AttrStep{Name: "instance_name"},
+		},
+		Path: path,
+		Range: hcl.Range{
+			Filename: "main.tf",
+			Start:    hcl.Pos{Line: 7, Column: 1, Byte: 63},
+			End:      hcl.Pos{Line: 8, Column: 2, Byte: 91},
+		},
+	}
+
+	testCases := []struct {
+		query           string
+		limit           uint
+		expectedSymbols []WorkspaceSymbol
+	}{
+		{
+			"",
+			0,
+			[]WorkspaceSymbol{
+				resourceSymbol,
+				countSymbol,
+				settingSymbol,
+				variableSymbol,
+			},
+		},
+		{
+			"",
+			2,
+			[]WorkspaceSymbol{
+				resourceSymbol,
+				countSymbol,
+			},
+		},
+		{
+			"var.inst",
+			0,
+			[]WorkspaceSymbol{
+				variableSymbol,
+			},
+		},
+		{
+			"inst",
+			0,
+			[]WorkspaceSymbol{
+				resourceSymbol,
+				variableSymbol,
+			},
+		},
+		{
+			"stng",
+			0,
+			[]WorkspaceSymbol{
+				settingSymbol,
+			},
+		},
+		{
+			"xyz",
+			0,
+			[]WorkspaceSymbol{},
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("%d-%s", i, tc.query), func(t *testing.T) {
+			symbols, err := d.WorkspaceSymbols(context.Background(), tc.query, tc.limit)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if diff := cmp.Diff(tc.expectedSymbols, symbols, cmpopts.IgnoreUnexported(WorkspaceSymbol{})); diff != "" {
+				t.Fatalf("unexpected symbols: %s", diff)
+			}
+		})
+	}
+}
+
+func TestDecoder_WorkspaceSymbols_cancelled(t *testing.T) {
+	d := NewDecoder(&testPathReader{
+		paths: map[string]*PathContext{
+			t.TempDir(): {
+				Files: map[string]*hcl.File{},
+			},
+		},
+	})
+
+	ctx, cancelFunc := context.WithCancel(context.Background())
+	cancelFunc()
+
+	_, err := d.WorkspaceSymbols(ctx, "", 0)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled error, given: %#v", err)
+	}
+}
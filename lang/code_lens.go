@@ -14,6 +14,18 @@ type CodeLensFunc func(ctx context.Context, path Path, file string) ([]CodeLens,
 type CodeLens struct {
 	Range   hcl.Range
 	Command Command
+
+	// ResolveHook allows lazy resolution of the Command
+	// via Decoder.ResolveCodeLens, e.g. as part of codeLens/resolve
+	// LSP request. Command is typically empty if the hook is present.
+	ResolveHook *CodeLensResolveHook
+}
+
+// CodeLensResolveHook represents a hook to call to resolve
+// Command of a CodeLens and Path the lens belongs to
+type CodeLensResolveHook struct {
+	Name string `json:"resolve_hook"`
+	Path Path   `json:"path"`
 }
 
 type Command struct {
@@ -0,0 +1,29 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package lang
+
+import (
+	"github.com/hashicorp/hcl/v2"
+)
+
+const (
+	NilFoldingRangeKind FoldingRangeKind = iota
+	RegionFoldingRangeKind
+	CommentFoldingRangeKind
+)
+
+//go:generate go run golang.org/x/tools/cmd/stringer -type=FoldingRangeKind -output=folding_range_kind_string.go
+type FoldingRangeKind uint
+
+// FoldingRange represents a range which can be folded (collapsed)
+// in the editor, such as a block or a run of comments
+type FoldingRange struct {
+	Range hcl.Range
+	Kind  FoldingRangeKind
+
+	// CollapsedText represents text to display in place
+	// of the folded range. It is empty if the client
+	// is expected to choose a default.
+	CollapsedText string
+}
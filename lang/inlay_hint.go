@@ -0,0 +1,42 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package lang
+
+import (
+	"github.com/hashicorp/hcl/v2"
+)
+
+const (
+	// NilInlayHintKind represents hints which are neither types
+	// nor parameters, such as default values
+	NilInlayHintKind InlayHintKind = iota
+	TypeInlayHintKind
+	ParameterInlayHintKind
+)
+
+//go:generate go run golang.org/x/tools/cmd/stringer -type=InlayHintKind -output=inlay_hint_kind_string.go
+type InlayHintKind uint
+
+// InlayHint represents additional information rendered
+// inline at the given position, such as the name of
+// a function parameter in front of an argument
+type InlayHint struct {
+	Pos     hcl.Pos
+	Label   []InlayHintLabelPart
+	Kind    InlayHintKind
+	Tooltip MarkupContent
+
+	// PaddingLeft and PaddingRight indicate whether
+	// the hint should be rendered with padding
+	// before or after it
+	PaddingLeft  bool
+	PaddingRight bool
+}
+
+// InlayHintLabelPart represents a part of the hint label
+// which may have its own tooltip
+type InlayHintLabelPart struct {
+	Value   string
+	Tooltip MarkupContent
+}
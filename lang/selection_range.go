@@ -0,0 +1,16 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package lang
+
+import (
+	"github.com/hashicorp/hcl/v2"
+)
+
+// SelectionRange represents a range which can be selected
+// when expanding the selection, along with the parent range
+// to select when expanding the selection further.
+type SelectionRange struct {
+	Range  hcl.Range
+	Parent *SelectionRange
+}
@@ -34,6 +34,15 @@ const (
 	TokenTypeComplex   SemanticTokenType = "hcl-typeComplex"
 	TokenTypePrimitive SemanticTokenType = "hcl-typePrimitive"
 	TokenFunctionName  SemanticTokenType = "hcl-functionName"
+
+	// syntax tokens, which are opt-in via OptionalSemanticTokenTypes
+	TokenOperator              SemanticTokenType = "hcl-operator"
+	TokenComment               SemanticTokenType = "hcl-comment"
+	TokenHeredocAnchor         SemanticTokenType = "hcl-heredocAnchor"
+	TokenTemplateInterpolation SemanticTokenType = "hcl-templateInterpolation"
+	TokenTemplateDirective     SemanticTokenType = "hcl-templateDirective"
+	TokenIteratorVariable      SemanticTokenType = "hcl-iteratorVariable"
+	TokenConditionalOperator   SemanticTokenType = "hcl-conditionalOperator"
 )
 
 var SupportedSemanticTokenTypes = SemanticTokenTypes{
@@ -52,6 +61,29 @@ var SupportedSemanticTokenTypes = SemanticTokenTypes{
 	TokenFunctionName,
 }
 
+// OptionalSemanticTokenTypes represents token types which are only
+// reported when enabled individually, so that clients unaware
+// of them are not affected.
+var OptionalSemanticTokenTypes = SemanticTokenTypes{
+	TokenOperator,
+	TokenComment,
+	TokenHeredocAnchor,
+	TokenTemplateInterpolation,
+	TokenTemplateDirective,
+	TokenIteratorVariable,
+	TokenConditionalOperator,
+}
+
+// Contains reports whether the given type is among the types
+func (stt SemanticTokenTypes) Contains(tokenType SemanticTokenType) bool {
+	for _, t := range stt {
+		if t == tokenType {
+			return true
+		}
+	}
+	return false
+}
+
 type SemanticTokenModifier string
 
 type SemanticTokenModifiers []SemanticTokenModifier
@@ -66,6 +98,36 @@ func (stm SemanticTokenModifiers) Copy() SemanticTokenModifiers {
 	return modifiersCopy
 }
 
+// Contains reports whether the given modifier is among the modifiers
+func (stm SemanticTokenModifiers) Contains(modifier SemanticTokenModifier) bool {
+	for _, m := range stm {
+		if m == modifier {
+			return true
+		}
+	}
+	return false
+}
+
 const (
 	TokenModifierDependent = SemanticTokenModifier("hcl-dependent")
+
+	// built-in modifiers, which are opt-in via OptionalSemanticTokenModifiers
+	TokenModifierDeprecated  = SemanticTokenModifier("hcl-deprecated")
+	TokenModifierReadonly    = SemanticTokenModifier("hcl-readonly")
+	TokenModifierSensitive   = SemanticTokenModifier("hcl-sensitive")
+	TokenModifierDeclaration = SemanticTokenModifier("hcl-declaration")
+	TokenModifierReference   = SemanticTokenModifier("hcl-reference")
+	TokenModifierUnresolved  = SemanticTokenModifier("hcl-unresolved")
 )
+
+// OptionalSemanticTokenModifiers represents modifiers derived from
+// the schema and references, which are only reported when enabled
+// individually, so that clients unaware of them are not affected.
+var OptionalSemanticTokenModifiers = SemanticTokenModifiers{
+	TokenModifierDeprecated,
+	TokenModifierReadonly,
+	TokenModifierSensitive,
+	TokenModifierDeclaration,
+	TokenModifierReference,
+	TokenModifierUnresolved,
+}
@@ -38,3 +38,34 @@ type ReferenceExprKind struct{}
 func (ReferenceExprKind) isSymbolExprKindSigil() exprKindSigil {
 	return exprKindSigil{}
 }
+
+const (
+	NilSymbolKind SymbolKind = iota
+
+	// structural kinds
+	ModuleSymbolKind
+	NamespaceSymbolKind
+	ClassSymbolKind
+	StructSymbolKind
+	InterfaceSymbolKind
+	FunctionSymbolKind
+	EventSymbolKind
+	PropertySymbolKind
+	FieldSymbolKind
+	KeySymbolKind
+
+	// expressions
+	VariableSymbolKind
+	ConstantSymbolKind
+	StringSymbolKind
+	NumberSymbolKind
+	BooleanSymbolKind
+	ArraySymbolKind
+	ObjectSymbolKind
+)
+
+// SymbolKind represents the kind of a symbol as presented to the user,
+// e.g. in the workspace symbol search
+//
+//go:generate go run golang.org/x/tools/cmd/stringer -type=SymbolKind -output=symbol_kind_string.go
+type SymbolKind uint
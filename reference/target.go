@@ -51,6 +51,12 @@ type Target struct {
 	// the editor near the middle of this range.
 	DefRangePtr *hcl.Range
 
+	// TypeDefRangePtr represents a range of the declaration which defines
+	// the Type of the target, such as the name of a type attribute
+	// (e.g. type = object({...})) or nil if the type is not declared
+	// explicitly in the configuration.
+	TypeDefRangePtr *hcl.Range
+
 	Type        cty.Type
 	Name        string
 	Description lang.MarkupContent
@@ -86,6 +92,7 @@ func (ref Target) Copy() Target {
 		ScopeId:                ref.ScopeId,
 		RangePtr:               copyHclRangePtr(ref.RangePtr),
 		DefRangePtr:            copyHclRangePtr(ref.DefRangePtr),
+		TypeDefRangePtr:        copyHclRangePtr(ref.TypeDefRangePtr),
 		Type:                   ref.Type, // cty.Type is immutable by design
 		Name:                   ref.Name,
 		Description:            ref.Description,
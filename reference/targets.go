@@ -188,6 +188,49 @@ func (refs Targets) Match(origin MatchableOrigin) (Targets, bool) {
 	return matchingReferences, len(matchingReferences) > 0
 }
 
+// MatchTypeDefinitions returns targets with declared type (TypeDefRangePtr)
+// which the origin refers to, either directly or via one of the nested
+// attributes or elements implied by the target's type.
+//
+// e.g. var.foo.bar is matched against var.foo target of
+// object({ bar = string }) type declared via type = ... attribute
+func (refs Targets) MatchTypeDefinitions(origin MatchableOrigin) (Targets, bool) {
+	matchingReferences := make(Targets, 0)
+
+	refs.deepWalk(func(ref Target) error {
+		if ref.TypeDefRangePtr == nil {
+			return nil
+		}
+		if ref.Matches(origin) || ref.matchesAddressPrefix(origin) {
+			matchingReferences = append(matchingReferences, ref)
+		}
+
+		return nil
+	}, InfiniteDepth)
+
+	return matchingReferences, len(matchingReferences) > 0
+}
+
+func (target Target) matchesAddressPrefix(origin MatchableOrigin) bool {
+	originAddr := origin.Address()
+	if len(target.Addr) == 0 || len(target.Addr) >= len(originAddr) {
+		return false
+	}
+	if !originAddr.FirstSteps(uint(len(target.Addr))).Equals(target.Addr) {
+		return false
+	}
+
+	if len(origin.OriginConstraints()) == 0 {
+		return true
+	}
+	for _, cons := range origin.OriginConstraints() {
+		if target.MatchesScopeId(cons.OfScopeId) {
+			return true
+		}
+	}
+	return false
+}
+
 func (refs Targets) OutermostInFile(file string) Targets {
 	targets := make(Targets, 0)
 
@@ -496,6 +496,154 @@ func TestTargets_Match_localRefs(t *testing.T) {
 	}
 }
 
+func TestTargets_MatchTypeDefinitions(t *testing.T) {
+	typeDefRange := &hcl.Range{
+		Filename: "main.tf",
+		Start:    hcl.Pos{Line: 2, Column: 3, Byte: 21},
+		End:      hcl.Pos{Line: 2, Column: 7, Byte: 25},
+	}
+	objectType := cty.Object(map[string]cty.Type{
+		"attr": cty.String,
+	})
+
+	testCases := []struct {
+		name            string
+		targets         Targets
+		origin          MatchableOrigin
+		expectedTargets Targets
+		expectedMatch   bool
+	}{
+		{
+			"no declared type",
+			Targets{
+				{
+					Addr: lang.Address{
+						lang.RootStep{Name: "var"},
+						lang.AttrStep{Name: "test"},
+					},
+					Type: cty.String,
+				},
+			},
+			LocalOrigin{
+				Addr: lang.Address{
+					lang.RootStep{Name: "var"},
+					lang.AttrStep{Name: "test"},
+				},
+				Constraints: OriginConstraints{
+					{OfType: cty.String},
+				},
+			},
+			Targets{},
+			false,
+		},
+		{
+			"exact match",
+			Targets{
+				{
+					Addr: lang.Address{
+						lang.RootStep{Name: "var"},
+						lang.AttrStep{Name: "test"},
+					},
+					Type:            objectType,
+					TypeDefRangePtr: typeDefRange,
+				},
+			},
+			LocalOrigin{
+				Addr: lang.Address{
+					lang.RootStep{Name: "var"},
+					lang.AttrStep{Name: "test"},
+				},
+				Constraints: OriginConstraints{
+					{OfType: cty.DynamicPseudoType},
+				},
+			},
+			Targets{
+				{
+					Addr: lang.Address{
+						lang.RootStep{Name: "var"},
+						lang.AttrStep{Name: "test"},
+					},
+					Type:            objectType,
+					TypeDefRangePtr: typeDefRange,
+				},
+			},
+			true,
+		},
+		{
+			"nested attribute match",
+			Targets{
+				{
+					Addr: lang.Address{
+						lang.RootStep{Name: "var"},
+						lang.AttrStep{Name: "test"},
+					},
+					Type:            objectType,
+					TypeDefRangePtr: typeDefRange,
+				},
+			},
+			LocalOrigin{
+				Addr: lang.Address{
+					lang.RootStep{Name: "var"},
+					lang.AttrStep{Name: "test"},
+					lang.AttrStep{Name: "attr"},
+				},
+				Constraints: OriginConstraints{
+					{OfType: cty.String},
+				},
+			},
+			Targets{
+				{
+					Addr: lang.Address{
+						lang.RootStep{Name: "var"},
+						lang.AttrStep{Name: "test"},
+					},
+					Type:            objectType,
+					TypeDefRangePtr: typeDefRange,
+				},
+			},
+			true,
+		},
+		{
+			"nested attribute of mismatching scope",
+			Targets{
+				{
+					Addr: lang.Address{
+						lang.RootStep{Name: "var"},
+						lang.AttrStep{Name: "test"},
+					},
+					ScopeId:         lang.ScopeId("variable"),
+					Type:            objectType,
+					TypeDefRangePtr: typeDefRange,
+				},
+			},
+			LocalOrigin{
+				Addr: lang.Address{
+					lang.RootStep{Name: "var"},
+					lang.AttrStep{Name: "test"},
+					lang.AttrStep{Name: "attr"},
+				},
+				Constraints: OriginConstraints{
+					{OfScopeId: lang.ScopeId("local")},
+				},
+			},
+			Targets{},
+			false,
+		},
+	}
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("%d-%s", i, tc.name), func(t *testing.T) {
+			refTargets, ok := tc.targets.MatchTypeDefinitions(tc.origin)
+			if ok != tc.expectedMatch {
+				t.Fatalf("expected match: %t, given: %t", tc.expectedMatch, ok)
+			}
+
+			if diff := cmp.Diff(tc.expectedTargets, refTargets, ctydebug.CmpOptions); diff != "" {
+				t.Fatalf("mismatch of reference targets: %s", diff)
+			}
+		})
+	}
+}
+
 func TestTargets_OutermostInFile(t *testing.T) {
 	testCases := []struct {
 		name            string
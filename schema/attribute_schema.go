@@ -26,8 +26,14 @@ type AttributeSchema struct {
 	// DefaultValue represents default value which applies
 	// if the attribute is not declared (e.g. when looking up
 	// attribute-dependent body).
+	//
+	// Defaults depending on other attributes or the environment
+	// are resolved via ResolveDefault.
 	DefaultValue Default
 
+	// Versions describes in which versions the attribute is available
+	Versions VersionInfo
+
 	// IsDepKey describes whether to use this attribute (and its value)
 	// as key when looking up dependent schema
 	IsDepKey bool
@@ -114,6 +120,14 @@ func (as *AttributeSchema) Validate() error {
 		}
 	}
 
+	if err := as.Versions.Validate(); err != nil {
+		return fmt.Errorf("Versions: %w", err)
+	}
+
+	if err := validateDefault(as.DefaultValue); err != nil {
+		return fmt.Errorf("DefaultValue: %w", err)
+	}
+
 	if con, ok := as.Constraint.(Validatable); ok {
 		err := con.Validate()
 		if err != nil {
@@ -137,6 +151,7 @@ func (as *AttributeSchema) Copy() *AttributeSchema {
 		IsSensitive:            as.IsSensitive,
 		IsDepKey:               as.IsDepKey,
 		DefaultValue:           as.DefaultValue,
+		Versions:               as.Versions,
 		Description:            as.Description,
 		Address:                as.Address.Copy(),
 		OriginForTarget:        as.OriginForTarget.Copy(),
@@ -22,6 +22,11 @@ type BlockSchema struct {
 	// (in addition to any modifiers of any parent blocks)
 	SemanticTokenModifiers lang.SemanticTokenModifiers
 
+	// SymbolKind represents the kind of symbol to report for the block
+	// e.g. in workspace symbol search. The kind is derived from the schema
+	// if left empty.
+	SymbolKind lang.SymbolKind
+
 	// Body represents the body within block
 	// such as attributes and nested blocks
 	Body *BodySchema
@@ -30,11 +35,22 @@ type BlockSchema struct {
 	// depending on SchemaKey (labels or attributes)
 	DependentBody map[SchemaKey]*BodySchema
 
+	// DependentBodyResolver (optional) resolves dependent bodies
+	// on demand, when they are not found in DependentBody.
+	//
+	// The resolver is shared (not copied) by Copy() and is not
+	// represented in JSON. Bodies it provides are not enumerated
+	// when generating documentation, JSON schemas or schema diffs.
+	DependentBodyResolver DependentBodyResolver
+
 	Description  lang.MarkupContent
 	IsDeprecated bool
 	MinItems     uint64
 	MaxItems     uint64
 
+	// Versions describes in which versions the block is available
+	Versions VersionInfo
+
 	Address *BlockAddrSchema
 }
 
@@ -183,6 +199,10 @@ func (bSchema *BlockSchema) Validate() error {
 		}
 	}
 
+	if err := bSchema.Versions.Validate(); err != nil {
+		errs = multierror.Append(errs, fmt.Errorf("Versions: %w", err))
+	}
+
 	if errs != nil && len(errs.Errors) == 1 {
 		return errs.Errors[0]
 	}
@@ -198,12 +218,15 @@ func (bs *BlockSchema) Copy() *BlockSchema {
 	newBs := &BlockSchema{
 		Type:                   bs.Type,
 		SemanticTokenModifiers: bs.SemanticTokenModifiers.Copy(),
+		SymbolKind:             bs.SymbolKind,
 		IsDeprecated:           bs.IsDeprecated,
 		MinItems:               bs.MinItems,
 		MaxItems:               bs.MaxItems,
+		Versions:               bs.Versions,
 		Description:            bs.Description,
 		Body:                   bs.Body.Copy(),
 		Address:                bs.Address.Copy(),
+		DependentBodyResolver:  bs.DependentBodyResolver,
 	}
 
 	if bs.Labels != nil {
@@ -51,6 +51,12 @@ type BodySchema struct {
 
 	// Extensions represents any HCL extensions supported in this body
 	Extensions *BodyExtensions
+
+	// AttributeOrder represents the order in which attributes
+	// are expected to be declared, which is used when formatting.
+	// Attributes not listed are kept in their original order
+	// after the listed ones.
+	AttributeOrder []string
 }
 
 type BodyExtensions struct {
@@ -73,6 +79,15 @@ func (be *BodyExtensions) Copy() *BodyExtensions {
 	}
 }
 
+// declaresAttribute returns true if the attribute
+// is declared by any of the extensions
+func (be *BodyExtensions) declaresAttribute(name string) bool {
+	if be == nil {
+		return false
+	}
+	return (be.Count && name == "count") || (be.ForEach && name == "for_each")
+}
+
 type ImpliedOrigins []ImpliedOrigin
 
 type ImpliedOrigin struct {
@@ -166,6 +181,21 @@ func (bs *BodySchema) Validate() error {
 	}
 
 	var result *multierror.Error
+
+	declaredOrder := make(map[string]bool, len(bs.AttributeOrder))
+	for _, name := range bs.AttributeOrder {
+		if declaredOrder[name] {
+			result = multierror.Append(result, fmt.Errorf("AttributeOrder: duplicate attribute %q", name))
+			continue
+		}
+		declaredOrder[name] = true
+		if bs.AnyAttribute == nil && !bs.Extensions.declaresAttribute(name) {
+			if _, ok := bs.Attributes[name]; !ok {
+				result = multierror.Append(result, fmt.Errorf("AttributeOrder: unknown attribute %q", name))
+			}
+		}
+	}
+
 	for name, attr := range bs.Attributes {
 		err := attr.Validate()
 		if err != nil {
@@ -226,6 +256,11 @@ func (bs *BodySchema) Copy() *BodySchema {
 		}
 	}
 
+	if bs.AttributeOrder != nil {
+		newBs.AttributeOrder = make([]string, len(bs.AttributeOrder))
+		copy(newBs.AttributeOrder, bs.AttributeOrder)
+	}
+
 	if bs.ImpliedOrigins != nil {
 		newBs.ImpliedOrigins = make(ImpliedOrigins, len(bs.ImpliedOrigins))
 		for id, impliedOrigin := range bs.ImpliedOrigins {
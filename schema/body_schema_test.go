@@ -0,0 +1,70 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package schema
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/zclconf/go-cty/cty"
+)
+
+func TestBodySchema_Validate_attributeOrder(t *testing.T) {
+	testCases := []struct {
+		schema      *BodySchema
+		expectedErr error
+	}{
+		{
+			&BodySchema{
+				Extensions: &BodyExtensions{Count: true},
+				Attributes: map[string]*AttributeSchema{
+					"foo": {Constraint: LiteralType{Type: cty.String}, IsOptional: true},
+				},
+				AttributeOrder: []string{"count", "foo"},
+			},
+			nil,
+		},
+		{
+			&BodySchema{
+				AnyAttribute:   &AttributeSchema{Constraint: LiteralType{Type: cty.String}, IsOptional: true},
+				AttributeOrder: []string{"anything"},
+			},
+			nil,
+		},
+		{
+			&BodySchema{
+				Attributes: map[string]*AttributeSchema{
+					"foo": {Constraint: LiteralType{Type: cty.String}, IsOptional: true},
+				},
+				AttributeOrder: []string{"bar"},
+			},
+			errors.New("1 error occurred:\n\t* AttributeOrder: unknown attribute \"bar\"\n\n"),
+		},
+		{
+			&BodySchema{
+				Attributes: map[string]*AttributeSchema{
+					"foo": {Constraint: LiteralType{Type: cty.String}, IsOptional: true},
+				},
+				AttributeOrder: []string{"foo", "foo"},
+			},
+			errors.New("1 error occurred:\n\t* AttributeOrder: duplicate attribute \"foo\"\n\n"),
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("%d", i), func(t *testing.T) {
+			err := tc.schema.Validate()
+			if tc.expectedErr == nil && err != nil {
+				t.Fatal(err)
+			}
+			if tc.expectedErr != nil && err == nil {
+				t.Fatalf("expected error: %q, none given", tc.expectedErr.Error())
+			}
+			if tc.expectedErr != nil && tc.expectedErr.Error() != err.Error() {
+				t.Fatalf("error mismatch,\nexpected: %q\ngiven: %q", tc.expectedErr.Error(), err.Error())
+			}
+		})
+	}
+}
@@ -24,6 +24,9 @@ type LiteralValue struct {
 
 	// Description defines description of the value
 	Description lang.MarkupContent
+
+	// Versions describes in which versions the value is supported
+	Versions VersionInfo
 }
 
 func (LiteralValue) isConstraintImpl() constraintSigil {
@@ -39,6 +42,7 @@ func (lv LiteralValue) Copy() Constraint {
 		Value:        lv.Value,
 		IsDeprecated: lv.IsDeprecated,
 		Description:  lv.Description,
+		Versions:     lv.Versions,
 	}
 }
 
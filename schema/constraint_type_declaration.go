@@ -9,7 +9,10 @@ import "context"
 // interpreted by HCL's ext/typeexpr package,
 // i.e. declaration of cty.Type in HCL
 type TypeDeclaration struct {
-	// TODO: optional object attribute mode
+	// OptionalAttrs enables the optional(type) and optional(type, default)
+	// modifiers for object attributes, as interpreted by
+	// typeexpr.TypeConstraintWithDefaults
+	OptionalAttrs bool
 }
 
 func (TypeDeclaration) isConstraintImpl() constraintSigil {
@@ -21,7 +24,9 @@ func (td TypeDeclaration) FriendlyName() string {
 }
 
 func (td TypeDeclaration) Copy() Constraint {
-	return TypeDeclaration{}
+	return TypeDeclaration{
+		OptionalAttrs: td.OptionalAttrs,
+	}
 }
 
 func (td TypeDeclaration) EmptyCompletionData(ctx context.Context, nextPlaceholder int, nestingLevel int) CompletionData {
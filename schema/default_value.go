@@ -3,7 +3,13 @@
 
 package schema
 
-import "github.com/zclconf/go-cty/cty"
+import (
+	"errors"
+	"fmt"
+
+	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/convert"
+)
 
 type defaultSigil struct{}
 
@@ -11,6 +17,8 @@ type Default interface {
 	isDefaultImpl() defaultSigil
 }
 
+// DefaultValue represents a static value which applies
+// if the attribute is not declared
 type DefaultValue struct {
 	Value cty.Value
 }
@@ -19,7 +27,166 @@ func (dv DefaultValue) isDefaultImpl() defaultSigil {
 	return defaultSigil{}
 }
 
-// TODO: DefaultKeyword
-// TODO: DefaultTypeDeclaration
-// TODO: defaults dependent on other attributes
-// TODO: defaults dependent on env variables
+// DefaultKeyword represents a keyword which applies
+// if the attribute is not declared
+type DefaultKeyword struct {
+	Keyword string
+}
+
+func (dk DefaultKeyword) isDefaultImpl() defaultSigil {
+	return defaultSigil{}
+}
+
+// DefaultTypeDeclaration represents a type which applies
+// if the attribute is not declared, e.g. type = any
+// in Terraform's variable block
+type DefaultTypeDeclaration struct {
+	Type cty.Type
+}
+
+func (dtd DefaultTypeDeclaration) isDefaultImpl() defaultSigil {
+	return defaultSigil{}
+}
+
+// DefaultAttributeDependent represents a default which depends
+// on the value of another attribute declared in the same body
+type DefaultAttributeDependent struct {
+	// Name represents the name of the attribute in the same body
+	Name string
+
+	// Cases represents defaults for particular values
+	// of the attribute, where the first matching case applies
+	Cases []DefaultCase
+
+	// Fallback represents the default which applies if the attribute
+	// is not declared, its value cannot be determined statically,
+	// or it does not match any of the cases
+	Fallback Default
+}
+
+func (dad DefaultAttributeDependent) isDefaultImpl() defaultSigil {
+	return defaultSigil{}
+}
+
+// DefaultCase represents a default which applies
+// when the attribute has the given value
+type DefaultCase struct {
+	Value   cty.Value
+	Default Default
+}
+
+// DefaultEnvVar represents a default which is read from
+// an environment variable. The value is converted to the type
+// of the attribute's constraint where possible.
+//
+// The variable is looked up via DefaultContext.LookupEnv,
+// which is typically provided by the host (language server).
+type DefaultEnvVar struct {
+	Name string
+
+	// Fallback represents the default which applies
+	// if the environment variable is not set
+	Fallback Default
+}
+
+func (dev DefaultEnvVar) isDefaultImpl() defaultSigil {
+	return defaultSigil{}
+}
+
+// LookupEnvFunc represents a function which looks up
+// the value of the given environment variable, e.g. os.LookupEnv
+type LookupEnvFunc func(name string) (string, bool)
+
+// DefaultContext provides data needed to resolve defaults
+// depending on other attributes or the environment
+type DefaultContext struct {
+	// AttributeValue returns the static value of the given attribute
+	// declared in the same body, if the value can be determined
+	AttributeValue func(name string) (cty.Value, bool)
+
+	// LookupEnv looks up environment variables. DefaultEnvVar
+	// falls back to its Fallback if LookupEnv is nil.
+	LookupEnv LookupEnvFunc
+}
+
+// ResolveDefault returns the default which applies to the attribute
+// in the given context, i.e. one of DefaultValue, DefaultKeyword
+// or DefaultTypeDeclaration, or nil if no default applies.
+func (as *AttributeSchema) ResolveDefault(ctx DefaultContext) Default {
+	return resolveDefault(as.DefaultValue, as.Constraint, ctx)
+}
+
+func resolveDefault(def Default, con Constraint, ctx DefaultContext) Default {
+	switch d := def.(type) {
+	case DefaultValue, DefaultKeyword, DefaultTypeDeclaration:
+		return d
+	case DefaultAttributeDependent:
+		if ctx.AttributeValue == nil {
+			return resolveDefault(d.Fallback, con, ctx)
+		}
+		value, ok := ctx.AttributeValue(d.Name)
+		if !ok || !value.IsWhollyKnown() {
+			return resolveDefault(d.Fallback, con, ctx)
+		}
+		for _, dc := range d.Cases {
+			if dc.Value.RawEquals(value) {
+				return resolveDefault(dc.Default, con, ctx)
+			}
+		}
+		return resolveDefault(d.Fallback, con, ctx)
+	case DefaultEnvVar:
+		if ctx.LookupEnv == nil {
+			return resolveDefault(d.Fallback, con, ctx)
+		}
+		rawValue, ok := ctx.LookupEnv(d.Name)
+		if !ok {
+			return resolveDefault(d.Fallback, con, ctx)
+		}
+		value := cty.StringVal(rawValue)
+		if tc, ok := con.(TypeAwareConstraint); ok {
+			typ, ok := tc.ConstraintType()
+			if ok && typ != cty.DynamicPseudoType {
+				convertedValue, err := convert.Convert(value, typ)
+				if err != nil {
+					return resolveDefault(d.Fallback, con, ctx)
+				}
+				value = convertedValue
+			}
+		}
+		return DefaultValue{Value: value}
+	}
+	return nil
+}
+
+func validateDefault(def Default) error {
+	switch d := def.(type) {
+	case DefaultKeyword:
+		if d.Keyword == "" {
+			return errors.New("DefaultKeyword: Keyword must not be empty")
+		}
+	case DefaultTypeDeclaration:
+		if d.Type == cty.NilType {
+			return errors.New("DefaultTypeDeclaration: Type must be set")
+		}
+	case DefaultAttributeDependent:
+		if d.Name == "" {
+			return errors.New("DefaultAttributeDependent: Name must not be empty")
+		}
+		for i, dc := range d.Cases {
+			if err := validateDefault(dc.Default); err != nil {
+				return fmt.Errorf("DefaultAttributeDependent: Cases[%d]: %w", i, err)
+			}
+		}
+		if err := validateDefault(d.Fallback); err != nil {
+			return fmt.Errorf("DefaultAttributeDependent: Fallback: %w", err)
+		}
+	case DefaultEnvVar:
+		if d.Name == "" {
+			return errors.New("DefaultEnvVar: Name must not be empty")
+		}
+		if err := validateDefault(d.Fallback); err != nil {
+			return fmt.Errorf("DefaultEnvVar: Fallback: %w", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,177 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package schema
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/zclconf/go-cty-debug/ctydebug"
+	"github.com/zclconf/go-cty/cty"
+)
+
+func TestAttributeSchema_ResolveDefault(t *testing.T) {
+	env := map[string]string{
+		"PORT":    "8080",
+		"INVALID": "not-a-number",
+	}
+	lookupEnv := func(name string) (string, bool) {
+		v, ok := env[name]
+		return v, ok
+	}
+	attributeValue := func(name string) (cty.Value, bool) {
+		if name == "engine" {
+			return cty.StringVal("postgres"), true
+		}
+		return cty.NilVal, false
+	}
+	engineDefault := DefaultAttributeDependent{
+		Name: "engine",
+		Cases: []DefaultCase{
+			{
+				Value:   cty.StringVal("mysql"),
+				Default: DefaultValue{Value: cty.NumberIntVal(3306)},
+			},
+			{
+				Value:   cty.StringVal("postgres"),
+				Default: DefaultValue{Value: cty.NumberIntVal(5432)},
+			},
+		},
+		Fallback: DefaultValue{Value: cty.NumberIntVal(0)},
+	}
+
+	testCases := []struct {
+		name            string
+		defaultValue    Default
+		ctx             DefaultContext
+		expectedDefault Default
+	}{
+		{
+			"no default",
+			nil,
+			DefaultContext{},
+			nil,
+		},
+		{
+			"static value",
+			DefaultValue{Value: cty.NumberIntVal(42)},
+			DefaultContext{},
+			DefaultValue{Value: cty.NumberIntVal(42)},
+		},
+		{
+			"keyword",
+			DefaultKeyword{Keyword: "auto"},
+			DefaultContext{},
+			DefaultKeyword{Keyword: "auto"},
+		},
+		{
+			"env var converted to type",
+			DefaultEnvVar{Name: "PORT"},
+			DefaultContext{LookupEnv: lookupEnv},
+			DefaultValue{Value: cty.NumberIntVal(8080)},
+		},
+		{
+			"env var without lookup",
+			DefaultEnvVar{Name: "PORT", Fallback: DefaultValue{Value: cty.NumberIntVal(80)}},
+			DefaultContext{},
+			DefaultValue{Value: cty.NumberIntVal(80)},
+		},
+		{
+			"env var not set",
+			DefaultEnvVar{Name: "UNSET"},
+			DefaultContext{LookupEnv: lookupEnv},
+			nil,
+		},
+		{
+			"env var of invalid type",
+			DefaultEnvVar{Name: "INVALID", Fallback: DefaultValue{Value: cty.NumberIntVal(80)}},
+			DefaultContext{LookupEnv: lookupEnv},
+			DefaultValue{Value: cty.NumberIntVal(80)},
+		},
+		{
+			"attribute dependent",
+			engineDefault,
+			DefaultContext{AttributeValue: attributeValue},
+			DefaultValue{Value: cty.NumberIntVal(5432)},
+		},
+		{
+			"attribute dependent without attribute",
+			engineDefault,
+			DefaultContext{},
+			DefaultValue{Value: cty.NumberIntVal(0)},
+		},
+		{
+			"env var falling back to attribute dependent",
+			DefaultEnvVar{Name: "UNSET", Fallback: engineDefault},
+			DefaultContext{AttributeValue: attributeValue, LookupEnv: lookupEnv},
+			DefaultValue{Value: cty.NumberIntVal(5432)},
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("%2d-%s", i, tc.name), func(t *testing.T) {
+			aSchema := &AttributeSchema{
+				IsOptional:   true,
+				Constraint:   LiteralType{Type: cty.Number},
+				DefaultValue: tc.defaultValue,
+			}
+			def := aSchema.ResolveDefault(tc.ctx)
+			if diff := cmp.Diff(tc.expectedDefault, def, ctydebug.CmpOptions); diff != "" {
+				t.Fatalf("unexpected default: %s", diff)
+			}
+		})
+	}
+}
+
+func TestAttributeSchema_Validate_defaults(t *testing.T) {
+	testCases := []struct {
+		defaultValue  Default
+		expectedError error
+	}{
+		{
+			DefaultKeyword{Keyword: "auto"},
+			nil,
+		},
+		{
+			DefaultKeyword{},
+			errors.New("DefaultValue: DefaultKeyword: Keyword must not be empty"),
+		},
+		{
+			DefaultTypeDeclaration{},
+			errors.New("DefaultValue: DefaultTypeDeclaration: Type must be set"),
+		},
+		{
+			DefaultAttributeDependent{},
+			errors.New("DefaultValue: DefaultAttributeDependent: Name must not be empty"),
+		},
+		{
+			DefaultEnvVar{Name: "FOO", Fallback: DefaultKeyword{}},
+			errors.New("DefaultValue: DefaultEnvVar: Fallback: DefaultKeyword: Keyword must not be empty"),
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("%2d", i), func(t *testing.T) {
+			aSchema := &AttributeSchema{
+				IsOptional:   true,
+				DefaultValue: tc.defaultValue,
+			}
+			err := aSchema.Validate()
+			if tc.expectedError == nil {
+				if err != nil {
+					t.Fatal(err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatalf("expected error: %s", tc.expectedError)
+			}
+			if err.Error() != tc.expectedError.Error() {
+				t.Fatalf("unexpected error: %s", err)
+			}
+		})
+	}
+}
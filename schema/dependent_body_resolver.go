@@ -0,0 +1,83 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package schema
+
+import (
+	"github.com/hashicorp/hcl-lang/lang"
+)
+
+// DependentBodyResolver resolves dependent body schemas on demand,
+// as an alternative to declaring all of them upfront
+// in BlockSchema.DependentBody.
+//
+// This is useful where the number of dependent bodies is large
+// (e.g. resource types of providers in Terraform) and only
+// schemas of blocks actually present in the configuration
+// should be loaded.
+type DependentBodyResolver interface {
+	// ResolveDependentBody returns the body schema corresponding
+	// to the given dependency keys, or false if there is none.
+	ResolveDependentBody(keys DependencyKeys) (*BodySchema, bool)
+
+	// ListDependentBodies returns up to limit summaries of dependent
+	// bodies depending on a label at the given index whose value
+	// begins with prefix, to be used in label completion.
+	//
+	// The returned bool reports whether the list is complete,
+	// i.e. whether there are no more matching bodies.
+	ListDependentBodies(labelIndex int, prefix string, limit uint) ([]DependentBodySummary, bool)
+}
+
+// DependentBodySummary describes a dependent body
+// without its content, as needed for label completion
+type DependentBodySummary struct {
+	// Label represents the value of the label
+	// on which the body depends
+	Label string
+
+	Detail       string
+	Description  lang.MarkupContent
+	IsDeprecated bool
+}
+
+// LookupDependentBody returns the dependent body schema corresponding
+// to the given dependency keys, as declared in DependentBody
+// or provided by DependentBodyResolver.
+func (bs *BlockSchema) LookupDependentBody(keys DependencyKeys) (*BodySchema, bool) {
+	depBody, ok := bs.DependentBody[NewSchemaKey(keys)]
+	if ok {
+		return depBody, true
+	}
+
+	if bs.DependentBodyResolver != nil {
+		return bs.DependentBodyResolver.ResolveDependentBody(keys)
+	}
+
+	return nil, false
+}
+
+// HasDependentBody returns true if the block's body
+// may depend on labels or attributes
+func (bs *BlockSchema) HasDependentBody() bool {
+	return len(bs.DependentBody) > 0 || bs.DependentBodyResolver != nil
+}
+
+// versionedDependentBodyResolver projects dependent bodies
+// provided by another resolver for the given version
+type versionedDependentBodyResolver struct {
+	resolver DependentBodyResolver
+	version  Version
+}
+
+func (r versionedDependentBodyResolver) ResolveDependentBody(keys DependencyKeys) (*BodySchema, bool) {
+	depBody, ok := r.resolver.ResolveDependentBody(keys)
+	if !ok {
+		return nil, false
+	}
+	return depBody.ForVersion(r.version), true
+}
+
+func (r versionedDependentBodyResolver) ListDependentBodies(labelIndex int, prefix string, limit uint) ([]DependentBodySummary, bool) {
+	return r.resolver.ListDependentBodies(labelIndex, prefix, limit)
+}
@@ -0,0 +1,569 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package schema
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/convert"
+)
+
+// ChangeKind represents kind of a change between two schemas
+type ChangeKind uint
+
+const (
+	ChangeKindNil ChangeKind = iota
+	ChangeKindAttributeAdded
+	ChangeKindAttributeRemoved
+	ChangeKindBlockAdded
+	ChangeKindBlockRemoved
+	ChangeKindBecameRequired
+	ChangeKindBecameOptional
+	ChangeKindConstraintChanged
+	ChangeKindLabelsChanged
+	ChangeKindDependentBodyAdded
+	ChangeKindDependentBodyRemoved
+	ChangeKindDeprecated
+	ChangeKindUndeprecated
+	ChangeKindMaxItemsDecreased
+	ChangeKindMaxItemsIncreased
+	ChangeKindBlockTypeChanged
+	ChangeKindAnyAttributeAdded
+	ChangeKindAnyAttributeRemoved
+)
+
+func (k ChangeKind) String() string {
+	switch k {
+	case ChangeKindAttributeAdded:
+		return "attribute added"
+	case ChangeKindAttributeRemoved:
+		return "attribute removed"
+	case ChangeKindBlockAdded:
+		return "block added"
+	case ChangeKindBlockRemoved:
+		return "block removed"
+	case ChangeKindBecameRequired:
+		return "became required"
+	case ChangeKindBecameOptional:
+		return "became optional"
+	case ChangeKindConstraintChanged:
+		return "constraint changed"
+	case ChangeKindLabelsChanged:
+		return "labels changed"
+	case ChangeKindDependentBodyAdded:
+		return "dependent body added"
+	case ChangeKindDependentBodyRemoved:
+		return "dependent body removed"
+	case ChangeKindDeprecated:
+		return "deprecated"
+	case ChangeKindUndeprecated:
+		return "no longer deprecated"
+	case ChangeKindMaxItemsDecreased:
+		return "max items decreased"
+	case ChangeKindMaxItemsIncreased:
+		return "max items increased"
+	case ChangeKindBlockTypeChanged:
+		return "block type changed"
+	case ChangeKindAnyAttributeAdded:
+		return "any attribute added"
+	case ChangeKindAnyAttributeRemoved:
+		return "any attribute removed"
+	}
+	return ""
+}
+
+func (k ChangeKind) MarshalText() ([]byte, error) {
+	return []byte(strings.ReplaceAll(k.String(), " ", "_")), nil
+}
+
+// Change represents a single change between two schemas
+type Change struct {
+	// Path represents the path to the changed schema, consisting
+	// of block types, dependent body keys and attribute names,
+	// where "*" represents AnyAttribute
+	Path []string   `json:"path"`
+	Kind ChangeKind `json:"kind"`
+
+	// IsBreaking represents whether the change may cause
+	// configuration valid under the old schema to be invalid
+	// under the new one
+	IsBreaking bool `json:"breaking"`
+
+	// Detail provides more details about the change (if any)
+	// e.g. the old and new constraint
+	Detail string `json:"detail,omitempty"`
+}
+
+func (c Change) String() string {
+	classification := "non-breaking"
+	if c.IsBreaking {
+		classification = "breaking"
+	}
+
+	msg := fmt.Sprintf("[%s] %s: %s", classification, strings.Join(c.Path, "."), c.Kind)
+	if c.Detail != "" {
+		msg += fmt.Sprintf(" (%s)", c.Detail)
+	}
+	return msg
+}
+
+// Changes represents a list of changes between two schemas
+type Changes []Change
+
+// HasBreaking returns true if any of the changes is breaking
+func (cs Changes) HasBreaking() bool {
+	for _, c := range cs {
+		if c.IsBreaking {
+			return true
+		}
+	}
+	return false
+}
+
+// TextReport returns the changes in a human-readable form,
+// one change per line
+func (cs Changes) TextReport() string {
+	var sb strings.Builder
+	for _, c := range cs {
+		sb.WriteString(c.String())
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
+
+// JSONReport returns the changes in a machine-readable form
+func (cs Changes) JSONReport() ([]byte, error) {
+	changes := cs
+	if changes == nil {
+		changes = Changes{}
+	}
+	return json.Marshal(struct {
+		HasBreaking bool    `json:"has_breaking"`
+		Changes     Changes `json:"changes"`
+	}{
+		HasBreaking: cs.HasBreaking(),
+		Changes:     changes,
+	})
+}
+
+// Diff returns changes between the old and the new body schema,
+// including any nested bodies and dependent bodies.
+//
+// Changes are sorted by path and kind.
+func Diff(old, new *BodySchema) Changes {
+	changes := diffBodySchemas([]string{}, old, new)
+
+	sort.SliceStable(changes, func(i, j int) bool {
+		pathI, pathJ := strings.Join(changes[i].Path, "."), strings.Join(changes[j].Path, ".")
+		if pathI != pathJ {
+			return pathI < pathJ
+		}
+		return changes[i].Kind < changes[j].Kind
+	})
+
+	return changes
+}
+
+func diffBodySchemas(path []string, old, new *BodySchema) Changes {
+	if old == nil {
+		old = &BodySchema{}
+	}
+	if new == nil {
+		new = &BodySchema{}
+	}
+
+	changes := diffAttributes(path, old.Attributes, new.Attributes)
+
+	switch {
+	case old.AnyAttribute != nil && new.AnyAttribute == nil:
+		changes = append(changes, Change{
+			Path:       path,
+			Kind:       ChangeKindAnyAttributeRemoved,
+			IsBreaking: true,
+		})
+	case old.AnyAttribute == nil && new.AnyAttribute != nil:
+		changes = append(changes, Change{
+			Path:       path,
+			Kind:       ChangeKindAnyAttributeAdded,
+			IsBreaking: new.AnyAttribute.IsRequired,
+		})
+	case old.AnyAttribute != nil && new.AnyAttribute != nil:
+		changes = append(changes, diffAttributeSchemas(appendPath(path, "*"), old.AnyAttribute, new.AnyAttribute)...)
+	}
+
+	for name, oldBlock := range old.Blocks {
+		blockPath := appendPath(path, name)
+		newBlock, ok := new.Blocks[name]
+		if !ok {
+			changes = append(changes, Change{
+				Path:       blockPath,
+				Kind:       ChangeKindBlockRemoved,
+				IsBreaking: true,
+			})
+			continue
+		}
+		changes = append(changes, diffBlockSchemas(blockPath, oldBlock, newBlock)...)
+	}
+	for name, newBlock := range new.Blocks {
+		if _, ok := old.Blocks[name]; !ok {
+			changes = append(changes, Change{
+				Path:       appendPath(path, name),
+				Kind:       ChangeKindBlockAdded,
+				IsBreaking: newBlock.MinItems > 0,
+			})
+		}
+	}
+
+	return changes
+}
+
+func diffAttributes(path []string, old, new map[string]*AttributeSchema) Changes {
+	changes := make(Changes, 0)
+
+	for name, oldAttr := range old {
+		attrPath := appendPath(path, name)
+		newAttr, ok := new[name]
+		if !ok {
+			changes = append(changes, Change{
+				Path:       attrPath,
+				Kind:       ChangeKindAttributeRemoved,
+				IsBreaking: true,
+			})
+			continue
+		}
+		changes = append(changes, diffAttributeSchemas(attrPath, oldAttr, newAttr)...)
+	}
+	for name, newAttr := range new {
+		if _, ok := old[name]; !ok {
+			changes = append(changes, Change{
+				Path:       appendPath(path, name),
+				Kind:       ChangeKindAttributeAdded,
+				IsBreaking: newAttr.IsRequired,
+			})
+		}
+	}
+
+	return changes
+}
+
+func diffAttributeSchemas(path []string, old, new *AttributeSchema) Changes {
+	changes := make(Changes, 0)
+
+	if !old.IsRequired && new.IsRequired {
+		changes = append(changes, Change{
+			Path:       path,
+			Kind:       ChangeKindBecameRequired,
+			IsBreaking: true,
+		})
+	}
+	if old.IsRequired && !new.IsRequired {
+		changes = append(changes, Change{
+			Path: path,
+			Kind: ChangeKindBecameOptional,
+		})
+	}
+
+	changes = append(changes, diffDeprecation(path, old.IsDeprecated, new.IsDeprecated)...)
+
+	oldObj, isOldObj := old.Constraint.(Object)
+	newObj, isNewObj := new.Constraint.(Object)
+	if isOldObj && isNewObj {
+		return append(changes, diffAttributes(path, oldObj.Attributes, newObj.Attributes)...)
+	}
+
+	if !constraintsEqual(old.Constraint, new.Constraint) {
+		changes = append(changes, Change{
+			Path:       path,
+			Kind:       ChangeKindConstraintChanged,
+			IsBreaking: !isConstraintCompatible(old.Constraint, new.Constraint),
+			Detail:     fmt.Sprintf("%s to %s", constraintName(old.Constraint), constraintName(new.Constraint)),
+		})
+	}
+
+	return changes
+}
+
+func diffBlockSchemas(path []string, old, new *BlockSchema) Changes {
+	changes := make(Changes, 0)
+
+	if old.MinItems == 0 && new.MinItems > 0 {
+		changes = append(changes, Change{
+			Path:       path,
+			Kind:       ChangeKindBecameRequired,
+			IsBreaking: true,
+		})
+	}
+	if old.MinItems > 0 && new.MinItems == 0 {
+		changes = append(changes, Change{
+			Path: path,
+			Kind: ChangeKindBecameOptional,
+		})
+	}
+
+	// zero MaxItems represents no limit
+	if new.MaxItems > 0 && (old.MaxItems == 0 || new.MaxItems < old.MaxItems) {
+		changes = append(changes, Change{
+			Path:       path,
+			Kind:       ChangeKindMaxItemsDecreased,
+			IsBreaking: true,
+			Detail:     fmt.Sprintf("%s to %d", maxItemsName(old.MaxItems), new.MaxItems),
+		})
+	}
+	if old.MaxItems > 0 && (new.MaxItems == 0 || new.MaxItems > old.MaxItems) {
+		changes = append(changes, Change{
+			Path:   path,
+			Kind:   ChangeKindMaxItemsIncreased,
+			Detail: fmt.Sprintf("%d to %s", old.MaxItems, maxItemsName(new.MaxItems)),
+		})
+	}
+
+	if old.Type != new.Type {
+		changes = append(changes, Change{
+			Path:       path,
+			Kind:       ChangeKindBlockTypeChanged,
+			IsBreaking: true,
+			Detail:     fmt.Sprintf("%s to %s", blockTypeName(old.Type), blockTypeName(new.Type)),
+		})
+	}
+
+	changes = append(changes, diffDeprecation(path, old.IsDeprecated, new.IsDeprecated)...)
+
+	if len(old.Labels) != len(new.Labels) {
+		changes = append(changes, Change{
+			Path:       path,
+			Kind:       ChangeKindLabelsChanged,
+			IsBreaking: true,
+			Detail:     fmt.Sprintf("%d to %d labels", len(old.Labels), len(new.Labels)),
+		})
+	}
+
+	changes = append(changes, diffBodySchemas(path, old.Body, new.Body)...)
+
+	for key, oldBody := range old.DependentBody {
+		bodyPath := appendPath(path, string(key))
+		newBody, ok := new.DependentBody[key]
+		if !ok {
+			changes = append(changes, Change{
+				Path:       bodyPath,
+				Kind:       ChangeKindDependentBodyRemoved,
+				IsBreaking: true,
+			})
+			continue
+		}
+		changes = append(changes, diffBodySchemas(bodyPath, oldBody, newBody)...)
+	}
+	for key, newBody := range new.DependentBody {
+		if _, ok := old.DependentBody[key]; !ok {
+			bodyPath := appendPath(path, string(key))
+			changes = append(changes, Change{
+				Path: bodyPath,
+				Kind: ChangeKindDependentBodyAdded,
+			})
+			// blocks matching the key were previously decoded
+			// using the static body only
+			changes = append(changes, diffBodySchemas(bodyPath, staticBodyOverlap(old.Body, newBody), newBody)...)
+		}
+	}
+
+	return changes
+}
+
+// staticBodyOverlap returns the part of the static body
+// which is overridden by the dependent body, such that any
+// fields not declared in the dependent body, which are
+// inherited from the static body, are not reported as removed.
+func staticBodyOverlap(static, dependent *BodySchema) *BodySchema {
+	overlap := &BodySchema{
+		Attributes: make(map[string]*AttributeSchema, 0),
+		Blocks:     make(map[string]*BlockSchema, 0),
+	}
+	if static == nil || dependent == nil {
+		return overlap
+	}
+
+	for name := range dependent.Attributes {
+		if attr, ok := static.Attributes[name]; ok {
+			overlap.Attributes[name] = attr
+		}
+	}
+	for name := range dependent.Blocks {
+		if block, ok := static.Blocks[name]; ok {
+			overlap.Blocks[name] = block
+		}
+	}
+	if dependent.AnyAttribute != nil {
+		overlap.AnyAttribute = static.AnyAttribute
+	}
+
+	return overlap
+}
+
+func maxItemsName(maxItems uint64) string {
+	if maxItems == 0 {
+		return "unlimited"
+	}
+	return fmt.Sprintf("%d", maxItems)
+}
+
+func blockTypeName(t BlockType) string {
+	if t == BlockTypeNil {
+		return "default"
+	}
+	return t.String()
+}
+
+func diffDeprecation(path []string, old, new bool) Changes {
+	if !old && new {
+		return Changes{{Path: path, Kind: ChangeKindDeprecated}}
+	}
+	if old && !new {
+		return Changes{{Path: path, Kind: ChangeKindUndeprecated}}
+	}
+	return Changes{}
+}
+
+// constraintsEqual returns true if both constraints
+// accept the same expressions
+func constraintsEqual(old, new Constraint) bool {
+	if old == nil || new == nil {
+		return old == nil && new == nil
+	}
+	if fmt.Sprintf("%T", old) != fmt.Sprintf("%T", new) {
+		return false
+	}
+	if old.FriendlyName() != new.FriendlyName() {
+		return false
+	}
+
+	if oldTyped, ok := old.(TypeAwareConstraint); ok {
+		oldType, oldOk := oldTyped.ConstraintType()
+		newType, newOk := new.(TypeAwareConstraint).ConstraintType()
+		if oldOk != newOk || (oldOk && !oldType.Equals(newType)) {
+			return false
+		}
+	}
+
+	switch oldCon := old.(type) {
+	case LiteralValue:
+		return oldCon.Value.RawEquals(new.(LiteralValue).Value)
+	case Keyword:
+		return oldCon.Keyword == new.(Keyword).Keyword
+	case Reference:
+		newCon := new.(Reference)
+		if oldCon.Address != nil || newCon.Address != nil {
+			if oldCon.Address == nil || newCon.Address == nil ||
+				oldCon.Address.ScopeId != newCon.Address.ScopeId {
+				return false
+			}
+		}
+		return oldCon.OfScopeId == newCon.OfScopeId &&
+			oldCon.OfType.Equals(newCon.OfType) &&
+			oldCon.Name == newCon.Name
+	case OneOf:
+		newCon := new.(OneOf)
+		if len(oldCon) != len(newCon) {
+			return false
+		}
+		for i := range oldCon {
+			if !constraintsEqual(oldCon[i], newCon[i]) {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+// isConstraintCompatible returns true if any expression
+// accepted by the old constraint is also accepted by the new one
+func isConstraintCompatible(old, new Constraint) bool {
+	if new == nil {
+		return true
+	}
+	if old == nil {
+		return false
+	}
+
+	if oldOneOf, ok := old.(OneOf); ok {
+		for _, oldCon := range oldOneOf {
+			if !isConstraintCompatible(oldCon, new) {
+				return false
+			}
+		}
+		return true
+	}
+	if newOneOf, ok := new.(OneOf); ok {
+		for _, newCon := range newOneOf {
+			if isConstraintCompatible(old, newCon) {
+				return true
+			}
+		}
+		return false
+	}
+
+	if constraintsEqual(old, new) {
+		return true
+	}
+
+	if newRef, ok := new.(Reference); ok {
+		oldRef, ok := old.(Reference)
+		if !ok {
+			return false
+		}
+		// the name is only used for display
+		oldRef.Name = newRef.Name
+		return constraintsEqual(oldRef, newRef)
+	}
+
+	oldType, oldOk := constraintType(old)
+	newType, newOk := constraintType(new)
+	if !oldOk || !newOk {
+		return false
+	}
+
+	switch new.(type) {
+	case AnyExpression:
+		// any expression of a compatible type is accepted
+		return isTypeCompatible(oldType, newType)
+	case LiteralType:
+		switch old.(type) {
+		case LiteralType, LiteralValue:
+			return isTypeCompatible(oldType, newType)
+		}
+	}
+
+	return false
+}
+
+func constraintType(con Constraint) (cty.Type, bool) {
+	typed, ok := con.(TypeAwareConstraint)
+	if !ok {
+		return cty.NilType, false
+	}
+	return typed.ConstraintType()
+}
+
+func isTypeCompatible(old, new cty.Type) bool {
+	if old.Equals(new) || new == cty.DynamicPseudoType {
+		return true
+	}
+	return convert.GetConversion(old, new) != nil
+}
+
+func constraintName(con Constraint) string {
+	if con == nil {
+		return "none"
+	}
+	return con.FriendlyName()
+}
+
+func appendPath(path []string, step string) []string {
+	newPath := make([]string, len(path), len(path)+1)
+	copy(newPath, path)
+	return append(newPath, step)
+}
@@ -0,0 +1,438 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package schema
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/hcl-lang/lang"
+	"github.com/zclconf/go-cty/cty"
+)
+
+func TestDiff(t *testing.T) {
+	oldSchema := &BodySchema{
+		Attributes: map[string]*AttributeSchema{
+			"name": {
+				IsRequired: true,
+				Constraint: LiteralType{Type: cty.String},
+			},
+			"port": {
+				IsOptional: true,
+				Constraint: LiteralType{Type: cty.Number},
+			},
+			"mode": {
+				IsOptional: true,
+				Constraint: LiteralType{Type: cty.String},
+			},
+			"legacy": {
+				IsOptional: true,
+				Constraint: LiteralType{Type: cty.String},
+			},
+			"settings": {
+				IsOptional: true,
+				Constraint: Object{
+					Attributes: ObjectAttributes{
+						"enabled": {
+							IsOptional: true,
+							Constraint: LiteralType{Type: cty.Bool},
+						},
+					},
+				},
+			},
+		},
+		Blocks: map[string]*BlockSchema{
+			"resource": {
+				Labels: []*LabelSchema{
+					{Name: "type", IsDepKey: true},
+					{Name: "name"},
+				},
+				Body: &BodySchema{
+					Attributes: map[string]*AttributeSchema{
+						"count": {
+							IsOptional: true,
+							Constraint: AnyExpression{OfType: cty.Number},
+						},
+					},
+				},
+				DependentBody: map[SchemaKey]*BodySchema{
+					NewSchemaKey(DependencyKeys{
+						Labels: []LabelDependent{{Index: 0, Value: "aws_instance"}},
+					}): {
+						Attributes: map[string]*AttributeSchema{
+							"ami": {
+								IsRequired: true,
+								Constraint: LiteralType{Type: cty.String},
+							},
+						},
+					},
+					NewSchemaKey(DependencyKeys{
+						Labels: []LabelDependent{{Index: 0, Value: "aws_vpc"}},
+					}): {},
+				},
+			},
+			"provider": {
+				Labels: []*LabelSchema{{Name: "name"}},
+			},
+		},
+	}
+	newSchema := &BodySchema{
+		Attributes: map[string]*AttributeSchema{
+			"name": {
+				IsRequired:   true,
+				IsDeprecated: true,
+				Constraint:   OneOf{LiteralType{Type: cty.String}, Keyword{Keyword: "auto"}},
+			},
+			"port": {
+				IsRequired: true,
+				Constraint: LiteralType{Type: cty.Bool},
+			},
+			"mode": {
+				IsOptional: true,
+				Constraint: Keyword{Keyword: "fast"},
+			},
+			"region": {
+				IsOptional: true,
+				Constraint: LiteralType{Type: cty.String},
+			},
+			"settings": {
+				IsOptional: true,
+				Constraint: Object{
+					Attributes: ObjectAttributes{
+						"enabled": {
+							IsOptional: true,
+							Constraint: LiteralType{Type: cty.Bool},
+						},
+						"level": {
+							IsRequired: true,
+							Constraint: LiteralType{Type: cty.Number},
+						},
+					},
+				},
+			},
+		},
+		Blocks: map[string]*BlockSchema{
+			"resource": {
+				Labels: []*LabelSchema{
+					{Name: "type", IsDepKey: true},
+					{Name: "name"},
+				},
+				Body: &BodySchema{
+					Attributes: map[string]*AttributeSchema{
+						"count": {
+							IsOptional: true,
+							Constraint: AnyExpression{OfType: cty.Number},
+						},
+					},
+				},
+				DependentBody: map[SchemaKey]*BodySchema{
+					NewSchemaKey(DependencyKeys{
+						Labels: []LabelDependent{{Index: 0, Value: "aws_instance"}},
+					}): {
+						Attributes: map[string]*AttributeSchema{
+							"ami": {
+								IsOptional: true,
+								Constraint: LiteralType{Type: cty.String},
+							},
+						},
+					},
+					NewSchemaKey(DependencyKeys{
+						Labels: []LabelDependent{{Index: 0, Value: "aws_subnet"}},
+					}): {
+						Attributes: map[string]*AttributeSchema{
+							"cidr_block": {
+								IsRequired: true,
+								Constraint: LiteralType{Type: cty.String},
+							},
+							"count": {
+								IsOptional: true,
+								Constraint: LiteralType{Type: cty.Number},
+							},
+						},
+					},
+				},
+			},
+			"terraform": {
+				MinItems: 1,
+			},
+		},
+	}
+
+	changes := Diff(oldSchema, newSchema)
+
+	expectedReport := `[breaking] legacy: attribute removed
+[breaking] mode: constraint changed (string to keyword)
+[non-breaking] name: constraint changed (string to string or keyword)
+[non-breaking] name: deprecated
+[breaking] port: became required
+[breaking] port: constraint changed (number to bool)
+[breaking] provider: block removed
+[non-breaking] region: attribute added
+[non-breaking] resource.{"labels":[{"index":0,"value":"aws_instance"}]}.ami: became optional
+[non-breaking] resource.{"labels":[{"index":0,"value":"aws_subnet"}]}: dependent body added
+[breaking] resource.{"labels":[{"index":0,"value":"aws_subnet"}]}.cidr_block: attribute added
+[breaking] resource.{"labels":[{"index":0,"value":"aws_subnet"}]}.count: constraint changed (number to number)
+[breaking] resource.{"labels":[{"index":0,"value":"aws_vpc"}]}: dependent body removed
+[breaking] settings.level: attribute added
+[breaking] terraform: block added
+`
+	if diff := cmp.Diff(expectedReport, changes.TextReport()); diff != "" {
+		t.Fatalf("unexpected report: %s", diff)
+	}
+
+	if !changes.HasBreaking() {
+		t.Fatal("expected breaking changes")
+	}
+}
+
+func TestDiff_blocksAndAnyAttribute(t *testing.T) {
+	oldSchema := &BodySchema{
+		Blocks: map[string]*BlockSchema{
+			"limited": {
+				Type:     BlockTypeList,
+				MaxItems: 3,
+			},
+			"unlimited": {
+				Type: BlockTypeList,
+			},
+			"relaxed": {
+				Type:     BlockTypeSet,
+				MaxItems: 1,
+			},
+			"tags": {
+				Body: &BodySchema{
+					AnyAttribute: &AttributeSchema{
+						IsOptional: true,
+						Constraint: LiteralType{Type: cty.String},
+					},
+				},
+			},
+			"labels": {
+				Body: &BodySchema{
+					AnyAttribute: &AttributeSchema{
+						IsOptional: true,
+						Constraint: LiteralType{Type: cty.String},
+					},
+				},
+			},
+			"meta": {
+				Body: &BodySchema{},
+			},
+		},
+	}
+	newSchema := &BodySchema{
+		Blocks: map[string]*BlockSchema{
+			"limited": {
+				Type:     BlockTypeList,
+				MaxItems: 1,
+			},
+			"unlimited": {
+				Type:     BlockTypeSet,
+				MaxItems: 2,
+			},
+			"relaxed": {
+				Type: BlockTypeSet,
+			},
+			"tags": {
+				Body: &BodySchema{},
+			},
+			"labels": {
+				Body: &BodySchema{
+					AnyAttribute: &AttributeSchema{
+						IsOptional: true,
+						Constraint: LiteralType{Type: cty.Number},
+					},
+				},
+			},
+			"meta": {
+				Body: &BodySchema{
+					AnyAttribute: &AttributeSchema{
+						IsOptional: true,
+						Constraint: LiteralType{Type: cty.String},
+					},
+				},
+			},
+		},
+	}
+
+	changes := Diff(oldSchema, newSchema)
+
+	expectedReport := `[breaking] labels.*: constraint changed (string to number)
+[breaking] limited: max items decreased (3 to 1)
+[non-breaking] meta: any attribute added
+[non-breaking] relaxed: max items increased (1 to unlimited)
+[breaking] tags: any attribute removed
+[breaking] unlimited: max items decreased (unlimited to 2)
+[breaking] unlimited: block type changed (list to set)
+`
+	if diff := cmp.Diff(expectedReport, changes.TextReport()); diff != "" {
+		t.Fatalf("unexpected report: %s", diff)
+	}
+}
+
+func TestDiff_noChanges(t *testing.T) {
+	bodySchema := &BodySchema{
+		Attributes: map[string]*AttributeSchema{
+			"foo": {
+				IsOptional: true,
+				Constraint: OneOf{
+					LiteralValue{Value: cty.StringVal("bar")},
+					Keyword{Keyword: "baz"},
+				},
+			},
+		},
+		Blocks: map[string]*BlockSchema{
+			"block": {
+				Body: &BodySchema{
+					Attributes: map[string]*AttributeSchema{
+						"nested": {
+							IsRequired: true,
+							Constraint: List{Elem: LiteralType{Type: cty.String}},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	changes := Diff(bodySchema, bodySchema.Copy())
+	if len(changes) != 0 {
+		t.Fatalf("expected no changes, given: %s", changes.TextReport())
+	}
+
+	b, err := changes.JSONReport()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectedJSON := `{"has_breaking":false,"changes":[]}`
+	if diff := cmp.Diff(expectedJSON, string(b)); diff != "" {
+		t.Fatalf("unexpected JSON report: %s", diff)
+	}
+}
+
+func TestDiff_constraintCompatibility(t *testing.T) {
+	testCases := []struct {
+		name               string
+		oldConstraint      Constraint
+		newConstraint      Constraint
+		expectedIsBreaking bool
+	}{
+		{
+			"literal type widened to expression",
+			LiteralType{Type: cty.String},
+			AnyExpression{OfType: cty.String},
+			false,
+		},
+		{
+			"expression narrowed to literal type",
+			AnyExpression{OfType: cty.String},
+			LiteralType{Type: cty.String},
+			true,
+		},
+		{
+			"number to string",
+			LiteralType{Type: cty.Number},
+			LiteralType{Type: cty.String},
+			false,
+		},
+		{
+			"literal value to its type",
+			LiteralValue{Value: cty.StringVal("foo")},
+			LiteralType{Type: cty.String},
+			false,
+		},
+		{
+			"different literal value",
+			LiteralValue{Value: cty.StringVal("foo")},
+			LiteralValue{Value: cty.StringVal("bar")},
+			true,
+		},
+		{
+			"removed one of element",
+			OneOf{Keyword{Keyword: "foo"}, Keyword{Keyword: "bar"}},
+			OneOf{Keyword{Keyword: "foo"}},
+			true,
+		},
+		{
+			"added one of element",
+			OneOf{Keyword{Keyword: "foo"}},
+			OneOf{Keyword{Keyword: "foo"}, Keyword{Keyword: "bar"}},
+			false,
+		},
+		{
+			"reference scope changed",
+			Reference{OfScopeId: lang.ScopeId("variable")},
+			Reference{OfScopeId: lang.ScopeId("local")},
+			true,
+		},
+		{
+			"reference type changed",
+			Reference{OfType: cty.String, Name: "value"},
+			Reference{OfType: cty.Number, Name: "value"},
+			true,
+		},
+		{
+			"reference renamed",
+			Reference{OfScopeId: lang.ScopeId("variable"), Name: "variable"},
+			Reference{OfScopeId: lang.ScopeId("variable"), Name: "input variable"},
+			false,
+		},
+		{
+			"constraint removed",
+			Keyword{Keyword: "foo"},
+			nil,
+			false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			changes := Diff(&BodySchema{
+				Attributes: map[string]*AttributeSchema{
+					"foo": {IsOptional: true, Constraint: tc.oldConstraint},
+				},
+			}, &BodySchema{
+				Attributes: map[string]*AttributeSchema{
+					"foo": {IsOptional: true, Constraint: tc.newConstraint},
+				},
+			})
+			if len(changes) != 1 {
+				t.Fatalf("expected exactly 1 change, given: %s", changes.TextReport())
+			}
+			if changes[0].Kind != ChangeKindConstraintChanged {
+				t.Fatalf("unexpected change: %s", changes[0])
+			}
+			if changes[0].IsBreaking != tc.expectedIsBreaking {
+				t.Fatalf("expected breaking: %t, given: %s", tc.expectedIsBreaking, changes[0])
+			}
+		})
+	}
+}
+
+func TestChanges_JSONReport(t *testing.T) {
+	changes := Changes{
+		{
+			Path:       []string{"foo", "bar"},
+			Kind:       ChangeKindAttributeRemoved,
+			IsBreaking: true,
+		},
+		{
+			Path:   []string{"baz"},
+			Kind:   ChangeKindConstraintChanged,
+			Detail: "string to any expression",
+		},
+	}
+
+	b, err := changes.JSONReport()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectedJSON := `{"has_breaking":true,"changes":[` +
+		`{"path":["foo","bar"],"kind":"attribute_removed","breaking":true},` +
+		`{"path":["baz"],"kind":"constraint_changed","breaking":false,"detail":"string to any expression"}]}`
+	if diff := cmp.Diff(expectedJSON, string(b)); diff != "" {
+		t.Fatalf("unexpected JSON report: %s", diff)
+	}
+}
@@ -0,0 +1,278 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+// Package gohclschema builds schema.BodySchema from Go struct types
+// annotated with gohcl field tags.
+package gohclschema
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/hcl-lang/lang"
+	"github.com/hashicorp/hcl-lang/schema"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/gocty"
+)
+
+const (
+	// DescriptionTag is the struct tag supplying Markdown description
+	// of the attribute, block or label represented by the field
+	DescriptionTag = "description"
+
+	// DeprecatedTag is the struct tag marking the attribute or block
+	// represented by the field as deprecated, e.g. deprecated:"true"
+	DeprecatedTag = "deprecated"
+
+	// CompletionHooksTag is the struct tag supplying comma-separated
+	// names of completion hooks of the attribute represented by the field
+	CompletionHooksTag = "completion_hooks"
+)
+
+var (
+	exprType       = reflect.TypeOf((*hcl.Expression)(nil)).Elem()
+	bodyType       = reflect.TypeOf((*hcl.Body)(nil)).Elem()
+	attributesType = reflect.TypeOf(hcl.Attributes(nil))
+)
+
+// Options represents options for building the schema
+type Options struct {
+	// LiteralTypesOnly represents whether attribute types are mapped
+	// to LiteralType constraints, which is appropriate when the config
+	// is decoded without hcl.EvalContext, i.e. when no variables
+	// or functions are available. AnyExpression constraints
+	// are used otherwise.
+	LiteralTypesOnly bool
+}
+
+// ImpliedBodySchema returns schema of the body represented by the type
+// of the given value, which must be a struct or a pointer to one,
+// with fields tagged the same way as for gohcl.DecodeBody.
+//
+// Attribute field types are mapped to cty types via gocty,
+// block fields of struct type to BlockTypeObject and slices
+// of structs to BlockTypeList. A "remain" field is represented
+// by AnyAttribute.
+//
+// Descriptions, deprecation and completion hooks can be provided
+// via DescriptionTag, DeprecatedTag and CompletionHooksTag.
+func ImpliedBodySchema(val interface{}, opts Options) (*schema.BodySchema, error) {
+	ty := reflect.TypeOf(val)
+	if ty != nil && ty.Kind() == reflect.Ptr {
+		ty = ty.Elem()
+	}
+	if ty == nil || ty.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("given value must be struct, not %T", val)
+	}
+
+	b := builder{
+		opts:     opts,
+		visiting: make(map[reflect.Type]bool, 0),
+	}
+	return b.bodySchema(ty)
+}
+
+type builder struct {
+	opts Options
+
+	// visiting tracks struct types being built
+	// to detect recursive block types
+	visiting map[reflect.Type]bool
+}
+
+func (b builder) bodySchema(ty reflect.Type) (*schema.BodySchema, error) {
+	if b.visiting[ty] {
+		return nil, fmt.Errorf("%s: recursive block type", ty)
+	}
+	b.visiting[ty] = true
+	defer delete(b.visiting, ty)
+
+	bodySchema := &schema.BodySchema{
+		Attributes: make(map[string]*schema.AttributeSchema, 0),
+		Blocks:     make(map[string]*schema.BlockSchema, 0),
+	}
+
+	for i := 0; i < ty.NumField(); i++ {
+		field := ty.Field(i)
+		name, kind, ok := parseHCLTag(field)
+		if !ok {
+			continue
+		}
+
+		switch kind {
+		case "attr", "optional":
+			aSchema, err := b.attributeSchema(field, kind == "optional")
+			if err != nil {
+				return nil, err
+			}
+			bodySchema.Attributes[name] = aSchema
+		case "block":
+			bSchema, err := b.blockSchema(field)
+			if err != nil {
+				return nil, err
+			}
+			bodySchema.Blocks[name] = bSchema
+		case "remain":
+			if field.Type != bodyType && field.Type != attributesType {
+				return nil, fmt.Errorf("%s.%s: unsupported remain field type %s", ty, field.Name, field.Type)
+			}
+			bodySchema.AnyAttribute = &schema.AttributeSchema{
+				IsOptional: true,
+				Constraint: b.constraintForType(cty.DynamicPseudoType),
+			}
+		case "label", "body":
+			// labels are part of the block schema
+			// and body is the body itself
+		default:
+			return nil, fmt.Errorf("%s.%s: invalid hcl field tag kind %q", ty, field.Name, kind)
+		}
+	}
+
+	return bodySchema, nil
+}
+
+func (b builder) attributeSchema(field reflect.StructField, optional bool) (*schema.AttributeSchema, error) {
+	fty := field.Type
+
+	// required-ness follows gohcl.ImpliedBodySchema
+	var required bool
+	var typ cty.Type
+	switch {
+	case fty.AssignableTo(exprType):
+		typ = cty.DynamicPseudoType
+	case fty.Kind() == reflect.Interface:
+		return nil, fmt.Errorf("%s: unsupported attribute field type %s", field.Name, fty)
+	default:
+		required = fty.Kind() != reflect.Ptr && !optional
+
+		var err error
+		typ, err = gocty.ImpliedType(reflect.Zero(fty).Interface())
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", field.Name, err)
+		}
+	}
+
+	return &schema.AttributeSchema{
+		Description:     descriptionForField(field),
+		IsRequired:      required,
+		IsOptional:      !required,
+		IsDeprecated:    isDeprecatedField(field),
+		Constraint:      b.constraintForType(typ),
+		CompletionHooks: completionHooksForField(field),
+	}, nil
+}
+
+func (b builder) blockSchema(field reflect.StructField) (*schema.BlockSchema, error) {
+	fty := field.Type
+
+	bSchema := &schema.BlockSchema{
+		Description:  descriptionForField(field),
+		IsDeprecated: isDeprecatedField(field),
+	}
+
+	switch fty.Kind() {
+	case reflect.Slice:
+		fty = fty.Elem()
+		bSchema.Type = schema.BlockTypeList
+	case reflect.Ptr:
+		bSchema.Type = schema.BlockTypeObject
+		bSchema.MaxItems = 1
+	default:
+		bSchema.Type = schema.BlockTypeObject
+		bSchema.MinItems = 1
+		bSchema.MaxItems = 1
+	}
+	if fty.Kind() == reflect.Ptr {
+		fty = fty.Elem()
+	}
+	if fty.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("%s: block field type must be struct, not %s", field.Name, field.Type)
+	}
+
+	labels, err := labelsForType(fty)
+	if err != nil {
+		return nil, err
+	}
+	bSchema.Labels = labels
+
+	bSchema.Body, err = b.bodySchema(fty)
+	if err != nil {
+		return nil, err
+	}
+
+	return bSchema, nil
+}
+
+func (b builder) constraintForType(typ cty.Type) schema.Constraint {
+	if b.opts.LiteralTypesOnly {
+		return schema.LiteralType{Type: typ}
+	}
+	return schema.AnyExpression{OfType: typ}
+}
+
+func labelsForType(ty reflect.Type) ([]*schema.LabelSchema, error) {
+	labels := make([]*schema.LabelSchema, 0)
+	for i := 0; i < ty.NumField(); i++ {
+		field := ty.Field(i)
+		name, kind, ok := parseHCLTag(field)
+		if !ok || kind != "label" {
+			continue
+		}
+		if field.Type.Kind() != reflect.String {
+			return nil, fmt.Errorf("%s.%s: label field type must be string, not %s", ty, field.Name, field.Type)
+		}
+		labels = append(labels, &schema.LabelSchema{
+			Name:        name,
+			Description: descriptionForField(field),
+		})
+	}
+	return labels, nil
+}
+
+// parseHCLTag returns name and kind from the hcl tag of the field
+// the same way as gohcl does
+func parseHCLTag(field reflect.StructField) (string, string, bool) {
+	tag := field.Tag.Get("hcl")
+	if tag == "" {
+		return "", "", false
+	}
+
+	name, kind, found := strings.Cut(tag, ",")
+	if !found {
+		kind = "attr"
+	}
+	return name, kind, true
+}
+
+func descriptionForField(field reflect.StructField) lang.MarkupContent {
+	description := field.Tag.Get(DescriptionTag)
+	if description == "" {
+		return lang.MarkupContent{}
+	}
+	return lang.Markdown(description)
+}
+
+func isDeprecatedField(field reflect.StructField) bool {
+	deprecated, _ := strconv.ParseBool(field.Tag.Get(DeprecatedTag))
+	return deprecated
+}
+
+func completionHooksForField(field reflect.StructField) lang.CompletionHooks {
+	tag := field.Tag.Get(CompletionHooksTag)
+	if tag == "" {
+		return nil
+	}
+
+	names := strings.Split(tag, ",")
+	hooks := make(lang.CompletionHooks, 0, len(names))
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		if name != "" {
+			hooks = append(hooks, lang.CompletionHook{Name: name})
+		}
+	}
+	return hooks
+}
@@ -0,0 +1,216 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package gohclschema
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/hcl-lang/lang"
+	"github.com/hashicorp/hcl-lang/schema"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/zclconf/go-cty-debug/ctydebug"
+	"github.com/zclconf/go-cty/cty"
+)
+
+type testConfig struct {
+	Name     string            `hcl:"name" description:"Name of the service"`
+	Port     *int              `hcl:"port,attr"`
+	Tags     map[string]string `hcl:"tags,optional" deprecated:"true"`
+	Command  hcl.Expression    `hcl:"command,attr" completion_hooks:"CompleteCommand, CompleteShell"`
+	Value    cty.Value         `hcl:"value,optional"`
+	Network  testNetwork       `hcl:"network,block" description:"Network settings"`
+	Logging  *testLogging      `hcl:"logging,block"`
+	Services []testService     `hcl:"service,block" deprecated:"true"`
+	Remain   hcl.Body          `hcl:",remain"`
+	Ignored  string
+}
+
+type testNetwork struct {
+	Mode string `hcl:"mode,optional"`
+}
+
+type testLogging struct {
+	Level string `hcl:"level"`
+}
+
+type testService struct {
+	Type   string   `hcl:"type,label" description:"Type of the service"`
+	Name   string   `hcl:"name,label"`
+	Ports  []int    `hcl:"ports"`
+	Config hcl.Body `hcl:",body"`
+}
+
+func TestImpliedBodySchema(t *testing.T) {
+	bodySchema, err := ImpliedBodySchema(&testConfig{}, Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectedSchema := &schema.BodySchema{
+		Attributes: map[string]*schema.AttributeSchema{
+			"name": {
+				Description: lang.Markdown("Name of the service"),
+				IsRequired:  true,
+				Constraint:  schema.AnyExpression{OfType: cty.String},
+			},
+			"port": {
+				IsOptional: true,
+				Constraint: schema.AnyExpression{OfType: cty.Number},
+			},
+			"tags": {
+				IsOptional:   true,
+				IsDeprecated: true,
+				Constraint:   schema.AnyExpression{OfType: cty.Map(cty.String)},
+			},
+			"command": {
+				IsOptional: true,
+				Constraint: schema.AnyExpression{OfType: cty.DynamicPseudoType},
+				CompletionHooks: lang.CompletionHooks{
+					{Name: "CompleteCommand"},
+					{Name: "CompleteShell"},
+				},
+			},
+			"value": {
+				IsOptional: true,
+				Constraint: schema.AnyExpression{OfType: cty.DynamicPseudoType},
+			},
+		},
+		Blocks: map[string]*schema.BlockSchema{
+			"network": {
+				Labels:      []*schema.LabelSchema{},
+				Type:        schema.BlockTypeObject,
+				Description: lang.Markdown("Network settings"),
+				MinItems:    1,
+				MaxItems:    1,
+				Body: &schema.BodySchema{
+					Attributes: map[string]*schema.AttributeSchema{
+						"mode": {
+							IsOptional: true,
+							Constraint: schema.AnyExpression{OfType: cty.String},
+						},
+					},
+					Blocks: map[string]*schema.BlockSchema{},
+				},
+			},
+			"logging": {
+				Labels:   []*schema.LabelSchema{},
+				Type:     schema.BlockTypeObject,
+				MaxItems: 1,
+				Body: &schema.BodySchema{
+					Attributes: map[string]*schema.AttributeSchema{
+						"level": {
+							IsRequired: true,
+							Constraint: schema.AnyExpression{OfType: cty.String},
+						},
+					},
+					Blocks: map[string]*schema.BlockSchema{},
+				},
+			},
+			"service": {
+				Labels: []*schema.LabelSchema{
+					{
+						Name:        "type",
+						Description: lang.Markdown("Type of the service"),
+					},
+					{Name: "name"},
+				},
+				Type:         schema.BlockTypeList,
+				IsDeprecated: true,
+				Body: &schema.BodySchema{
+					Attributes: map[string]*schema.AttributeSchema{
+						"ports": {
+							IsRequired: true,
+							Constraint: schema.AnyExpression{OfType: cty.List(cty.Number)},
+						},
+					},
+					Blocks: map[string]*schema.BlockSchema{},
+				},
+			},
+		},
+		AnyAttribute: &schema.AttributeSchema{
+			IsOptional: true,
+			Constraint: schema.AnyExpression{OfType: cty.DynamicPseudoType},
+		},
+	}
+
+	if diff := cmp.Diff(expectedSchema, bodySchema, ctydebug.CmpOptions); diff != "" {
+		t.Fatalf("unexpected schema: %s", diff)
+	}
+}
+
+func TestImpliedBodySchema_literalTypesOnly(t *testing.T) {
+	type config struct {
+		Names []string `hcl:"names"`
+	}
+
+	bodySchema, err := ImpliedBodySchema(config{}, Options{LiteralTypesOnly: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectedSchema := &schema.BodySchema{
+		Attributes: map[string]*schema.AttributeSchema{
+			"names": {
+				IsRequired: true,
+				Constraint: schema.LiteralType{Type: cty.List(cty.String)},
+			},
+		},
+		Blocks: map[string]*schema.BlockSchema{},
+	}
+
+	if diff := cmp.Diff(expectedSchema, bodySchema, ctydebug.CmpOptions); diff != "" {
+		t.Fatalf("unexpected schema: %s", diff)
+	}
+}
+
+type testRecursive struct {
+	Children []testRecursive `hcl:"child,block"`
+}
+
+func TestImpliedBodySchema_invalid(t *testing.T) {
+	testCases := []struct {
+		name        string
+		val         interface{}
+		expectedErr string
+	}{
+		{
+			"not a struct",
+			"foo",
+			"given value must be struct, not string",
+		},
+		{
+			"block of non-struct type",
+			struct {
+				Foo string `hcl:"foo,block"`
+			}{},
+			"Foo: block field type must be struct, not string",
+		},
+		{
+			"invalid tag kind",
+			struct {
+				Foo string `hcl:"foo,unknown"`
+			}{},
+			`Foo: invalid hcl field tag kind "unknown"`,
+		},
+		{
+			"recursive block",
+			testRecursive{},
+			"gohclschema.testRecursive: recursive block type",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := ImpliedBodySchema(tc.val, Options{})
+			if err == nil {
+				t.Fatalf("expected error: %s", tc.expectedErr)
+			}
+			if !strings.HasSuffix(err.Error(), tc.expectedErr) {
+				t.Fatalf("unexpected error: %s", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,273 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+// Package hcldecschema converts hcldec.Spec (as used e.g. by Packer
+// and Nomad plugins) into schema.BodySchema.
+package hcldecschema
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/hashicorp/hcl-lang/schema"
+	"github.com/hashicorp/hcl/v2/hcldec"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// Options represents options for the conversion
+type Options struct {
+	// LiteralTypesOnly represents whether attribute types are mapped
+	// to LiteralType constraints, which is appropriate when the config
+	// is decoded without hcl.EvalContext, i.e. when no variables
+	// or functions are available. AnyExpression constraints
+	// are used otherwise.
+	LiteralTypesOnly bool
+}
+
+// FromSpec converts the given spec (typically hcldec.ObjectSpec)
+// into a body schema.
+//
+// Specs which do not declare any attributes or blocks,
+// such as LiteralSpec or ExprSpec, are ignored.
+// Specs wrapping other specs (e.g. ValidateSpec) are represented
+// by the schema of the wrapped spec.
+func FromSpec(spec hcldec.Spec, opts Options) (*schema.BodySchema, error) {
+	c := converter{opts: opts}
+	return c.bodySchema(spec)
+}
+
+type converter struct {
+	opts Options
+}
+
+func (c converter) bodySchema(spec hcldec.Spec) (*schema.BodySchema, error) {
+	bodySchema := &schema.BodySchema{
+		Attributes: make(map[string]*schema.AttributeSchema, 0),
+		Blocks:     make(map[string]*schema.BlockSchema, 0),
+	}
+
+	err := c.addSpec(bodySchema, spec)
+	if err != nil {
+		return nil, err
+	}
+
+	return bodySchema, nil
+}
+
+func (c converter) addSpec(bodySchema *schema.BodySchema, spec hcldec.Spec) error {
+	switch s := spec.(type) {
+	case nil:
+		return nil
+	case hcldec.ObjectSpec:
+		names := make([]string, 0, len(s))
+		for name := range s {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		for _, name := range names {
+			err := c.addSpec(bodySchema, s[name])
+			if err != nil {
+				return err
+			}
+		}
+	case hcldec.TupleSpec:
+		for _, elemSpec := range s {
+			err := c.addSpec(bodySchema, elemSpec)
+			if err != nil {
+				return err
+			}
+		}
+	case *hcldec.AttrSpec:
+		typ := s.Type
+		if typ == cty.NilType {
+			typ = cty.DynamicPseudoType
+		}
+		return addAttribute(bodySchema, s.Name, &schema.AttributeSchema{
+			IsRequired: s.Required,
+			IsOptional: !s.Required,
+			Constraint: c.constraintForType(typ),
+		})
+	case *hcldec.DefaultSpec:
+		err := c.addSpec(bodySchema, s.Primary)
+		if err != nil {
+			return err
+		}
+		err = c.addSpec(bodySchema, s.Default)
+		if err != nil {
+			return err
+		}
+
+		// the primary attribute becomes optional
+		// with the (static) default value
+		if attrSpec, ok := unwrapSpec(s.Primary).(*hcldec.AttrSpec); ok {
+			aSchema := bodySchema.Attributes[attrSpec.Name]
+			aSchema.IsRequired = false
+			aSchema.IsOptional = true
+			if literalSpec, ok := unwrapSpec(s.Default).(*hcldec.LiteralSpec); ok {
+				aSchema.DefaultValue = schema.DefaultValue{Value: literalSpec.Value}
+			}
+		}
+	case *hcldec.BlockSpec:
+		return c.addBlock(bodySchema, s.TypeName, s.Nested, nil, schema.BlockTypeObject,
+			minItemsForRequired(s.Required), 1)
+	case *hcldec.BlockListSpec:
+		return c.addBlock(bodySchema, s.TypeName, s.Nested, nil, schema.BlockTypeList,
+			itemsLimit(s.MinItems), itemsLimit(s.MaxItems))
+	case *hcldec.BlockTupleSpec:
+		return c.addBlock(bodySchema, s.TypeName, s.Nested, nil, schema.BlockTypeList,
+			itemsLimit(s.MinItems), itemsLimit(s.MaxItems))
+	case *hcldec.BlockSetSpec:
+		return c.addBlock(bodySchema, s.TypeName, s.Nested, nil, schema.BlockTypeSet,
+			itemsLimit(s.MinItems), itemsLimit(s.MaxItems))
+	case *hcldec.BlockMapSpec:
+		return c.addBlock(bodySchema, s.TypeName, s.Nested, s.LabelNames, schema.BlockTypeMap, 0, 0)
+	case *hcldec.BlockObjectSpec:
+		return c.addBlock(bodySchema, s.TypeName, s.Nested, s.LabelNames, schema.BlockTypeMap, 0, 0)
+	case *hcldec.BlockAttrsSpec:
+		return addBlock(bodySchema, s.TypeName, &schema.BlockSchema{
+			Type: schema.BlockTypeObject,
+			Body: &schema.BodySchema{
+				AnyAttribute: &schema.AttributeSchema{
+					IsOptional: true,
+					Constraint: c.constraintForType(s.ElementType),
+				},
+			},
+			MinItems: minItemsForRequired(s.Required),
+			MaxItems: 1,
+		})
+	case *hcldec.BlockLabelSpec:
+		// labels are collected as part of the block
+		return nil
+	case *hcldec.LiteralSpec, *hcldec.ExprSpec:
+		// no attributes or blocks
+		return nil
+	case *hcldec.TransformExprSpec, *hcldec.TransformFuncSpec,
+		*hcldec.RefineValueSpec, *hcldec.ValidateSpec:
+		return c.addSpec(bodySchema, unwrapSpec(s))
+	default:
+		return fmt.Errorf("unsupported spec: %T", spec)
+	}
+
+	return nil
+}
+
+func (c converter) addBlock(bodySchema *schema.BodySchema, typeName string, nested hcldec.Spec,
+	labelNames []string, blockType schema.BlockType, minItems, maxItems uint64) error {
+	body, err := c.bodySchema(nested)
+	if err != nil {
+		return fmt.Errorf("%s: %w", typeName, err)
+	}
+
+	labels := make([]*schema.LabelSchema, 0)
+	if labelNames == nil {
+		labelNames = labelNamesForSpec(nested)
+	}
+	for _, name := range labelNames {
+		labels = append(labels, &schema.LabelSchema{Name: name})
+	}
+
+	return addBlock(bodySchema, typeName, &schema.BlockSchema{
+		Labels:   labels,
+		Type:     blockType,
+		Body:     body,
+		MinItems: minItems,
+		MaxItems: maxItems,
+	})
+}
+
+func (c converter) constraintForType(typ cty.Type) schema.Constraint {
+	if c.opts.LiteralTypesOnly {
+		return schema.LiteralType{Type: typ}
+	}
+	return schema.AnyExpression{OfType: typ}
+}
+
+func addAttribute(bodySchema *schema.BodySchema, name string, aSchema *schema.AttributeSchema) error {
+	if _, ok := bodySchema.Blocks[name]; ok {
+		return fmt.Errorf("%q is declared as both block and attribute", name)
+	}
+	bodySchema.Attributes[name] = aSchema
+	return nil
+}
+
+func addBlock(bodySchema *schema.BodySchema, typeName string, bSchema *schema.BlockSchema) error {
+	if _, ok := bodySchema.Attributes[typeName]; ok {
+		return fmt.Errorf("%q is declared as both attribute and block", typeName)
+	}
+	bodySchema.Blocks[typeName] = bSchema
+	return nil
+}
+
+// labelNamesForSpec returns names of labels declared
+// via BlockLabelSpec in the body of the block, ordered by index
+func labelNamesForSpec(spec hcldec.Spec) []string {
+	labelSpecs := make([]*hcldec.BlockLabelSpec, 0)
+
+	var collectLabels func(spec hcldec.Spec)
+	collectLabels = func(spec hcldec.Spec) {
+		switch s := unwrapSpec(spec).(type) {
+		case *hcldec.BlockLabelSpec:
+			labelSpecs = append(labelSpecs, s)
+		case hcldec.ObjectSpec:
+			for _, elemSpec := range s {
+				collectLabels(elemSpec)
+			}
+		case hcldec.TupleSpec:
+			for _, elemSpec := range s {
+				collectLabels(elemSpec)
+			}
+		case *hcldec.DefaultSpec:
+			collectLabels(s.Primary)
+			collectLabels(s.Default)
+		}
+	}
+	collectLabels(spec)
+
+	sort.SliceStable(labelSpecs, func(i, j int) bool {
+		return labelSpecs[i].Index < labelSpecs[j].Index
+	})
+
+	names := make([]string, 0, len(labelSpecs))
+	for i, labelSpec := range labelSpecs {
+		if i > 0 && labelSpecs[i-1].Index == labelSpec.Index {
+			// the same label decoded more than once
+			continue
+		}
+		names = append(names, labelSpec.Name)
+	}
+	return names
+}
+
+// unwrapSpec returns the innermost spec wrapped
+// by any transforming or validating specs
+func unwrapSpec(spec hcldec.Spec) hcldec.Spec {
+	for {
+		switch s := spec.(type) {
+		case *hcldec.TransformExprSpec:
+			spec = s.Wrapped
+		case *hcldec.TransformFuncSpec:
+			spec = s.Wrapped
+		case *hcldec.RefineValueSpec:
+			spec = s.Wrapped
+		case *hcldec.ValidateSpec:
+			spec = s.Wrapped
+		default:
+			return spec
+		}
+	}
+}
+
+func minItemsForRequired(required bool) uint64 {
+	if required {
+		return 1
+	}
+	return 0
+}
+
+func itemsLimit(limit int) uint64 {
+	if limit < 0 {
+		return 0
+	}
+	return uint64(limit)
+}
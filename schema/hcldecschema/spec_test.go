@@ -0,0 +1,240 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package hcldecschema
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/hcl-lang/schema"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hcldec"
+	"github.com/zclconf/go-cty-debug/ctydebug"
+	"github.com/zclconf/go-cty/cty"
+)
+
+func TestFromSpec(t *testing.T) {
+	spec := hcldec.ObjectSpec{
+		"name": &hcldec.AttrSpec{
+			Name:     "name",
+			Type:     cty.String,
+			Required: true,
+		},
+		"port": &hcldec.DefaultSpec{
+			Primary: &hcldec.AttrSpec{
+				Name:     "port",
+				Type:     cty.Number,
+				Required: true,
+			},
+			Default: &hcldec.LiteralSpec{
+				Value: cty.NumberIntVal(8080),
+			},
+		},
+		"tags": &hcldec.ValidateSpec{
+			Wrapped: &hcldec.AttrSpec{
+				Name: "tags",
+				Type: cty.Map(cty.String),
+			},
+			Func: func(value cty.Value) hcl.Diagnostics { return nil },
+		},
+		"constant": &hcldec.LiteralSpec{
+			Value: cty.True,
+		},
+		"network": &hcldec.BlockSpec{
+			TypeName: "network",
+			Required: true,
+			Nested: hcldec.ObjectSpec{
+				"mode": &hcldec.AttrSpec{
+					Name: "mode",
+					Type: cty.String,
+				},
+			},
+		},
+		"provisioner": &hcldec.BlockListSpec{
+			TypeName: "provisioner",
+			MinItems: 1,
+			MaxItems: 3,
+			Nested: hcldec.ObjectSpec{
+				"type": &hcldec.BlockLabelSpec{
+					Index: 0,
+					Name:  "type",
+				},
+				"command": &hcldec.AttrSpec{
+					Name: "command",
+					Type: cty.List(cty.String),
+				},
+			},
+		},
+		"volume": &hcldec.BlockSetSpec{
+			TypeName: "volume",
+			Nested: hcldec.TupleSpec{
+				&hcldec.BlockLabelSpec{Index: 1, Name: "name"},
+				&hcldec.BlockLabelSpec{Index: 0, Name: "kind"},
+			},
+		},
+		"service": &hcldec.BlockMapSpec{
+			TypeName:   "service",
+			LabelNames: []string{"name"},
+			Nested: hcldec.ObjectSpec{
+				"check": &hcldec.BlockTupleSpec{
+					TypeName: "check",
+					Nested:   hcldec.ObjectSpec{},
+				},
+			},
+		},
+		"env": &hcldec.BlockAttrsSpec{
+			TypeName:    "env",
+			ElementType: cty.String,
+		},
+	}
+
+	bodySchema, err := FromSpec(spec, Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectedSchema := &schema.BodySchema{
+		Attributes: map[string]*schema.AttributeSchema{
+			"name": {
+				IsRequired: true,
+				Constraint: schema.AnyExpression{OfType: cty.String},
+			},
+			"port": {
+				IsOptional:   true,
+				Constraint:   schema.AnyExpression{OfType: cty.Number},
+				DefaultValue: schema.DefaultValue{Value: cty.NumberIntVal(8080)},
+			},
+			"tags": {
+				IsOptional: true,
+				Constraint: schema.AnyExpression{OfType: cty.Map(cty.String)},
+			},
+		},
+		Blocks: map[string]*schema.BlockSchema{
+			"network": {
+				Labels: []*schema.LabelSchema{},
+				Type:   schema.BlockTypeObject,
+				Body: &schema.BodySchema{
+					Attributes: map[string]*schema.AttributeSchema{
+						"mode": {
+							IsOptional: true,
+							Constraint: schema.AnyExpression{OfType: cty.String},
+						},
+					},
+					Blocks: map[string]*schema.BlockSchema{},
+				},
+				MinItems: 1,
+				MaxItems: 1,
+			},
+			"provisioner": {
+				Labels: []*schema.LabelSchema{
+					{Name: "type"},
+				},
+				Type: schema.BlockTypeList,
+				Body: &schema.BodySchema{
+					Attributes: map[string]*schema.AttributeSchema{
+						"command": {
+							IsOptional: true,
+							Constraint: schema.AnyExpression{OfType: cty.List(cty.String)},
+						},
+					},
+					Blocks: map[string]*schema.BlockSchema{},
+				},
+				MinItems: 1,
+				MaxItems: 3,
+			},
+			"volume": {
+				Labels: []*schema.LabelSchema{
+					{Name: "kind"},
+					{Name: "name"},
+				},
+				Type: schema.BlockTypeSet,
+				Body: &schema.BodySchema{
+					Attributes: map[string]*schema.AttributeSchema{},
+					Blocks:     map[string]*schema.BlockSchema{},
+				},
+			},
+			"service": {
+				Labels: []*schema.LabelSchema{
+					{Name: "name"},
+				},
+				Type: schema.BlockTypeMap,
+				Body: &schema.BodySchema{
+					Attributes: map[string]*schema.AttributeSchema{},
+					Blocks: map[string]*schema.BlockSchema{
+						"check": {
+							Labels: []*schema.LabelSchema{},
+							Type:   schema.BlockTypeList,
+							Body: &schema.BodySchema{
+								Attributes: map[string]*schema.AttributeSchema{},
+								Blocks:     map[string]*schema.BlockSchema{},
+							},
+						},
+					},
+				},
+			},
+			"env": {
+				Type: schema.BlockTypeObject,
+				Body: &schema.BodySchema{
+					AnyAttribute: &schema.AttributeSchema{
+						IsOptional: true,
+						Constraint: schema.AnyExpression{OfType: cty.String},
+					},
+				},
+				MaxItems: 1,
+			},
+		},
+	}
+
+	if diff := cmp.Diff(expectedSchema, bodySchema, ctydebug.CmpOptions); diff != "" {
+		t.Fatalf("unexpected schema: %s", diff)
+	}
+
+	err = bodySchema.Validate()
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestFromSpec_literalTypesOnly(t *testing.T) {
+	spec := &hcldec.AttrSpec{
+		Name: "name",
+		Type: cty.String,
+	}
+
+	bodySchema, err := FromSpec(spec, Options{LiteralTypesOnly: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectedSchema := &schema.BodySchema{
+		Attributes: map[string]*schema.AttributeSchema{
+			"name": {
+				IsOptional: true,
+				Constraint: schema.LiteralType{Type: cty.String},
+			},
+		},
+		Blocks: map[string]*schema.BlockSchema{},
+	}
+
+	if diff := cmp.Diff(expectedSchema, bodySchema, ctydebug.CmpOptions); diff != "" {
+		t.Fatalf("unexpected schema: %s", diff)
+	}
+}
+
+func TestFromSpec_conflict(t *testing.T) {
+	spec := hcldec.TupleSpec{
+		&hcldec.AttrSpec{Name: "foo", Type: cty.String},
+		&hcldec.BlockSpec{TypeName: "foo"},
+	}
+
+	_, err := FromSpec(spec, Options{})
+	if err == nil {
+		t.Fatal("expected error")
+	}
+
+	expectedErr := `"foo" is declared as both attribute and block`
+	if err.Error() != expectedErr {
+		t.Fatalf("unexpected error: %s", err)
+	}
+}
@@ -0,0 +1,524 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package schema
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/hcl-lang/lang"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/zclconf/go-cty/cty"
+	ctyjson "github.com/zclconf/go-cty/cty/json"
+)
+
+// JSONFormatVersion represents the version of the JSON representation
+// of the schema, as produced by MarshalBodySchema.
+//
+// The major version is incremented on any breaking change
+// of the representation, the minor version when it is extended
+// in a backwards-compatible way.
+const JSONFormatVersion = "1.0"
+
+type bodySchemaEnvelopeJSON struct {
+	FormatVersion string          `json:"format_version"`
+	Schema        json.RawMessage `json:"schema"`
+}
+
+// MarshalBodySchema returns the JSON representation of the body schema
+// wrapped in a versioned envelope, such that it can be cached
+// or shared with other processes and decoded via UnmarshalBodySchema.
+//
+// Values which cannot be represented in JSON, such as unknown
+// or marked values, result in an error. Empty collections are
+// represented the same way as nil ones and decoded as nil.
+func MarshalBodySchema(bs *BodySchema) ([]byte, error) {
+	b, err := json.Marshal(bs)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(bodySchemaEnvelopeJSON{
+		FormatVersion: JSONFormatVersion,
+		Schema:        b,
+	})
+}
+
+// UnmarshalBodySchema decodes the body schema from its JSON representation
+// as produced by MarshalBodySchema.
+//
+// Representations of any format version with the same major version
+// as JSONFormatVersion are accepted.
+func UnmarshalBodySchema(b []byte) (*BodySchema, error) {
+	var envelope bodySchemaEnvelopeJSON
+	err := json.Unmarshal(b, &envelope)
+	if err != nil {
+		return nil, err
+	}
+
+	if !isSupportedJSONFormatVersion(envelope.FormatVersion) {
+		return nil, fmt.Errorf("unsupported format version %q, expected %q",
+			envelope.FormatVersion, JSONFormatVersion)
+	}
+
+	if isNullJSON(envelope.Schema) {
+		return nil, nil
+	}
+
+	var bs BodySchema
+	err = json.Unmarshal(envelope.Schema, &bs)
+	if err != nil {
+		return nil, err
+	}
+	return &bs, nil
+}
+
+func isSupportedJSONFormatVersion(version string) bool {
+	major, _, _ := strings.Cut(version, ".")
+	supportedMajor, _, _ := strings.Cut(JSONFormatVersion, ".")
+	return version != "" && major == supportedMajor
+}
+
+func isNullJSON(b json.RawMessage) bool {
+	return len(b) == 0 || bytes.Equal(b, []byte("null"))
+}
+
+type markupJSON struct {
+	Value string `json:"value"`
+	Kind  string `json:"kind,omitempty"`
+}
+
+func newMarkupJSON(mc lang.MarkupContent) *markupJSON {
+	if mc == (lang.MarkupContent{}) {
+		return nil
+	}
+
+	mj := &markupJSON{Value: mc.Value}
+	switch mc.Kind {
+	case lang.PlainTextKind:
+		mj.Kind = "plaintext"
+	case lang.MarkdownKind:
+		mj.Kind = "markdown"
+	}
+	return mj
+}
+
+func (mj *markupJSON) markupContent() (lang.MarkupContent, error) {
+	if mj == nil {
+		return lang.MarkupContent{}, nil
+	}
+
+	mc := lang.MarkupContent{Value: mj.Value}
+	switch mj.Kind {
+	case "":
+		mc.Kind = lang.NilKind
+	case "plaintext":
+		mc.Kind = lang.PlainTextKind
+	case "markdown":
+		mc.Kind = lang.MarkdownKind
+	default:
+		return lang.MarkupContent{}, fmt.Errorf("unknown markup kind %q", mj.Kind)
+	}
+	return mc, nil
+}
+
+type versionInfoJSON struct {
+	IntroducedIn string `json:"introduced_in,omitempty"`
+	DeprecatedIn string `json:"deprecated_in,omitempty"`
+	RemovedIn    string `json:"removed_in,omitempty"`
+}
+
+func newVersionInfoJSON(vi VersionInfo) *versionInfoJSON {
+	if vi == (VersionInfo{}) {
+		return nil
+	}
+
+	vj := &versionInfoJSON{}
+	if !vi.IntroducedIn.IsZero() {
+		vj.IntroducedIn = vi.IntroducedIn.String()
+	}
+	if !vi.DeprecatedIn.IsZero() {
+		vj.DeprecatedIn = vi.DeprecatedIn.String()
+	}
+	if !vi.RemovedIn.IsZero() {
+		vj.RemovedIn = vi.RemovedIn.String()
+	}
+	return vj
+}
+
+func (vj *versionInfoJSON) versionInfo() (VersionInfo, error) {
+	if vj == nil {
+		return VersionInfo{}, nil
+	}
+
+	var vi VersionInfo
+	var err error
+	for _, v := range []struct {
+		raw    string
+		target *Version
+		name   string
+	}{
+		{vj.IntroducedIn, &vi.IntroducedIn, "IntroducedIn"},
+		{vj.DeprecatedIn, &vi.DeprecatedIn, "DeprecatedIn"},
+		{vj.RemovedIn, &vi.RemovedIn, "RemovedIn"},
+	} {
+		if v.raw == "" {
+			continue
+		}
+		*v.target, err = ParseVersion(v.raw)
+		if err != nil {
+			return VersionInfo{}, fmt.Errorf("%s: %w", v.name, err)
+		}
+	}
+	return vi, nil
+}
+
+func marshalType(typ cty.Type) (json.RawMessage, error) {
+	if typ == cty.NilType {
+		return nil, nil
+	}
+	return typ.MarshalJSON()
+}
+
+func unmarshalType(b json.RawMessage) (cty.Type, error) {
+	if isNullJSON(b) {
+		return cty.NilType, nil
+	}
+	var typ cty.Type
+	err := typ.UnmarshalJSON(b)
+	if err != nil {
+		return cty.NilType, err
+	}
+	return typ, nil
+}
+
+type valueJSON struct {
+	Type  json.RawMessage `json:"type"`
+	Value json.RawMessage `json:"value"`
+}
+
+func newValueJSON(val cty.Value) (*valueJSON, error) {
+	if val.Type() == cty.NilType {
+		return nil, nil
+	}
+
+	typ, err := val.Type().MarshalJSON()
+	if err != nil {
+		return nil, err
+	}
+	value, err := ctyjson.Marshal(val, val.Type())
+	if err != nil {
+		return nil, err
+	}
+
+	return &valueJSON{
+		Type:  typ,
+		Value: value,
+	}, nil
+}
+
+func (vj *valueJSON) value() (cty.Value, error) {
+	if vj == nil {
+		return cty.NilVal, nil
+	}
+
+	typ, err := unmarshalType(vj.Type)
+	if err != nil {
+		return cty.NilVal, err
+	}
+	return ctyjson.Unmarshal(vj.Value, typ)
+}
+
+type addressStepJSON struct {
+	Type string     `json:"type"`
+	Name string     `json:"name,omitempty"`
+	Key  *valueJSON `json:"key,omitempty"`
+}
+
+func newAddressJSON(addr lang.Address) ([]addressStepJSON, error) {
+	if len(addr) == 0 {
+		return nil, nil
+	}
+
+	steps := make([]addressStepJSON, 0, len(addr))
+	for _, step := range addr {
+		switch s := step.(type) {
+		case lang.RootStep:
+			steps = append(steps, addressStepJSON{Type: "root", Name: s.Name})
+		case lang.AttrStep:
+			steps = append(steps, addressStepJSON{Type: "attr", Name: s.Name})
+		case lang.IndexStep:
+			key, err := newValueJSON(s.Key)
+			if err != nil {
+				return nil, err
+			}
+			steps = append(steps, addressStepJSON{Type: "index", Key: key})
+		default:
+			return nil, fmt.Errorf("unsupported address step: %T", step)
+		}
+	}
+	return steps, nil
+}
+
+func addressFromJSON(steps []addressStepJSON) (lang.Address, error) {
+	if len(steps) == 0 {
+		return nil, nil
+	}
+
+	addr := make(lang.Address, 0, len(steps))
+	for _, step := range steps {
+		switch step.Type {
+		case "root":
+			addr = append(addr, lang.RootStep{Name: step.Name})
+		case "attr":
+			addr = append(addr, lang.AttrStep{Name: step.Name})
+		case "index":
+			key, err := step.Key.value()
+			if err != nil {
+				return nil, err
+			}
+			addr = append(addr, lang.IndexStep{Key: key})
+		default:
+			return nil, fmt.Errorf("unknown address step type %q", step.Type)
+		}
+	}
+	return addr, nil
+}
+
+type addrStepJSON struct {
+	Type       string `json:"type"`
+	Name       string `json:"name,omitempty"`
+	Index      uint   `json:"index,omitempty"`
+	IsOptional bool   `json:"is_optional,omitempty"`
+}
+
+func newSchemaAddressJSON(addr Address) ([]addrStepJSON, error) {
+	if len(addr) == 0 {
+		return nil, nil
+	}
+
+	steps := make([]addrStepJSON, 0, len(addr))
+	for _, step := range addr {
+		switch s := step.(type) {
+		case StaticStep:
+			steps = append(steps, addrStepJSON{Type: "static", Name: s.Name})
+		case LabelStep:
+			steps = append(steps, addrStepJSON{Type: "label", Index: s.Index})
+		case AttrNameStep:
+			steps = append(steps, addrStepJSON{Type: "attr_name"})
+		case AttrValueStep:
+			steps = append(steps, addrStepJSON{Type: "attr_value", Name: s.Name, IsOptional: s.IsOptional})
+		default:
+			return nil, fmt.Errorf("unsupported address step: %T", step)
+		}
+	}
+	return steps, nil
+}
+
+func schemaAddressFromJSON(steps []addrStepJSON) (Address, error) {
+	if len(steps) == 0 {
+		return nil, nil
+	}
+
+	addr := make(Address, 0, len(steps))
+	for _, step := range steps {
+		switch step.Type {
+		case "static":
+			addr = append(addr, StaticStep{Name: step.Name})
+		case "label":
+			addr = append(addr, LabelStep{Index: step.Index})
+		case "attr_name":
+			addr = append(addr, AttrNameStep{})
+		case "attr_value":
+			addr = append(addr, AttrValueStep{Name: step.Name, IsOptional: step.IsOptional})
+		default:
+			return nil, fmt.Errorf("unknown address step type %q", step.Type)
+		}
+	}
+	return addr, nil
+}
+
+type pathJSON struct {
+	Path       string `json:"path,omitempty"`
+	LanguageID string `json:"language_id,omitempty"`
+}
+
+func newPathJSON(path lang.Path) pathJSON {
+	return pathJSON{
+		Path:       path.Path,
+		LanguageID: path.LanguageID,
+	}
+}
+
+func (pj pathJSON) path() lang.Path {
+	return lang.Path{
+		Path:       pj.Path,
+		LanguageID: pj.LanguageID,
+	}
+}
+
+type posJSON struct {
+	Line   int `json:"line"`
+	Column int `json:"column"`
+	Byte   int `json:"byte"`
+}
+
+type rangeJSON struct {
+	Filename string  `json:"filename,omitempty"`
+	Start    posJSON `json:"start"`
+	End      posJSON `json:"end"`
+}
+
+func newRangeJSON(rng hcl.Range) rangeJSON {
+	return rangeJSON{
+		Filename: rng.Filename,
+		Start:    posJSON(rng.Start),
+		End:      posJSON(rng.End),
+	}
+}
+
+func (rj rangeJSON) rng() hcl.Range {
+	return hcl.Range{
+		Filename: rj.Filename,
+		Start:    hcl.Pos(rj.Start),
+		End:      hcl.Pos(rj.End),
+	}
+}
+
+type constraintsJSON struct {
+	ScopeId lang.ScopeId    `json:"scope_id,omitempty"`
+	Type    json.RawMessage `json:"type,omitempty"`
+}
+
+func newConstraintsJSON(c Constraints) (constraintsJSON, error) {
+	typ, err := marshalType(c.Type)
+	if err != nil {
+		return constraintsJSON{}, err
+	}
+	return constraintsJSON{
+		ScopeId: c.ScopeId,
+		Type:    typ,
+	}, nil
+}
+
+func (cj constraintsJSON) constraints() (Constraints, error) {
+	typ, err := unmarshalType(cj.Type)
+	if err != nil {
+		return Constraints{}, err
+	}
+	return Constraints{
+		ScopeId: cj.ScopeId,
+		Type:    typ,
+	}, nil
+}
+
+type defaultJSON struct {
+	Type            string            `json:"type"`
+	Value           *valueJSON        `json:"value,omitempty"`
+	Keyword         string            `json:"keyword,omitempty"`
+	TypeDeclaration json.RawMessage   `json:"type_declaration,omitempty"`
+	Name            string            `json:"name,omitempty"`
+	Cases           []defaultCaseJSON `json:"cases,omitempty"`
+	Fallback        *defaultJSON      `json:"fallback,omitempty"`
+}
+
+type defaultCaseJSON struct {
+	Value   *valueJSON   `json:"value"`
+	Default *defaultJSON `json:"default"`
+}
+
+func newDefaultJSON(d Default) (*defaultJSON, error) {
+	switch dv := d.(type) {
+	case nil:
+		return nil, nil
+	case DefaultValue:
+		value, err := newValueJSON(dv.Value)
+		if err != nil {
+			return nil, err
+		}
+		return &defaultJSON{Type: "value", Value: value}, nil
+	case DefaultKeyword:
+		return &defaultJSON{Type: "keyword", Keyword: dv.Keyword}, nil
+	case DefaultTypeDeclaration:
+		typ, err := marshalType(dv.Type)
+		if err != nil {
+			return nil, err
+		}
+		return &defaultJSON{Type: "type_declaration", TypeDeclaration: typ}, nil
+	case DefaultAttributeDependent:
+		fallback, err := newDefaultJSON(dv.Fallback)
+		if err != nil {
+			return nil, fmt.Errorf("Fallback: %w", err)
+		}
+		dj := &defaultJSON{Type: "attribute_dependent", Name: dv.Name, Fallback: fallback}
+		for i, dc := range dv.Cases {
+			value, err := newValueJSON(dc.Value)
+			if err != nil {
+				return nil, fmt.Errorf("Cases[%d]: %w", i, err)
+			}
+			def, err := newDefaultJSON(dc.Default)
+			if err != nil {
+				return nil, fmt.Errorf("Cases[%d]: %w", i, err)
+			}
+			dj.Cases = append(dj.Cases, defaultCaseJSON{Value: value, Default: def})
+		}
+		return dj, nil
+	case DefaultEnvVar:
+		fallback, err := newDefaultJSON(dv.Fallback)
+		if err != nil {
+			return nil, fmt.Errorf("Fallback: %w", err)
+		}
+		return &defaultJSON{Type: "env_var", Name: dv.Name, Fallback: fallback}, nil
+	}
+	return nil, fmt.Errorf("unsupported default: %T", d)
+}
+
+func (dj *defaultJSON) defaultValue() (Default, error) {
+	if dj == nil {
+		return nil, nil
+	}
+
+	switch dj.Type {
+	case "value":
+		value, err := dj.Value.value()
+		if err != nil {
+			return nil, err
+		}
+		return DefaultValue{Value: value}, nil
+	case "keyword":
+		return DefaultKeyword{Keyword: dj.Keyword}, nil
+	case "type_declaration":
+		typ, err := unmarshalType(dj.TypeDeclaration)
+		if err != nil {
+			return nil, err
+		}
+		return DefaultTypeDeclaration{Type: typ}, nil
+	case "attribute_dependent":
+		fallback, err := dj.Fallback.defaultValue()
+		if err != nil {
+			return nil, fmt.Errorf("Fallback: %w", err)
+		}
+		dad := DefaultAttributeDependent{Name: dj.Name, Fallback: fallback}
+		for i, dcj := range dj.Cases {
+			value, err := dcj.Value.value()
+			if err != nil {
+				return nil, fmt.Errorf("Cases[%d]: %w", i, err)
+			}
+			def, err := dcj.Default.defaultValue()
+			if err != nil {
+				return nil, fmt.Errorf("Cases[%d]: %w", i, err)
+			}
+			dad.Cases = append(dad.Cases, DefaultCase{Value: value, Default: def})
+		}
+		return dad, nil
+	case "env_var":
+		fallback, err := dj.Fallback.defaultValue()
+		if err != nil {
+			return nil, fmt.Errorf("Fallback: %w", err)
+		}
+		return DefaultEnvVar{Name: dj.Name, Fallback: fallback}, nil
+	}
+	return nil, fmt.Errorf("unknown default type %q", dj.Type)
+}
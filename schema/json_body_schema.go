@@ -0,0 +1,673 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package schema
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/hashicorp/hcl-lang/lang"
+)
+
+type bodySchemaJSON struct {
+	Blocks         map[string]*BlockSchema     `json:"blocks,omitempty"`
+	Attributes     map[string]*AttributeSchema `json:"attributes,omitempty"`
+	AnyAttribute   *AttributeSchema            `json:"any_attribute,omitempty"`
+	IsDeprecated   bool                        `json:"is_deprecated,omitempty"`
+	Detail         string                      `json:"detail,omitempty"`
+	Description    *markupJSON                 `json:"description,omitempty"`
+	DocsLink       *docsLinkJSON               `json:"docs_link,omitempty"`
+	HoverURL       string                      `json:"hover_url,omitempty"`
+	TargetableAs   []*targetableJSON           `json:"targetable_as,omitempty"`
+	Targets        *targetJSON                 `json:"targets,omitempty"`
+	ImpliedOrigins []impliedOriginJSON         `json:"implied_origins,omitempty"`
+	Extensions     *bodyExtensionsJSON         `json:"extensions,omitempty"`
+	AttributeOrder []string                    `json:"attribute_order,omitempty"`
+}
+
+type docsLinkJSON struct {
+	URL     string `json:"url"`
+	Tooltip string `json:"tooltip,omitempty"`
+}
+
+type targetJSON struct {
+	Path  pathJSON  `json:"path"`
+	Range rangeJSON `json:"range"`
+}
+
+type impliedOriginJSON struct {
+	OriginAddress []addressStepJSON `json:"origin_address,omitempty"`
+	TargetAddress []addressStepJSON `json:"target_address,omitempty"`
+	Path          pathJSON          `json:"path"`
+	Constraints   constraintsJSON   `json:"constraints"`
+}
+
+type bodyExtensionsJSON struct {
+	Count         bool `json:"count,omitempty"`
+	ForEach       bool `json:"for_each,omitempty"`
+	DynamicBlocks bool `json:"dynamic_blocks,omitempty"`
+	SelfRefs      bool `json:"self_refs,omitempty"`
+}
+
+type targetableJSON struct {
+	Address           []addressStepJSON `json:"address,omitempty"`
+	ScopeId           lang.ScopeId      `json:"scope_id,omitempty"`
+	AsType            json.RawMessage   `json:"as_type,omitempty"`
+	IsSensitive       bool              `json:"is_sensitive,omitempty"`
+	FriendlyName      string            `json:"friendly_name,omitempty"`
+	Description       *markupJSON       `json:"description,omitempty"`
+	NestedTargetables []*targetableJSON `json:"nested_targetables,omitempty"`
+}
+
+func (bs *BodySchema) MarshalJSON() ([]byte, error) {
+	bj := bodySchemaJSON{
+		Blocks:         bs.Blocks,
+		Attributes:     bs.Attributes,
+		AnyAttribute:   bs.AnyAttribute,
+		IsDeprecated:   bs.IsDeprecated,
+		Detail:         bs.Detail,
+		Description:    newMarkupJSON(bs.Description),
+		HoverURL:       bs.HoverURL,
+		AttributeOrder: bs.AttributeOrder,
+	}
+
+	if bs.DocsLink != nil {
+		bj.DocsLink = &docsLinkJSON{
+			URL:     bs.DocsLink.URL,
+			Tooltip: bs.DocsLink.Tooltip,
+		}
+	}
+
+	var err error
+	bj.TargetableAs, err = newTargetablesJSON(bs.TargetableAs)
+	if err != nil {
+		return nil, fmt.Errorf("TargetableAs: %w", err)
+	}
+
+	if bs.Targets != nil {
+		bj.Targets = &targetJSON{
+			Path:  newPathJSON(bs.Targets.Path),
+			Range: newRangeJSON(bs.Targets.Range),
+		}
+	}
+
+	if len(bs.ImpliedOrigins) > 0 {
+		bj.ImpliedOrigins = make([]impliedOriginJSON, 0, len(bs.ImpliedOrigins))
+		for _, io := range bs.ImpliedOrigins {
+			ioj, err := newImpliedOriginJSON(io)
+			if err != nil {
+				return nil, fmt.Errorf("ImpliedOrigins: %w", err)
+			}
+			bj.ImpliedOrigins = append(bj.ImpliedOrigins, ioj)
+		}
+	}
+
+	if bs.Extensions != nil {
+		bj.Extensions = &bodyExtensionsJSON{
+			Count:         bs.Extensions.Count,
+			ForEach:       bs.Extensions.ForEach,
+			DynamicBlocks: bs.Extensions.DynamicBlocks,
+			SelfRefs:      bs.Extensions.SelfRefs,
+		}
+	}
+
+	return json.Marshal(bj)
+}
+
+func (bs *BodySchema) UnmarshalJSON(b []byte) error {
+	var bj bodySchemaJSON
+	err := json.Unmarshal(b, &bj)
+	if err != nil {
+		return err
+	}
+
+	description, err := bj.Description.markupContent()
+	if err != nil {
+		return fmt.Errorf("Description: %w", err)
+	}
+
+	newBs := BodySchema{
+		Blocks:         bj.Blocks,
+		Attributes:     bj.Attributes,
+		AnyAttribute:   bj.AnyAttribute,
+		IsDeprecated:   bj.IsDeprecated,
+		Detail:         bj.Detail,
+		Description:    description,
+		HoverURL:       bj.HoverURL,
+		AttributeOrder: bj.AttributeOrder,
+	}
+
+	if bj.DocsLink != nil {
+		newBs.DocsLink = &DocsLink{
+			URL:     bj.DocsLink.URL,
+			Tooltip: bj.DocsLink.Tooltip,
+		}
+	}
+
+	newBs.TargetableAs, err = targetablesFromJSON(bj.TargetableAs)
+	if err != nil {
+		return fmt.Errorf("TargetableAs: %w", err)
+	}
+
+	if bj.Targets != nil {
+		newBs.Targets = &Target{
+			Path:  bj.Targets.Path.path(),
+			Range: bj.Targets.Range.rng(),
+		}
+	}
+
+	if len(bj.ImpliedOrigins) > 0 {
+		newBs.ImpliedOrigins = make(ImpliedOrigins, 0, len(bj.ImpliedOrigins))
+		for _, ioj := range bj.ImpliedOrigins {
+			io, err := ioj.impliedOrigin()
+			if err != nil {
+				return fmt.Errorf("ImpliedOrigins: %w", err)
+			}
+			newBs.ImpliedOrigins = append(newBs.ImpliedOrigins, io)
+		}
+	}
+
+	if bj.Extensions != nil {
+		newBs.Extensions = &BodyExtensions{
+			Count:         bj.Extensions.Count,
+			ForEach:       bj.Extensions.ForEach,
+			DynamicBlocks: bj.Extensions.DynamicBlocks,
+			SelfRefs:      bj.Extensions.SelfRefs,
+		}
+	}
+
+	*bs = newBs
+	return nil
+}
+
+func newImpliedOriginJSON(io ImpliedOrigin) (impliedOriginJSON, error) {
+	originAddr, err := newAddressJSON(io.OriginAddress)
+	if err != nil {
+		return impliedOriginJSON{}, err
+	}
+	targetAddr, err := newAddressJSON(io.TargetAddress)
+	if err != nil {
+		return impliedOriginJSON{}, err
+	}
+	constraints, err := newConstraintsJSON(io.Constraints)
+	if err != nil {
+		return impliedOriginJSON{}, err
+	}
+
+	return impliedOriginJSON{
+		OriginAddress: originAddr,
+		TargetAddress: targetAddr,
+		Path:          newPathJSON(io.Path),
+		Constraints:   constraints,
+	}, nil
+}
+
+func (ioj impliedOriginJSON) impliedOrigin() (ImpliedOrigin, error) {
+	originAddr, err := addressFromJSON(ioj.OriginAddress)
+	if err != nil {
+		return ImpliedOrigin{}, err
+	}
+	targetAddr, err := addressFromJSON(ioj.TargetAddress)
+	if err != nil {
+		return ImpliedOrigin{}, err
+	}
+	constraints, err := ioj.Constraints.constraints()
+	if err != nil {
+		return ImpliedOrigin{}, err
+	}
+
+	return ImpliedOrigin{
+		OriginAddress: originAddr,
+		TargetAddress: targetAddr,
+		Path:          ioj.Path.path(),
+		Constraints:   constraints,
+	}, nil
+}
+
+func newTargetablesJSON(ts Targetables) ([]*targetableJSON, error) {
+	if len(ts) == 0 {
+		return nil, nil
+	}
+
+	tjs := make([]*targetableJSON, 0, len(ts))
+	for _, tb := range ts {
+		if tb == nil {
+			tjs = append(tjs, nil)
+			continue
+		}
+
+		addr, err := newAddressJSON(tb.Address)
+		if err != nil {
+			return nil, err
+		}
+		asType, err := marshalType(tb.AsType)
+		if err != nil {
+			return nil, err
+		}
+		nested, err := newTargetablesJSON(tb.NestedTargetables)
+		if err != nil {
+			return nil, err
+		}
+
+		tjs = append(tjs, &targetableJSON{
+			Address:           addr,
+			ScopeId:           tb.ScopeId,
+			AsType:            asType,
+			IsSensitive:       tb.IsSensitive,
+			FriendlyName:      tb.FriendlyName,
+			Description:       newMarkupJSON(tb.Description),
+			NestedTargetables: nested,
+		})
+	}
+	return tjs, nil
+}
+
+func targetablesFromJSON(tjs []*targetableJSON) (Targetables, error) {
+	if len(tjs) == 0 {
+		return nil, nil
+	}
+
+	ts := make(Targetables, 0, len(tjs))
+	for _, tj := range tjs {
+		if tj == nil {
+			ts = append(ts, nil)
+			continue
+		}
+
+		addr, err := addressFromJSON(tj.Address)
+		if err != nil {
+			return nil, err
+		}
+		asType, err := unmarshalType(tj.AsType)
+		if err != nil {
+			return nil, err
+		}
+		description, err := tj.Description.markupContent()
+		if err != nil {
+			return nil, err
+		}
+		nested, err := targetablesFromJSON(tj.NestedTargetables)
+		if err != nil {
+			return nil, err
+		}
+
+		ts = append(ts, &Targetable{
+			Address:           addr,
+			ScopeId:           tj.ScopeId,
+			AsType:            asType,
+			IsSensitive:       tj.IsSensitive,
+			FriendlyName:      tj.FriendlyName,
+			Description:       description,
+			NestedTargetables: nested,
+		})
+	}
+	return ts, nil
+}
+
+type blockSchemaJSON struct {
+	Labels                 []*LabelSchema              `json:"labels,omitempty"`
+	Type                   string                      `json:"type,omitempty"`
+	SemanticTokenModifiers lang.SemanticTokenModifiers `json:"semantic_token_modifiers,omitempty"`
+	SymbolKind             lang.SymbolKind             `json:"symbol_kind,omitempty"`
+	Body                   *BodySchema                 `json:"body,omitempty"`
+	DependentBody          map[SchemaKey]*BodySchema   `json:"dependent_body,omitempty"`
+	Description            *markupJSON                 `json:"description,omitempty"`
+	IsDeprecated           bool                        `json:"is_deprecated,omitempty"`
+	MinItems               uint64                      `json:"min_items,omitempty"`
+	MaxItems               uint64                      `json:"max_items,omitempty"`
+	Versions               *versionInfoJSON            `json:"versions,omitempty"`
+	Address                *blockAddrSchemaJSON        `json:"address,omitempty"`
+}
+
+type blockAddrSchemaJSON struct {
+	Steps                []addrStepJSON     `json:"steps,omitempty"`
+	FriendlyName         string             `json:"friendly_name,omitempty"`
+	ScopeId              lang.ScopeId       `json:"scope_id,omitempty"`
+	AsReference          bool               `json:"as_reference,omitempty"`
+	BodyAsData           bool               `json:"body_as_data,omitempty"`
+	InferBody            bool               `json:"infer_body,omitempty"`
+	BodySelfRef          bool               `json:"body_self_ref,omitempty"`
+	AsTypeOf             *blockAsTypeOfJSON `json:"as_type_of,omitempty"`
+	DependentBodyAsData  bool               `json:"dependent_body_as_data,omitempty"`
+	InferDependentBody   bool               `json:"infer_dependent_body,omitempty"`
+	DependentBodySelfRef bool               `json:"dependent_body_self_ref,omitempty"`
+}
+
+type blockAsTypeOfJSON struct {
+	AttributeExpr string `json:"attribute_expr,omitempty"`
+}
+
+func (bSchema *BlockSchema) MarshalJSON() ([]byte, error) {
+	bj := blockSchemaJSON{
+		Labels:                 bSchema.Labels,
+		Type:                   bSchema.Type.String(),
+		SemanticTokenModifiers: bSchema.SemanticTokenModifiers,
+		SymbolKind:             bSchema.SymbolKind,
+		Body:                   bSchema.Body,
+		DependentBody:          bSchema.DependentBody,
+		Description:            newMarkupJSON(bSchema.Description),
+		IsDeprecated:           bSchema.IsDeprecated,
+		MinItems:               bSchema.MinItems,
+		MaxItems:               bSchema.MaxItems,
+		Versions:               newVersionInfoJSON(bSchema.Versions),
+	}
+
+	if bSchema.Type != BlockTypeNil && bj.Type == "" {
+		return nil, fmt.Errorf("Type: unsupported block type %#v", bSchema.Type)
+	}
+
+	if bSchema.Address != nil {
+		addr := bSchema.Address
+		steps, err := newSchemaAddressJSON(addr.Steps)
+		if err != nil {
+			return nil, fmt.Errorf("Address: %w", err)
+		}
+		bj.Address = &blockAddrSchemaJSON{
+			Steps:                steps,
+			FriendlyName:         addr.FriendlyName,
+			ScopeId:              addr.ScopeId,
+			AsReference:          addr.AsReference,
+			BodyAsData:           addr.BodyAsData,
+			InferBody:            addr.InferBody,
+			BodySelfRef:          addr.BodySelfRef,
+			DependentBodyAsData:  addr.DependentBodyAsData,
+			InferDependentBody:   addr.InferDependentBody,
+			DependentBodySelfRef: addr.DependentBodySelfRef,
+		}
+		if addr.AsTypeOf != nil {
+			bj.Address.AsTypeOf = &blockAsTypeOfJSON{
+				AttributeExpr: addr.AsTypeOf.AttributeExpr,
+			}
+		}
+	}
+
+	return json.Marshal(bj)
+}
+
+func (bSchema *BlockSchema) UnmarshalJSON(b []byte) error {
+	var bj blockSchemaJSON
+	err := json.Unmarshal(b, &bj)
+	if err != nil {
+		return err
+	}
+
+	blockType, err := blockTypeFromString(bj.Type)
+	if err != nil {
+		return fmt.Errorf("Type: %w", err)
+	}
+	description, err := bj.Description.markupContent()
+	if err != nil {
+		return fmt.Errorf("Description: %w", err)
+	}
+	versions, err := bj.Versions.versionInfo()
+	if err != nil {
+		return fmt.Errorf("Versions: %w", err)
+	}
+
+	newBSchema := BlockSchema{
+		Labels:                 bj.Labels,
+		Type:                   blockType,
+		SemanticTokenModifiers: bj.SemanticTokenModifiers,
+		SymbolKind:             bj.SymbolKind,
+		Body:                   bj.Body,
+		DependentBody:          bj.DependentBody,
+		Description:            description,
+		IsDeprecated:           bj.IsDeprecated,
+		MinItems:               bj.MinItems,
+		MaxItems:               bj.MaxItems,
+		Versions:               versions,
+	}
+
+	if bj.Address != nil {
+		steps, err := schemaAddressFromJSON(bj.Address.Steps)
+		if err != nil {
+			return fmt.Errorf("Address: %w", err)
+		}
+		newBSchema.Address = &BlockAddrSchema{
+			Steps:                steps,
+			FriendlyName:         bj.Address.FriendlyName,
+			ScopeId:              bj.Address.ScopeId,
+			AsReference:          bj.Address.AsReference,
+			BodyAsData:           bj.Address.BodyAsData,
+			InferBody:            bj.Address.InferBody,
+			BodySelfRef:          bj.Address.BodySelfRef,
+			DependentBodyAsData:  bj.Address.DependentBodyAsData,
+			InferDependentBody:   bj.Address.InferDependentBody,
+			DependentBodySelfRef: bj.Address.DependentBodySelfRef,
+		}
+		if bj.Address.AsTypeOf != nil {
+			newBSchema.Address.AsTypeOf = &BlockAsTypeOf{
+				AttributeExpr: bj.Address.AsTypeOf.AttributeExpr,
+			}
+		}
+	}
+
+	*bSchema = newBSchema
+	return nil
+}
+
+func blockTypeFromString(s string) (BlockType, error) {
+	for _, t := range []BlockType{BlockTypeList, BlockTypeMap, BlockTypeObject, BlockTypeSet} {
+		if t.String() == s {
+			return t, nil
+		}
+	}
+	if s == "" {
+		return BlockTypeNil, nil
+	}
+	return BlockTypeNil, fmt.Errorf("unknown block type %q", s)
+}
+
+type labelSchemaJSON struct {
+	Name                   string                      `json:"name"`
+	Description            *markupJSON                 `json:"description,omitempty"`
+	SemanticTokenModifiers lang.SemanticTokenModifiers `json:"semantic_token_modifiers,omitempty"`
+	IsDepKey               bool                        `json:"is_dep_key,omitempty"`
+	Completable            bool                        `json:"completable,omitempty"`
+}
+
+func (ls *LabelSchema) MarshalJSON() ([]byte, error) {
+	return json.Marshal(labelSchemaJSON{
+		Name:                   ls.Name,
+		Description:            newMarkupJSON(ls.Description),
+		SemanticTokenModifiers: ls.SemanticTokenModifiers,
+		IsDepKey:               ls.IsDepKey,
+		Completable:            ls.Completable,
+	})
+}
+
+func (ls *LabelSchema) UnmarshalJSON(b []byte) error {
+	var lj labelSchemaJSON
+	err := json.Unmarshal(b, &lj)
+	if err != nil {
+		return err
+	}
+
+	description, err := lj.Description.markupContent()
+	if err != nil {
+		return fmt.Errorf("Description: %w", err)
+	}
+
+	*ls = LabelSchema{
+		Name:                   lj.Name,
+		Description:            description,
+		SemanticTokenModifiers: lj.SemanticTokenModifiers,
+		IsDepKey:               lj.IsDepKey,
+		Completable:            lj.Completable,
+	}
+	return nil
+}
+
+type attributeSchemaJSON struct {
+	Description            *markupJSON                 `json:"description,omitempty"`
+	IsRequired             bool                        `json:"is_required,omitempty"`
+	IsOptional             bool                        `json:"is_optional,omitempty"`
+	IsDeprecated           bool                        `json:"is_deprecated,omitempty"`
+	IsComputed             bool                        `json:"is_computed,omitempty"`
+	IsSensitive            bool                        `json:"is_sensitive,omitempty"`
+	Constraint             json.RawMessage             `json:"constraint,omitempty"`
+	DefaultValue           *defaultJSON                `json:"default_value,omitempty"`
+	Versions               *versionInfoJSON            `json:"versions,omitempty"`
+	IsDepKey               bool                        `json:"is_dep_key,omitempty"`
+	Address                *attributeAddrSchemaJSON    `json:"address,omitempty"`
+	OriginForTarget        *pathTargetJSON             `json:"origin_for_target,omitempty"`
+	SemanticTokenModifiers lang.SemanticTokenModifiers `json:"semantic_token_modifiers,omitempty"`
+	CompletionHooks        []completionHookJSON        `json:"completion_hooks,omitempty"`
+}
+
+type attributeAddrSchemaJSON struct {
+	Steps        []addrStepJSON `json:"steps,omitempty"`
+	FriendlyName string         `json:"friendly_name,omitempty"`
+	ScopeId      lang.ScopeId   `json:"scope_id,omitempty"`
+	AsExprType   bool           `json:"as_expr_type,omitempty"`
+	AsReference  bool           `json:"as_reference,omitempty"`
+}
+
+type pathTargetJSON struct {
+	Address     []addrStepJSON  `json:"address,omitempty"`
+	Path        pathJSON        `json:"path"`
+	Constraints constraintsJSON `json:"constraints"`
+}
+
+type completionHookJSON struct {
+	Name string `json:"name"`
+}
+
+func (as *AttributeSchema) MarshalJSON() ([]byte, error) {
+	aj := attributeSchemaJSON{
+		Description:            newMarkupJSON(as.Description),
+		IsRequired:             as.IsRequired,
+		IsOptional:             as.IsOptional,
+		IsDeprecated:           as.IsDeprecated,
+		IsComputed:             as.IsComputed,
+		IsSensitive:            as.IsSensitive,
+		Versions:               newVersionInfoJSON(as.Versions),
+		IsDepKey:               as.IsDepKey,
+		SemanticTokenModifiers: as.SemanticTokenModifiers,
+	}
+
+	var err error
+	aj.Constraint, err = marshalConstraint(as.Constraint)
+	if err != nil {
+		return nil, fmt.Errorf("Constraint: %w", err)
+	}
+	aj.DefaultValue, err = newDefaultJSON(as.DefaultValue)
+	if err != nil {
+		return nil, fmt.Errorf("DefaultValue: %w", err)
+	}
+
+	if as.Address != nil {
+		steps, err := newSchemaAddressJSON(as.Address.Steps)
+		if err != nil {
+			return nil, fmt.Errorf("Address: %w", err)
+		}
+		aj.Address = &attributeAddrSchemaJSON{
+			Steps:        steps,
+			FriendlyName: as.Address.FriendlyName,
+			ScopeId:      as.Address.ScopeId,
+			AsExprType:   as.Address.AsExprType,
+			AsReference:  as.Address.AsReference,
+		}
+	}
+
+	if as.OriginForTarget != nil {
+		addr, err := newSchemaAddressJSON(as.OriginForTarget.Address)
+		if err != nil {
+			return nil, fmt.Errorf("OriginForTarget: %w", err)
+		}
+		constraints, err := newConstraintsJSON(as.OriginForTarget.Constraints)
+		if err != nil {
+			return nil, fmt.Errorf("OriginForTarget: %w", err)
+		}
+		aj.OriginForTarget = &pathTargetJSON{
+			Address:     addr,
+			Path:        newPathJSON(as.OriginForTarget.Path),
+			Constraints: constraints,
+		}
+	}
+
+	if len(as.CompletionHooks) > 0 {
+		aj.CompletionHooks = make([]completionHookJSON, 0, len(as.CompletionHooks))
+		for _, hook := range as.CompletionHooks {
+			aj.CompletionHooks = append(aj.CompletionHooks, completionHookJSON{Name: hook.Name})
+		}
+	}
+
+	return json.Marshal(aj)
+}
+
+func (as *AttributeSchema) UnmarshalJSON(b []byte) error {
+	var aj attributeSchemaJSON
+	err := json.Unmarshal(b, &aj)
+	if err != nil {
+		return err
+	}
+
+	newAs := AttributeSchema{
+		IsRequired:             aj.IsRequired,
+		IsOptional:             aj.IsOptional,
+		IsDeprecated:           aj.IsDeprecated,
+		IsComputed:             aj.IsComputed,
+		IsSensitive:            aj.IsSensitive,
+		IsDepKey:               aj.IsDepKey,
+		SemanticTokenModifiers: aj.SemanticTokenModifiers,
+	}
+
+	newAs.Description, err = aj.Description.markupContent()
+	if err != nil {
+		return fmt.Errorf("Description: %w", err)
+	}
+	newAs.Constraint, err = unmarshalConstraint(aj.Constraint)
+	if err != nil {
+		return fmt.Errorf("Constraint: %w", err)
+	}
+	newAs.DefaultValue, err = aj.DefaultValue.defaultValue()
+	if err != nil {
+		return fmt.Errorf("DefaultValue: %w", err)
+	}
+	newAs.Versions, err = aj.Versions.versionInfo()
+	if err != nil {
+		return fmt.Errorf("Versions: %w", err)
+	}
+
+	if aj.Address != nil {
+		steps, err := schemaAddressFromJSON(aj.Address.Steps)
+		if err != nil {
+			return fmt.Errorf("Address: %w", err)
+		}
+		newAs.Address = &AttributeAddrSchema{
+			Steps:        steps,
+			FriendlyName: aj.Address.FriendlyName,
+			ScopeId:      aj.Address.ScopeId,
+			AsExprType:   aj.Address.AsExprType,
+			AsReference:  aj.Address.AsReference,
+		}
+	}
+
+	if aj.OriginForTarget != nil {
+		addr, err := schemaAddressFromJSON(aj.OriginForTarget.Address)
+		if err != nil {
+			return fmt.Errorf("OriginForTarget: %w", err)
+		}
+		constraints, err := aj.OriginForTarget.Constraints.constraints()
+		if err != nil {
+			return fmt.Errorf("OriginForTarget: %w", err)
+		}
+		newAs.OriginForTarget = &PathTarget{
+			Address:     addr,
+			Path:        aj.OriginForTarget.Path.path(),
+			Constraints: constraints,
+		}
+	}
+
+	if len(aj.CompletionHooks) > 0 {
+		newAs.CompletionHooks = make(lang.CompletionHooks, 0, len(aj.CompletionHooks))
+		for _, hook := range aj.CompletionHooks {
+			newAs.CompletionHooks = append(newAs.CompletionHooks, lang.CompletionHook{Name: hook.Name})
+		}
+	}
+
+	*as = newAs
+	return nil
+}
@@ -0,0 +1,623 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package schema
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/hashicorp/hcl-lang/lang"
+)
+
+// Constraints are represented in JSON as objects
+// with a "type" discriminator of the following values
+const (
+	anyExpressionJSONType   = "any_expression"
+	keywordJSONType         = "keyword"
+	listJSONType            = "list"
+	literalTypeJSONType     = "literal_type"
+	literalValueJSONType    = "literal_value"
+	mapJSONType             = "map"
+	objectJSONType          = "object"
+	oneOfJSONType           = "one_of"
+	referenceJSONType       = "reference"
+	setJSONType             = "set"
+	tupleJSONType           = "tuple"
+	typeDeclarationJSONType = "type_declaration"
+)
+
+func marshalConstraint(c Constraint) (json.RawMessage, error) {
+	if c == nil {
+		return nil, nil
+	}
+	return json.Marshal(c)
+}
+
+func marshalConstraints(cons []Constraint) ([]json.RawMessage, error) {
+	if len(cons) == 0 {
+		return nil, nil
+	}
+
+	b := make([]json.RawMessage, 0, len(cons))
+	for i, c := range cons {
+		cb, err := marshalConstraint(c)
+		if err != nil {
+			return nil, fmt.Errorf("[%d]: %w", i, err)
+		}
+		if cb == nil {
+			cb = json.RawMessage("null")
+		}
+		b = append(b, cb)
+	}
+	return b, nil
+}
+
+// unmarshalConstraint decodes a constraint
+// based on its type discriminator
+func unmarshalConstraint(b json.RawMessage) (Constraint, error) {
+	if isNullJSON(b) {
+		return nil, nil
+	}
+
+	var discriminator struct {
+		Type string `json:"type"`
+	}
+	err := json.Unmarshal(b, &discriminator)
+	if err != nil {
+		return nil, err
+	}
+
+	var con interface {
+		Constraint
+		json.Unmarshaler
+	}
+	switch discriminator.Type {
+	case anyExpressionJSONType:
+		con = &AnyExpression{}
+	case keywordJSONType:
+		con = &Keyword{}
+	case listJSONType:
+		con = &List{}
+	case literalTypeJSONType:
+		con = &LiteralType{}
+	case literalValueJSONType:
+		con = &LiteralValue{}
+	case mapJSONType:
+		con = &Map{}
+	case objectJSONType:
+		con = &Object{}
+	case oneOfJSONType:
+		con = &OneOf{}
+	case referenceJSONType:
+		con = &Reference{}
+	case setJSONType:
+		con = &Set{}
+	case tupleJSONType:
+		con = &Tuple{}
+	case typeDeclarationJSONType:
+		con = &TypeDeclaration{}
+	default:
+		return nil, fmt.Errorf("unknown constraint type %q", discriminator.Type)
+	}
+
+	err = con.UnmarshalJSON(b)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", discriminator.Type, err)
+	}
+
+	// constraints are implemented as values
+	switch c := con.(type) {
+	case *AnyExpression:
+		return *c, nil
+	case *Keyword:
+		return *c, nil
+	case *List:
+		return *c, nil
+	case *LiteralType:
+		return *c, nil
+	case *LiteralValue:
+		return *c, nil
+	case *Map:
+		return *c, nil
+	case *Object:
+		return *c, nil
+	case *OneOf:
+		return *c, nil
+	case *Reference:
+		return *c, nil
+	case *Set:
+		return *c, nil
+	case *Tuple:
+		return *c, nil
+	case *TypeDeclaration:
+		return *c, nil
+	}
+	return nil, fmt.Errorf("unsupported constraint: %T", con)
+}
+
+func unmarshalConstraints(b []json.RawMessage) ([]Constraint, error) {
+	if len(b) == 0 {
+		return nil, nil
+	}
+
+	cons := make([]Constraint, 0, len(b))
+	for i, cb := range b {
+		c, err := unmarshalConstraint(cb)
+		if err != nil {
+			return nil, fmt.Errorf("[%d]: %w", i, err)
+		}
+		cons = append(cons, c)
+	}
+	return cons, nil
+}
+
+type anyExpressionJSON struct {
+	Type                    string          `json:"type"`
+	OfType                  json.RawMessage `json:"of_type,omitempty"`
+	SkipLiteralComplexTypes bool            `json:"skip_literal_complex_types,omitempty"`
+}
+
+func (ae AnyExpression) MarshalJSON() ([]byte, error) {
+	ofType, err := marshalType(ae.OfType)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(anyExpressionJSON{
+		Type:                    anyExpressionJSONType,
+		OfType:                  ofType,
+		SkipLiteralComplexTypes: ae.SkipLiteralComplexTypes,
+	})
+}
+
+func (ae *AnyExpression) UnmarshalJSON(b []byte) error {
+	var aej anyExpressionJSON
+	err := json.Unmarshal(b, &aej)
+	if err != nil {
+		return err
+	}
+	ofType, err := unmarshalType(aej.OfType)
+	if err != nil {
+		return err
+	}
+	*ae = AnyExpression{
+		OfType:                  ofType,
+		SkipLiteralComplexTypes: aej.SkipLiteralComplexTypes,
+	}
+	return nil
+}
+
+type keywordJSON struct {
+	Type        string      `json:"type"`
+	Keyword     string      `json:"keyword"`
+	Name        string      `json:"name,omitempty"`
+	Description *markupJSON `json:"description,omitempty"`
+}
+
+func (k Keyword) MarshalJSON() ([]byte, error) {
+	return json.Marshal(keywordJSON{
+		Type:        keywordJSONType,
+		Keyword:     k.Keyword,
+		Name:        k.Name,
+		Description: newMarkupJSON(k.Description),
+	})
+}
+
+func (k *Keyword) UnmarshalJSON(b []byte) error {
+	var kj keywordJSON
+	err := json.Unmarshal(b, &kj)
+	if err != nil {
+		return err
+	}
+	description, err := kj.Description.markupContent()
+	if err != nil {
+		return err
+	}
+	*k = Keyword{
+		Keyword:     kj.Keyword,
+		Name:        kj.Name,
+		Description: description,
+	}
+	return nil
+}
+
+// collectionJSON represents List and Set constraints
+type collectionJSON struct {
+	Type        string          `json:"type"`
+	Elem        json.RawMessage `json:"elem,omitempty"`
+	Description *markupJSON     `json:"description,omitempty"`
+	MinItems    uint64          `json:"min_items,omitempty"`
+	MaxItems    uint64          `json:"max_items,omitempty"`
+}
+
+func newCollectionJSON(typ string, elem Constraint, description lang.MarkupContent, minItems, maxItems uint64) ([]byte, error) {
+	elemJSON, err := marshalConstraint(elem)
+	if err != nil {
+		return nil, fmt.Errorf("Elem: %w", err)
+	}
+	return json.Marshal(collectionJSON{
+		Type:        typ,
+		Elem:        elemJSON,
+		Description: newMarkupJSON(description),
+		MinItems:    minItems,
+		MaxItems:    maxItems,
+	})
+}
+
+func (cj collectionJSON) decode() (Constraint, lang.MarkupContent, error) {
+	elem, err := unmarshalConstraint(cj.Elem)
+	if err != nil {
+		return nil, lang.MarkupContent{}, fmt.Errorf("Elem: %w", err)
+	}
+	description, err := cj.Description.markupContent()
+	if err != nil {
+		return nil, lang.MarkupContent{}, err
+	}
+	return elem, description, nil
+}
+
+func (l List) MarshalJSON() ([]byte, error) {
+	return newCollectionJSON(listJSONType, l.Elem, l.Description, l.MinItems, l.MaxItems)
+}
+
+func (l *List) UnmarshalJSON(b []byte) error {
+	var cj collectionJSON
+	err := json.Unmarshal(b, &cj)
+	if err != nil {
+		return err
+	}
+	elem, description, err := cj.decode()
+	if err != nil {
+		return err
+	}
+	*l = List{
+		Elem:        elem,
+		Description: description,
+		MinItems:    cj.MinItems,
+		MaxItems:    cj.MaxItems,
+	}
+	return nil
+}
+
+func (s Set) MarshalJSON() ([]byte, error) {
+	return newCollectionJSON(setJSONType, s.Elem, s.Description, s.MinItems, s.MaxItems)
+}
+
+func (s *Set) UnmarshalJSON(b []byte) error {
+	var cj collectionJSON
+	err := json.Unmarshal(b, &cj)
+	if err != nil {
+		return err
+	}
+	elem, description, err := cj.decode()
+	if err != nil {
+		return err
+	}
+	*s = Set{
+		Elem:        elem,
+		Description: description,
+		MinItems:    cj.MinItems,
+		MaxItems:    cj.MaxItems,
+	}
+	return nil
+}
+
+type literalTypeJSON struct {
+	Type             string          `json:"type"`
+	LiteralType      json.RawMessage `json:"literal_type,omitempty"`
+	SkipComplexTypes bool            `json:"skip_complex_types,omitempty"`
+}
+
+func (lt LiteralType) MarshalJSON() ([]byte, error) {
+	typ, err := marshalType(lt.Type)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(literalTypeJSON{
+		Type:             literalTypeJSONType,
+		LiteralType:      typ,
+		SkipComplexTypes: lt.SkipComplexTypes,
+	})
+}
+
+func (lt *LiteralType) UnmarshalJSON(b []byte) error {
+	var ltj literalTypeJSON
+	err := json.Unmarshal(b, &ltj)
+	if err != nil {
+		return err
+	}
+	typ, err := unmarshalType(ltj.LiteralType)
+	if err != nil {
+		return err
+	}
+	*lt = LiteralType{
+		Type:             typ,
+		SkipComplexTypes: ltj.SkipComplexTypes,
+	}
+	return nil
+}
+
+type literalValueJSON struct {
+	Type         string           `json:"type"`
+	Value        *valueJSON       `json:"value,omitempty"`
+	IsDeprecated bool             `json:"is_deprecated,omitempty"`
+	Description  *markupJSON      `json:"description,omitempty"`
+	Versions     *versionInfoJSON `json:"versions,omitempty"`
+}
+
+func (lv LiteralValue) MarshalJSON() ([]byte, error) {
+	value, err := newValueJSON(lv.Value)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(literalValueJSON{
+		Type:         literalValueJSONType,
+		Value:        value,
+		IsDeprecated: lv.IsDeprecated,
+		Description:  newMarkupJSON(lv.Description),
+		Versions:     newVersionInfoJSON(lv.Versions),
+	})
+}
+
+func (lv *LiteralValue) UnmarshalJSON(b []byte) error {
+	var lvj literalValueJSON
+	err := json.Unmarshal(b, &lvj)
+	if err != nil {
+		return err
+	}
+	value, err := lvj.Value.value()
+	if err != nil {
+		return err
+	}
+	description, err := lvj.Description.markupContent()
+	if err != nil {
+		return err
+	}
+	versions, err := lvj.Versions.versionInfo()
+	if err != nil {
+		return err
+	}
+	*lv = LiteralValue{
+		Value:        value,
+		IsDeprecated: lvj.IsDeprecated,
+		Description:  description,
+		Versions:     versions,
+	}
+	return nil
+}
+
+type mapJSON struct {
+	Type                  string          `json:"type"`
+	Elem                  json.RawMessage `json:"elem,omitempty"`
+	Name                  string          `json:"name,omitempty"`
+	Description           *markupJSON     `json:"description,omitempty"`
+	MinItems              uint64          `json:"min_items,omitempty"`
+	MaxItems              uint64          `json:"max_items,omitempty"`
+	AllowInterpolatedKeys bool            `json:"allow_interpolated_keys,omitempty"`
+}
+
+func (m Map) MarshalJSON() ([]byte, error) {
+	elem, err := marshalConstraint(m.Elem)
+	if err != nil {
+		return nil, fmt.Errorf("Elem: %w", err)
+	}
+	return json.Marshal(mapJSON{
+		Type:                  mapJSONType,
+		Elem:                  elem,
+		Name:                  m.Name,
+		Description:           newMarkupJSON(m.Description),
+		MinItems:              m.MinItems,
+		MaxItems:              m.MaxItems,
+		AllowInterpolatedKeys: m.AllowInterpolatedKeys,
+	})
+}
+
+func (m *Map) UnmarshalJSON(b []byte) error {
+	var mj mapJSON
+	err := json.Unmarshal(b, &mj)
+	if err != nil {
+		return err
+	}
+	elem, err := unmarshalConstraint(mj.Elem)
+	if err != nil {
+		return fmt.Errorf("Elem: %w", err)
+	}
+	description, err := mj.Description.markupContent()
+	if err != nil {
+		return err
+	}
+	*m = Map{
+		Elem:                  elem,
+		Name:                  mj.Name,
+		Description:           description,
+		MinItems:              mj.MinItems,
+		MaxItems:              mj.MaxItems,
+		AllowInterpolatedKeys: mj.AllowInterpolatedKeys,
+	}
+	return nil
+}
+
+type objectJSON struct {
+	Type                  string           `json:"type"`
+	Attributes            ObjectAttributes `json:"attributes,omitempty"`
+	Name                  string           `json:"name,omitempty"`
+	Description           *markupJSON      `json:"description,omitempty"`
+	AllowInterpolatedKeys bool             `json:"allow_interpolated_keys,omitempty"`
+}
+
+func (o Object) MarshalJSON() ([]byte, error) {
+	return json.Marshal(objectJSON{
+		Type:                  objectJSONType,
+		Attributes:            o.Attributes,
+		Name:                  o.Name,
+		Description:           newMarkupJSON(o.Description),
+		AllowInterpolatedKeys: o.AllowInterpolatedKeys,
+	})
+}
+
+func (o *Object) UnmarshalJSON(b []byte) error {
+	var oj objectJSON
+	err := json.Unmarshal(b, &oj)
+	if err != nil {
+		return err
+	}
+	description, err := oj.Description.markupContent()
+	if err != nil {
+		return err
+	}
+	*o = Object{
+		Attributes:            oj.Attributes,
+		Name:                  oj.Name,
+		Description:           description,
+		AllowInterpolatedKeys: oj.AllowInterpolatedKeys,
+	}
+	return nil
+}
+
+type oneOfJSON struct {
+	Type        string            `json:"type"`
+	Constraints []json.RawMessage `json:"constraints,omitempty"`
+}
+
+func (o OneOf) MarshalJSON() ([]byte, error) {
+	cons, err := marshalConstraints(o)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(oneOfJSON{
+		Type:        oneOfJSONType,
+		Constraints: cons,
+	})
+}
+
+func (o *OneOf) UnmarshalJSON(b []byte) error {
+	var oj oneOfJSON
+	err := json.Unmarshal(b, &oj)
+	if err != nil {
+		return err
+	}
+	cons, err := unmarshalConstraints(oj.Constraints)
+	if err != nil {
+		return err
+	}
+	*o = OneOf(cons)
+	return nil
+}
+
+type referenceJSON struct {
+	Type      string                   `json:"type"`
+	OfScopeId lang.ScopeId             `json:"of_scope_id,omitempty"`
+	OfType    json.RawMessage          `json:"of_type,omitempty"`
+	Name      string                   `json:"name,omitempty"`
+	Address   *referenceAddrSchemaJSON `json:"address,omitempty"`
+}
+
+type referenceAddrSchemaJSON struct {
+	ScopeId lang.ScopeId `json:"scope_id,omitempty"`
+}
+
+func (r Reference) MarshalJSON() ([]byte, error) {
+	ofType, err := marshalType(r.OfType)
+	if err != nil {
+		return nil, err
+	}
+	rj := referenceJSON{
+		Type:      referenceJSONType,
+		OfScopeId: r.OfScopeId,
+		OfType:    ofType,
+		Name:      r.Name,
+	}
+	if r.Address != nil {
+		rj.Address = &referenceAddrSchemaJSON{
+			ScopeId: r.Address.ScopeId,
+		}
+	}
+	return json.Marshal(rj)
+}
+
+func (r *Reference) UnmarshalJSON(b []byte) error {
+	var rj referenceJSON
+	err := json.Unmarshal(b, &rj)
+	if err != nil {
+		return err
+	}
+	ofType, err := unmarshalType(rj.OfType)
+	if err != nil {
+		return err
+	}
+	newRef := Reference{
+		OfScopeId: rj.OfScopeId,
+		OfType:    ofType,
+		Name:      rj.Name,
+	}
+	if rj.Address != nil {
+		newRef.Address = &ReferenceAddrSchema{
+			ScopeId: rj.Address.ScopeId,
+		}
+	}
+	*r = newRef
+	return nil
+}
+
+type tupleJSON struct {
+	Type        string            `json:"type"`
+	Elems       []json.RawMessage `json:"elems,omitempty"`
+	Description *markupJSON       `json:"description,omitempty"`
+}
+
+func (t Tuple) MarshalJSON() ([]byte, error) {
+	elems, err := marshalConstraints(t.Elems)
+	if err != nil {
+		return nil, fmt.Errorf("Elems: %w", err)
+	}
+	return json.Marshal(tupleJSON{
+		Type:        tupleJSONType,
+		Elems:       elems,
+		Description: newMarkupJSON(t.Description),
+	})
+}
+
+func (t *Tuple) UnmarshalJSON(b []byte) error {
+	var tj tupleJSON
+	err := json.Unmarshal(b, &tj)
+	if err != nil {
+		return err
+	}
+	elems, err := unmarshalConstraints(tj.Elems)
+	if err != nil {
+		return fmt.Errorf("Elems: %w", err)
+	}
+	description, err := tj.Description.markupContent()
+	if err != nil {
+		return err
+	}
+	*t = Tuple{
+		Elems:       elems,
+		Description: description,
+	}
+	return nil
+}
+
+type typeDeclarationJSON struct {
+	Type          string `json:"type"`
+	OptionalAttrs bool   `json:"optional_attrs,omitempty"`
+}
+
+func (td TypeDeclaration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(typeDeclarationJSON{
+		Type:          typeDeclarationJSONType,
+		OptionalAttrs: td.OptionalAttrs,
+	})
+}
+
+func (td *TypeDeclaration) UnmarshalJSON(b []byte) error {
+	var tdj typeDeclarationJSON
+	err := json.Unmarshal(b, &tdj)
+	if err != nil {
+		return err
+	}
+	*td = TypeDeclaration{
+		OptionalAttrs: tdj.OptionalAttrs,
+	}
+	return nil
+}
@@ -0,0 +1,463 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package schema
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/hcl-lang/lang"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/zclconf/go-cty-debug/ctydebug"
+	"github.com/zclconf/go-cty/cty"
+)
+
+func TestMarshalBodySchema_roundTrip(t *testing.T) {
+	bodySchema := &BodySchema{
+		Blocks: map[string]*BlockSchema{
+			"resource": {
+				Labels: []*LabelSchema{
+					{
+						Name:                   "type",
+						Description:            lang.PlainText("Resource type"),
+						SemanticTokenModifiers: lang.SemanticTokenModifiers{"type"},
+						IsDepKey:               true,
+						Completable:            true,
+					},
+					{Name: "name"},
+				},
+				Type:       BlockTypeObject,
+				SymbolKind: lang.ClassSymbolKind,
+				Body: &BodySchema{
+					Attributes: map[string]*AttributeSchema{
+						"count": {
+							IsOptional: true,
+							Constraint: AnyExpression{OfType: cty.Number},
+						},
+					},
+					Extensions: &BodyExtensions{
+						Count:    true,
+						SelfRefs: true,
+					},
+					AttributeOrder: []string{"count"},
+				},
+				DependentBody: map[SchemaKey]*BodySchema{
+					NewSchemaKey(DependencyKeys{
+						Labels: []LabelDependent{
+							{Index: 0, Value: "aws_instance"},
+						},
+					}): {
+						Description: lang.Markdown("An **EC2** instance"),
+						Attributes: map[string]*AttributeSchema{
+							"ami": {
+								IsRequired: true,
+								Constraint: LiteralType{Type: cty.String},
+								Versions: VersionInfo{
+									IntroducedIn: MustParseVersion("1.3.0"),
+									DeprecatedIn: MustParseVersion("1.6.0"),
+									RemovedIn:    MustParseVersion("2.0.0-beta1"),
+								},
+							},
+						},
+					},
+				},
+				Description:  lang.Markdown("Resource block"),
+				IsDeprecated: true,
+				MinItems:     1,
+				MaxItems:     2,
+				Versions: VersionInfo{
+					IntroducedIn: MustParseVersion("1.0.0"),
+				},
+				Address: &BlockAddrSchema{
+					Steps: Address{
+						LabelStep{Index: 0},
+						LabelStep{Index: 1},
+					},
+					FriendlyName:        "resource",
+					ScopeId:             lang.ScopeId("resource"),
+					AsReference:         true,
+					BodyAsData:          true,
+					InferBody:           true,
+					AsTypeOf:            &BlockAsTypeOf{AttributeExpr: "type"},
+					DependentBodyAsData: true,
+					InferDependentBody:  true,
+				},
+			},
+		},
+		Attributes: map[string]*AttributeSchema{
+			"kind": {
+				IsOptional:   true,
+				Constraint:   Keyword{Keyword: "auto"},
+				DefaultValue: DefaultKeyword{Keyword: "auto"},
+			},
+			"type": {
+				IsOptional:   true,
+				Constraint:   TypeDeclaration{OptionalAttrs: true},
+				DefaultValue: DefaultTypeDeclaration{Type: cty.List(cty.String)},
+			},
+			"port": {
+				IsOptional: true,
+				Constraint: LiteralType{Type: cty.Number},
+				DefaultValue: DefaultEnvVar{
+					Name: "PORT",
+					Fallback: DefaultAttributeDependent{
+						Name: "engine",
+						Cases: []DefaultCase{
+							{
+								Value:   cty.StringVal("postgres"),
+								Default: DefaultValue{Value: cty.NumberIntVal(5432)},
+							},
+						},
+						Fallback: DefaultValue{Value: cty.NumberIntVal(0)},
+					},
+				},
+			},
+			"any": {
+				IsOptional:   true,
+				IsSensitive:  true,
+				IsDeprecated: true,
+				Constraint: OneOf{
+					Keyword{
+						Keyword:     "foo",
+						Name:        "foo keyword",
+						Description: lang.PlainText("Foo"),
+					},
+					List{
+						Elem:        LiteralType{Type: cty.String, SkipComplexTypes: true},
+						Description: lang.PlainText("List"),
+						MinItems:    1,
+						MaxItems:    3,
+					},
+					Set{
+						Elem: Reference{
+							OfScopeId: lang.ScopeId("variable"),
+							OfType:    cty.List(cty.String),
+							Name:      "variable",
+							Address:   &ReferenceAddrSchema{ScopeId: lang.ScopeId("variable")},
+						},
+					},
+					Map{
+						Elem:                  TypeDeclaration{},
+						Name:                  "map of types",
+						Description:           lang.Markdown("Map"),
+						MinItems:              1,
+						MaxItems:              2,
+						AllowInterpolatedKeys: true,
+					},
+					Object{
+						Attributes: ObjectAttributes{
+							"nested": {
+								IsRequired: true,
+								Constraint: Tuple{
+									Elems: []Constraint{
+										LiteralValue{
+											Value:        cty.StringVal("first"),
+											IsDeprecated: true,
+											Description:  lang.PlainText("First"),
+											Versions: VersionInfo{
+												DeprecatedIn: MustParseVersion("1.2.0"),
+											},
+										},
+										LiteralValue{
+											Value: cty.ObjectVal(map[string]cty.Value{
+												"foo": cty.NullVal(cty.Number),
+												"bar": cty.ListVal([]cty.Value{cty.True}),
+											}),
+										},
+									},
+									Description: lang.PlainText("Tuple"),
+								},
+							},
+						},
+						Name:                  "object",
+						AllowInterpolatedKeys: true,
+					},
+					AnyExpression{
+						OfType:                  cty.Object(map[string]cty.Type{"foo": cty.DynamicPseudoType}),
+						SkipLiteralComplexTypes: true,
+					},
+				},
+				DefaultValue: DefaultValue{Value: cty.MapVal(map[string]cty.Value{
+					"key": cty.NumberIntVal(42),
+				})},
+				IsDepKey: true,
+				Address: &AttributeAddrSchema{
+					Steps: Address{
+						StaticStep{Name: "var"},
+						AttrNameStep{},
+					},
+					FriendlyName: "attribute",
+					ScopeId:      lang.ScopeId("attribute"),
+					AsExprType:   true,
+					AsReference:  true,
+				},
+				OriginForTarget: &PathTarget{
+					Address: Address{
+						StaticStep{Name: "var"},
+						AttrValueStep{Name: "name", IsOptional: true},
+					},
+					Path: lang.Path{Path: "./module", LanguageID: "terraform"},
+					Constraints: Constraints{
+						ScopeId: lang.ScopeId("variable"),
+						Type:    cty.String,
+					},
+				},
+				SemanticTokenModifiers: lang.SemanticTokenModifiers{"foo", "bar"},
+				CompletionHooks: lang.CompletionHooks{
+					{Name: "CompleteFoo"},
+				},
+			},
+		},
+		AnyAttribute: &AttributeSchema{
+			IsComputed: true,
+			Constraint: LiteralType{Type: cty.DynamicPseudoType},
+		},
+		Detail:      "detail",
+		Description: lang.PlainText("Root body"),
+		DocsLink: &DocsLink{
+			URL:     "https://example.com",
+			Tooltip: "Docs",
+		},
+		HoverURL: "https://example.com/hover",
+		TargetableAs: Targetables{
+			{
+				Address: lang.Address{
+					lang.RootStep{Name: "module"},
+					lang.AttrStep{Name: "foo"},
+					lang.IndexStep{Key: cty.StringVal("bar")},
+					lang.IndexStep{Key: cty.NumberIntVal(0)},
+				},
+				ScopeId:      lang.ScopeId("module"),
+				AsType:       cty.Object(map[string]cty.Type{"baz": cty.Bool}),
+				IsSensitive:  true,
+				FriendlyName: "module",
+				Description:  lang.Markdown("Module"),
+				NestedTargetables: Targetables{
+					{
+						Address: lang.Address{
+							lang.RootStep{Name: "module"},
+							lang.AttrStep{Name: "foo"},
+							lang.AttrStep{Name: "baz"},
+						},
+						AsType: cty.Bool,
+					},
+				},
+			},
+		},
+		Targets: &Target{
+			Path: lang.Path{Path: "./module", LanguageID: "terraform"},
+			Range: hcl.Range{
+				Filename: "main.tf",
+				Start:    hcl.Pos{Line: 1, Column: 1, Byte: 0},
+				End:      hcl.Pos{Line: 2, Column: 3, Byte: 10},
+			},
+		},
+		ImpliedOrigins: ImpliedOrigins{
+			{
+				OriginAddress: lang.Address{
+					lang.RootStep{Name: "module"},
+					lang.AttrStep{Name: "foo"},
+				},
+				TargetAddress: lang.Address{
+					lang.RootStep{Name: "output"},
+				},
+				Path: lang.Path{Path: "./module", LanguageID: "terraform"},
+				Constraints: Constraints{
+					ScopeId: lang.ScopeId("output"),
+					Type:    cty.DynamicPseudoType,
+				},
+			},
+		},
+	}
+
+	b, err := MarshalBodySchema(bodySchema)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	decodedSchema, err := UnmarshalBodySchema(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if diff := cmp.Diff(bodySchema, decodedSchema, ctydebug.CmpOptions); diff != "" {
+		t.Fatalf("unexpected schema after round trip: %s", diff)
+	}
+
+	// marshaling the decoded schema should be stable
+	b2, err := MarshalBodySchema(decodedSchema)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(string(b), string(b2)); diff != "" {
+		t.Fatalf("unexpected JSON after round trip: %s", diff)
+	}
+}
+
+func TestMarshalBodySchema_envelope(t *testing.T) {
+	b, err := MarshalBodySchema(&BodySchema{
+		Attributes: map[string]*AttributeSchema{
+			"foo": {
+				IsOptional: true,
+				Constraint: LiteralType{Type: cty.String},
+			},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectedJSON := `{"format_version":"1.0","schema":{"attributes":{"foo":{"is_optional":true,"constraint":{"type":"literal_type","literal_type":"string"}}}}}`
+	if diff := cmp.Diff(expectedJSON, string(b)); diff != "" {
+		t.Fatalf("unexpected JSON: %s", diff)
+	}
+}
+
+func TestMarshalBodySchema_emptyCollections(t *testing.T) {
+	bodySchema := &BodySchema{
+		Attributes: map[string]*AttributeSchema{},
+		Blocks: map[string]*BlockSchema{
+			"foo": {
+				Labels:        []*LabelSchema{},
+				DependentBody: map[SchemaKey]*BodySchema{},
+				Body: &BodySchema{
+					Blocks:         map[string]*BlockSchema{},
+					AttributeOrder: []string{},
+				},
+			},
+		},
+	}
+
+	b, err := MarshalBodySchema(bodySchema)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// empty collections are omitted, the same as nil ones
+	expectedJSON := `{"format_version":"1.0","schema":{"blocks":{"foo":{"body":{}}}}}`
+	if diff := cmp.Diff(expectedJSON, string(b)); diff != "" {
+		t.Fatalf("unexpected JSON: %s", diff)
+	}
+
+	decodedSchema, err := UnmarshalBodySchema(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectedSchema := &BodySchema{
+		Blocks: map[string]*BlockSchema{
+			"foo": {
+				Body: &BodySchema{},
+			},
+		},
+	}
+	if diff := cmp.Diff(expectedSchema, decodedSchema, ctydebug.CmpOptions); diff != "" {
+		t.Fatalf("unexpected schema: %s", diff)
+	}
+}
+
+func TestUnmarshalBodySchema_formatVersion(t *testing.T) {
+	testCases := []struct {
+		formatVersion string
+		expectedErr   string
+	}{
+		{"1.0", ""},
+		{"1.3", ""},
+		{"", `unsupported format version "", expected "1.0"`},
+		{"2.0", `unsupported format version "2.0", expected "1.0"`},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.formatVersion, func(t *testing.T) {
+			b, err := json.Marshal(map[string]interface{}{
+				"format_version": tc.formatVersion,
+				"schema": map[string]interface{}{
+					"detail": "foo",
+				},
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			bodySchema, err := UnmarshalBodySchema(b)
+			if tc.expectedErr != "" {
+				if err == nil {
+					t.Fatalf("expected error: %s", tc.expectedErr)
+				}
+				if err.Error() != tc.expectedErr {
+					t.Fatalf("unexpected error: %s", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if bodySchema.Detail != "foo" {
+				t.Fatalf("unexpected schema: %#v", bodySchema)
+			}
+		})
+	}
+}
+
+func TestUnmarshalBodySchema_invalid(t *testing.T) {
+	testCases := []struct {
+		name        string
+		schemaJSON  string
+		expectedErr string
+	}{
+		{
+			"unknown constraint type",
+			`{"attributes":{"foo":{"constraint":{"type":"unknown"}}}}`,
+			`Constraint: unknown constraint type "unknown"`,
+		},
+		{
+			"unknown nested constraint type",
+			`{"attributes":{"foo":{"constraint":{"type":"list","elem":{"type":"unknown"}}}}}`,
+			`Constraint: list: Elem: unknown constraint type "unknown"`,
+		},
+		{
+			"unknown address step",
+			`{"blocks":{"foo":{"address":{"steps":[{"type":"unknown"}]}}}}`,
+			`Address: unknown address step type "unknown"`,
+		},
+		{
+			"unknown block type",
+			`{"blocks":{"foo":{"type":"unknown"}}}`,
+			`Type: unknown block type "unknown"`,
+		},
+		{
+			"unknown markup kind",
+			`{"description":{"value":"foo","kind":"html"}}`,
+			`Description: unknown markup kind "html"`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			b := `{"format_version":"1.0","schema":` + tc.schemaJSON + `}`
+			_, err := UnmarshalBodySchema([]byte(b))
+			if err == nil {
+				t.Fatalf("expected error: %s", tc.expectedErr)
+			}
+			if !strings.Contains(err.Error(), tc.expectedErr) {
+				t.Fatalf("unexpected error: %s", err)
+			}
+		})
+	}
+}
+
+func TestMarshalBodySchema_unknownValue(t *testing.T) {
+	_, err := MarshalBodySchema(&BodySchema{
+		Attributes: map[string]*AttributeSchema{
+			"foo": {
+				IsOptional: true,
+				Constraint: LiteralValue{Value: cty.UnknownVal(cty.String)},
+			},
+		},
+	})
+	if err == nil {
+		t.Fatal("expected error for unknown value")
+	}
+}
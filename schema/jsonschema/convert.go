@@ -0,0 +1,501 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package jsonschema
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/hcl-lang/schema"
+	"github.com/zclconf/go-cty/cty"
+	ctyjson "github.com/zclconf/go-cty/cty/json"
+)
+
+// FromBodySchema returns a JSON Schema document describing
+// configuration of the given body in the HCL JSON syntax.
+//
+// Blocks with labels are represented as nested objects keyed
+// by label values, where each level may also be an array
+// of such objects, as the HCL JSON syntax permits.
+// Dependent bodies keyed by a single label are represented
+// via if/then subschemas on that label's keys.
+//
+// Constraints which allow expressions also accept strings
+// (i.e. template expressions) in place of any value.
+func FromBodySchema(bodySchema *schema.BodySchema) (*Schema, error) {
+	doc, err := bodySchemaToJSON("", bodySchema, false)
+	if err != nil {
+		return nil, err
+	}
+	doc.Schema = DraftURI
+	return doc, nil
+}
+
+// bodySchemaToJSON returns schema of the body to be placed
+// at the given JSON pointer, where isPartial represents whether
+// the body may contain other attributes or blocks, e.g. when
+// they depend on labels or attributes not known here
+func bodySchemaToJSON(ptr string, bodySchema *schema.BodySchema, isPartial bool) (*Schema, error) {
+	s := &Schema{
+		Type:       "object",
+		Properties: make(map[string]*Schema, 0),
+	}
+	if bodySchema == nil {
+		return s, nil
+	}
+
+	s.Description = bodySchema.Description.Value
+	s.Deprecated = bodySchema.IsDeprecated
+
+	required := make([]string, 0)
+
+	for name, aSchema := range bodySchema.Attributes {
+		attrSchema, err := attributeSchemaToJSON(aSchema)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+		s.Properties[name] = attrSchema
+		if aSchema.IsRequired {
+			required = append(required, name)
+		}
+	}
+
+	for name, bSchema := range bodySchema.Blocks {
+		blockSchema, err := blockSchemaToJSON(pointerTo(ptr, "properties", name), bSchema)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+		s.Properties[name] = blockSchema
+		if bSchema.MinItems > 0 {
+			required = append(required, name)
+		}
+	}
+
+	if ext := bodySchema.Extensions; ext != nil {
+		if ext.Count {
+			s.Properties["count"] = exprTypeSchema(cty.Number)
+		}
+		if ext.ForEach {
+			s.Properties["for_each"] = exprTypeSchema(cty.DynamicPseudoType)
+		}
+		if ext.DynamicBlocks {
+			s.Properties["dynamic"] = &Schema{}
+		}
+	}
+
+	if len(required) > 0 {
+		sort.Strings(required)
+		s.Required = required
+	}
+
+	switch {
+	case bodySchema.AnyAttribute != nil:
+		attrSchema, err := attributeSchemaToJSON(bodySchema.AnyAttribute)
+		if err != nil {
+			return nil, err
+		}
+		s.AdditionalProperties = attrSchema
+	case !isPartial:
+		s.AdditionalProperties = False()
+	}
+
+	return s, nil
+}
+
+func attributeSchemaToJSON(aSchema *schema.AttributeSchema) (*Schema, error) {
+	s, err := constraintToJSON(aSchema.Constraint)
+	if err != nil {
+		return nil, err
+	}
+	if aSchema.Description.Value != "" {
+		s.Description = aSchema.Description.Value
+	}
+	if aSchema.IsDeprecated {
+		s.Deprecated = true
+	}
+	return s, nil
+}
+
+// blockSchemaToJSON returns schema of the block
+// to be placed at the given JSON pointer
+func blockSchemaToJSON(ptr string, bSchema *schema.BlockSchema) (*Schema, error) {
+	hasDependentBody := bSchema.HasDependentBody()
+	dependentBodies := labelDependentBodies(bSchema)
+
+	s, err := labelLevelsToJSON(ptr, bSchema, 0, func(ptr string) (*Schema, error) {
+		body, err := bodySchemaToJSON(ptr, bSchema.Body, hasDependentBody)
+		if err != nil {
+			return nil, err
+		}
+		// body description is already part of the block
+		body.Description = ""
+		body.Deprecated = false
+		return body, nil
+	}, dependentBodies)
+	if err != nil {
+		return nil, err
+	}
+
+	s.Description = bSchema.Description.Value
+	s.Deprecated = bSchema.IsDeprecated
+
+	return s, nil
+}
+
+// labelLevelsToJSON returns schema of nested objects keyed by label values,
+// starting at the given label index, with the body as the innermost value
+func labelLevelsToJSON(ptr string, bSchema *schema.BlockSchema, labelIdx int,
+	bodyFunc func(ptr string) (*Schema, error), dependentBodies map[int][]labelDependentBody) (*Schema, error) {
+	if labelIdx >= len(bSchema.Labels) {
+		return objectOrArrayOf(ptr, bodyFunc)
+	}
+
+	return objectOrArrayOf(ptr, func(ptr string) (*Schema, error) {
+		next, err := labelLevelsToJSON(pointerTo(ptr, "additionalProperties"), bSchema, labelIdx+1,
+			bodyFunc, dependentBodies)
+		if err != nil {
+			return nil, err
+		}
+		level := &Schema{
+			Type:                 "object",
+			AdditionalProperties: next,
+		}
+
+		for i, dep := range dependentBodies[labelIdx] {
+			depBody := mergeBodySchemas(bSchema.Body, dep.body)
+			depPtr := pointerTo(ptr, "allOf", strconv.Itoa(i), "then", "properties", dep.value)
+			depSchema, err := labelLevelsToJSON(depPtr, bSchema, labelIdx+1, func(ptr string) (*Schema, error) {
+				return bodySchemaToJSON(ptr, depBody, false)
+			}, nil)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", dep.value, err)
+			}
+
+			level.AllOf = append(level.AllOf, &Schema{
+				If: &Schema{
+					Required: []string{dep.value},
+				},
+				Then: &Schema{
+					Properties: map[string]*Schema{
+						dep.value: depSchema,
+					},
+				},
+			})
+		}
+
+		return level, nil
+	})
+}
+
+type labelDependentBody struct {
+	value string
+	body  *schema.BodySchema
+}
+
+// labelDependentBodies returns dependent bodies keyed
+// by a single label, grouped by the label index
+// and sorted by the label value
+func labelDependentBodies(bSchema *schema.BlockSchema) map[int][]labelDependentBody {
+	bodies := make(map[int][]labelDependentBody, 0)
+
+	for key, body := range bSchema.DependentBody {
+		keys, err := decodeSchemaKey(key)
+		if err != nil || len(keys.Labels) != 1 || len(keys.Attributes) > 0 {
+			continue
+		}
+		label := keys.Labels[0]
+		if label.Index >= len(bSchema.Labels) {
+			continue
+		}
+		bodies[label.Index] = append(bodies[label.Index], labelDependentBody{
+			value: label.Value,
+			body:  body,
+		})
+	}
+
+	for _, b := range bodies {
+		sort.Slice(b, func(i, j int) bool {
+			return b[i].value < b[j].value
+		})
+	}
+
+	return bodies
+}
+
+// mergeBodySchemas returns the static body merged with the dependent one
+func mergeBodySchemas(static, dependent *schema.BodySchema) *schema.BodySchema {
+	merged := &schema.BodySchema{}
+	if static != nil {
+		merged = static.Copy()
+	}
+	if dependent == nil {
+		return merged
+	}
+
+	if merged.Attributes == nil {
+		merged.Attributes = make(map[string]*schema.AttributeSchema, 0)
+	}
+	for name, aSchema := range dependent.Attributes {
+		merged.Attributes[name] = aSchema
+	}
+
+	if merged.Blocks == nil {
+		merged.Blocks = make(map[string]*schema.BlockSchema, 0)
+	}
+	for name, bSchema := range dependent.Blocks {
+		merged.Blocks[name] = bSchema
+	}
+
+	if dependent.AnyAttribute != nil {
+		merged.AnyAttribute = dependent.AnyAttribute
+	}
+	if dependent.Extensions != nil {
+		merged.Extensions = dependent.Extensions.Copy()
+	}
+	if dependent.Description.Value != "" {
+		merged.Description = dependent.Description
+	}
+	if dependent.IsDeprecated {
+		merged.IsDeprecated = true
+	}
+
+	return merged
+}
+
+// objectOrArrayOf returns schema to be placed at the given JSON pointer
+// accepting either the object or an array of such objects,
+// where the array items refer to the object schema
+func objectOrArrayOf(ptr string, objectFunc func(ptr string) (*Schema, error)) (*Schema, error) {
+	objectPtr := pointerTo(ptr, "anyOf", "0")
+	object, err := objectFunc(objectPtr)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Schema{
+		AnyOf: []*Schema{
+			object,
+			{
+				Type: "array",
+				Items: &Schema{
+					Ref: "#" + (&url.URL{Fragment: objectPtr}).EscapedFragment(),
+				},
+			},
+		},
+	}, nil
+}
+
+// pointerTo returns JSON pointer to the given path under ptr
+func pointerTo(ptr string, tokens ...string) string {
+	for _, token := range tokens {
+		ptr += "/" + pointerTokenReplacer.Replace(token)
+	}
+	return ptr
+}
+
+var pointerTokenReplacer = strings.NewReplacer("~", "~0", "/", "~1")
+
+func constraintToJSON(con schema.Constraint) (*Schema, error) {
+	switch c := con.(type) {
+	case nil:
+		return &Schema{}, nil
+	case schema.AnyExpression:
+		return exprTypeSchema(c.OfType), nil
+	case schema.LiteralType:
+		return typeSchema(c.Type, false), nil
+	case schema.LiteralValue:
+		value, err := ctyjson.SimpleJSONValue{Value: c.Value}.MarshalJSON()
+		if err != nil {
+			return nil, err
+		}
+		return &Schema{
+			Const:       value,
+			Description: c.Description.Value,
+			Deprecated:  c.IsDeprecated,
+		}, nil
+	case schema.Keyword:
+		value, err := ctyjson.SimpleJSONValue{Value: cty.StringVal(c.Keyword)}.MarshalJSON()
+		if err != nil {
+			return nil, err
+		}
+		return &Schema{
+			Const:       value,
+			Description: c.Description.Value,
+		}, nil
+	case schema.Reference, schema.TypeDeclaration:
+		// represented as strings in JSON
+		return &Schema{Type: "string"}, nil
+	case schema.List:
+		return collectionToJSON(c.Elem, c.Description.Value, c.MinItems, c.MaxItems)
+	case schema.Set:
+		return collectionToJSON(c.Elem, c.Description.Value, c.MinItems, c.MaxItems)
+	case schema.Map:
+		elem, err := constraintToJSON(c.Elem)
+		if err != nil {
+			return nil, err
+		}
+		return &Schema{
+			Type:                 "object",
+			Description:          c.Description.Value,
+			AdditionalProperties: elem,
+			MinProperties:        optionalLimit(c.MinItems),
+			MaxProperties:        optionalLimit(c.MaxItems),
+		}, nil
+	case schema.Object:
+		s := &Schema{
+			Type:                 "object",
+			Description:          c.Description.Value,
+			Properties:           make(map[string]*Schema, 0),
+			AdditionalProperties: False(),
+		}
+		required := make([]string, 0)
+		for name, aSchema := range c.Attributes {
+			attrSchema, err := attributeSchemaToJSON(aSchema)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", name, err)
+			}
+			s.Properties[name] = attrSchema
+			if aSchema.IsRequired {
+				required = append(required, name)
+			}
+		}
+		if len(required) > 0 {
+			sort.Strings(required)
+			s.Required = required
+		}
+		return s, nil
+	case schema.Tuple:
+		s := &Schema{
+			Type:        "array",
+			Description: c.Description.Value,
+			PrefixItems: make([]*Schema, 0, len(c.Elems)),
+			Items:       False(),
+		}
+		for _, elem := range c.Elems {
+			elemSchema, err := constraintToJSON(elem)
+			if err != nil {
+				return nil, err
+			}
+			s.PrefixItems = append(s.PrefixItems, elemSchema)
+		}
+		return s, nil
+	case schema.OneOf:
+		// constraints may overlap (e.g. keyword and string),
+		// so anyOf is used rather than oneOf
+		s := &Schema{
+			AnyOf: make([]*Schema, 0, len(c)),
+		}
+		for _, elem := range c {
+			elemSchema, err := constraintToJSON(elem)
+			if err != nil {
+				return nil, err
+			}
+			s.AnyOf = append(s.AnyOf, elemSchema)
+		}
+		return s, nil
+	}
+
+	return nil, fmt.Errorf("unsupported constraint: %T", con)
+}
+
+func collectionToJSON(elem schema.Constraint, description string, minItems, maxItems uint64) (*Schema, error) {
+	elemSchema, err := constraintToJSON(elem)
+	if err != nil {
+		return nil, err
+	}
+	return &Schema{
+		Type:        "array",
+		Description: description,
+		Items:       elemSchema,
+		MinItems:    optionalLimit(minItems),
+		MaxItems:    optionalLimit(maxItems),
+	}, nil
+}
+
+// exprTypeSchema returns schema of an expression of the given type,
+// where any value may be expressed by a string template
+func exprTypeSchema(typ cty.Type) *Schema {
+	return typeSchema(typ, true)
+}
+
+func typeSchema(typ cty.Type, allowTemplates bool) *Schema {
+	var s *Schema
+
+	switch {
+	case typ == cty.NilType || typ == cty.DynamicPseudoType:
+		return &Schema{}
+	case typ == cty.String:
+		return &Schema{Type: "string"}
+	case typ == cty.Number:
+		s = &Schema{Type: "number"}
+	case typ == cty.Bool:
+		s = &Schema{Type: "boolean"}
+	case typ.IsListType() || typ.IsSetType():
+		s = &Schema{
+			Type:  "array",
+			Items: typeSchema(typ.ElementType(), allowTemplates),
+		}
+	case typ.IsMapType():
+		s = &Schema{
+			Type:                 "object",
+			AdditionalProperties: typeSchema(typ.ElementType(), allowTemplates),
+		}
+	case typ.IsObjectType():
+		s = &Schema{
+			Type:                 "object",
+			Properties:           make(map[string]*Schema, 0),
+			AdditionalProperties: False(),
+		}
+		required := make([]string, 0)
+		for name, attrType := range typ.AttributeTypes() {
+			s.Properties[name] = typeSchema(attrType, allowTemplates)
+			if !typ.AttributeOptional(name) {
+				required = append(required, name)
+			}
+		}
+		if len(required) > 0 {
+			sort.Strings(required)
+			s.Required = required
+		}
+	case typ.IsTupleType():
+		s = &Schema{
+			Type:        "array",
+			PrefixItems: make([]*Schema, 0),
+			Items:       False(),
+		}
+		for _, elemType := range typ.TupleElementTypes() {
+			s.PrefixItems = append(s.PrefixItems, typeSchema(elemType, allowTemplates))
+		}
+	default:
+		// e.g. capsule types
+		return &Schema{}
+	}
+
+	if allowTemplates {
+		return &Schema{
+			AnyOf: []*Schema{
+				s,
+				{Type: "string"},
+			},
+		}
+	}
+	return s
+}
+
+func optionalLimit(limit uint64) *uint64 {
+	if limit == 0 {
+		return nil
+	}
+	return &limit
+}
+
+func decodeSchemaKey(key schema.SchemaKey) (schema.DependencyKeys, error) {
+	var dk schema.DependencyKeys
+	err := json.Unmarshal([]byte(key), &dk)
+	return dk, err
+}
@@ -0,0 +1,306 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package jsonschema
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/hcl-lang/lang"
+	"github.com/hashicorp/hcl-lang/schema"
+	"github.com/zclconf/go-cty/cty"
+)
+
+func TestFromBodySchema(t *testing.T) {
+	bodySchema := &schema.BodySchema{
+		Attributes: map[string]*schema.AttributeSchema{
+			"name": {
+				IsRequired:  true,
+				Description: lang.PlainText("Name"),
+				Constraint:  schema.LiteralType{Type: cty.String},
+			},
+			"port": {
+				IsOptional:   true,
+				IsDeprecated: true,
+				Constraint:   schema.AnyExpression{OfType: cty.Number},
+			},
+			"mode": {
+				IsOptional: true,
+				Constraint: schema.OneOf{
+					schema.LiteralValue{Value: cty.StringVal("fast")},
+					schema.Keyword{Keyword: "auto"},
+				},
+			},
+		},
+		Blocks: map[string]*schema.BlockSchema{
+			"resource": {
+				Description: lang.Markdown("Resource"),
+				Labels: []*schema.LabelSchema{
+					{Name: "type", IsDepKey: true},
+					{Name: "name"},
+				},
+				Body: &schema.BodySchema{
+					Attributes: map[string]*schema.AttributeSchema{
+						"count": {
+							IsOptional: true,
+							Constraint: schema.LiteralType{Type: cty.Number},
+						},
+					},
+				},
+				DependentBody: map[schema.SchemaKey]*schema.BodySchema{
+					schema.NewSchemaKey(schema.DependencyKeys{
+						Labels: []schema.LabelDependent{
+							{Index: 0, Value: "instance"},
+						},
+					}): {
+						Attributes: map[string]*schema.AttributeSchema{
+							"ami": {
+								IsRequired: true,
+								Constraint: schema.LiteralType{Type: cty.String},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	doc, err := FromBodySchema(bodySchema)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	b, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectedJSON := `{
+  "$schema": "https://json-schema.org/draft/2020-12/schema",
+  "type": "object",
+  "properties": {
+    "mode": {
+      "anyOf": [
+        {
+          "const": "fast"
+        },
+        {
+          "const": "auto"
+        }
+      ]
+    },
+    "name": {
+      "type": "string",
+      "description": "Name"
+    },
+    "port": {
+      "deprecated": true,
+      "anyOf": [
+        {
+          "type": "number"
+        },
+        {
+          "type": "string"
+        }
+      ]
+    },
+    "resource": {
+      "description": "Resource",
+      "anyOf": [
+        {
+          "type": "object",
+          "additionalProperties": {
+            "anyOf": [
+              {
+                "type": "object",
+                "additionalProperties": {
+                  "anyOf": [
+                    {
+                      "type": "object",
+                      "properties": {
+                        "count": {
+                          "type": "number"
+                        }
+                      }
+                    },
+                    {
+                      "type": "array",
+                      "items": {
+                        "$ref": "#/properties/resource/anyOf/0/additionalProperties/anyOf/0/additionalProperties/anyOf/0"
+                      }
+                    }
+                  ]
+                }
+              },
+              {
+                "type": "array",
+                "items": {
+                  "$ref": "#/properties/resource/anyOf/0/additionalProperties/anyOf/0"
+                }
+              }
+            ]
+          },
+          "allOf": [
+            {
+              "if": {
+                "required": [
+                  "instance"
+                ]
+              },
+              "then": {
+                "properties": {
+                  "instance": {
+                    "anyOf": [
+                      {
+                        "type": "object",
+                        "additionalProperties": {
+                          "anyOf": [
+                            {
+                              "type": "object",
+                              "properties": {
+                                "ami": {
+                                  "type": "string"
+                                },
+                                "count": {
+                                  "type": "number"
+                                }
+                              },
+                              "required": [
+                                "ami"
+                              ],
+                              "additionalProperties": false
+                            },
+                            {
+                              "type": "array",
+                              "items": {
+                                "$ref": "#/properties/resource/anyOf/0/allOf/0/then/properties/instance/anyOf/0/additionalProperties/anyOf/0"
+                              }
+                            }
+                          ]
+                        }
+                      },
+                      {
+                        "type": "array",
+                        "items": {
+                          "$ref": "#/properties/resource/anyOf/0/allOf/0/then/properties/instance/anyOf/0"
+                        }
+                      }
+                    ]
+                  }
+                }
+              }
+            }
+          ]
+        },
+        {
+          "type": "array",
+          "items": {
+            "$ref": "#/properties/resource/anyOf/0"
+          }
+        }
+      ]
+    }
+  },
+  "required": [
+    "name"
+  ],
+  "additionalProperties": false
+}`
+	if diff := cmp.Diff(expectedJSON, string(b)); diff != "" {
+		t.Fatalf("unexpected JSON schema: %s", diff)
+	}
+}
+
+func TestConstraintToJSON(t *testing.T) {
+	testCases := []struct {
+		name         string
+		constraint   schema.Constraint
+		expectedJSON string
+	}{
+		{
+			"no constraint",
+			nil,
+			`{}`,
+		},
+		{
+			"expression of list type",
+			schema.AnyExpression{OfType: cty.List(cty.Bool)},
+			`{"anyOf":[{"type":"array","items":{"anyOf":[{"type":"boolean"},{"type":"string"}]}},{"type":"string"}]}`,
+		},
+		{
+			"literal object type",
+			schema.LiteralType{Type: cty.ObjectWithOptionalAttrs(map[string]cty.Type{
+				"foo": cty.String,
+				"bar": cty.Number,
+			}, []string{"bar"})},
+			`{"type":"object","properties":{"bar":{"type":"number"},"foo":{"type":"string"}},"required":["foo"],"additionalProperties":false}`,
+		},
+		{
+			"literal tuple type",
+			schema.LiteralType{Type: cty.Tuple([]cty.Type{cty.String, cty.DynamicPseudoType})},
+			`{"type":"array","prefixItems":[{"type":"string"},{}],"items":false}`,
+		},
+		{
+			"reference",
+			schema.Reference{OfScopeId: lang.ScopeId("variable")},
+			`{"type":"string"}`,
+		},
+		{
+			"set with limits",
+			schema.Set{
+				Elem:        schema.LiteralType{Type: cty.Number},
+				Description: lang.PlainText("Set"),
+				MinItems:    1,
+				MaxItems:    2,
+			},
+			`{"type":"array","description":"Set","items":{"type":"number"},"minItems":1,"maxItems":2}`,
+		},
+		{
+			"map",
+			schema.Map{
+				Elem:     schema.LiteralValue{Value: cty.NumberIntVal(1), IsDeprecated: true},
+				MaxItems: 3,
+			},
+			`{"type":"object","additionalProperties":{"deprecated":true,"const":1},"maxProperties":3}`,
+		},
+		{
+			"object",
+			schema.Object{
+				Attributes: schema.ObjectAttributes{
+					"foo": {
+						IsRequired: true,
+						Constraint: schema.TypeDeclaration{},
+					},
+				},
+			},
+			`{"type":"object","properties":{"foo":{"type":"string"}},"required":["foo"],"additionalProperties":false}`,
+		},
+		{
+			"tuple",
+			schema.Tuple{
+				Elems: []schema.Constraint{
+					schema.Keyword{Keyword: "all"},
+				},
+			},
+			`{"type":"array","prefixItems":[{"const":"all"}],"items":false}`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			s, err := constraintToJSON(tc.constraint)
+			if err != nil {
+				t.Fatal(err)
+			}
+			b, err := json.Marshal(s)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(tc.expectedJSON, string(b)); diff != "" {
+				t.Fatalf("unexpected JSON schema: %s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,60 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+// Package jsonschema exports schema.BodySchema as JSON Schema
+// (draft 2020-12) describing configuration in the HCL JSON syntax.
+package jsonschema
+
+import (
+	"encoding/json"
+)
+
+// DraftURI identifies the JSON Schema dialect of the exported documents
+const DraftURI = "https://json-schema.org/draft/2020-12/schema"
+
+// Schema represents a JSON Schema document or any of its subschemas.
+//
+// Only keywords used by the exporter are represented.
+// An empty Schema accepts any value.
+type Schema struct {
+	Schema      string `json:"$schema,omitempty"`
+	Ref         string `json:"$ref,omitempty"`
+	Type        string `json:"type,omitempty"`
+	Description string `json:"description,omitempty"`
+	Deprecated  bool   `json:"deprecated,omitempty"`
+
+	Const json.RawMessage `json:"const,omitempty"`
+
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+	MinProperties        *uint64            `json:"minProperties,omitempty"`
+	MaxProperties        *uint64            `json:"maxProperties,omitempty"`
+
+	PrefixItems []*Schema `json:"prefixItems,omitempty"`
+	Items       *Schema   `json:"items,omitempty"`
+	MinItems    *uint64   `json:"minItems,omitempty"`
+	MaxItems    *uint64   `json:"maxItems,omitempty"`
+
+	AnyOf []*Schema `json:"anyOf,omitempty"`
+	AllOf []*Schema `json:"allOf,omitempty"`
+	If    *Schema   `json:"if,omitempty"`
+	Then  *Schema   `json:"then,omitempty"`
+
+	// rejectAll represents the "false" boolean schema
+	rejectAll bool
+}
+
+// False returns a schema which does not accept any value
+func False() *Schema {
+	return &Schema{rejectAll: true}
+}
+
+func (s *Schema) MarshalJSON() ([]byte, error) {
+	if s.rejectAll {
+		return []byte("false"), nil
+	}
+
+	type schema Schema
+	return json.Marshal((*schema)(s))
+}
@@ -0,0 +1,517 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+// Package mddocs generates Markdown reference documentation
+// from schema.BodySchema.
+package mddocs
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/hcl-lang/schema"
+	"github.com/hashicorp/hcl/v2/ext/typeexpr"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// IndexPageName represents name of the page documenting the root body
+const IndexPageName = "index.md"
+
+// Options represents options for the generator
+type Options struct {
+	// Title represents the title of the index page.
+	// "Reference" is used if empty.
+	Title string
+}
+
+// Page represents a single generated Markdown page
+type Page struct {
+	// Name represents the file name of the page, which is also
+	// used in links between pages, e.g. "resource.md"
+	Name    string
+	Content string
+}
+
+// Generate renders the given body schema into Markdown pages.
+//
+// The first page (IndexPageName) documents the root body
+// and links to a page per each root block type. Nested blocks
+// and dependent bodies are documented as sections of the page
+// of the root block they belong to.
+//
+// The output is deterministic, i.e. the same schema
+// always renders into the same pages.
+func Generate(bodySchema *schema.BodySchema, opts Options) ([]Page, error) {
+	if bodySchema == nil {
+		bodySchema = schema.NewBodySchema()
+	}
+
+	title := opts.Title
+	if title == "" {
+		title = "Reference"
+	}
+
+	index := &page{name: IndexPageName}
+	index.heading(1, "", title)
+	err := index.body(section{}, bodySchema, func(blockType string) string {
+		return blockPageName(blockType)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	pages := []Page{index.Page()}
+
+	for _, blockType := range bodySchema.BlockTypes() {
+		p := &page{name: blockPageName(blockType)}
+		err := p.rootBlock(blockType, bodySchema.Blocks[blockType])
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", blockType, err)
+		}
+		pages = append(pages, p.Page())
+	}
+
+	return pages, nil
+}
+
+func blockPageName(blockType string) string {
+	return blockType + ".md"
+}
+
+// section represents a documented body, identified
+// by the path of block types and dependency keys
+type section struct {
+	steps []string
+}
+
+func (s section) child(step string) section {
+	steps := make([]string, len(s.steps), len(s.steps)+1)
+	copy(steps, s.steps)
+	return section{steps: append(steps, step)}
+}
+
+func (s section) title() string {
+	return "`" + strings.Join(s.steps, " › ") + "`"
+}
+
+// anchor returns an explicit anchor for the section
+// as heading IDs differ between Markdown renderers
+func (s section) anchor() string {
+	var sb strings.Builder
+	for _, r := range strings.ToLower(strings.Join(s.steps, " ")) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') || r == '_' {
+			sb.WriteRune(r)
+			continue
+		}
+		if sb.Len() > 0 && !strings.HasSuffix(sb.String(), "-") {
+			sb.WriteRune('-')
+		}
+	}
+	return strings.TrimSuffix(sb.String(), "-")
+}
+
+type page struct {
+	name string
+	sb   strings.Builder
+
+	// pending represents nested sections
+	// to be rendered after the current one
+	pending []pendingSection
+}
+
+type pendingSection struct {
+	section section
+	render  func() error
+}
+
+func (p *page) Page() Page {
+	return Page{
+		Name:    p.name,
+		Content: p.sb.String(),
+	}
+}
+
+func (p *page) heading(level int, anchor, text string) {
+	if p.sb.Len() > 0 {
+		p.sb.WriteString("\n")
+	}
+	if anchor != "" {
+		fmt.Fprintf(&p.sb, "<a id=%q></a>\n\n", anchor)
+	}
+	fmt.Fprintf(&p.sb, "%s %s\n", strings.Repeat("#", level), text)
+}
+
+func (p *page) paragraph(text string) {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return
+	}
+	fmt.Fprintf(&p.sb, "\n%s\n", text)
+}
+
+func (p *page) rootBlock(blockType string, bSchema *schema.BlockSchema) error {
+	s := section{steps: []string{blockType}}
+	p.heading(1, s.anchor(), s.title()+" Block")
+	p.paragraph(fmt.Sprintf("[← %s](%s)", "Back to index", IndexPageName))
+
+	err := p.block(s, bSchema)
+	if err != nil {
+		return err
+	}
+
+	// sections are rendered breadth-first, so that
+	// nested blocks follow their parent
+	for len(p.pending) > 0 {
+		next := p.pending[0]
+		p.pending = p.pending[1:]
+
+		p.heading(2, next.section.anchor(), next.section.title())
+		err := next.render()
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (p *page) block(s section, bSchema *schema.BlockSchema) error {
+	if bSchema.IsDeprecated {
+		p.paragraph("**Deprecated**")
+	}
+	p.paragraph(bSchema.Description.Value)
+	if detail := detailForBlock(bSchema); detail != "" {
+		p.paragraph(fmt.Sprintf("_%s_", detail))
+	}
+
+	if len(bSchema.Labels) > 0 {
+		p.paragraph("**Labels**")
+		p.sb.WriteString("\n")
+		for i, label := range bSchema.Labels {
+			item := fmt.Sprintf("`%s`", label.Name)
+			if label.IsDepKey {
+				item += " (determines the schema of the block body)"
+			}
+			p.indentedListItem(0, fmt.Sprintf("%d. ", i+1), item, label.Description.Value)
+		}
+	}
+
+	depBodies, err := sortedDependentBodies(s, bSchema)
+	if err != nil {
+		return err
+	}
+	if len(depBodies) > 0 {
+		p.paragraph("**Dependent Bodies**")
+		p.sb.WriteString("\n")
+		for _, depBody := range depBodies {
+			p.listItem(fmt.Sprintf("[%s](#%s)", depBody.section.title(), depBody.section.anchor()), "")
+		}
+	}
+
+	err = p.body(s, bSchema.Body, func(blockType string) string {
+		return "#" + s.child(blockType).anchor()
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, depBody := range depBodies {
+		depBody := depBody
+		p.pending = append(p.pending, pendingSection{
+			section: depBody.section,
+			render: func() error {
+				return p.body(depBody.section, depBody.body, func(blockType string) string {
+					return "#" + depBody.section.child(blockType).anchor()
+				})
+			},
+		})
+	}
+
+	return nil
+}
+
+// body renders attributes and blocks of the body, where blockLink
+// returns the link target for documentation of the given block type
+func (p *page) body(s section, bodySchema *schema.BodySchema, blockLink func(string) string) error {
+	if bodySchema == nil {
+		return nil
+	}
+
+	if bodySchema.IsDeprecated {
+		p.paragraph("**Deprecated**")
+	}
+	p.paragraph(bodySchema.Description.Value)
+	if bodySchema.Detail != "" {
+		p.paragraph(fmt.Sprintf("_%s_", bodySchema.Detail))
+	}
+	if link := bodySchema.DocsLink; link != nil {
+		text := link.Tooltip
+		if text == "" {
+			text = "Documentation"
+		}
+		p.paragraph(fmt.Sprintf("[%s](%s)", text, link.URL))
+	}
+
+	if len(bodySchema.Attributes) > 0 || bodySchema.AnyAttribute != nil {
+		p.paragraph("**Attributes**")
+		p.sb.WriteString("\n")
+		for _, name := range bodySchema.AttributeNames() {
+			p.attribute(0, "`"+name+"`", bodySchema.Attributes[name])
+		}
+		if bodySchema.AnyAttribute != nil {
+			p.attribute(0, "_any name_", bodySchema.AnyAttribute)
+		}
+	}
+
+	if len(bodySchema.Blocks) > 0 {
+		p.paragraph("**Blocks**")
+		p.sb.WriteString("\n")
+		for _, blockType := range bodySchema.BlockTypes() {
+			bSchema := bodySchema.Blocks[blockType]
+			item := fmt.Sprintf("[`%s`](%s)", blockType, blockLink(blockType))
+			if detail := detailForBlock(bSchema); detail != "" {
+				item += fmt.Sprintf(" (%s)", detail)
+			}
+			if bSchema.IsDeprecated {
+				item += " **Deprecated**"
+			}
+			p.listItem(item, bSchema.Description.Value)
+		}
+	}
+
+	// root blocks are documented on their own pages
+	if len(s.steps) == 0 {
+		return nil
+	}
+
+	for _, blockType := range bodySchema.BlockTypes() {
+		blockType := blockType
+		childSection := s.child(blockType)
+		p.pending = append(p.pending, pendingSection{
+			section: childSection,
+			render: func() error {
+				return p.block(childSection, bodySchema.Blocks[blockType])
+			},
+		})
+	}
+
+	return nil
+}
+
+func (p *page) attribute(depth int, name string, aSchema *schema.AttributeSchema) {
+	item := name
+	if detail := detailForAttribute(aSchema); detail != "" {
+		item += fmt.Sprintf(" (%s)", detail)
+	}
+	if aSchema.IsDeprecated {
+		item += " **Deprecated**"
+	}
+
+	description := aSchema.Description.Value
+	if defaultDesc, ok := defaultDescription(aSchema.DefaultValue); ok {
+		description = strings.TrimSpace(description + "\n\nDefault: " + defaultDesc)
+	}
+
+	p.indentedListItem(depth, "- ", item, description)
+
+	if obj, ok := aSchema.Constraint.(schema.Object); ok {
+		names := make([]string, 0, len(obj.Attributes))
+		for name := range obj.Attributes {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			p.attribute(depth+1, "`"+name+"`", obj.Attributes[name])
+		}
+	}
+}
+
+func (p *page) listItem(item, description string) {
+	p.indentedListItem(0, "- ", item, description)
+}
+
+func (p *page) indentedListItem(depth int, bullet, item, description string) {
+	indent := strings.Repeat("  ", depth)
+	fmt.Fprintf(&p.sb, "%s%s%s\n", indent, bullet, item)
+
+	description = strings.TrimSpace(description)
+	if description == "" {
+		return
+	}
+	for _, line := range strings.Split(description, "\n") {
+		if line == "" {
+			p.sb.WriteString("\n")
+			continue
+		}
+		fmt.Fprintf(&p.sb, "%s%s%s\n", indent, strings.Repeat(" ", len(bullet)), line)
+	}
+}
+
+func detailForAttribute(aSchema *schema.AttributeSchema) string {
+	details := []string{}
+
+	if aSchema.IsRequired {
+		details = append(details, "required")
+	} else if aSchema.IsOptional {
+		details = append(details, "optional")
+	}
+	if aSchema.IsComputed {
+		details = append(details, "computed")
+	}
+	if aSchema.IsSensitive {
+		details = append(details, "sensitive")
+	}
+
+	if aSchema.Constraint != nil {
+		if friendlyName := aSchema.Constraint.FriendlyName(); friendlyName != "" {
+			details = append(details, friendlyName)
+		}
+	}
+
+	return strings.Join(details, ", ")
+}
+
+func detailForBlock(bSchema *schema.BlockSchema) string {
+	details := []string{}
+
+	if bSchema.Type != schema.BlockTypeNil {
+		details = append(details, bSchema.Type.String())
+	}
+	if bSchema.MinItems > 0 {
+		details = append(details, fmt.Sprintf("min: %d", bSchema.MinItems))
+	}
+	if bSchema.MaxItems > 0 {
+		details = append(details, fmt.Sprintf("max: %d", bSchema.MaxItems))
+	}
+
+	return strings.Join(details, ", ")
+}
+
+// defaultDescription returns Markdown describing the default,
+// consistent with how defaults are described in hover data
+func defaultDescription(def schema.Default) (string, bool) {
+	switch d := def.(type) {
+	case schema.DefaultValue:
+		if d.Value.IsNull() || !d.Value.IsWhollyKnown() {
+			return "", false
+		}
+		return fmt.Sprintf("`%s`", valueString(d.Value)), true
+	case schema.DefaultKeyword:
+		return fmt.Sprintf("`%s`", d.Keyword), true
+	case schema.DefaultTypeDeclaration:
+		return fmt.Sprintf("`%s`", typeexpr.TypeString(d.Type)), true
+	case schema.DefaultAttributeDependent:
+		descriptions := make([]string, 0)
+		for _, dc := range d.Cases {
+			caseDesc, ok := defaultDescription(dc.Default)
+			if !ok || !dc.Value.IsWhollyKnown() {
+				continue
+			}
+			descriptions = append(descriptions, fmt.Sprintf("%s if `%s` is `%s`",
+				caseDesc, d.Name, valueString(dc.Value)))
+		}
+		if fallbackDesc, ok := defaultDescription(d.Fallback); ok {
+			if len(descriptions) == 0 {
+				return fallbackDesc, true
+			}
+			descriptions = append(descriptions, "otherwise "+fallbackDesc)
+		}
+		if len(descriptions) == 0 {
+			return fmt.Sprintf("depends on `%s`", d.Name), true
+		}
+		return strings.Join(descriptions, ", "), true
+	case schema.DefaultEnvVar:
+		desc := fmt.Sprintf("value of `%s` environment variable", d.Name)
+		if fallbackDesc, ok := defaultDescription(d.Fallback); ok {
+			desc += ", otherwise " + fallbackDesc
+		}
+		return desc, true
+	}
+	return "", false
+}
+
+func valueString(val cty.Value) string {
+	return strings.TrimSpace(string(hclwrite.TokensForValue(val).Bytes()))
+}
+
+type dependentBody struct {
+	section section
+	body    *schema.BodySchema
+}
+
+// sortedDependentBodies returns dependent bodies of the block
+// sorted by their schema keys
+func sortedDependentBodies(s section, bSchema *schema.BlockSchema) ([]dependentBody, error) {
+	keys := make([]string, 0, len(bSchema.DependentBody))
+	for key := range bSchema.DependentBody {
+		keys = append(keys, string(key))
+	}
+	sort.Strings(keys)
+
+	parent := section{steps: s.steps[:len(s.steps)-1]}
+	blockType := s.steps[len(s.steps)-1]
+
+	depBodies := make([]dependentBody, 0, len(keys))
+	for _, key := range keys {
+		dk, err := decodeSchemaKey(schema.SchemaKey(key))
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", key, err)
+		}
+		depBodies = append(depBodies, dependentBody{
+			section: parent.child(dependentBodyTitle(blockType, dk)),
+			body:    bSchema.DependentBody[schema.SchemaKey(key)],
+		})
+	}
+
+	return depBodies, nil
+}
+
+// dependentBodyTitle returns the block header matching the given
+// dependency keys, e.g. resource "aws_instance" "*"
+func dependentBodyTitle(blockType string, dk dependencyKeys) string {
+	labels := make([]string, 0)
+	for _, label := range dk.Labels {
+		for len(labels) <= label.Index {
+			labels = append(labels, `"*"`)
+		}
+		labels[label.Index] = fmt.Sprintf("%q", label.Value)
+	}
+
+	title := strings.Join(append([]string{blockType}, labels...), " ")
+
+	attrs := make([]string, 0, len(dk.Attributes))
+	for _, attr := range dk.Attributes {
+		value := attr.Expr.Address
+		if len(attr.Expr.Static) > 0 {
+			value = string(attr.Expr.Static)
+		}
+		attrs = append(attrs, fmt.Sprintf("%s = %s", attr.Name, value))
+	}
+	if len(attrs) > 0 {
+		title += " (" + strings.Join(attrs, ", ") + ")"
+	}
+
+	return title
+}
+
+// dependencyKeys represents decoded schema.SchemaKey, where
+// attribute values are kept in their JSON form
+type dependencyKeys struct {
+	Labels     []schema.LabelDependent `json:"labels"`
+	Attributes []struct {
+		Name string `json:"name"`
+		Expr struct {
+			Static  json.RawMessage `json:"static"`
+			Address string          `json:"addr"`
+		} `json:"expr"`
+	} `json:"attrs"`
+}
+
+func decodeSchemaKey(key schema.SchemaKey) (dependencyKeys, error) {
+	var dk dependencyKeys
+	err := json.Unmarshal([]byte(key), &dk)
+	return dk, err
+}
@@ -0,0 +1,359 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package mddocs
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/hcl-lang/lang"
+	"github.com/hashicorp/hcl-lang/schema"
+	"github.com/zclconf/go-cty/cty"
+)
+
+func TestGenerate(t *testing.T) {
+	bodySchema := &schema.BodySchema{
+		Description: lang.Markdown("Configuration of the **app**."),
+		Attributes: map[string]*schema.AttributeSchema{
+			"name": {
+				IsRequired:  true,
+				Constraint:  schema.LiteralType{Type: cty.String},
+				Description: lang.PlainText("Name of the app"),
+			},
+			"port": {
+				IsOptional:   true,
+				Constraint:   schema.LiteralType{Type: cty.Number},
+				DefaultValue: schema.DefaultValue{Value: cty.NumberIntVal(8080)},
+			},
+			"settings": {
+				IsOptional: true,
+				Constraint: schema.Object{
+					Attributes: schema.ObjectAttributes{
+						"debug": {
+							IsOptional: true,
+							Constraint: schema.LiteralType{Type: cty.Bool},
+						},
+					},
+				},
+			},
+		},
+		Blocks: map[string]*schema.BlockSchema{
+			"resource": {
+				Labels: []*schema.LabelSchema{
+					{Name: "type", IsDepKey: true, Description: lang.PlainText("Resource type")},
+					{Name: "name"},
+				},
+				Type:        schema.BlockTypeList,
+				Description: lang.PlainText("Managed resource"),
+				Body: &schema.BodySchema{
+					DocsLink: &schema.DocsLink{URL: "https://example.com/resource"},
+					Attributes: map[string]*schema.AttributeSchema{
+						"id": {
+							IsComputed: true,
+							Constraint: schema.LiteralType{Type: cty.String},
+						},
+					},
+					Blocks: map[string]*schema.BlockSchema{
+						"lifecycle": {
+							Type:     schema.BlockTypeObject,
+							MaxItems: 1,
+							Body: &schema.BodySchema{
+								Attributes: map[string]*schema.AttributeSchema{
+									"prevent_destroy": {
+										IsOptional:   true,
+										IsDeprecated: true,
+										Constraint:   schema.LiteralType{Type: cty.Bool},
+									},
+								},
+							},
+						},
+					},
+				},
+				DependentBody: map[schema.SchemaKey]*schema.BodySchema{
+					schema.NewSchemaKey(schema.DependencyKeys{
+						Labels: []schema.LabelDependent{{Index: 0, Value: "server"}},
+					}): {
+						Attributes: map[string]*schema.AttributeSchema{
+							"image": {
+								IsRequired:  true,
+								IsSensitive: true,
+								Constraint:  schema.LiteralType{Type: cty.String},
+							},
+						},
+						Blocks: map[string]*schema.BlockSchema{
+							"disk": {
+								MinItems: 1,
+								Body: &schema.BodySchema{
+									AnyAttribute: &schema.AttributeSchema{
+										IsOptional: true,
+										Constraint: schema.LiteralType{Type: cty.String},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			"variable": {
+				Labels:       []*schema.LabelSchema{{Name: "name"}},
+				IsDeprecated: true,
+			},
+		},
+	}
+
+	pages, err := Generate(bodySchema, Options{Title: "App Reference"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectedPages := []Page{
+		{
+			Name: "index.md",
+			Content: "# App Reference\n" +
+				"\n" +
+				"Configuration of the **app**.\n" +
+				"\n" +
+				"**Attributes**\n" +
+				"\n" +
+				"- `name` (required, string)\n" +
+				"  Name of the app\n" +
+				"- `port` (optional, number)\n" +
+				"  Default: `8080`\n" +
+				"- `settings` (optional, object)\n" +
+				"  - `debug` (optional, bool)\n" +
+				"\n" +
+				"**Blocks**\n" +
+				"\n" +
+				"- [`resource`](resource.md) (list)\n" +
+				"  Managed resource\n" +
+				"- [`variable`](variable.md) **Deprecated**\n",
+		},
+		{
+			Name: "resource.md",
+			Content: "<a id=\"resource\"></a>\n" +
+				"\n" +
+				"# `resource` Block\n" +
+				"\n" +
+				"[← Back to index](index.md)\n" +
+				"\n" +
+				"Managed resource\n" +
+				"\n" +
+				"_list_\n" +
+				"\n" +
+				"**Labels**\n" +
+				"\n" +
+				"1. `type` (determines the schema of the block body)\n" +
+				"   Resource type\n" +
+				"2. `name`\n" +
+				"\n" +
+				"**Dependent Bodies**\n" +
+				"\n" +
+				"- [`resource \"server\"`](#resource-server)\n" +
+				"\n" +
+				"[Documentation](https://example.com/resource)\n" +
+				"\n" +
+				"**Attributes**\n" +
+				"\n" +
+				"- `id` (computed, string)\n" +
+				"\n" +
+				"**Blocks**\n" +
+				"\n" +
+				"- [`lifecycle`](#resource-lifecycle) (object, max: 1)\n" +
+				"\n" +
+				"<a id=\"resource-lifecycle\"></a>\n" +
+				"\n" +
+				"## `resource › lifecycle`\n" +
+				"\n" +
+				"_object, max: 1_\n" +
+				"\n" +
+				"**Attributes**\n" +
+				"\n" +
+				"- `prevent_destroy` (optional, bool) **Deprecated**\n" +
+				"\n" +
+				"<a id=\"resource-server\"></a>\n" +
+				"\n" +
+				"## `resource \"server\"`\n" +
+				"\n" +
+				"**Attributes**\n" +
+				"\n" +
+				"- `image` (required, sensitive, string)\n" +
+				"\n" +
+				"**Blocks**\n" +
+				"\n" +
+				"- [`disk`](#resource-server-disk) (min: 1)\n" +
+				"\n" +
+				"<a id=\"resource-server-disk\"></a>\n" +
+				"\n" +
+				"## `resource \"server\" › disk`\n" +
+				"\n" +
+				"_min: 1_\n" +
+				"\n" +
+				"**Attributes**\n" +
+				"\n" +
+				"- _any name_ (optional, string)\n",
+		},
+		{
+			Name: "variable.md",
+			Content: "<a id=\"variable\"></a>\n" +
+				"\n" +
+				"# `variable` Block\n" +
+				"\n" +
+				"[← Back to index](index.md)\n" +
+				"\n" +
+				"**Deprecated**\n" +
+				"\n" +
+				"**Labels**\n" +
+				"\n" +
+				"1. `name`\n",
+		},
+	}
+
+	if diff := cmp.Diff(expectedPages, pages); diff != "" {
+		t.Fatalf("unexpected pages: %s", diff)
+	}
+
+	// the output is expected to be deterministic
+	for i := 0; i < 10; i++ {
+		pages, err := Generate(bodySchema, Options{Title: "App Reference"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff(expectedPages, pages); diff != "" {
+			t.Fatalf("unexpected pages (run %d): %s", i, diff)
+		}
+	}
+}
+
+func TestGenerate_defaults(t *testing.T) {
+	bodySchema := &schema.BodySchema{
+		Attributes: map[string]*schema.AttributeSchema{
+			"mode": {
+				IsOptional:   true,
+				Constraint:   schema.Keyword{Keyword: "auto"},
+				DefaultValue: schema.DefaultKeyword{Keyword: "auto"},
+			},
+			"size": {
+				IsOptional: true,
+				Constraint: schema.LiteralType{Type: cty.Number},
+				DefaultValue: schema.DefaultAttributeDependent{
+					Name: "tier",
+					Cases: []schema.DefaultCase{
+						{
+							Value:   cty.StringVal("large"),
+							Default: schema.DefaultValue{Value: cty.NumberIntVal(8)},
+						},
+					},
+					Fallback: schema.DefaultValue{Value: cty.NumberIntVal(2)},
+				},
+			},
+			"token": {
+				IsOptional:   true,
+				Constraint:   schema.LiteralType{Type: cty.String},
+				Description:  lang.PlainText("API token"),
+				DefaultValue: schema.DefaultEnvVar{Name: "APP_TOKEN"},
+			},
+			"type": {
+				IsOptional:   true,
+				Constraint:   schema.TypeDeclaration{},
+				DefaultValue: schema.DefaultTypeDeclaration{Type: cty.List(cty.String)},
+			},
+		},
+	}
+
+	pages, err := Generate(bodySchema, Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectedPages := []Page{
+		{
+			Name: "index.md",
+			Content: "# Reference\n" +
+				"\n" +
+				"**Attributes**\n" +
+				"\n" +
+				"- `mode` (optional, keyword)\n" +
+				"  Default: `auto`\n" +
+				"- `size` (optional, number)\n" +
+				"  Default: `8` if `tier` is `\"large\"`, otherwise `2`\n" +
+				"- `token` (optional, string)\n" +
+				"  API token\n" +
+				"\n" +
+				"  Default: value of `APP_TOKEN` environment variable\n" +
+				"- `type` (optional, type)\n" +
+				"  Default: `list(string)`\n",
+		},
+	}
+
+	if diff := cmp.Diff(expectedPages, pages); diff != "" {
+		t.Fatalf("unexpected pages: %s", diff)
+	}
+}
+
+func TestGenerate_attributeDependentBody(t *testing.T) {
+	bodySchema := &schema.BodySchema{
+		Blocks: map[string]*schema.BlockSchema{
+			"module": {
+				Labels: []*schema.LabelSchema{{Name: "name"}},
+				Body: &schema.BodySchema{
+					Attributes: map[string]*schema.AttributeSchema{
+						"source": {
+							IsRequired: true,
+							IsDepKey:   true,
+							Constraint: schema.LiteralType{Type: cty.String},
+						},
+					},
+				},
+				DependentBody: map[schema.SchemaKey]*schema.BodySchema{
+					schema.NewSchemaKey(schema.DependencyKeys{
+						Attributes: []schema.AttributeDependent{
+							{
+								Name: "source",
+								Expr: schema.ExpressionValue{Static: cty.StringVal("./network")},
+							},
+						},
+					}): {
+						Description: lang.PlainText("Network module"),
+					},
+				},
+			},
+		},
+	}
+
+	pages, err := Generate(bodySchema, Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectedContent := "<a id=\"module\"></a>\n" +
+		"\n" +
+		"# `module` Block\n" +
+		"\n" +
+		"[← Back to index](index.md)\n" +
+		"\n" +
+		"**Labels**\n" +
+		"\n" +
+		"1. `name`\n" +
+		"\n" +
+		"**Dependent Bodies**\n" +
+		"\n" +
+		"- [`module (source = \"./network\")`](#module-source-network)\n" +
+		"\n" +
+		"**Attributes**\n" +
+		"\n" +
+		"- `source` (required, string)\n" +
+		"\n" +
+		"<a id=\"module-source-network\"></a>\n" +
+		"\n" +
+		"## `module (source = \"./network\")`\n" +
+		"\n" +
+		"Network module\n"
+
+	if diff := cmp.Diff(expectedContent, pages[1].Content); diff != "" {
+		t.Fatalf("unexpected content: %s", diff)
+	}
+	if pages[0].Content != "# Reference\n\n**Blocks**\n\n- [`module`](module.md)\n" {
+		t.Fatalf("unexpected index: %q", pages[0].Content)
+	}
+}
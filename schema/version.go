@@ -0,0 +1,167 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package schema
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Version represents a version of the configuration language
+// (or of the product interpreting it), such as 1.6.0.
+//
+// The zero value represents an unknown or unspecified version.
+type Version struct {
+	Major      uint64
+	Minor      uint64
+	Patch      uint64
+	Prerelease string
+}
+
+// ParseVersion parses a version such as 1.6, v1.6.0 or 1.6.0-beta1.
+// Any build metadata (e.g. +abc) is ignored.
+func ParseVersion(s string) (Version, error) {
+	raw := strings.TrimPrefix(s, "v")
+	raw, _, _ = strings.Cut(raw, "+")
+	raw, prerelease, hasPrerelease := strings.Cut(raw, "-")
+	if hasPrerelease && prerelease == "" {
+		return Version{}, fmt.Errorf("invalid version %q: empty prerelease", s)
+	}
+
+	segments := strings.Split(raw, ".")
+	if len(segments) > 3 {
+		return Version{}, fmt.Errorf("invalid version %q: expected at most 3 segments", s)
+	}
+
+	numbers := make([]uint64, 3)
+	for i, segment := range segments {
+		n, err := strconv.ParseUint(segment, 10, 64)
+		if err != nil {
+			return Version{}, fmt.Errorf("invalid version %q: %w", s, err)
+		}
+		numbers[i] = n
+	}
+
+	return Version{
+		Major:      numbers[0],
+		Minor:      numbers[1],
+		Patch:      numbers[2],
+		Prerelease: prerelease,
+	}, nil
+}
+
+// MustParseVersion is like ParseVersion but panics
+// if the version cannot be parsed. It is intended
+// for use in statically declared schemas.
+func MustParseVersion(s string) Version {
+	v, err := ParseVersion(s)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+func (v Version) IsZero() bool {
+	return v == Version{}
+}
+
+func (v Version) String() string {
+	s := fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
+	if v.Prerelease != "" {
+		s += "-" + v.Prerelease
+	}
+	return s
+}
+
+// Compare returns -1, 0 or 1 depending on whether v is lower than,
+// equal to or greater than other. Prerelease versions are lower
+// than the corresponding release and are compared with each other
+// by their dot-separated identifiers, as described by semver,
+// e.g. beta.2 is lower than beta.10.
+//
+// Numeric suffixes of otherwise equal identifiers are compared
+// as numbers too, such that beta2 is lower than beta10.
+func (v Version) Compare(other Version) int {
+	if c := compareUint(v.Major, other.Major); c != 0 {
+		return c
+	}
+	if c := compareUint(v.Minor, other.Minor); c != 0 {
+		return c
+	}
+	if c := compareUint(v.Patch, other.Patch); c != 0 {
+		return c
+	}
+
+	switch {
+	case v.Prerelease == other.Prerelease:
+		return 0
+	case v.Prerelease == "":
+		return 1
+	case other.Prerelease == "":
+		return -1
+	}
+	return comparePrerelease(v.Prerelease, other.Prerelease)
+}
+
+func (v Version) LessThan(other Version) bool {
+	return v.Compare(other) < 0
+}
+
+func compareUint(a, b uint64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+func comparePrerelease(a, b string) int {
+	aIds, bIds := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < len(aIds) && i < len(bIds); i++ {
+		if c := comparePrereleaseIdentifier(aIds[i], bIds[i]); c != 0 {
+			return c
+		}
+	}
+	// a larger set of identifiers has higher precedence
+	return compareUint(uint64(len(aIds)), uint64(len(bIds)))
+}
+
+func comparePrereleaseIdentifier(a, b string) int {
+	aNum, aErr := strconv.ParseUint(a, 10, 64)
+	bNum, bErr := strconv.ParseUint(b, 10, 64)
+	switch {
+	case aErr == nil && bErr == nil:
+		return compareUint(aNum, bNum)
+	case aErr == nil:
+		// numeric identifiers have lower precedence
+		return -1
+	case bErr == nil:
+		return 1
+	}
+
+	aPrefix, aSuffix := splitNumericSuffix(a)
+	bPrefix, bSuffix := splitNumericSuffix(b)
+	if aPrefix == bPrefix && aSuffix != "" && bSuffix != "" {
+		aNum, aErr := strconv.ParseUint(aSuffix, 10, 64)
+		bNum, bErr := strconv.ParseUint(bSuffix, 10, 64)
+		if aErr == nil && bErr == nil && aNum != bNum {
+			return compareUint(aNum, bNum)
+		}
+	}
+
+	return strings.Compare(a, b)
+}
+
+// splitNumericSuffix splits an identifier such as beta10
+// into its prefix (beta) and numeric suffix (10)
+func splitNumericSuffix(id string) (string, string) {
+	i := len(id)
+	for i > 0 && id[i-1] >= '0' && id[i-1] <= '9' {
+		i--
+	}
+	return id[:i], id[i:]
+}
@@ -0,0 +1,216 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package schema
+
+import (
+	"errors"
+)
+
+// VersionInfo describes in which versions of the language
+// an attribute, block or literal value is available.
+//
+// Zero (unset) versions impose no constraint.
+type VersionInfo struct {
+	// IntroducedIn represents the first version supporting the element
+	IntroducedIn Version
+
+	// DeprecatedIn represents the first version in which
+	// the element is deprecated
+	DeprecatedIn Version
+
+	// RemovedIn represents the first version no longer
+	// supporting the element
+	RemovedIn Version
+}
+
+// IsAvailableIn returns true if the element is supported
+// in the given version, or if the version is unknown
+func (vi VersionInfo) IsAvailableIn(v Version) bool {
+	if v.IsZero() {
+		return true
+	}
+	if !vi.IntroducedIn.IsZero() && v.LessThan(vi.IntroducedIn) {
+		return false
+	}
+	if !vi.RemovedIn.IsZero() && !v.LessThan(vi.RemovedIn) {
+		return false
+	}
+	return true
+}
+
+// IsDeprecatedIn returns true if the element is deprecated
+// in the given (known) version
+func (vi VersionInfo) IsDeprecatedIn(v Version) bool {
+	if v.IsZero() || vi.DeprecatedIn.IsZero() {
+		return false
+	}
+	return !v.LessThan(vi.DeprecatedIn)
+}
+
+func (vi VersionInfo) Validate() error {
+	if !vi.IntroducedIn.IsZero() && !vi.DeprecatedIn.IsZero() &&
+		vi.DeprecatedIn.LessThan(vi.IntroducedIn) {
+		return errors.New("DeprecatedIn must not be lower than IntroducedIn")
+	}
+	if !vi.IntroducedIn.IsZero() && !vi.RemovedIn.IsZero() &&
+		!vi.IntroducedIn.LessThan(vi.RemovedIn) {
+		return errors.New("RemovedIn must be greater than IntroducedIn")
+	}
+	if !vi.DeprecatedIn.IsZero() && !vi.RemovedIn.IsZero() &&
+		vi.RemovedIn.LessThan(vi.DeprecatedIn) {
+		return errors.New("RemovedIn must not be lower than DeprecatedIn")
+	}
+	return nil
+}
+
+// ForVersion returns a copy of the schema with any attributes,
+// blocks and literal values unavailable in the given version removed,
+// and those deprecated in the version marked as deprecated.
+// Attributes whose constraint allows no value in the version
+// (e.g. OneOf of unavailable literal values) are removed too.
+//
+// The schema is returned as-is (copied) if the version is unknown.
+func (bs *BodySchema) ForVersion(v Version) *BodySchema {
+	if bs == nil {
+		return nil
+	}
+
+	newBs := bs.Copy()
+	if !v.IsZero() {
+		projectBodySchema(newBs, v)
+	}
+	return newBs
+}
+
+func projectBodySchema(bs *BodySchema, v Version) {
+	for name, aSchema := range bs.Attributes {
+		if !aSchema.Versions.IsAvailableIn(v) || !projectAttributeSchema(aSchema, v) {
+			delete(bs.Attributes, name)
+		}
+	}
+	if bs.AnyAttribute != nil && !projectAttributeSchema(bs.AnyAttribute, v) {
+		bs.AnyAttribute = nil
+	}
+
+	if bs.AttributeOrder != nil {
+		attrOrder := make([]string, 0, len(bs.AttributeOrder))
+		for _, name := range bs.AttributeOrder {
+			if _, ok := bs.Attributes[name]; ok {
+				attrOrder = append(attrOrder, name)
+			}
+		}
+		bs.AttributeOrder = attrOrder
+	}
+
+	for name, bSchema := range bs.Blocks {
+		if !bSchema.Versions.IsAvailableIn(v) {
+			delete(bs.Blocks, name)
+			continue
+		}
+		if bSchema.Versions.IsDeprecatedIn(v) {
+			bSchema.IsDeprecated = true
+		}
+		if bSchema.Body != nil {
+			projectBodySchema(bSchema.Body, v)
+		}
+		for _, depBody := range bSchema.DependentBody {
+			projectBodySchema(depBody, v)
+		}
+		if bSchema.DependentBodyResolver != nil {
+			bSchema.DependentBodyResolver = versionedDependentBodyResolver{
+				resolver: bSchema.DependentBodyResolver,
+				version:  v,
+			}
+		}
+	}
+}
+
+// projectAttributeSchema projects the attribute's constraint
+// for the given version and returns false if no value
+// of the attribute is available in that version
+func projectAttributeSchema(as *AttributeSchema, v Version) bool {
+	if as.Versions.IsDeprecatedIn(v) {
+		as.IsDeprecated = true
+	}
+	if as.Constraint == nil {
+		return true
+	}
+	as.Constraint = constraintForVersion(as.Constraint, v)
+	return as.Constraint != nil
+}
+
+// constraintForVersion returns the constraint without any literal
+// values unavailable in the given version, or nil if the constraint
+// no longer allows any value (e.g. OneOf with no values left)
+func constraintForVersion(con Constraint, v Version) Constraint {
+	switch c := con.(type) {
+	case LiteralValue:
+		if c.Versions.IsDeprecatedIn(v) {
+			c.IsDeprecated = true
+		}
+		return c
+	case OneOf:
+		newOneOf := make(OneOf, 0, len(c))
+		for _, elem := range c {
+			if lv, ok := elem.(LiteralValue); ok && !lv.Versions.IsAvailableIn(v) {
+				continue
+			}
+			newElem := constraintForVersion(elem, v)
+			if newElem == nil {
+				continue
+			}
+			newOneOf = append(newOneOf, newElem)
+		}
+		switch len(newOneOf) {
+		case 0:
+			return nil
+		case 1:
+			return newOneOf[0]
+		}
+		return newOneOf
+	case List:
+		if c.Elem == nil {
+			return c
+		}
+		c.Elem = constraintForVersion(c.Elem, v)
+		if c.Elem == nil {
+			return nil
+		}
+		return c
+	case Set:
+		if c.Elem == nil {
+			return c
+		}
+		c.Elem = constraintForVersion(c.Elem, v)
+		if c.Elem == nil {
+			return nil
+		}
+		return c
+	case Map:
+		if c.Elem == nil {
+			return c
+		}
+		c.Elem = constraintForVersion(c.Elem, v)
+		if c.Elem == nil {
+			return nil
+		}
+		return c
+	case Tuple:
+		for i, elem := range c.Elems {
+			c.Elems[i] = constraintForVersion(elem, v)
+			if c.Elems[i] == nil {
+				return nil
+			}
+		}
+		return c
+	case Object:
+		for name, aSchema := range c.Attributes {
+			if !aSchema.Versions.IsAvailableIn(v) || !projectAttributeSchema(aSchema, v) {
+				delete(c.Attributes, name)
+			}
+		}
+		return c
+	}
+	return con
+}
@@ -0,0 +1,406 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package schema
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/zclconf/go-cty-debug/ctydebug"
+	"github.com/zclconf/go-cty/cty"
+)
+
+func TestBodySchema_ForVersion(t *testing.T) {
+	v1_3 := MustParseVersion("1.3")
+	v1_6 := MustParseVersion("1.6")
+	v2_0 := MustParseVersion("2.0")
+
+	bodySchema := &BodySchema{
+		Attributes: map[string]*AttributeSchema{
+			"name": {
+				IsRequired: true,
+				Constraint: LiteralType{Type: cty.String},
+			},
+			"timeout": {
+				IsOptional: true,
+				Constraint: LiteralType{Type: cty.Number},
+				Versions: VersionInfo{
+					IntroducedIn: v1_3,
+					DeprecatedIn: v1_6,
+				},
+			},
+			"mode": {
+				IsOptional: true,
+				Constraint: OneOf{
+					LiteralValue{Value: cty.StringVal("fast")},
+					LiteralValue{
+						Value:    cty.StringVal("turbo"),
+						Versions: VersionInfo{IntroducedIn: v1_6},
+					},
+					LiteralValue{
+						Value:    cty.StringVal("legacy"),
+						Versions: VersionInfo{DeprecatedIn: v1_3, RemovedIn: v2_0},
+					},
+				},
+			},
+		},
+		AttributeOrder: []string{"name", "timeout", "mode"},
+		Blocks: map[string]*BlockSchema{
+			"retry": {
+				Versions: VersionInfo{IntroducedIn: v1_6},
+				Body: &BodySchema{
+					Attributes: map[string]*AttributeSchema{
+						"count": {
+							IsOptional: true,
+							Constraint: LiteralType{Type: cty.Number},
+						},
+					},
+				},
+			},
+			"legacy": {
+				Versions: VersionInfo{DeprecatedIn: v1_3, RemovedIn: v2_0},
+				Body: &BodySchema{
+					Attributes: map[string]*AttributeSchema{
+						"flag": {
+							IsOptional: true,
+							Constraint: LiteralType{Type: cty.Bool},
+							Versions:   VersionInfo{IntroducedIn: v1_3},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	testCases := []struct {
+		version        Version
+		expectedSchema *BodySchema
+	}{
+		{
+			Version{},
+			bodySchema,
+		},
+		{
+			MustParseVersion("1.2"),
+			&BodySchema{
+				Attributes: map[string]*AttributeSchema{
+					"name": bodySchema.Attributes["name"],
+					"mode": {
+						IsOptional: true,
+						Constraint: OneOf{
+							LiteralValue{Value: cty.StringVal("fast")},
+							LiteralValue{
+								Value:    cty.StringVal("legacy"),
+								Versions: VersionInfo{DeprecatedIn: v1_3, RemovedIn: v2_0},
+							},
+						},
+					},
+				},
+				AttributeOrder: []string{"name", "mode"},
+				Blocks: map[string]*BlockSchema{
+					"legacy": {
+						Versions: VersionInfo{DeprecatedIn: v1_3, RemovedIn: v2_0},
+						Body:     &BodySchema{Attributes: map[string]*AttributeSchema{}},
+					},
+				},
+			},
+		},
+		{
+			MustParseVersion("1.6.0"),
+			&BodySchema{
+				Attributes: map[string]*AttributeSchema{
+					"name": bodySchema.Attributes["name"],
+					"timeout": {
+						IsOptional:   true,
+						IsDeprecated: true,
+						Constraint:   LiteralType{Type: cty.Number},
+						Versions: VersionInfo{
+							IntroducedIn: v1_3,
+							DeprecatedIn: v1_6,
+						},
+					},
+					"mode": {
+						IsOptional: true,
+						Constraint: OneOf{
+							LiteralValue{Value: cty.StringVal("fast")},
+							LiteralValue{
+								Value:    cty.StringVal("turbo"),
+								Versions: VersionInfo{IntroducedIn: v1_6},
+							},
+							LiteralValue{
+								Value:        cty.StringVal("legacy"),
+								IsDeprecated: true,
+								Versions:     VersionInfo{DeprecatedIn: v1_3, RemovedIn: v2_0},
+							},
+						},
+					},
+				},
+				AttributeOrder: []string{"name", "timeout", "mode"},
+				Blocks: map[string]*BlockSchema{
+					"retry": bodySchema.Blocks["retry"],
+					"legacy": {
+						IsDeprecated: true,
+						Versions:     VersionInfo{DeprecatedIn: v1_3, RemovedIn: v2_0},
+						Body: &BodySchema{
+							Attributes: map[string]*AttributeSchema{
+								"flag": bodySchema.Blocks["legacy"].Body.Attributes["flag"],
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			MustParseVersion("2.0.0"),
+			&BodySchema{
+				Attributes: map[string]*AttributeSchema{
+					"name": bodySchema.Attributes["name"],
+					"timeout": {
+						IsOptional:   true,
+						IsDeprecated: true,
+						Constraint:   LiteralType{Type: cty.Number},
+						Versions: VersionInfo{
+							IntroducedIn: v1_3,
+							DeprecatedIn: v1_6,
+						},
+					},
+					"mode": {
+						IsOptional: true,
+						Constraint: OneOf{
+							LiteralValue{Value: cty.StringVal("fast")},
+							LiteralValue{
+								Value:    cty.StringVal("turbo"),
+								Versions: VersionInfo{IntroducedIn: v1_6},
+							},
+						},
+					},
+				},
+				AttributeOrder: []string{"name", "timeout", "mode"},
+				Blocks: map[string]*BlockSchema{
+					"retry": bodySchema.Blocks["retry"],
+				},
+			},
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("%2d-%s", i, tc.version), func(t *testing.T) {
+			projectedSchema := bodySchema.ForVersion(tc.version)
+			if diff := cmp.Diff(tc.expectedSchema, projectedSchema, ctydebug.CmpOptions); diff != "" {
+				t.Fatalf("unexpected schema: %s", diff)
+			}
+		})
+	}
+
+	// the original schema must remain untouched
+	if _, ok := bodySchema.Blocks["legacy"]; !ok {
+		t.Fatal("expected original schema to retain all blocks")
+	}
+	if bodySchema.Attributes["timeout"].IsDeprecated {
+		t.Fatal("expected original schema to remain undeprecated")
+	}
+}
+
+func TestBodySchema_ForVersion_unavailableOneOf(t *testing.T) {
+	v1_6 := MustParseVersion("1.6")
+
+	turbo := LiteralValue{
+		Value:    cty.StringVal("turbo"),
+		Versions: VersionInfo{IntroducedIn: v1_6},
+	}
+	eco := LiteralValue{
+		Value:    cty.StringVal("eco"),
+		Versions: VersionInfo{IntroducedIn: v1_6},
+	}
+
+	bodySchema := &BodySchema{
+		Attributes: map[string]*AttributeSchema{
+			"mode": {
+				IsOptional: true,
+				Constraint: OneOf{
+					LiteralValue{Value: cty.StringVal("fast")},
+					turbo,
+				},
+			},
+			"profile": {
+				IsOptional: true,
+				Constraint: OneOf{turbo, eco},
+			},
+			"profiles": {
+				IsOptional: true,
+				Constraint: List{Elem: OneOf{turbo, eco}},
+			},
+			"settings": {
+				IsOptional: true,
+				Constraint: Object{
+					Attributes: ObjectAttributes{
+						"name": {
+							IsOptional: true,
+							Constraint: LiteralType{Type: cty.String},
+						},
+						"profile": {
+							IsOptional: true,
+							Constraint: OneOf{turbo, eco},
+						},
+					},
+				},
+			},
+		},
+		AttributeOrder: []string{"mode", "profile", "profiles", "settings"},
+		AnyAttribute: &AttributeSchema{
+			IsOptional: true,
+			Constraint: OneOf{turbo, eco},
+		},
+	}
+
+	expectedSchema := &BodySchema{
+		Attributes: map[string]*AttributeSchema{
+			"mode": {
+				IsOptional: true,
+				Constraint: LiteralValue{Value: cty.StringVal("fast")},
+			},
+			"settings": {
+				IsOptional: true,
+				Constraint: Object{
+					Attributes: ObjectAttributes{
+						"name": {
+							IsOptional: true,
+							Constraint: LiteralType{Type: cty.String},
+						},
+					},
+				},
+			},
+		},
+		AttributeOrder: []string{"mode", "settings"},
+	}
+
+	projectedSchema := bodySchema.ForVersion(MustParseVersion("1.2"))
+	if diff := cmp.Diff(expectedSchema, projectedSchema, ctydebug.CmpOptions); diff != "" {
+		t.Fatalf("unexpected schema: %s", diff)
+	}
+}
+
+func TestVersionInfo_Validate(t *testing.T) {
+	testCases := []struct {
+		versions    VersionInfo
+		expectedErr error
+	}{
+		{
+			VersionInfo{},
+			nil,
+		},
+		{
+			VersionInfo{
+				IntroducedIn: MustParseVersion("1.3"),
+				DeprecatedIn: MustParseVersion("1.3"),
+				RemovedIn:    MustParseVersion("2.0"),
+			},
+			nil,
+		},
+		{
+			VersionInfo{
+				IntroducedIn: MustParseVersion("1.3"),
+				DeprecatedIn: MustParseVersion("1.2"),
+			},
+			errors.New("DeprecatedIn must not be lower than IntroducedIn"),
+		},
+		{
+			VersionInfo{
+				IntroducedIn: MustParseVersion("1.3"),
+				RemovedIn:    MustParseVersion("1.3"),
+			},
+			errors.New("RemovedIn must be greater than IntroducedIn"),
+		},
+		{
+			VersionInfo{
+				DeprecatedIn: MustParseVersion("1.6"),
+				RemovedIn:    MustParseVersion("1.5"),
+			},
+			errors.New("RemovedIn must not be lower than DeprecatedIn"),
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("%2d", i), func(t *testing.T) {
+			err := tc.versions.Validate()
+			if tc.expectedErr == nil {
+				if err != nil {
+					t.Fatal(err)
+				}
+				return
+			}
+			if err == nil || err.Error() != tc.expectedErr.Error() {
+				t.Fatalf("expected error %q, given: %v", tc.expectedErr, err)
+			}
+		})
+	}
+}
+
+type staticDependentBodyResolver map[SchemaKey]*BodySchema
+
+func (r staticDependentBodyResolver) ResolveDependentBody(keys DependencyKeys) (*BodySchema, bool) {
+	bodySchema, ok := r[NewSchemaKey(keys)]
+	return bodySchema, ok
+}
+
+func (r staticDependentBodyResolver) ListDependentBodies(int, string, uint) ([]DependentBodySummary, bool) {
+	return nil, true
+}
+
+func TestBodySchema_ForVersion_dependentBodyResolver(t *testing.T) {
+	keys := DependencyKeys{
+		Labels: []LabelDependent{
+			{Index: 0, Value: "instance"},
+		},
+	}
+	resolver := staticDependentBodyResolver{
+		NewSchemaKey(keys): {
+			Attributes: map[string]*AttributeSchema{
+				"name": {
+					IsOptional: true,
+					Constraint: LiteralType{Type: cty.String},
+				},
+				"zone": {
+					IsOptional: true,
+					Constraint: LiteralType{Type: cty.String},
+					Versions:   VersionInfo{IntroducedIn: MustParseVersion("1.6")},
+				},
+			},
+		},
+	}
+	bodySchema := &BodySchema{
+		Blocks: map[string]*BlockSchema{
+			"resource": {
+				Labels: []*LabelSchema{
+					{Name: "type", IsDepKey: true},
+				},
+				DependentBodyResolver: resolver,
+			},
+		},
+	}
+
+	projectedSchema := bodySchema.ForVersion(MustParseVersion("1.5"))
+	depBody, ok := projectedSchema.Blocks["resource"].LookupDependentBody(keys)
+	if !ok {
+		t.Fatal("expected dependent body to be resolved")
+	}
+	expectedBody := &BodySchema{
+		Attributes: map[string]*AttributeSchema{
+			"name": {
+				IsOptional: true,
+				Constraint: LiteralType{Type: cty.String},
+			},
+		},
+	}
+	if diff := cmp.Diff(expectedBody, depBody, ctydebug.CmpOptions); diff != "" {
+		t.Fatalf("unexpected dependent body: %s", diff)
+	}
+
+	// bodies of the original resolver must remain untouched
+	if _, ok := resolver[NewSchemaKey(keys)].Attributes["zone"]; !ok {
+		t.Fatal("expected resolved body to retain all attributes")
+	}
+}
@@ -0,0 +1,80 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package schema
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestParseVersion(t *testing.T) {
+	testCases := []struct {
+		rawVersion      string
+		expectedVersion Version
+		expectedErr     bool
+	}{
+		{"1", Version{Major: 1}, false},
+		{"1.6", Version{Major: 1, Minor: 6}, false},
+		{"v1.6.2", Version{Major: 1, Minor: 6, Patch: 2}, false},
+		{"1.6.0-beta1", Version{Major: 1, Minor: 6, Prerelease: "beta1"}, false},
+		{"1.6.0+abc", Version{Major: 1, Minor: 6}, false},
+		{"", Version{}, true},
+		{"1.6.0-", Version{}, true},
+		{"1.2.3.4", Version{}, true},
+		{"1.x", Version{}, true},
+	}
+
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("%2d-%s", i, tc.rawVersion), func(t *testing.T) {
+			v, err := ParseVersion(tc.rawVersion)
+			if tc.expectedErr {
+				if err == nil {
+					t.Fatalf("expected error for %q, given: %#v", tc.rawVersion, v)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(tc.expectedVersion, v); diff != "" {
+				t.Fatalf("unexpected version: %s", diff)
+			}
+		})
+	}
+}
+
+func TestVersion_Compare(t *testing.T) {
+	testCases := []struct {
+		a, b     string
+		expected int
+	}{
+		{"1.0.0", "1.0.0", 0},
+		{"1.0", "1.0.0", 0},
+		{"1.2.0", "1.10.0", -1},
+		{"2.0.0", "1.99.99", 1},
+		{"1.0.1", "1.0.0", 1},
+		{"1.6.0-beta1", "1.6.0", -1},
+		{"1.6.0", "1.6.0-rc1", 1},
+		{"1.6.0-alpha1", "1.6.0-beta1", -1},
+		{"1.6.0-beta2", "1.6.0-beta10", -1},
+		{"1.6.0-rc1", "1.6.0-beta10", 1},
+		{"1.6.0-beta.2", "1.6.0-beta.10", -1},
+		{"1.6.0-alpha", "1.6.0-alpha.1", -1},
+		{"1.6.0-alpha.1", "1.6.0-alpha.beta", -1},
+		{"1.6.0-beta.11", "1.6.0-beta.2", 1},
+		{"1.6.0-beta02", "1.6.0-beta2", -1},
+	}
+
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("%2d-%s-%s", i, tc.a, tc.b), func(t *testing.T) {
+			given := MustParseVersion(tc.a).Compare(MustParseVersion(tc.b))
+			if given != tc.expected {
+				t.Fatalf("expected %s compared to %s to be %d, given %d",
+					tc.a, tc.b, tc.expected, given)
+			}
+		})
+	}
+}
@@ -3,12 +3,18 @@
 
 package schemacontext
 
-import "context"
+import (
+	"context"
+
+	"github.com/hashicorp/hcl-lang/schema"
+)
 
 type unknownSchemaCtxKey struct{}
 type foundBlocksCtxKey struct{}
 type dynamicBlocksCtxKey struct{}
 type blockNestingLevelCtxKey struct{}
+type targetVersionCtxKey struct{}
+type lookupEnvCtxKey struct{}
 
 // WithUnknownSchema attaches a flag indicating that the schema being passed
 // is not wholly known.
@@ -52,3 +58,33 @@ func BlockNestingLevel(ctx context.Context) (uint64, bool) {
 	lvl, ok := ctx.Value(blockNestingLevelCtxKey{}).(uint64)
 	return lvl, ok
 }
+
+// WithTargetVersion attaches the declared target version
+// of the language, which validators may compare against
+// versions declared in the schema.
+func WithTargetVersion(ctx context.Context, v schema.Version) context.Context {
+	return context.WithValue(ctx, targetVersionCtxKey{}, v)
+}
+
+// TargetVersion returns the declared target version, if known.
+func TargetVersion(ctx context.Context) (schema.Version, bool) {
+	v, ok := ctx.Value(targetVersionCtxKey{}).(schema.Version)
+	if !ok || v.IsZero() {
+		return schema.Version{}, false
+	}
+	return v, true
+}
+
+// WithLookupEnv attaches the function used to look up environment
+// variables, such that dependent bodies selected via DefaultEnvVar
+// are resolved the same way across all features.
+func WithLookupEnv(ctx context.Context, lookupEnv schema.LookupEnvFunc) context.Context {
+	return context.WithValue(ctx, lookupEnvCtxKey{}, lookupEnv)
+}
+
+// LookupEnv returns the function used to look up environment
+// variables, or nil if there is none.
+func LookupEnv(ctx context.Context) schema.LookupEnvFunc {
+	lookupEnv, _ := ctx.Value(lookupEnvCtxKey{}).(schema.LookupEnvFunc)
+	return lookupEnv
+}
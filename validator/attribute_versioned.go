@@ -0,0 +1,83 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package validator
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/hcl-lang/schema"
+	"github.com/hashicorp/hcl-lang/schemacontext"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+)
+
+// VersionedAttribute reports attributes and literal values
+// which are not available or deprecated in the declared target
+// version (see schemacontext.WithTargetVersion)
+type VersionedAttribute struct{}
+
+func (v VersionedAttribute) Visit(ctx context.Context, node hclsyntax.Node, nodeSchema schema.Schema) (context.Context, hcl.Diagnostics) {
+	var diags hcl.Diagnostics
+
+	attr, ok := node.(*hclsyntax.Attribute)
+	if !ok {
+		return ctx, diags
+	}
+
+	if nodeSchema == nil {
+		return ctx, diags
+	}
+	targetVersion, ok := schemacontext.TargetVersion(ctx)
+	if !ok {
+		return ctx, diags
+	}
+
+	attrSchema := nodeSchema.(*schema.AttributeSchema)
+	diags = append(diags, versionDiagnostics(fmt.Sprintf("%q", attr.Name),
+		attrSchema.Versions, targetVersion, attr.SrcRange)...)
+	if diags.HasErrors() {
+		return ctx, diags
+	}
+
+	lv, ok := matchingLiteralValue(attr.Expr, attrSchema.Constraint)
+	if ok {
+		value := strings.TrimSpace(string(hclwrite.TokensForValue(lv.Value).Bytes()))
+		diags = append(diags, versionDiagnostics(fmt.Sprintf("Value %s", value),
+			lv.Versions, targetVersion, attr.Expr.Range())...)
+	}
+
+	return ctx, diags
+}
+
+// matchingLiteralValue returns the LiteralValue constraint
+// (declared directly or as part of OneOf) matching
+// the static value of the expression, if any
+func matchingLiteralValue(expr hcl.Expression, con schema.Constraint) (schema.LiteralValue, bool) {
+	var candidates []schema.Constraint
+	switch c := con.(type) {
+	case schema.LiteralValue:
+		candidates = []schema.Constraint{c}
+	case schema.OneOf:
+		candidates = c
+	default:
+		return schema.LiteralValue{}, false
+	}
+
+	value, diags := expr.Value(nil)
+	if diags.HasErrors() || !value.IsWhollyKnown() {
+		return schema.LiteralValue{}, false
+	}
+
+	for _, candidate := range candidates {
+		lv, ok := candidate.(schema.LiteralValue)
+		if ok && lv.Value.RawEquals(value) {
+			return lv, true
+		}
+	}
+
+	return schema.LiteralValue{}, false
+}
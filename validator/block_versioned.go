@@ -0,0 +1,42 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package validator
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/hcl-lang/schema"
+	"github.com/hashicorp/hcl-lang/schemacontext"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+)
+
+// VersionedBlock reports blocks which are not available
+// or deprecated in the declared target version
+// (see schemacontext.WithTargetVersion)
+type VersionedBlock struct{}
+
+func (v VersionedBlock) Visit(ctx context.Context, node hclsyntax.Node, nodeSchema schema.Schema) (context.Context, hcl.Diagnostics) {
+	var diags hcl.Diagnostics
+
+	block, ok := node.(*hclsyntax.Block)
+	if !ok {
+		return ctx, diags
+	}
+
+	if nodeSchema == nil {
+		return ctx, diags
+	}
+	targetVersion, ok := schemacontext.TargetVersion(ctx)
+	if !ok {
+		return ctx, diags
+	}
+
+	blockSchema := nodeSchema.(*schema.BlockSchema)
+	diags = append(diags, versionDiagnostics(fmt.Sprintf("%q", block.Type),
+		blockSchema.Versions, targetVersion, block.TypeRange)...)
+
+	return ctx, diags
+}
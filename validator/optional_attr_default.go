@@ -0,0 +1,114 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package validator
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/hcl-lang/schema"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/ext/typeexpr"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/zclconf/go-cty/cty/convert"
+)
+
+// InvalidOptionalAttrDefault reports default values of optional
+// object attributes which do not conform to the attribute type,
+// e.g. optional(number, "foo"), in type declarations
+// with schema.TypeDeclaration{OptionalAttrs: true}
+type InvalidOptionalAttrDefault struct{}
+
+func (v InvalidOptionalAttrDefault) Visit(ctx context.Context, node hclsyntax.Node, nodeSchema schema.Schema) (context.Context, hcl.Diagnostics) {
+	var diags hcl.Diagnostics
+
+	attr, ok := node.(*hclsyntax.Attribute)
+	if !ok {
+		return ctx, diags
+	}
+
+	if nodeSchema == nil {
+		return ctx, diags
+	}
+	attrSchema := nodeSchema.(*schema.AttributeSchema)
+	cons, ok := attrSchema.Constraint.(schema.TypeDeclaration)
+	if !ok || !cons.OptionalAttrs {
+		return ctx, diags
+	}
+
+	return ctx, invalidOptionalAttrDefaults(attr.Expr)
+}
+
+func invalidOptionalAttrDefaults(expr hcl.Expression) hcl.Diagnostics {
+	var diags hcl.Diagnostics
+
+	call, callDiags := hcl.ExprCall(expr)
+	if callDiags.HasErrors() {
+		return diags
+	}
+
+	switch call.Name {
+	case "list", "set", "map":
+		if len(call.Arguments) == 1 {
+			diags = append(diags, invalidOptionalAttrDefaults(call.Arguments[0])...)
+		}
+	case "tuple":
+		if len(call.Arguments) != 1 {
+			return diags
+		}
+		elemExprs, listDiags := hcl.ExprList(call.Arguments[0])
+		if listDiags.HasErrors() {
+			return diags
+		}
+		for _, elemExpr := range elemExprs {
+			diags = append(diags, invalidOptionalAttrDefaults(elemExpr)...)
+		}
+	case "object":
+		if len(call.Arguments) != 1 {
+			return diags
+		}
+		items, mapDiags := hcl.ExprMap(call.Arguments[0])
+		if mapDiags.HasErrors() {
+			return diags
+		}
+		for _, item := range items {
+			diags = append(diags, invalidOptionalAttrDefault(item.Value)...)
+		}
+	}
+
+	return diags
+}
+
+func invalidOptionalAttrDefault(expr hcl.Expression) hcl.Diagnostics {
+	call, callDiags := hcl.ExprCall(expr)
+	if callDiags.HasErrors() || call.Name != "optional" || len(call.Arguments) == 0 {
+		return invalidOptionalAttrDefaults(expr)
+	}
+
+	diags := invalidOptionalAttrDefaults(call.Arguments[0])
+	if len(call.Arguments) != 2 {
+		return diags
+	}
+
+	typ, _, typeDiags := typeexpr.TypeConstraintWithDefaults(call.Arguments[0])
+	if typeDiags.HasErrors() {
+		return diags
+	}
+	defaultVal, valDiags := call.Arguments[1].Value(nil)
+	if valDiags.HasErrors() {
+		return diags
+	}
+
+	_, err := convert.Convert(defaultVal, typ)
+	if err != nil {
+		diags = append(diags, &hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  "Invalid default value for optional attribute",
+			Detail:   fmt.Sprintf("This default value is not compatible with the attribute's type constraint: %s.", err),
+			Subject:  call.Arguments[1].Range().Ptr(),
+		})
+	}
+
+	return diags
+}
@@ -0,0 +1,50 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package validator
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/hcl-lang/schema"
+	"github.com/hashicorp/hcl/v2"
+)
+
+// versionDiagnostics reports whether the element described
+// by subject (e.g. `"foo"`) is unavailable or deprecated
+// in the given target version
+func versionDiagnostics(subject string, vi schema.VersionInfo, target schema.Version, rng hcl.Range) hcl.Diagnostics {
+	var diags hcl.Diagnostics
+	detail := fmt.Sprintf("The declared target version is %s", target)
+
+	if !vi.IntroducedIn.IsZero() && target.LessThan(vi.IntroducedIn) {
+		diags = append(diags, &hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  fmt.Sprintf("%s requires version >= %s", subject, vi.IntroducedIn),
+			Detail:   detail,
+			Subject:  rng.Ptr(),
+		})
+		return diags
+	}
+
+	if !vi.RemovedIn.IsZero() && !target.LessThan(vi.RemovedIn) {
+		diags = append(diags, &hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  fmt.Sprintf("%s was removed in version %s", subject, vi.RemovedIn),
+			Detail:   detail,
+			Subject:  rng.Ptr(),
+		})
+		return diags
+	}
+
+	if vi.IsDeprecatedIn(target) {
+		diags = append(diags, &hcl.Diagnostic{
+			Severity: hcl.DiagWarning,
+			Summary:  fmt.Sprintf("%s is deprecated since version %s", subject, vi.DeprecatedIn),
+			Detail:   detail,
+			Subject:  rng.Ptr(),
+		})
+	}
+
+	return diags
+}